@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"log"
 	"os"
 
+	"github.com/Reyysusanto/warasin-web/backend/config"
 	"github.com/Reyysusanto/warasin-web/backend/migrations"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/Reyysusanto/warasin-web/backend/service"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
 	"gorm.io/gorm"
 )
 
@@ -12,6 +17,10 @@ func Command(db *gorm.DB) {
 	migrate := false
 	seed := false
 	rollback := false
+	generateSlot := false
+	reencrypt := false
+	expirePayment := false
+	retryRefund := false
 
 	for _, arg := range os.Args[1:] {
 		if arg == "--migrate" {
@@ -25,6 +34,22 @@ func Command(db *gorm.DB) {
 		if arg == "--rollback" {
 			rollback = true
 		}
+
+		if arg == "--generate-slot" {
+			generateSlot = true
+		}
+
+		if arg == "--reencrypt" {
+			reencrypt = true
+		}
+
+		if arg == "--expire-payment" {
+			expirePayment = true
+		}
+
+		if arg == "--retry-refund" {
+			retryRefund = true
+		}
 	}
 
 	if migrate {
@@ -50,4 +75,72 @@ func Command(db *gorm.DB) {
 
 		log.Println("rollback complete successfully")
 	}
+
+	if generateSlot {
+		slotGenerator := service.NewSlotGenerator(repository.NewPsychologRepository(db))
+
+		total, err := slotGenerator.GenerateAll(context.Background())
+		if err != nil {
+			log.Fatalf("error generate slot: %v (%d slot created before failure)", err, total)
+		}
+
+		log.Printf("generate slot complete successfully, %d slot created", total)
+	}
+
+	if reencrypt {
+		if err := migrations.Reencrypt(db); err != nil {
+			log.Fatalf("error reencrypt: %v", err)
+		}
+
+		log.Println("reencrypt complete successfully")
+	}
+
+	// dijalankan berkala (cron) untuk melepas slot dari consultation yang tidak dibayar
+	if expirePayment {
+		paymentConfig, err := config.NewPaymentConfig()
+		if err != nil {
+			log.Fatalf("error loading payment config: %v", err)
+		}
+
+		paymentGateway, err := utils.NewPaymentGateway(paymentConfig)
+		if err != nil {
+			log.Fatalf("error loading payment gateway: %v", err)
+		}
+
+		paymentService := service.NewPaymentService(repository.NewPaymentRepository(db), repository.NewConsultationRepository(db), paymentGateway, paymentConfig)
+
+		total, err := paymentService.ExpireOverduePayments(context.Background())
+		if err != nil {
+			log.Fatalf("error expire payment: %v (%d payment expired before failure)", err, total)
+		}
+
+		log.Printf("expire payment complete successfully, %d payment expired", total)
+	}
+
+	// dijalankan berkala (cron) untuk mengulang refund yang gagal atau tertunda
+	if retryRefund {
+		paymentConfig, err := config.NewPaymentConfig()
+		if err != nil {
+			log.Fatalf("error loading payment config: %v", err)
+		}
+
+		paymentGateway, err := utils.NewPaymentGateway(paymentConfig)
+		if err != nil {
+			log.Fatalf("error loading payment gateway: %v", err)
+		}
+
+		cancellationConfig, err := config.NewCancellationConfig()
+		if err != nil {
+			log.Fatalf("error loading cancellation config: %v", err)
+		}
+
+		cancellationService := service.NewCancellationService(repository.NewPaymentRepository(db), paymentGateway, cancellationConfig)
+
+		total, err := cancellationService.RetryRefunds(context.Background())
+		if err != nil {
+			log.Fatalf("error retry refund: %v", err)
+		}
+
+		log.Printf("retry refund complete successfully, %d refund succeeded", total)
+	}
 }
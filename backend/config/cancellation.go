@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// CancellationTier berlaku jika user membatalkan minimal MinHoursBefore jam sebelum sesi
+type CancellationTier struct {
+	MinHoursBefore int
+	RefundPercent  int
+}
+
+type CancellationConfig struct {
+	// format "jam:persen" dipisah koma, contoh "24:100,0:50" berarti gratis sampai 24 jam
+	// sebelum sesi, setelah itu refund 50%, dan tanpa refund setelah sesi dimulai
+	Tiers                  []CancellationTier `mapstructure:"CANCELLATION_REFUND_TIERS"`
+	NoShowRefundPercent    int                `mapstructure:"CANCELLATION_NO_SHOW_REFUND_PERCENT"`
+	PsychologRefundPercent int                `mapstructure:"CANCELLATION_PSYCHOLOG_REFUND_PERCENT"`
+}
+
+func NewCancellationConfig() (*CancellationConfig, error) {
+	viper.AutomaticEnv()
+
+	viper.BindEnv("CANCELLATION_REFUND_TIERS")
+	viper.BindEnv("CANCELLATION_NO_SHOW_REFUND_PERCENT")
+	viper.BindEnv("CANCELLATION_PSYCHOLOG_REFUND_PERCENT")
+
+	viper.SetDefault("CANCELLATION_REFUND_TIERS", "24:100,0:50")
+	viper.SetDefault("CANCELLATION_NO_SHOW_REFUND_PERCENT", 0)
+	viper.SetDefault("CANCELLATION_PSYCHOLOG_REFUND_PERCENT", 100)
+
+	tiers, err := parseCancellationTiers(viper.GetString("CANCELLATION_REFUND_TIERS"))
+	if err != nil {
+		return nil, err
+	}
+
+	config := CancellationConfig{
+		Tiers:                  tiers,
+		NoShowRefundPercent:    viper.GetInt("CANCELLATION_NO_SHOW_REFUND_PERCENT"),
+		PsychologRefundPercent: viper.GetInt("CANCELLATION_PSYCHOLOG_REFUND_PERCENT"),
+	}
+
+	if !isValidRefundPercent(config.NoShowRefundPercent) || !isValidRefundPercent(config.PsychologRefundPercent) {
+		return nil, errors.New("CANCELLATION_NO_SHOW_REFUND_PERCENT and CANCELLATION_PSYCHOLOG_REFUND_PERCENT must be between 0 and 100")
+	}
+
+	return &config, nil
+}
+
+func parseCancellationTiers(value string) ([]CancellationTier, error) {
+	var tiers []CancellationTier
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		hours, percent, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid CANCELLATION_REFUND_TIERS entry %q, expected hours:percent", part)
+		}
+
+		tier := CancellationTier{}
+		var err error
+		if tier.MinHoursBefore, err = strconv.Atoi(strings.TrimSpace(hours)); err != nil || tier.MinHoursBefore < 0 {
+			return nil, fmt.Errorf("invalid hours in CANCELLATION_REFUND_TIERS entry %q", part)
+		}
+		if tier.RefundPercent, err = strconv.Atoi(strings.TrimSpace(percent)); err != nil || !isValidRefundPercent(tier.RefundPercent) {
+			return nil, fmt.Errorf("invalid percent in CANCELLATION_REFUND_TIERS entry %q", part)
+		}
+
+		if seen[tier.MinHoursBefore] {
+			return nil, fmt.Errorf("duplicate hours in CANCELLATION_REFUND_TIERS entry %q", part)
+		}
+		seen[tier.MinHoursBefore] = true
+
+		tiers = append(tiers, tier)
+	}
+
+	// tier dengan batas jam terbesar dicek lebih dulu
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinHoursBefore > tiers[j].MinHoursBefore
+	})
+
+	return tiers, nil
+}
+
+func isValidRefundPercent(percent int) bool {
+	return percent >= 0 && percent <= 100
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/spf13/viper"
+)
+
+type ChatLimitConfig struct {
+	RatePerMinute     float64 `mapstructure:"CHAT_RATE_LIMIT_PER_MINUTE"`
+	Burst             int     `mapstructure:"CHAT_RATE_LIMIT_BURST"`
+	DailyMessageQuota int     `mapstructure:"CHAT_DAILY_MESSAGE_QUOTA"`
+	DailyTokenQuota   int     `mapstructure:"CHAT_DAILY_TOKEN_QUOTA"`
+}
+
+func NewChatLimitConfig() (*ChatLimitConfig, error) {
+	viper.AutomaticEnv()
+
+	viper.BindEnv("CHAT_RATE_LIMIT_PER_MINUTE")
+	viper.BindEnv("CHAT_RATE_LIMIT_BURST")
+	viper.BindEnv("CHAT_DAILY_MESSAGE_QUOTA")
+	viper.BindEnv("CHAT_DAILY_TOKEN_QUOTA")
+
+	viper.SetDefault("CHAT_RATE_LIMIT_PER_MINUTE", 6)
+	viper.SetDefault("CHAT_RATE_LIMIT_BURST", 3)
+	viper.SetDefault("CHAT_DAILY_MESSAGE_QUOTA", 100)
+	viper.SetDefault("CHAT_DAILY_TOKEN_QUOTA", 100000)
+
+	config := ChatLimitConfig{
+		RatePerMinute:     viper.GetFloat64("CHAT_RATE_LIMIT_PER_MINUTE"),
+		Burst:             viper.GetInt("CHAT_RATE_LIMIT_BURST"),
+		DailyMessageQuota: viper.GetInt("CHAT_DAILY_MESSAGE_QUOTA"),
+		DailyTokenQuota:   viper.GetInt("CHAT_DAILY_TOKEN_QUOTA"),
+	}
+
+	if config.RatePerMinute <= 0 || config.Burst <= 0 {
+		return nil, errors.New("CHAT_RATE_LIMIT_PER_MINUTE and CHAT_RATE_LIMIT_BURST must be greater than 0")
+	}
+
+	// quota 0 berarti tidak dibatasi
+	if config.DailyMessageQuota < 0 || config.DailyTokenQuota < 0 {
+		return nil, errors.New("CHAT_DAILY_MESSAGE_QUOTA and CHAT_DAILY_TOKEN_QUOTA must not be negative")
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EncryptionConfig berisi key-encryption key (KEK) untuk enkripsi field di database.
+// Key lama tetap didaftarkan selama rotasi agar data lama masih bisa dibaca.
+type EncryptionConfig struct {
+	ActiveKeyID string `mapstructure:"ENCRYPTION_ACTIVE_KEY_ID"`
+	Keys        map[string][]byte
+}
+
+func NewEncryptionConfig() (*EncryptionConfig, error) {
+	viper.AutomaticEnv()
+
+	viper.BindEnv("ENCRYPTION_KEYS")
+	viper.BindEnv("ENCRYPTION_ACTIVE_KEY_ID")
+
+	config := EncryptionConfig{
+		ActiveKeyID: viper.GetString("ENCRYPTION_ACTIVE_KEY_ID"),
+		Keys:        map[string][]byte{},
+	}
+
+	// format: <key id>:<base64 32 byte>,<key id>:<base64 32 byte>
+	rawKeys := viper.GetString("ENCRYPTION_KEYS")
+	for _, entry := range strings.Split(rawKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		keyID, encoded, found := strings.Cut(entry, ":")
+		if !found || keyID == "" {
+			return nil, errors.New("ENCRYPTION_KEYS must use the format <key id>:<base64 key>")
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %q must be 32 bytes encoded in base64", keyID)
+		}
+
+		config.Keys[keyID] = key
+	}
+
+	if len(config.Keys) == 0 {
+		return nil, errors.New("ENCRYPTION_KEYS is required")
+	}
+
+	if config.ActiveKeyID == "" {
+		return nil, errors.New("ENCRYPTION_ACTIVE_KEY_ID is required")
+	}
+
+	if _, ok := config.Keys[config.ActiveKeyID]; !ok {
+		return nil, errors.New("ENCRYPTION_ACTIVE_KEY_ID is not listed in ENCRYPTION_KEYS")
+	}
+
+	return &config, nil
+}
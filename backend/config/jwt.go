@@ -0,0 +1,49 @@
+package config
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/spf13/viper"
+)
+
+type JWTConfig struct {
+	Issuer                string `mapstructure:"JWT_ISSUER"`
+	PrivateKeyPath        string `mapstructure:"JWT_PRIVATE_KEY_PATH"`
+	KeyID                 string `mapstructure:"JWT_KEY_ID"`
+	PublicKeysDir         string `mapstructure:"JWT_PUBLIC_KEYS_DIR"`
+	AccessTokenTTLSeconds int    `mapstructure:"JWT_ACCESS_TOKEN_TTL_SECONDS"`
+}
+
+func NewJWTConfig() (*JWTConfig, error) {
+	viper.AutomaticEnv()
+
+	viper.BindEnv("JWT_ISSUER")
+	viper.BindEnv("JWT_PRIVATE_KEY_PATH")
+	viper.BindEnv("JWT_KEY_ID")
+	viper.BindEnv("JWT_PUBLIC_KEYS_DIR")
+	viper.BindEnv("JWT_ACCESS_TOKEN_TTL_SECONDS")
+
+	viper.SetDefault("JWT_ISSUER", "warasin")
+	viper.SetDefault("JWT_ACCESS_TOKEN_TTL_SECONDS", 300)
+
+	config := JWTConfig{
+		Issuer:                viper.GetString("JWT_ISSUER"),
+		PrivateKeyPath:        viper.GetString("JWT_PRIVATE_KEY_PATH"),
+		KeyID:                 viper.GetString("JWT_KEY_ID"),
+		PublicKeysDir:         viper.GetString("JWT_PUBLIC_KEYS_DIR"),
+		AccessTokenTTLSeconds: viper.GetInt("JWT_ACCESS_TOKEN_TTL_SECONDS"),
+	}
+
+	if config.AccessTokenTTLSeconds <= 0 {
+		return nil, errors.New("JWT_ACCESS_TOKEN_TTL_SECONDS must be greater than 0")
+	}
+
+	// di production tidak boleh jatuh ke key sementara
+	if os.Getenv("APP_ENV") == constants.ENUM_RUN_PRODUCTION && config.PrivateKeyPath == "" {
+		return nil, errors.New("JWT_PRIVATE_KEY_PATH is required in production")
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/spf13/viper"
+)
+
+type LLMConfig struct {
+	Provider       string  `mapstructure:"LLM_PROVIDER"`
+	BaseURL        string  `mapstructure:"LLM_BASE_URL"`
+	APIKey         string  `mapstructure:"LLM_API_KEY"`
+	Model          string  `mapstructure:"LLM_MODEL"`
+	Temperature    float64 `mapstructure:"LLM_TEMPERATURE"`
+	MaxTokens      int     `mapstructure:"LLM_MAX_TOKENS"`
+	TimeoutSeconds int     `mapstructure:"LLM_TIMEOUT_SECONDS"`
+
+	// budget token riwayat chat yang dikirim ke model, pesan lama di luar budget diringkas
+	ContextMaxTokens      int `mapstructure:"CHAT_CONTEXT_MAX_TOKENS"`
+	ContextRecentMessages int `mapstructure:"CHAT_CONTEXT_RECENT_MESSAGES"`
+}
+
+func NewLLMConfig() (*LLMConfig, error) {
+	viper.AutomaticEnv()
+
+	viper.BindEnv("LLM_PROVIDER")
+	viper.BindEnv("LLM_BASE_URL", "LLM_BASE_URL", "OPENAI_BASE_URL")
+	viper.BindEnv("LLM_API_KEY", "LLM_API_KEY", "OPENAI_API_KEY")
+	viper.BindEnv("LLM_MODEL")
+	viper.BindEnv("LLM_TEMPERATURE")
+	viper.BindEnv("LLM_MAX_TOKENS")
+	viper.BindEnv("LLM_TIMEOUT_SECONDS")
+	viper.BindEnv("CHAT_CONTEXT_MAX_TOKENS")
+	viper.BindEnv("CHAT_CONTEXT_RECENT_MESSAGES")
+
+	viper.SetDefault("LLM_PROVIDER", "openai")
+	viper.SetDefault("LLM_BASE_URL", "https://api.openai.com/v1")
+	viper.SetDefault("LLM_MODEL", "gpt-4o-mini")
+	viper.SetDefault("LLM_TEMPERATURE", 0.7)
+	viper.SetDefault("LLM_MAX_TOKENS", 0)
+	viper.SetDefault("LLM_TIMEOUT_SECONDS", 60)
+	viper.SetDefault("CHAT_CONTEXT_MAX_TOKENS", 3000)
+	viper.SetDefault("CHAT_CONTEXT_RECENT_MESSAGES", 6)
+
+	config := LLMConfig{
+		Provider:       viper.GetString("LLM_PROVIDER"),
+		BaseURL:        viper.GetString("LLM_BASE_URL"),
+		APIKey:         viper.GetString("LLM_API_KEY"),
+		Model:          viper.GetString("LLM_MODEL"),
+		Temperature:    viper.GetFloat64("LLM_TEMPERATURE"),
+		MaxTokens:      viper.GetInt("LLM_MAX_TOKENS"),
+		TimeoutSeconds: viper.GetInt("LLM_TIMEOUT_SECONDS"),
+
+		ContextMaxTokens:      viper.GetInt("CHAT_CONTEXT_MAX_TOKENS"),
+		ContextRecentMessages: viper.GetInt("CHAT_CONTEXT_RECENT_MESSAGES"),
+	}
+
+	if config.TimeoutSeconds <= 0 {
+		return nil, errors.New("LLM_TIMEOUT_SECONDS must be greater than 0")
+	}
+
+	if config.ContextMaxTokens <= 0 {
+		return nil, errors.New("CHAT_CONTEXT_MAX_TOKENS must be greater than 0")
+	}
+
+	return &config, nil
+}
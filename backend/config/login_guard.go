@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/spf13/viper"
+)
+
+type LoginGuardConfig struct {
+	DelayAfterAttempts   int `mapstructure:"LOGIN_DELAY_AFTER_ATTEMPTS"`
+	DelayBaseSeconds     int `mapstructure:"LOGIN_DELAY_BASE_SECONDS"`
+	DelayMaxSeconds      int `mapstructure:"LOGIN_DELAY_MAX_SECONDS"`
+	LockoutAttempts      int `mapstructure:"LOGIN_LOCKOUT_ATTEMPTS"`
+	IPLockoutAttempts    int `mapstructure:"LOGIN_IP_LOCKOUT_ATTEMPTS"`
+	LockoutMinutes       int `mapstructure:"LOGIN_LOCKOUT_MINUTES"`
+	AttemptWindowMinutes int `mapstructure:"LOGIN_ATTEMPT_WINDOW_MINUTES"`
+}
+
+func NewLoginGuardConfig() (*LoginGuardConfig, error) {
+	viper.AutomaticEnv()
+
+	viper.BindEnv("LOGIN_DELAY_AFTER_ATTEMPTS")
+	viper.BindEnv("LOGIN_DELAY_BASE_SECONDS")
+	viper.BindEnv("LOGIN_DELAY_MAX_SECONDS")
+	viper.BindEnv("LOGIN_LOCKOUT_ATTEMPTS")
+	viper.BindEnv("LOGIN_IP_LOCKOUT_ATTEMPTS")
+	viper.BindEnv("LOGIN_LOCKOUT_MINUTES")
+	viper.BindEnv("LOGIN_ATTEMPT_WINDOW_MINUTES")
+
+	viper.SetDefault("LOGIN_DELAY_AFTER_ATTEMPTS", 3)
+	viper.SetDefault("LOGIN_DELAY_BASE_SECONDS", 2)
+	viper.SetDefault("LOGIN_DELAY_MAX_SECONDS", 60)
+	viper.SetDefault("LOGIN_LOCKOUT_ATTEMPTS", 10)
+	viper.SetDefault("LOGIN_IP_LOCKOUT_ATTEMPTS", 50)
+	viper.SetDefault("LOGIN_LOCKOUT_MINUTES", 15)
+	viper.SetDefault("LOGIN_ATTEMPT_WINDOW_MINUTES", 15)
+
+	config := LoginGuardConfig{
+		DelayAfterAttempts:   viper.GetInt("LOGIN_DELAY_AFTER_ATTEMPTS"),
+		DelayBaseSeconds:     viper.GetInt("LOGIN_DELAY_BASE_SECONDS"),
+		DelayMaxSeconds:      viper.GetInt("LOGIN_DELAY_MAX_SECONDS"),
+		LockoutAttempts:      viper.GetInt("LOGIN_LOCKOUT_ATTEMPTS"),
+		IPLockoutAttempts:    viper.GetInt("LOGIN_IP_LOCKOUT_ATTEMPTS"),
+		LockoutMinutes:       viper.GetInt("LOGIN_LOCKOUT_MINUTES"),
+		AttemptWindowMinutes: viper.GetInt("LOGIN_ATTEMPT_WINDOW_MINUTES"),
+	}
+
+	if config.DelayAfterAttempts <= 0 || config.LockoutAttempts <= config.DelayAfterAttempts {
+		return nil, errors.New("LOGIN_LOCKOUT_ATTEMPTS must be greater than LOGIN_DELAY_AFTER_ATTEMPTS, and both greater than 0")
+	}
+
+	if config.IPLockoutAttempts < config.LockoutAttempts {
+		return nil, errors.New("LOGIN_IP_LOCKOUT_ATTEMPTS must not be less than LOGIN_LOCKOUT_ATTEMPTS")
+	}
+
+	if config.DelayBaseSeconds <= 0 || config.DelayMaxSeconds < config.DelayBaseSeconds {
+		return nil, errors.New("LOGIN_DELAY_MAX_SECONDS must not be less than LOGIN_DELAY_BASE_SECONDS, and both greater than 0")
+	}
+
+	if config.LockoutMinutes <= 0 || config.AttemptWindowMinutes <= 0 {
+		return nil, errors.New("LOGIN_LOCKOUT_MINUTES and LOGIN_ATTEMPT_WINDOW_MINUTES must be greater than 0")
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/spf13/viper"
+)
+
+type PaymentConfig struct {
+	Provider      string `mapstructure:"PAYMENT_PROVIDER"`
+	WebhookSecret string `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	HoldMinutes   int    `mapstructure:"PAYMENT_HOLD_MINUTES"`
+
+	// halaman checkout palsu untuk gateway fake, reference ditambahkan sebagai query ?ref=
+	FakeCheckoutURL string `mapstructure:"PAYMENT_FAKE_CHECKOUT_URL"`
+}
+
+func NewPaymentConfig() (*PaymentConfig, error) {
+	viper.AutomaticEnv()
+
+	viper.BindEnv("PAYMENT_PROVIDER")
+	viper.BindEnv("PAYMENT_WEBHOOK_SECRET")
+	viper.BindEnv("PAYMENT_HOLD_MINUTES")
+	viper.BindEnv("PAYMENT_FAKE_CHECKOUT_URL")
+
+	viper.SetDefault("PAYMENT_HOLD_MINUTES", 15)
+	viper.SetDefault("PAYMENT_FAKE_CHECKOUT_URL", "http://127.0.0.1:3000/payment/checkout")
+
+	config := PaymentConfig{
+		Provider:        viper.GetString("PAYMENT_PROVIDER"),
+		WebhookSecret:   viper.GetString("PAYMENT_WEBHOOK_SECRET"),
+		HoldMinutes:     viper.GetInt("PAYMENT_HOLD_MINUTES"),
+		FakeCheckoutURL: viper.GetString("PAYMENT_FAKE_CHECKOUT_URL"),
+	}
+
+	// provider harus dipilih eksplisit agar tidak diam-diam jatuh ke gateway fake
+	if config.Provider == "" {
+		return nil, errors.New("PAYMENT_PROVIDER must be set")
+	}
+
+	// gateway fake menerima pembayaran tanpa uang sungguhan
+	if os.Getenv("APP_ENV") == constants.ENUM_RUN_PRODUCTION && config.Provider == constants.ENUM_PAYMENT_GATEWAY_FAKE {
+		return nil, errors.New("PAYMENT_PROVIDER fake is not allowed in production")
+	}
+
+	if config.WebhookSecret == "" {
+		return nil, errors.New("PAYMENT_WEBHOOK_SECRET must be set")
+	}
+
+	if config.HoldMinutes <= 0 {
+		return nil, errors.New("PAYMENT_HOLD_MINUTES must be greater than 0")
+	}
+
+	return &config, nil
+}
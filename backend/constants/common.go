@@ -1,12 +1,104 @@
 package constants
 
 const (
-	ENUM_ROLE_ADMIN = "admin"
-	ENUM_ROLE_USER  = "user"
+	ENUM_ROLE_ADMIN     = "admin"
+	ENUM_ROLE_USER      = "user"
+	ENUM_ROLE_PSYCHOLOG = "psycholog"
 
 	ENUM_RUN_PRODUCTION = "production"
 	ENUM_RUN_TESTING    = "testing"
 
 	ENUM_PAGINATION_LIMIT = 10
 	ENUM_PAGINATION_PAGE  = 1
+
+	ENUM_SLOT_GENERATION_HORIZON_DAYS = 14
+
+	ENUM_REFRESH_TOKEN_TTL_HOURS = 24 * 7
+
+	ENUM_RBAC_CACHE_TTL_SECONDS = 60
+
+	ENUM_ONE_TIME_TOKEN_VERIFY_EMAIL      = "verify_email"
+	ENUM_ONE_TIME_TOKEN_RESET_PASSWORD    = "reset_password"
+	ENUM_VERIFY_EMAIL_TOKEN_TTL_HOURS     = 24
+	ENUM_RESET_PASSWORD_TOKEN_TTL_MINUTES = 60
+
+	ENUM_TWO_FACTOR_ISSUER                 = "Warasin"
+	ENUM_TWO_FACTOR_CHALLENGE_TTL_MINUTES  = 5
+	ENUM_TWO_FACTOR_CHALLENGE_MAX_ATTEMPTS = 5
+	ENUM_TWO_FACTOR_RECOVERY_CODE_COUNT    = 10
+
+	ENUM_PSYCHOLOG_STATUS_PENDING  = "pending"
+	ENUM_PSYCHOLOG_STATUS_APPROVED = "approved"
+	ENUM_PSYCHOLOG_STATUS_REJECTED = "rejected"
+
+	ENUM_PSYCHOLOG_DOCUMENT_LICENSE     = "license"
+	ENUM_PSYCHOLOG_DOCUMENT_STR         = "str"
+	ENUM_PSYCHOLOG_DOCUMENT_CERTIFICATE = "certificate"
+	ENUM_PSYCHOLOG_DOCUMENT_MAX_SIZE    = 5 << 20
+
+	ENUM_CHANGE_REQUEST_STATUS_PENDING  = "pending"
+	ENUM_CHANGE_REQUEST_STATUS_APPROVED = "approved"
+	ENUM_CHANGE_REQUEST_STATUS_REJECTED = "rejected"
+	ENUM_CHANGE_REQUEST_FIELD_EMAIL     = "email"
+	ENUM_CHANGE_REQUEST_FIELD_STR       = "str_number"
+
+	// nilai 0-2 dipertahankan agar data consultation lama tetap valid
+	ENUM_CONSULTATION_STATUS_REQUESTED             = 0
+	ENUM_CONSULTATION_STATUS_CANCELED_BY_USER      = 1
+	ENUM_CONSULTATION_STATUS_COMPLETED             = 2
+	ENUM_CONSULTATION_STATUS_CONFIRMED             = 3
+	ENUM_CONSULTATION_STATUS_IN_PROGRESS           = 4
+	ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG = 5
+	ENUM_CONSULTATION_STATUS_NO_SHOW               = 6
+	ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT      = 7
+	ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED       = 8
+
+	// pelaku perubahan status yang bukan user/psycholog, misalnya webhook pembayaran
+	ENUM_CONSULTATION_CHANGED_BY_SYSTEM = "system"
+
+	ENUM_PAYMENT_STATUS_PENDING   = "pending"
+	ENUM_PAYMENT_STATUS_PAID      = "paid"
+	ENUM_PAYMENT_STATUS_FAILED    = "failed"
+	ENUM_PAYMENT_STATUS_EXPIRED   = "expired"
+	ENUM_PAYMENT_STATUS_CANCELED  = "canceled"
+	ENUM_PAYMENT_STATUS_LATE_PAID = "late_paid" // dibayar setelah tagihan ditutup, dana dikembalikan penuh
+	ENUM_PAYMENT_GATEWAY_FAKE     = "fake"
+	ENUM_PAYMENT_CURRENCY         = "IDR"
+	ENUM_PAYMENT_EXPIRE_BATCH     = 100
+
+	ENUM_REFUND_STATUS_PENDING          = "pending"
+	ENUM_REFUND_STATUS_SUCCEEDED        = "succeeded"
+	ENUM_REFUND_STATUS_FAILED           = "failed"
+	ENUM_REFUND_RETRY_AFTER_MINUTES     = 10
+	ENUM_CANCELLATION_RULE_USER         = "canceled_by_user"
+	ENUM_CANCELLATION_RULE_PSYCHOLOG    = "canceled_by_psycholog"
+	ENUM_CANCELLATION_RULE_NO_SHOW      = "no_show"
+	ENUM_CANCELLATION_RULE_LATE_PAYMENT = "late_payment"
+
+	ENUM_REVIEW_STATUS_UNMODERATED = "unmoderated"
+	ENUM_REVIEW_STATUS_VISIBLE     = "visible"
+	ENUM_REVIEW_STATUS_HIDDEN      = "hidden"
+	ENUM_RECENT_REVIEW_LIMIT       = 5
+
+	ENUM_PRACTICE_TYPE_ONLINE = "Konsultasi Online"
+	ENUM_PRACTICE_TYPE_CLINIC = "Praktek Klinik"
+
+	ENUM_PRACTICE_TYPE_FILTER_ONLINE = "online"
+	ENUM_PRACTICE_TYPE_FILTER_CLINIC = "clinic"
+
+	ENUM_PSYCHOLOG_SORT_NEWEST       = "newest"
+	ENUM_PSYCHOLOG_SORT_RATING       = "rating"
+	ENUM_PSYCHOLOG_SORT_EXPERIENCE   = "experience"
+	ENUM_PSYCHOLOG_SORT_SOONEST_SLOT = "soonest_slot"
+	ENUM_SEARCH_MAX_AVAILABLE_WITHIN = 90
+
+	// event SSE chat stream, replace berarti client harus membuang balasan yang sudah diterima
+	ENUM_CHAT_STREAM_EVENT_MESSAGE = "message"
+	ENUM_CHAT_STREAM_EVENT_REPLACE = "replace"
+
+	// konfigurasi text search bawaan PostgreSQL dengan stemmer bahasa Indonesia
+	ENUM_SEARCH_TEXT_CONFIG     = "indonesian"
+	ENUM_SEARCH_TYPE_NEWS       = "news"
+	ENUM_SEARCH_TYPE_MOTIVATION = "motivation"
+	ENUM_SEARCH_TYPE_PSYCHOLOG  = "psycholog"
 )
@@ -3,6 +3,7 @@ package dto
 import (
 	"errors"
 	"mime/multipart"
+	"time"
 
 	"github.com/Reyysusanto/warasin-web/backend/entity"
 	"github.com/google/uuid"
@@ -27,6 +28,8 @@ const (
 	MESSAGE_FAILED_LOGIN_USER    = "failed login user"
 	MESSAGE_FAILED_REFRESH_TOKEN = "failed refresh token"
 	MESSAGE_FAILED_LOGIN_ADMIN   = "failed login admin"
+	MESSAGE_FAILED_LOGOUT        = "failed logout"
+	MESSAGE_FAILED_LOGOUT_ALL    = "failed logout from all devices"
 	// Send Email
 	MESSAGE_FAILED_SEND_VERIFICATION_EMAIL     = "failed to send verification email"
 	MESSAGE_FAILED_VERIFY_EMAIL                = "failed to verify email"
@@ -37,6 +40,14 @@ const (
 	MESSAGE_FAILED_GET_LIST_PROVINCE = "failed get list province"
 	// Role
 	MESSAGE_FAILED_GET_LIST_ROLE = "failed get all role"
+	MESSAGE_FAILED_CREATE_ROLE   = "failed create role"
+	MESSAGE_FAILED_UPDATE_ROLE   = "failed update role"
+	MESSAGE_FAILED_DELETE_ROLE   = "failed delete role"
+	// Permission
+	MESSAGE_FAILED_CREATE_PERMISSION   = "failed create permission"
+	MESSAGE_FAILED_GET_LIST_PERMISSION = "failed get list permission"
+	MESSAGE_FAILED_UPDATE_PERMISSION   = "failed update permission"
+	MESSAGE_FAILED_DELETE_PERMISSION   = "failed delete permission"
 	// User
 	MESSAGE_FAILED_CREATE_USER     = "failed create user"
 	MESSAGE_FAILED_GET_DETAIL_USER = "failed get detail user"
@@ -82,6 +93,8 @@ const (
 	MESSAGE_FAILED_GET_DETAIL_CONSULTATION = "failed get detail consultation"
 	MESSAGE_FAILED_UPDATE_CONSULTATION     = "failed update consultation"
 	MESSAGE_FAILED_DELETE_CONSULTATION     = "failed delete consultation"
+
+	MESSAGE_FAILED_GET_CONSULTATION_STATUS_HISTORY = "failed get consultation status history"
 	// Language Master
 	MESSAGE_FAILED_GET_ALL_LANGUAGE_MASTER = "failed get all language master"
 	// Specialization
@@ -93,8 +106,64 @@ const (
 	MESSAGE_FAILED_DELETE_PRACTICE   = "failed delete practice"
 	// Available Slot
 	MESSAGE_FAILED_GET_LIST_AVAILABLE_SLOT = "failed get all available slot"
+	MESSAGE_FAILED_GENERATE_AVAILABLE_SLOT = "failed generate available slot"
+	// Slot Template
+	MESSAGE_FAILED_CREATE_SLOT_TEMPLATE   = "failed create slot template"
+	MESSAGE_FAILED_GET_LIST_SLOT_TEMPLATE = "failed get all slot template"
+	MESSAGE_FAILED_UPDATE_SLOT_TEMPLATE   = "failed update slot template"
+	MESSAGE_FAILED_DELETE_SLOT_TEMPLATE   = "failed delete slot template"
 	// Chat
-	MESSAGE_FAILED_HANDLE_CHAT = "chat failed"
+	MESSAGE_FAILED_HANDLE_CHAT                   = "chat failed"
+	MESSAGE_FAILED_GET_LIST_FLAGGED_CONVERSATION = "failed get all flagged conversation"
+	MESSAGE_FAILED_GET_LIST_CONVERSATION         = "failed get all conversation"
+	MESSAGE_FAILED_GET_DETAIL_CONVERSATION       = "failed get detail conversation"
+	MESSAGE_FAILED_UPDATE_CONVERSATION           = "failed update conversation"
+	MESSAGE_FAILED_DELETE_CONVERSATION           = "failed delete conversation"
+	MESSAGE_FAILED_CHAT_RATE_LIMITED             = "too many chat requests"
+	MESSAGE_FAILED_CHAT_QUOTA_EXCEEDED           = "daily chat quota exceeded"
+	// Login Attempt
+	MESSAGE_FAILED_UNLOCK_ACCOUNT         = "failed unlock account"
+	MESSAGE_FAILED_GET_LIST_LOGIN_ATTEMPT = "failed get all login attempt"
+	MESSAGE_FAILED_CLEAR_LOGIN_ATTEMPT    = "failed clear login attempt"
+	// Two Factor
+	MESSAGE_FAILED_VERIFY_TWO_FACTOR             = "failed verify two factor code"
+	MESSAGE_FAILED_GET_TWO_FACTOR_STATUS         = "failed get two factor status"
+	MESSAGE_FAILED_SETUP_TWO_FACTOR              = "failed setup two factor"
+	MESSAGE_FAILED_ENABLE_TWO_FACTOR             = "failed enable two factor"
+	MESSAGE_FAILED_DISABLE_TWO_FACTOR            = "failed disable two factor"
+	MESSAGE_FAILED_REGENERATE_RECOVERY_CODES     = "failed regenerate recovery codes"
+	MESSAGE_FAILED_UPDATE_TWO_FACTOR_REQUIREMENT = "failed update two factor requirement"
+	// Psycholog Application
+	MESSAGE_FAILED_APPLY_PSYCHOLOG                  = "failed apply psycholog"
+	MESSAGE_FAILED_GET_LIST_PSYCHOLOG_APPLICATION   = "failed get all psycholog application"
+	MESSAGE_FAILED_GET_DETAIL_PSYCHOLOG_APPLICATION = "failed get detail psycholog application"
+	MESSAGE_FAILED_GET_PSYCHOLOG_DOCUMENT           = "failed get psycholog document"
+	MESSAGE_FAILED_APPROVE_PSYCHOLOG_APPLICATION    = "failed approve psycholog application"
+	MESSAGE_FAILED_REJECT_PSYCHOLOG_APPLICATION     = "failed reject psycholog application"
+	MESSAGE_FAILED_OPEN_DOCUMENT                    = "failed open document"
+	// Psycholog Profile
+	MESSAGE_FAILED_UPDATE_PSYCHOLOG_PROFILE          = "failed update psycholog profile"
+	MESSAGE_FAILED_CHANGE_PASSWORD                   = "failed change password"
+	MESSAGE_FAILED_CREATE_EDUCATION                  = "failed create education"
+	MESSAGE_FAILED_UPDATE_EDUCATION                  = "failed update education"
+	MESSAGE_FAILED_DELETE_EDUCATION                  = "failed delete education"
+	MESSAGE_FAILED_GET_LIST_PSYCHOLOG_CHANGE_REQUEST = "failed get all psycholog change request"
+	MESSAGE_FAILED_APPROVE_PSYCHOLOG_CHANGE_REQUEST  = "failed approve psycholog change request"
+	MESSAGE_FAILED_REJECT_PSYCHOLOG_CHANGE_REQUEST   = "failed reject psycholog change request"
+	// Review
+	MESSAGE_FAILED_GET_LIST_PSYCHOLOG_REVIEW = "failed get all psycholog review"
+	MESSAGE_FAILED_GET_LIST_REVIEW           = "failed get all review"
+	MESSAGE_FAILED_APPROVE_REVIEW            = "failed approve review"
+	MESSAGE_FAILED_HIDE_REVIEW               = "failed hide review"
+	// Psycholog Search
+	MESSAGE_FAILED_SEARCH_PSYCHOLOG = "failed search psycholog"
+	// Search
+	MESSAGE_FAILED_SEARCH = "failed search"
+	// Payment
+	MESSAGE_FAILED_GET_PAYMENT     = "failed get payment"
+	MESSAGE_FAILED_PAYMENT_WEBHOOK = "failed process payment webhook"
+	// Cancellation
+	MESSAGE_FAILED_CANCEL_CONSULTATION = "failed cancel consultation"
 
 	// ====================================== Success ======================================
 	// Authentication
@@ -102,6 +171,8 @@ const (
 	MESSAGE_SUCCESS_LOGIN_USER    = "success login user"
 	MESSAGE_SUCCESS_LOGIN_ADMIN   = "success login admin"
 	MESSAGE_SUCCESS_REFRESH_TOKEN = "success refresh token"
+	MESSAGE_SUCCESS_LOGOUT        = "success logout"
+	MESSAGE_SUCCESS_LOGOUT_ALL    = "success logout from all devices"
 	// Send Email
 	MESSAGE_SUCCESS_SEND_VERIFICATION_EMAIL     = "success to send verification email"
 	MESSAGE_SUCCESS_VERIFY_EMAIL                = "success to verify email"
@@ -112,6 +183,14 @@ const (
 	MESSAGE_SUCCESS_GET_LIST_PROVINCE = "success get list province"
 	// Role
 	MESSAGE_SUCCESS_GET_LIST_ROLE = "success get all role"
+	MESSAGE_SUCCESS_CREATE_ROLE   = "success create role"
+	MESSAGE_SUCCESS_UPDATE_ROLE   = "success update role"
+	MESSAGE_SUCCESS_DELETE_ROLE   = "success delete role"
+	// Permission
+	MESSAGE_SUCCESS_CREATE_PERMISSION   = "success create permission"
+	MESSAGE_SUCCESS_GET_LIST_PERMISSION = "success get list permission"
+	MESSAGE_SUCCESS_UPDATE_PERMISSION   = "success update permission"
+	MESSAGE_SUCCESS_DELETE_PERMISSION   = "success delete permission"
 	// User
 	MESSAGE_SUCCESS_CREATE_USER     = "success create user"
 	MESSAGE_SUCCESS_GET_DETAIL_USER = "success get detail user"
@@ -157,6 +236,8 @@ const (
 	MESSAGE_SUCCESS_GET_DETAIL_CONSULTATION = "success get detail consultation"
 	MESSAGE_SUCCESS_UPDATE_CONSULTATION     = "success update consultation"
 	MESSAGE_SUCCESS_DELETE_CONSULTATION     = "success delete consultation"
+
+	MESSAGE_SUCCESS_GET_CONSULTATION_STATUS_HISTORY = "success get consultation status history"
 	// Language Master
 	MESSAGE_SUCCESS_GET_ALL_LANGUAGE_MASTER = "success get all language master"
 	// Specialization
@@ -168,8 +249,60 @@ const (
 	MESSAGE_SUCCESS_DELETE_PRACTICE   = "success delete practice"
 	// Available Slot
 	MESSAGE_SUCCESS_GET_LIST_AVAILABLE_SLOT = "success get all available slot"
+	MESSAGE_SUCCESS_GENERATE_AVAILABLE_SLOT = "success generate available slot"
+	// Slot Template
+	MESSAGE_SUCCESS_CREATE_SLOT_TEMPLATE   = "success create slot template"
+	MESSAGE_SUCCESS_GET_LIST_SLOT_TEMPLATE = "success get all slot template"
+	MESSAGE_SUCCESS_UPDATE_SLOT_TEMPLATE   = "success update slot template"
+	MESSAGE_SUCCESS_DELETE_SLOT_TEMPLATE   = "success delete slot template"
 	// Chat
-	MESSAGE_SUCCESS_HANDLE_CHAT = "chat success"
+	MESSAGE_SUCCESS_HANDLE_CHAT                   = "chat success"
+	MESSAGE_SUCCESS_GET_LIST_FLAGGED_CONVERSATION = "success get all flagged conversation"
+	MESSAGE_SUCCESS_GET_LIST_CONVERSATION         = "success get all conversation"
+	MESSAGE_SUCCESS_GET_DETAIL_CONVERSATION       = "success get detail conversation"
+	MESSAGE_SUCCESS_UPDATE_CONVERSATION           = "success update conversation"
+	MESSAGE_SUCCESS_DELETE_CONVERSATION           = "success delete conversation"
+	// Login Attempt
+	MESSAGE_SUCCESS_UNLOCK_ACCOUNT         = "success unlock account"
+	MESSAGE_SUCCESS_GET_LIST_LOGIN_ATTEMPT = "success get all login attempt"
+	MESSAGE_SUCCESS_CLEAR_LOGIN_ATTEMPT    = "success clear login attempt"
+	// Two Factor
+	MESSAGE_SUCCESS_VERIFY_TWO_FACTOR             = "success verify two factor code"
+	MESSAGE_SUCCESS_GET_TWO_FACTOR_STATUS         = "success get two factor status"
+	MESSAGE_SUCCESS_SETUP_TWO_FACTOR              = "success setup two factor"
+	MESSAGE_SUCCESS_ENABLE_TWO_FACTOR             = "success enable two factor"
+	MESSAGE_SUCCESS_DISABLE_TWO_FACTOR            = "success disable two factor"
+	MESSAGE_SUCCESS_REGENERATE_RECOVERY_CODES     = "success regenerate recovery codes"
+	MESSAGE_SUCCESS_UPDATE_TWO_FACTOR_REQUIREMENT = "success update two factor requirement"
+	// Psycholog Application
+	MESSAGE_SUCCESS_APPLY_PSYCHOLOG                  = "success apply psycholog, please wait for admin review"
+	MESSAGE_SUCCESS_GET_LIST_PSYCHOLOG_APPLICATION   = "success get all psycholog application"
+	MESSAGE_SUCCESS_GET_DETAIL_PSYCHOLOG_APPLICATION = "success get detail psycholog application"
+	MESSAGE_SUCCESS_APPROVE_PSYCHOLOG_APPLICATION    = "success approve psycholog application"
+	MESSAGE_SUCCESS_REJECT_PSYCHOLOG_APPLICATION     = "success reject psycholog application"
+	// Psycholog Profile
+	MESSAGE_SUCCESS_UPDATE_PSYCHOLOG_PROFILE          = "success update psycholog profile"
+	MESSAGE_SUCCESS_CHANGE_PASSWORD                   = "success change password"
+	MESSAGE_SUCCESS_CREATE_EDUCATION                  = "success create education"
+	MESSAGE_SUCCESS_UPDATE_EDUCATION                  = "success update education"
+	MESSAGE_SUCCESS_DELETE_EDUCATION                  = "success delete education"
+	MESSAGE_SUCCESS_GET_LIST_PSYCHOLOG_CHANGE_REQUEST = "success get all psycholog change request"
+	MESSAGE_SUCCESS_APPROVE_PSYCHOLOG_CHANGE_REQUEST  = "success approve psycholog change request"
+	MESSAGE_SUCCESS_REJECT_PSYCHOLOG_CHANGE_REQUEST   = "success reject psycholog change request"
+	// Review
+	MESSAGE_SUCCESS_GET_LIST_PSYCHOLOG_REVIEW = "success get all psycholog review"
+	MESSAGE_SUCCESS_GET_LIST_REVIEW           = "success get all review"
+	MESSAGE_SUCCESS_APPROVE_REVIEW            = "success approve review"
+	MESSAGE_SUCCESS_HIDE_REVIEW               = "success hide review"
+	// Psycholog Search
+	MESSAGE_SUCCESS_SEARCH_PSYCHOLOG = "success search psycholog"
+	// Search
+	MESSAGE_SUCCESS_SEARCH = "success search"
+	// Payment
+	MESSAGE_SUCCESS_GET_PAYMENT     = "success get payment"
+	MESSAGE_SUCCESS_PAYMENT_WEBHOOK = "success process payment webhook"
+	// Cancellation
+	MESSAGE_SUCCESS_CANCEL_CONSULTATION = "success cancel consultation"
 )
 
 var (
@@ -213,20 +346,41 @@ var (
 	ErrGenerateAccessToken     = errors.New("failed to generate access token")
 	ErrGenerateRefreshToken    = errors.New("failed to generate refresh token")
 	ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
-	ErrDecryptToken            = errors.New("failed to decrypt token")
+	ErrUnknownSigningKey       = errors.New("unknown signing key")
 	ErrTokenInvalid            = errors.New("token invalid")
 	ErrValidateToken           = errors.New("failed to validate token")
-	ErrParsingExpiredTime      = errors.New("failed to parsing expired time")
 	ErrTokenExpired            = errors.New("token expired")
 	ErrInvalidToken            = errors.New("token invalid expired")
+	ErrRefreshTokenInvalid     = errors.New("refresh token invalid")
+	ErrRefreshTokenExpired     = errors.New("refresh token expired")
+	ErrRefreshTokenRevoked     = errors.New("refresh token revoked")
+	ErrRefreshTokenReused      = errors.New("refresh token reuse detected, all sessions from this login are revoked")
+	ErrRevokeRefreshToken      = errors.New("failed to revoke refresh token")
+	ErrTokenAlreadyUsed        = errors.New("token already used")
+	ErrGenerateOneTimeToken    = errors.New("failed to generate one time token")
+	ErrConsumeOneTimeToken     = errors.New("failed to consume one time token")
 	// City & Province
 	ErrGetCityByID    = errors.New("failed get city by id")
 	ErrGetAllProvince = errors.New("failed get list province")
 	// Role
-	ErrGetRoleIDFromToken = errors.New("failed get role id from token")
-	ErrGetRoleFromToken   = errors.New("failed get role from token")
-	ErrGetRoleFromName    = errors.New("failed get role by role name")
-	ErrGetRoleFromID      = errors.New("failed get role by role id")
+	ErrGetRoleIDFromToken    = errors.New("failed get role id from token")
+	ErrGetRoleFromToken      = errors.New("failed get role from token")
+	ErrGetRoleFromName       = errors.New("failed get role by role name")
+	ErrGetRoleFromID         = errors.New("failed get role by role id")
+	ErrInvalidRoleName       = errors.New("failed invalid role name")
+	ErrRoleNameAlreadyExists = errors.New("failed role name is exists")
+	ErrCreateRole            = errors.New("failed create role")
+	ErrUpdateRole            = errors.New("failed update role")
+	ErrDeleteRole            = errors.New("failed delete role")
+	ErrBuiltInRoleProtected  = errors.New("failed built-in role cannot be changed or deleted")
+	// Permission
+	ErrInvalidPermissionMethod   = errors.New("failed invalid permission method")
+	ErrInvalidPermissionEndpoint = errors.New("failed invalid permission endpoint")
+	ErrCreatePermission          = errors.New("failed create permission")
+	ErrGetAllPermission          = errors.New("failed get list permission")
+	ErrGetPermissionFromID       = errors.New("failed get permission by id")
+	ErrUpdatePermission          = errors.New("failed update permission")
+	ErrDeletePermission          = errors.New("failed delete permission")
 	// Psycholog
 	ErrGetPsychologIDFromToken       = errors.New("failed get psycholog id from token")
 	ErrRegisterPsycholog             = errors.New("failed to register psycholog")
@@ -277,6 +431,12 @@ var (
 	ErrUpdateConsultation               = errors.New("failed update consultation")
 	ErrCreateConsultation               = errors.New("failed create consultation")
 	ErrDeleteConsultation               = errors.New("failed delete consultation")
+	ErrInvalidConsultationTransition    = errors.New("failed invalid consultation status transition")
+	ErrNoShowBeforeSession              = errors.New("failed consultation can't be marked as no-show before the session starts")
+	ErrConsultationStatusChanged        = errors.New("failed consultation status already changed")
+	ErrCreateConsultationStatusHistory  = errors.New("failed create consultation status history")
+	ErrGetConsultationStatusHistory     = errors.New("failed get consultation status history")
+	ErrRescheduleNotAllowed             = errors.New("failed consultation can't be rescheduled")
 	// User motivation
 	ErrGetAllUserMotivation        = errors.New("failed all user motivation")
 	ErrUserMotivationAlreadyExists = errors.New("failed user motivation already exists")
@@ -309,6 +469,7 @@ var (
 	ErrGetAllPractice        = errors.New("failed get all practice")
 	ErrUpdatePractice        = errors.New("failed update practice")
 	ErrDeletePractice        = errors.New("failed delete practice")
+	ErrInvalidPracticeFee    = errors.New("failed practice fee must not be negative")
 	// Practice Schedule
 	ErrAddPracticeSchedule     = errors.New("failed add practice schedule")
 	ErrCreatePracticeSchedule  = errors.New("failed create practice schedule")
@@ -319,11 +480,126 @@ var (
 	ErrAvailableSlotNotFound      = errors.New("failed available slot not found")
 	ErrUpdateStatusBookSlot       = errors.New("failed update book status slot")
 	ErrCreateAvailableSlots       = errors.New("failed create available slots")
+	ErrDeleteAvailableSlots       = errors.New("failed delete available slots")
+	ErrGenerateAvailableSlots     = errors.New("failed generate available slots")
+	// Slot Template
+	ErrInvalidSlotTemplateDay      = errors.New("failed invalid slot template day")
+	ErrInvalidSlotTemplateTime     = errors.New("failed invalid slot template time")
+	ErrInvalidSlotTemplateDuration = errors.New("failed invalid slot template session or buffer duration")
+	ErrSlotTemplateOutsideSchedule = errors.New("failed slot template outside practice schedule")
+	ErrSlotTemplateOverlap         = errors.New("failed slot template overlaps another template")
+	ErrSlotTemplateNotFound        = errors.New("failed slot template not found")
+	ErrCreateSlotTemplate          = errors.New("failed create slot template")
+	ErrGetAllSlotTemplate          = errors.New("failed get all slot template")
+	ErrUpdateSlotTemplate          = errors.New("failed update slot template")
+	ErrDeleteSlotTemplate          = errors.New("failed delete slot template")
 	// Chat
-	ErrCreateConversation = errors.New("failed create conversation")
-	ErrSaveMessage        = errors.New("failed save message")
-	ErrGetChatGPTResponse = errors.New("failed get chat gpt response")
-	ErrGetMessages        = errors.New("failed get messages")
+	ErrCreateConversation       = errors.New("failed create conversation")
+	ErrSaveMessage              = errors.New("failed save message")
+	ErrGetChatGPTResponse       = errors.New("failed get chat gpt response")
+	ErrGetMessages              = errors.New("failed get messages")
+	ErrChatStreamInterrupted    = errors.New("failed chat stream interrupted")
+	ErrLoadChatTemplate         = errors.New("failed load chat template")
+	ErrFlagConversation         = errors.New("failed flag conversation")
+	ErrGetFlaggedConversation   = errors.New("failed get flagged conversation")
+	ErrConversationNotFound     = errors.New("failed conversation not found")
+	ErrGetAllConversation       = errors.New("failed get all conversation")
+	ErrInvalidConversationTitle = errors.New("failed invalid conversation title")
+	ErrUpdateConversation       = errors.New("failed update conversation")
+	ErrDeleteConversation       = errors.New("failed delete conversation")
+	ErrChatRateLimited          = errors.New("failed too many chat requests, please slow down")
+	ErrChatMessageQuotaExceeded = errors.New("failed daily chat message quota exceeded")
+	ErrChatTokenQuotaExceeded   = errors.New("failed daily chat token quota exceeded")
+	ErrGetChatUsage             = errors.New("failed get chat usage")
+	// Login Attempt
+	ErrTooManyLoginAttempts = errors.New("failed too many login attempts, please try again later")
+	ErrAccountLocked        = errors.New("failed account is temporarily locked, check your email to unlock it")
+	ErrRecordLoginAttempt   = errors.New("failed record login attempt")
+	ErrGetAllLoginAttempt   = errors.New("failed get all login attempt")
+	ErrLoginAttemptNotFound = errors.New("failed login attempt not found")
+	ErrClearLoginAttempt    = errors.New("failed clear login attempt")
+	// Two Factor
+	ErrTwoFactorChallengeInvalid = errors.New("failed two factor challenge invalid")
+	ErrTwoFactorChallengeExpired = errors.New("failed two factor challenge expired, please login again")
+	ErrTwoFactorTooManyAttempts  = errors.New("failed too many two factor attempts, please login again")
+	ErrTwoFactorCodeRequired     = errors.New("failed two factor code or recovery code is required")
+	ErrTwoFactorCodeInvalid      = errors.New("failed two factor code invalid")
+	ErrTwoFactorNotSetup         = errors.New("failed two factor has not been set up")
+	ErrTwoFactorAlreadyEnabled   = errors.New("failed two factor already enabled")
+	ErrTwoFactorNotEnabled       = errors.New("failed two factor not enabled")
+	ErrTwoFactorRequiredByAdmin  = errors.New("failed two factor is required by admin and cannot be disabled")
+	ErrGetTwoFactor              = errors.New("failed get two factor")
+	ErrSaveTwoFactor             = errors.New("failed save two factor")
+	ErrCreateTwoFactorChallenge  = errors.New("failed create two factor challenge")
+	ErrVerifyTwoFactor           = errors.New("failed verify two factor")
+	ErrGenerateTwoFactorSecret   = errors.New("failed generate two factor secret")
+	ErrGenerateRecoveryCodes     = errors.New("failed generate recovery codes")
+	ErrInvalidTwoFactorRoleName  = errors.New("failed two factor is only available for admin and psycholog")
+	// Psycholog Application
+	ErrPsychologPendingApproval          = errors.New("failed psycholog account is still waiting for admin approval")
+	ErrPsychologApplicationRejected      = errors.New("failed psycholog application has been rejected")
+	ErrApplyPsycholog                    = errors.New("failed apply psycholog")
+	ErrGetAllPsychologApplication        = errors.New("failed get list psycholog application")
+	ErrPsychologApplicationNotFound      = errors.New("failed psycholog application not found")
+	ErrPsychologApplicationAlreadyReview = errors.New("failed psycholog application has already been reviewed")
+	ErrInvalidPsychologStatus            = errors.New("failed invalid psycholog status, must be pending, approved or rejected")
+	ErrRejectReasonRequired              = errors.New("failed reject reason is required")
+	ErrReviewPsychologApplication        = errors.New("failed review psycholog application")
+	ErrLicenseDocumentRequired           = errors.New("failed license document is required")
+	ErrInvalidDocumentType               = errors.New("failed invalid document type, must be license, str or certificate")
+	ErrInvalidExtensionDocument          = errors.New("only pdf/jpg/jpeg/png allowed for document")
+	ErrDocumentTooLarge                  = errors.New("failed document size exceeds 5MB")
+	ErrPsychologDocumentNotFound         = errors.New("failed psycholog document not found")
+	ErrCreatePsychologDocuments          = errors.New("failed create psycholog documents")
+	// Psycholog Profile
+	ErrOldPasswordNotMatch                 = errors.New("failed old password not match")
+	ErrSamePassword                        = errors.New("failed new password must be different from old password")
+	ErrUpdatePassword                      = errors.New("failed update password")
+	ErrEducationNotFound                   = errors.New("failed education not found")
+	ErrInvalidGraduationYear               = errors.New("failed invalid graduation year")
+	ErrUpdateEducation                     = errors.New("failed update education")
+	ErrDeleteEducation                     = errors.New("failed delete education")
+	ErrChangeRequestAlreadyPending         = errors.New("failed there is already a pending change request for this field")
+	ErrCreatePsychologChangeRequest        = errors.New("failed create psycholog change request")
+	ErrGetAllPsychologChangeRequest        = errors.New("failed get list psycholog change request")
+	ErrPsychologChangeRequestNotFound      = errors.New("failed psycholog change request not found")
+	ErrPsychologChangeRequestAlreadyReview = errors.New("failed psycholog change request has already been reviewed")
+	ErrReviewPsychologChangeRequest        = errors.New("failed review psycholog change request")
+	ErrInvalidChangeRequestStatus          = errors.New("failed invalid change request status, must be pending, approved or rejected")
+	// Review
+	ErrReviewNotAllowed           = errors.New("failed review is only allowed after consultation is completed")
+	ErrRateRequired               = errors.New("failed rate is required before leaving a comment")
+	ErrRecalculatePsychologRating = errors.New("failed recalculate psycholog rating")
+	ErrGetAllReview               = errors.New("failed get list review")
+	ErrReviewNotFound             = errors.New("failed review not found")
+	ErrInvalidReviewStatus        = errors.New("failed invalid review status, must be unmoderated, visible or hidden")
+	ErrHideReasonRequired         = errors.New("failed hide reason is required")
+	ErrModerateReview             = errors.New("failed moderate review")
+	// Psycholog Search
+	ErrSearchPsycholog           = errors.New("failed search psycholog")
+	ErrInvalidPsychologSort      = errors.New("failed invalid sort, must be newest, rating, experience or soonest_slot")
+	ErrInvalidPracticeTypeFilter = errors.New("failed invalid practice type, must be online or clinic")
+	ErrInvalidFeeRange           = errors.New("failed invalid fee range")
+	ErrInvalidAvailableWithin    = errors.New("failed available within must be between 1 and 90 days")
+	// Search
+	ErrSearch              = errors.New("failed search")
+	ErrSearchQueryRequired = errors.New("failed search query is required")
+	ErrInvalidSearchType   = errors.New("failed invalid search type, must be news, motivation or psycholog")
+	// Payment
+	ErrCreatePaymentCharge         = errors.New("failed create payment charge")
+	ErrCreatePayment               = errors.New("failed create payment")
+	ErrPaymentNotFound             = errors.New("failed payment not found")
+	ErrUpdatePayment               = errors.New("failed update payment")
+	ErrInvalidPaymentSignature     = errors.New("failed invalid payment webhook signature")
+	ErrInvalidPaymentWebhook       = errors.New("failed invalid payment webhook payload")
+	ErrPaymentAmountMismatch       = errors.New("failed payment amount mismatch")
+	ErrExpirePayment               = errors.New("failed expire payment")
+	ErrConsultationAwaitingPayment = errors.New("failed consultation is awaiting payment")
+	ErrPracticeFeeChanged          = errors.New("failed practice fee differs from the booked consultation")
+	ErrRecordPaymentWebhookEvent   = errors.New("failed record payment webhook event")
+	// Cancellation
+	ErrCreateRefund = errors.New("failed create refund")
+	ErrRetryRefund  = errors.New("failed retry refund")
 	// File
 	ErrInvalidExtensionPhoto = errors.New("only jpg/jpeg/png allowed")
 	ErrCreateFile            = errors.New("failed create file")
@@ -342,6 +618,42 @@ type (
 		ID   *uuid.UUID `json:"role_id"`
 		Name string     `json:"role_name"`
 	}
+	CreateRoleRequest struct {
+		Name string `json:"name" form:"name" binding:"required"`
+	}
+	UpdateRoleRequest struct {
+		ID   string `json:"-"`
+		Name string `json:"name,omitempty" form:"name"`
+	}
+	DeleteRoleRequest struct {
+		RoleID string `json:"-"`
+	}
+	// Permission
+	PermissionResponse struct {
+		ID       *uuid.UUID `json:"permission_id"`
+		Method   string     `json:"permission_method"`
+		Endpoint string     `json:"permission_endpoint"`
+		RoleID   *uuid.UUID `json:"role_id"`
+	}
+	AllPermissionResponse struct {
+		Data []PermissionResponse `json:"data"`
+	}
+	GetAllPermissionRequest struct {
+		RoleID string `json:"role_id" form:"role_id"`
+	}
+	CreatePermissionRequest struct {
+		Method   string `json:"method" form:"method"`
+		Endpoint string `json:"endpoint" form:"endpoint" binding:"required"`
+		RoleID   string `json:"role_id" form:"role_id" binding:"required"`
+	}
+	UpdatePermissionRequest struct {
+		ID       string `json:"-"`
+		Method   string `json:"method,omitempty" form:"method"`
+		Endpoint string `json:"endpoint,omitempty" form:"endpoint"`
+	}
+	DeletePermissionRequest struct {
+		PermissionID string `json:"-"`
+	}
 	// Email
 	SendForgotPasswordEmailRequest struct {
 		Email string `json:"email" form:"email" binding:"required"`
@@ -400,42 +712,75 @@ type (
 		Password string `json:"password" form:"password" validate:"required,min=8"`
 	}
 	UserLoginRequest struct {
-		Email    string `json:"email" form:"email"`
-		Password string `json:"password" form:"password"`
+		Email     string `json:"email" form:"email"`
+		Password  string `json:"password" form:"password"`
+		IPAddress string `json:"-"`
 	}
 	UserLoginResponse struct {
 		AccessToken  string `json:"access_token"`
 		RefreshToken string `json:"refresh_token"`
 	}
 	AdminLoginRequest struct {
-		Email    string `json:"email" form:"email"`
-		Password string `json:"password" form:"password"`
+		Email     string `json:"email" form:"email"`
+		Password  string `json:"password" form:"password"`
+		IPAddress string `json:"-"`
 	}
 	AdminLoginResponse struct {
-		AccessToken  string `json:"access_token"`
-		RefreshToken string `json:"refresh_token"`
+		AccessToken  string `json:"access_token,omitempty"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+
+		// terisi jika login masih menunggu code 2FA
+		TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`
+		TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"`
+		ChallengeToken         string   `json:"challenge_token,omitempty"`
+		ChallengeExpiresIn     int      `json:"challenge_expires_in,omitempty"`
+		RecoveryCodes          []string `json:"recovery_codes,omitempty"`
 	}
 	PsychologLoginRequest struct {
-		Email    string `json:"email" form:"email"`
-		Password string `json:"password" form:"password"`
+		Email     string `json:"email" form:"email"`
+		Password  string `json:"password" form:"password"`
+		IPAddress string `json:"-"`
 	}
 	PsychologLoginResponse struct {
-		AccessToken  string `json:"access_token"`
-		RefreshToken string `json:"refresh_token"`
+		AccessToken  string `json:"access_token,omitempty"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+
+		// terisi jika login masih menunggu code 2FA
+		TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`
+		TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"`
+		ChallengeToken         string   `json:"challenge_token,omitempty"`
+		ChallengeExpiresIn     int      `json:"challenge_expires_in,omitempty"`
+		RecoveryCodes          []string `json:"recovery_codes,omitempty"`
 	}
 	RefreshTokenRequest struct {
 		RefreshToken string `json:"refresh_token"`
 	}
 
 	RefreshTokenResponse struct {
-		AccessToken string `json:"access_token"`
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	JWK struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+	}
+	JWKSResponse struct {
+		Keys []JWK `json:"keys"`
+	}
+	LogoutRequest struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 	// User
 	AllUserResponse struct {
 		ID          uuid.UUID    `json:"user_id"`
 		Name        string       `json:"user_name"`
 		Email       string       `json:"user_email"`
-		Password    string       `json:"user_password"`
 		Image       string       `json:"user_image"`
 		Gender      *bool        `json:"user_gender"`
 		Birthdate   string       `json:"user_birth_date"`
@@ -485,12 +830,11 @@ type (
 		UserID string `json:"-"`
 	}
 	UpdatePasswordRequest struct {
-		Email    string `json:"email" form:"email" binding:"required"`
+		Token    string `json:"token" form:"token" binding:"required"`
 		Password string `json:"password" form:"password" binding:"required"`
 	}
 	UpdatePasswordResponse struct {
-		OldPassword string `json:"old_password" form:"old_password" binding:"required"`
-		NewPassword string `json:"new_password" form:"new_password" binding:"required"`
+		Email string `json:"email"`
 	}
 	// News
 	CreateNewsRequest struct {
@@ -590,6 +934,7 @@ type (
 		Description       string                `json:"description"`
 		PhoneNumber       string                `json:"phone_number"`
 		Image             string                `json:"image,omitempty"`
+		Gender            *bool                 `json:"gender"`
 		CityID            *uuid.UUID            `json:"city_id"`
 		RoleID            *uuid.UUID            `json:"role_id"`
 		LanguageMasterIDs []string              `json:"language_master,omitempty"`
@@ -603,16 +948,19 @@ type (
 		Name            string                   `json:"psy_name"`
 		STRNumber       string                   `json:"psy_str_number"`
 		Email           string                   `json:"psy_email"`
-		Password        string                   `json:"psy_password"`
 		WorkYear        string                   `json:"psy_work_year"`
 		Description     string                   `json:"psy_description"`
 		PhoneNumber     string                   `json:"psy_phone_number"`
 		Image           string                   `json:"psy_image"`
+		Gender          *bool                    `json:"psy_gender"`
 		City            CityResponse             `json:"city"`
 		Role            RoleResponse             `json:"role"`
 		LanguageMasters []LanguageMasterResponse `json:"language"`
 		Specializations []SpecializationResponse `json:"specialization"`
 		Educations      []EducationResponse      `json:"education"`
+		RatingAverage   float64                  `json:"psy_rating_average"`
+		RatingCount     int64                    `json:"psy_rating_count"`
+		RecentReviews   []ReviewResponse         `json:"psy_recent_reviews,omitempty"`
 	}
 	PsychologPaginationResponse struct {
 		PaginationResponse
@@ -631,6 +979,7 @@ type (
 		Description       string                `form:"description,omitempty" json:"description,omitempty"`
 		PhoneNumber       string                `form:"phone_number,omitempty" json:"phone_number,omitempty"`
 		Image             string                `json:"image,omitempty"`
+		Gender            *bool                 `form:"gender,omitempty" json:"gender,omitempty"`
 		CityID            *uuid.UUID            `form:"city_id,omitempty" json:"city_id,omitempty"`
 		LanguageMasterIDs []string              `form:"language_master" json:"language_master,omitempty"`
 		SpecializationIDs []string              `form:"specialization" json:"specialization,omitempty"`
@@ -731,10 +1080,12 @@ type (
 	}
 	// Practice
 	CreatePracticeRequest struct {
-		Type        string `json:"prac_type"`
-		Name        string `json:"prac_name"`
-		Address     string `json:"prac_address"`
-		PhoneNumber string `json:"prac_phone_number"`
+		Type          string                `json:"prac_type"`
+		Name          string                `json:"prac_name"`
+		Address       string                `json:"prac_address"`
+		PhoneNumber   string                `json:"prac_phone_number"`
+		Fee           int64                 `json:"prac_fee"`
+		SlotTemplates []SlotTemplateRequest `json:"slot_template,omitempty"`
 	}
 	UpdatePracticeRequest struct {
 		ID          string `json:"-"`
@@ -742,6 +1093,7 @@ type (
 		Name        string `json:"prac_name"`
 		Address     string `json:"prac_address"`
 		PhoneNumber string `json:"prac_phone_number"`
+		Fee         *int64 `json:"prac_fee,omitempty"`
 	}
 	PracticeScheduleResponse struct {
 		ID    uuid.UUID `json:"prac_sched_id"`
@@ -755,7 +1107,9 @@ type (
 		Name              string                     `json:"prac_name"`
 		Address           string                     `json:"prac_address"`
 		PhoneNumber       string                     `json:"prac_phone_number"`
+		Fee               int64                      `json:"prac_fee"`
 		PracticeSchedules []PracticeScheduleResponse `json:"practice_schedule"`
+		SlotTemplates     []SlotTemplateResponse     `json:"slot_template,omitempty"`
 	}
 	AllPracticeRepositoryResponse struct {
 		Practices []entity.Practice
@@ -767,6 +1121,7 @@ type (
 	// Available Slot
 	AvailableSlotResponse struct {
 		ID       uuid.UUID `json:"slot_id"`
+		Date     string    `json:"slot_date"`
 		Start    string    `json:"slot_start"`
 		End      string    `json:"slot_end"`
 		IsBooked bool      `json:"slot_is_booked"`
@@ -778,6 +1133,44 @@ type (
 		Psycholog      PsychologResponse       `json:"psycholog"`
 		AvailableSlots []AvailableSlotResponse `json:"available_slot"`
 	}
+	GenerateAvailableSlotResponse struct {
+		From         string `json:"from"`
+		To           string `json:"to"`
+		CreatedCount int    `json:"created_count"`
+	}
+	// Slot Template
+	SlotTemplateRequest struct {
+		Day             string `json:"slot_tmpl_day"`
+		Start           string `json:"slot_tmpl_start"`
+		End             string `json:"slot_tmpl_end"`
+		SessionDuration int    `json:"slot_tmpl_session_duration"`
+		BufferDuration  int    `json:"slot_tmpl_buffer_duration"`
+	}
+	CreateSlotTemplateRequest struct {
+		PracticeID      string `json:"prac_id"`
+		Day             string `json:"slot_tmpl_day"`
+		Start           string `json:"slot_tmpl_start"`
+		End             string `json:"slot_tmpl_end"`
+		SessionDuration int    `json:"slot_tmpl_session_duration"`
+		BufferDuration  int    `json:"slot_tmpl_buffer_duration"`
+	}
+	UpdateSlotTemplateRequest struct {
+		ID              string `json:"-"`
+		Day             string `json:"slot_tmpl_day,omitempty"`
+		Start           string `json:"slot_tmpl_start,omitempty"`
+		End             string `json:"slot_tmpl_end,omitempty"`
+		SessionDuration *int   `json:"slot_tmpl_session_duration,omitempty"`
+		BufferDuration  *int   `json:"slot_tmpl_buffer_duration,omitempty"`
+	}
+	SlotTemplateResponse struct {
+		ID              uuid.UUID  `json:"slot_tmpl_id"`
+		Day             string     `json:"slot_tmpl_day"`
+		Start           string     `json:"slot_tmpl_start"`
+		End             string     `json:"slot_tmpl_end"`
+		SessionDuration int        `json:"slot_tmpl_session_duration"`
+		BufferDuration  int        `json:"slot_tmpl_buffer_duration"`
+		PracticeID      *uuid.UUID `json:"prac_id"`
+	}
 	// Consultation
 	ConsultationResponse struct {
 		ID            uuid.UUID             `json:"consul_id"`
@@ -785,9 +1178,12 @@ type (
 		Rate          int                   `json:"consul_rate"`
 		Comment       string                `json:"consul_comment"`
 		Status        int                   `json:"consul_status"`
+		Fee           int64                 `json:"consul_fee"`
 		User          AllUserResponse       `json:"user"`
 		AvailableSlot AvailableSlotResponse `json:"available_slot"`
 		Practice      PracticeResponse      `json:"practice"`
+		Payment       *PaymentResponse      `json:"payment,omitempty"`
+		Cancellation  *CancellationResponse `json:"cancellation,omitempty"`
 	}
 	AllConsultationRepositoryResponse struct {
 		PaginationResponse
@@ -802,7 +1198,8 @@ type (
 		Data AllConsultationResponse `json:"data"`
 	}
 	UpdateConsultationRequest struct {
-		Status *int `json:"status"`
+		Status *int   `json:"status"`
+		Note   string `json:"note,omitempty"`
 	}
 	CreateConsultationRequest struct {
 		Date            string `json:"consul_date"`
@@ -815,9 +1212,11 @@ type (
 		Rate          int                   `json:"consul_rate"`
 		Comment       string                `json:"consul_comment"`
 		Status        int                   `json:"consul_status"`
+		Fee           int64                 `json:"consul_fee"`
 		Psycholog     PsychologResponse     `json:"psycholog"`
 		AvailableSlot AvailableSlotResponse `json:"available_slot"`
 		Practice      PracticeResponse      `json:"practice"`
+		Cancellation  *CancellationResponse `json:"cancellation,omitempty"`
 	}
 	AllConsultationRepositoryResponseForUser struct {
 		PaginationResponse
@@ -836,10 +1235,22 @@ type (
 		Date            string `json:"consul_date,omitempty"`
 		Rate            *int   `json:"consul_rate,omitempty"`
 		Status          *int   `json:"consul_status,omitempty"`
+		StatusNote      string `json:"consul_status_note,omitempty"`
 		Comment         string `json:"consul_comment,omitempty"`
 		AvailableSlotID string `json:"slot_id,omitempty"`
 		PracticeID      string `json:"prac_id,omitempty"`
 	}
+	ConsultationStatusHistoryResponse struct {
+		ID             uuid.UUID  `json:"consul_hist_id"`
+		FromStatus     *int       `json:"consul_hist_from_status"`
+		FromStatusName string     `json:"consul_hist_from_status_name,omitempty"`
+		ToStatus       int        `json:"consul_hist_to_status"`
+		ToStatusName   string     `json:"consul_hist_to_status_name"`
+		ChangedByRole  string     `json:"consul_hist_changed_by_role"`
+		ChangedByID    *uuid.UUID `json:"consul_hist_changed_by_id"`
+		Note           string     `json:"consul_hist_note"`
+		CreatedAt      time.Time  `json:"created_at"`
+	}
 	PsychologFilter struct {
 		Name           string
 		City           string
@@ -855,5 +1266,402 @@ type (
 	ChatResponse struct {
 		Response       string    `json:"response"`
 		ConversationID uuid.UUID `json:"conversation_id"`
+		IsCrisis       bool      `json:"is_crisis"`
+
+		Suggestions *ChatSuggestions `json:"suggestions,omitempty"`
+	}
+	ChatSuggestions struct {
+		Psychologs  []ChatPsychologSuggestion  `json:"psychologs,omitempty"`
+		News        []ChatNewsSuggestion       `json:"news,omitempty"`
+		Motivations []ChatMotivationSuggestion `json:"motivations,omitempty"`
+	}
+	ChatPsychologSuggestion struct {
+		ID              uuid.UUID `json:"psy_id"`
+		Name            string    `json:"psy_name"`
+		Image           string    `json:"psy_image,omitempty"`
+		City            string    `json:"city_name,omitempty"`
+		Specializations []string  `json:"specializations,omitempty"`
+		Languages       []string  `json:"languages,omitempty"`
+	}
+	ChatNewsSuggestion struct {
+		ID    uuid.UUID `json:"news_id"`
+		Title string    `json:"news_title"`
+		Image string    `json:"news_image,omitempty"`
+		Date  string    `json:"news_date"`
+	}
+	ChatMotivationSuggestion struct {
+		ID       uuid.UUID `json:"mot_id"`
+		Author   string    `json:"mot_author"`
+		Content  string    `json:"mot_content"`
+		Category string    `json:"mot_cat_name,omitempty"`
+	}
+	MessageResponse struct {
+		ID        uuid.UUID `json:"message_id"`
+		Sender    string    `json:"message_sender"`
+		Content   string    `json:"message_content"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	ConversationResponse struct {
+		ID        uuid.UUID         `json:"conversation_id"`
+		Title     string            `json:"title"`
+		CreatedAt time.Time         `json:"created_at"`
+		UpdatedAt time.Time         `json:"updated_at"`
+		Messages  []MessageResponse `json:"messages,omitempty"`
+	}
+	AllConversationRepositoryResponse struct {
+		PaginationResponse
+		Conversations []entity.Conversation
+	}
+	ConversationPaginationResponse struct {
+		PaginationResponse
+		Data []ConversationResponse `json:"data"`
+	}
+	UpdateConversationRequest struct {
+		ID    string `json:"-"`
+		Title string `json:"title" binding:"required"`
+	}
+	ChatQuotaStatus struct {
+		Date              string `json:"date"`
+		MessageCount      int    `json:"message_count"`
+		TokenCount        int    `json:"token_count"`
+		MessageQuota      int    `json:"message_quota"`
+		TokenQuota        int    `json:"token_quota"`
+		MessagesRemaining int    `json:"messages_remaining"`
+		TokensRemaining   int    `json:"tokens_remaining"`
+	}
+	AllFlaggedConversationRepositoryResponse struct {
+		PaginationResponse
+		Conversations []entity.Conversation
+	}
+	FlaggedConversationResponse struct {
+		ID         uuid.UUID         `json:"conversation_id"`
+		FlagReason string            `json:"flag_reason"`
+		FlaggedAt  *time.Time        `json:"flagged_at"`
+		User       AllUserResponse   `json:"user"`
+		Messages   []MessageResponse `json:"messages"`
+	}
+	FlaggedConversationPaginationResponse struct {
+		PaginationResponse
+		Data []FlaggedConversationResponse `json:"data"`
+	}
+	// Login Attempt
+	UnlockAccountRequest struct {
+		Token string `json:"token" form:"token" binding:"required"`
+	}
+	AllLoginAttemptRepositoryResponse struct {
+		PaginationResponse
+		LoginAttempts []entity.LoginAttempt
+	}
+	LoginAttemptResponse struct {
+		ID           uuid.UUID  `json:"login_attempt_id"`
+		Scope        string     `json:"login_attempt_scope"`
+		RoleName     string     `json:"login_attempt_role_name"`
+		Identifier   string     `json:"login_attempt_identifier"`
+		FailedCount  int        `json:"login_attempt_failed_count"`
+		LastFailedAt time.Time  `json:"login_attempt_last_failed_at"`
+		LockedUntil  *time.Time `json:"login_attempt_locked_until"`
+		IsLocked     bool       `json:"login_attempt_is_locked"`
+	}
+	LoginAttemptPaginationResponse struct {
+		PaginationResponse
+		Data []LoginAttemptResponse `json:"data"`
+	}
+	// Two Factor
+	TwoFactorChallengeResponse struct {
+		Token         string
+		ExpiresIn     int
+		SetupRequired bool
+	}
+	TwoFactorLoginRequest struct {
+		ChallengeToken string `json:"challenge_token" form:"challenge_token" binding:"required"`
+		Code           string `json:"code" form:"code"`
+		RecoveryCode   string `json:"recovery_code" form:"recovery_code"`
+	}
+	TwoFactorChallengeSetupRequest struct {
+		ChallengeToken string `json:"challenge_token" form:"challenge_token" binding:"required"`
+	}
+	TwoFactorCodeRequest struct {
+		Code         string `json:"code" form:"code"`
+		RecoveryCode string `json:"recovery_code" form:"recovery_code"`
+	}
+	TwoFactorSetupResponse struct {
+		Secret     string `json:"two_factor_secret"`
+		OtpauthURI string `json:"two_factor_otpauth_uri"`
+	}
+	TwoFactorStatusResponse struct {
+		IsEnabled              bool       `json:"two_factor_is_enabled"`
+		IsRequired             bool       `json:"two_factor_is_required"`
+		EnabledAt              *time.Time `json:"two_factor_enabled_at"`
+		RemainingRecoveryCodes int64      `json:"two_factor_remaining_recovery_codes"`
+		RecoveryCodes          []string   `json:"recovery_codes,omitempty"`
+	}
+	UpdateTwoFactorRequirementRequest struct {
+		SubjectID  string `json:"-"`
+		RoleName   string `json:"role_name" form:"role_name" binding:"required"`
+		IsRequired *bool  `json:"is_required" form:"is_required" binding:"required"`
+	}
+	// Psycholog Application
+	PsychologDocumentRequest struct {
+		Type       string                `json:"type"`
+		FileHeader *multipart.FileHeader `json:"fileheader,omitempty"`
+		FileReader multipart.File        `json:"filereader,omitempty"`
+	}
+	ApplyPsychologRequest struct {
+		Name              string                     `json:"name"`
+		STRNumber         string                     `json:"str_number"`
+		Email             string                     `json:"email"`
+		Password          string                     `json:"password"`
+		WorkYear          string                     `json:"work_year"`
+		Description       string                     `json:"description"`
+		PhoneNumber       string                     `json:"phone_number"`
+		Image             string                     `json:"image,omitempty"`
+		Gender            *bool                      `json:"gender"`
+		CityID            *uuid.UUID                 `json:"city_id"`
+		LanguageMasterIDs []string                   `json:"language_master,omitempty"`
+		SpecializationIDs []string                   `json:"specialization,omitempty"`
+		Educations        []EducationRequest         `json:"education,omitempty"`
+		Documents         []PsychologDocumentRequest `json:"documents,omitempty"`
+		FileHeader        *multipart.FileHeader      `json:"fileheader,omitempty"`
+		FileReader        multipart.File             `json:"filereader,omitempty"`
+	}
+	PsychologDocumentResponse struct {
+		ID           uuid.UUID `json:"psy_doc_id"`
+		Type         string    `json:"psy_doc_type"`
+		OriginalName string    `json:"psy_doc_original_name"`
+		ContentType  string    `json:"psy_doc_content_type"`
+		Size         int64     `json:"psy_doc_size"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+	PsychologApplicationResponse struct {
+		PsychologResponse
+		Status     string                      `json:"psy_status"`
+		ReviewNote string                      `json:"psy_review_note,omitempty"`
+		ReviewedAt *time.Time                  `json:"psy_reviewed_at,omitempty"`
+		AppliedAt  time.Time                   `json:"psy_applied_at"`
+		Documents  []PsychologDocumentResponse `json:"documents"`
+	}
+	PsychologApplicationPaginationRequest struct {
+		PaginationRequest
+		Status string `form:"status"`
+	}
+	PsychologApplicationPaginationResponse struct {
+		PaginationResponse
+		Data []PsychologApplicationResponse `json:"data"`
+	}
+	ReviewPsychologApplicationRequest struct {
+		ID     string `json:"-"`
+		Reason string `json:"reason" form:"reason"`
+	}
+	PsychologDocumentFileResponse struct {
+		Path         string
+		OriginalName string
+		ContentType  string
+	}
+	// Psycholog Profile
+	UpdatePsychologProfileRequest struct {
+		Name              string                `json:"name,omitempty"`
+		STRNumber         string                `json:"str_number,omitempty"`
+		Email             string                `json:"email,omitempty"`
+		WorkYear          string                `json:"work_year,omitempty"`
+		Description       string                `json:"description,omitempty"`
+		PhoneNumber       string                `json:"phone_number,omitempty"`
+		Gender            *bool                 `json:"gender,omitempty"`
+		CityID            *uuid.UUID            `json:"city_id,omitempty"`
+		LanguageMasterIDs []string              `json:"language_master,omitempty"`
+		SpecializationIDs []string              `json:"specialization,omitempty"`
+		FileHeader        *multipart.FileHeader `json:"fileheader,omitempty"`
+		FileReader        multipart.File        `json:"filereader,omitempty"`
+	}
+	PsychologProfileResponse struct {
+		PsychologResponse
+		PendingChangeRequests []PsychologChangeRequestResponse `json:"pending_change_requests"`
+	}
+	ChangePsychologPasswordRequest struct {
+		OldPassword string `json:"old_password" form:"old_password" binding:"required"`
+		NewPassword string `json:"new_password" form:"new_password" binding:"required"`
+	}
+	CreateEducationRequest struct {
+		Degree         string `json:"degree" form:"degree" binding:"required"`
+		Major          string `json:"major" form:"major" binding:"required"`
+		Institution    string `json:"institution" form:"institution" binding:"required"`
+		GraduationYear string `json:"graduation_year" form:"graduation_year" binding:"required"`
+	}
+	UpdateEducationRequest struct {
+		ID             string `json:"-"`
+		Degree         string `json:"degree,omitempty" form:"degree"`
+		Major          string `json:"major,omitempty" form:"major"`
+		Institution    string `json:"institution,omitempty" form:"institution"`
+		GraduationYear string `json:"graduation_year,omitempty" form:"graduation_year"`
+	}
+	PsychologChangeRequestResponse struct {
+		ID          uuid.UUID  `json:"psy_change_id"`
+		PsychologID *uuid.UUID `json:"psy_id"`
+		Name        string     `json:"psy_name,omitempty"`
+		Field       string     `json:"psy_change_field"`
+		OldValue    string     `json:"psy_change_old_value"`
+		NewValue    string     `json:"psy_change_new_value"`
+		Status      string     `json:"psy_change_status"`
+		ReviewNote  string     `json:"psy_change_review_note,omitempty"`
+		ReviewedAt  *time.Time `json:"psy_change_reviewed_at,omitempty"`
+		CreatedAt   time.Time  `json:"created_at"`
+	}
+	PsychologChangeRequestPaginationRequest struct {
+		PaginationRequest
+		Status string `form:"status"`
+	}
+	AllPsychologChangeRequestRepositoryResponse struct {
+		PaginationResponse
+		ChangeRequests []entity.PsychologChangeRequest
+	}
+	PsychologChangeRequestPaginationResponse struct {
+		PaginationResponse
+		Data []PsychologChangeRequestResponse `json:"data"`
+	}
+	ReviewPsychologChangeRequestRequest struct {
+		ID     string `json:"-"`
+		Reason string `json:"reason" form:"reason"`
+	}
+	// Review
+	ReviewResponse struct {
+		ConsultationID uuid.UUID  `json:"consul_id"`
+		Rate           int        `json:"consul_rate"`
+		Comment        string     `json:"consul_comment"`
+		ReviewerName   string     `json:"reviewer_name"`
+		RatedAt        *time.Time `json:"consul_rated_at"`
+	}
+	AllReviewRepositoryResponse struct {
+		PaginationResponse
+		Consultations []entity.Consultation
+	}
+	ReviewPaginationResponse struct {
+		PaginationResponse
+		Data []ReviewResponse `json:"data"`
+	}
+	ReviewModerationResponse struct {
+		ConsultationID uuid.UUID  `json:"consul_id"`
+		Rate           int        `json:"consul_rate"`
+		Comment        string     `json:"consul_comment"`
+		Hidden         bool       `json:"consul_review_hidden"`
+		ModerationNote string     `json:"consul_review_moderation_note,omitempty"`
+		ModeratedAt    *time.Time `json:"consul_review_moderated_at,omitempty"`
+		RatedAt        *time.Time `json:"consul_rated_at"`
+		UserID         *uuid.UUID `json:"user_id"`
+		UserName       string     `json:"user_name"`
+		PsychologID    *uuid.UUID `json:"psy_id"`
+		PsychologName  string     `json:"psy_name"`
+	}
+	ReviewModerationPaginationRequest struct {
+		PaginationRequest
+		Status string `form:"status"`
+	}
+	ReviewModerationPaginationResponse struct {
+		PaginationResponse
+		Data []ReviewModerationResponse `json:"data"`
+	}
+	ModerateReviewRequest struct {
+		ID     string `json:"-"`
+		Reason string `json:"reason" form:"reason"`
+	}
+	// Psycholog Search
+	PsychologSearchRequest struct {
+		PaginationRequest
+		LanguageIDs       []string `form:"language"`
+		SpecializationIDs []string `form:"specialization"`
+		PracticeTypes     []string `form:"practice_type"`
+		CityID            string   `form:"city_id"`
+		ProvinceID        string   `form:"province_id"`
+		Gender            *bool    `form:"gender"`
+		MinFee            *int64   `form:"min_fee"`
+		MaxFee            *int64   `form:"max_fee"`
+		AvailableWithin   int      `form:"available_within"`
+		Sort              string   `form:"sort"`
+	}
+	PsychologSearchFacetResponse struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+		Count int64  `json:"count"`
+	}
+	PsychologSearchFacets struct {
+		Languages       []PsychologSearchFacetResponse `json:"language"`
+		Specializations []PsychologSearchFacetResponse `json:"specialization"`
+		PracticeTypes   []PsychologSearchFacetResponse `json:"practice_type"`
+		Genders         []PsychologSearchFacetResponse `json:"gender"`
+	}
+	AllPsychologSearchRepositoryResponse struct {
+		PaginationResponse
+		Psychologs []entity.Psycholog
+		NextSlots  []entity.AvailableSlot
+		Facets     PsychologSearchFacets
+	}
+	PsychologSearchResponse struct {
+		PsychologResponse
+		MinFee   *int64                 `json:"psy_min_fee"`
+		NextSlot *AvailableSlotResponse `json:"next_slot"`
+	}
+	PsychologSearchMeta struct {
+		PaginationResponse
+		Facets PsychologSearchFacets `json:"facets"`
+	}
+	PsychologSearchPaginationResponse struct {
+		PsychologSearchMeta
+		Data []PsychologSearchResponse `json:"data"`
+	}
+	// Search
+	SearchRequest struct {
+		PaginationRequest
+		Types []string `form:"type"`
+	}
+	SearchResultResponse struct {
+		Type    string    `json:"type"`
+		ID      uuid.UUID `json:"id"`
+		Title   string    `json:"title"`
+		Snippet string    `json:"snippet"`
+		Rank    float64   `json:"rank"`
+	}
+	SearchCountResponse struct {
+		Type  string `json:"type"`
+		Count int64  `json:"count"`
+	}
+	AllSearchRepositoryResponse struct {
+		PaginationResponse
+		Results []SearchResultResponse
+		Counts  []SearchCountResponse
+	}
+	SearchMeta struct {
+		PaginationResponse
+		Counts []SearchCountResponse `json:"counts"`
+	}
+	SearchPaginationResponse struct {
+		SearchMeta
+		Data []SearchResultResponse `json:"data"`
+	}
+	// Payment
+	PaymentResponse struct {
+		ID            uuid.UUID        `json:"pay_id"`
+		InvoiceNumber string           `json:"pay_invoice_number"`
+		Amount        int64            `json:"pay_amount"`
+		Currency      string           `json:"pay_currency"`
+		Status        string           `json:"pay_status"`
+		Gateway       string           `json:"pay_gateway"`
+		CheckoutURL   string           `json:"pay_checkout_url,omitempty"`
+		ExpiresAt     time.Time        `json:"pay_expires_at"`
+		PaidAt        *time.Time       `json:"pay_paid_at,omitempty"`
+		Refunds       []RefundResponse `json:"refunds,omitempty"`
+	}
+	RefundResponse struct {
+		ID          uuid.UUID  `json:"refund_id"`
+		Amount      int64      `json:"refund_amount"`
+		Percent     int        `json:"refund_percent"`
+		Rule        string     `json:"refund_rule"`
+		Status      string     `json:"refund_status"`
+		ProcessedAt *time.Time `json:"refund_processed_at,omitempty"`
+	}
+	// Cancellation
+	CancellationResponse struct {
+		Rule          string  `json:"cancel_rule"`
+		HoursBefore   float64 `json:"cancel_hours_before"`
+		RefundPercent int     `json:"cancel_refund_percent"`
+		RefundAmount  int64   `json:"cancel_refund_amount"`
+		RefundStatus  string  `json:"cancel_refund_status,omitempty"`
 	}
 )
@@ -0,0 +1,353 @@
+package dto_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+)
+
+// semua tipe response di dto dan semua entity yang bisa ikut terserialisasi ke response.
+// TestResponseTypesRegistered gagal jika ada tipe baru yang belum didaftarkan di sini.
+var responseTypes = map[string]any{
+	"RolePaginationResponse":                      dto.RolePaginationResponse{},
+	"AllRoleRepositoryResponse":                   dto.AllRoleRepositoryResponse{},
+	"RoleResponse":                                dto.RoleResponse{},
+	"PermissionResponse":                          dto.PermissionResponse{},
+	"AllPermissionResponse":                       dto.AllPermissionResponse{},
+	"ForgotPasswordResponse":                      dto.ForgotPasswordResponse{},
+	"VerifyEmailResponse":                         dto.VerifyEmailResponse{},
+	"ProvinceResponse":                            dto.ProvinceResponse{},
+	"CityResponse":                                dto.CityResponse{},
+	"ProvincesResponse":                           dto.ProvincesResponse{},
+	"CityResponseCustom":                          dto.CityResponseCustom{},
+	"CitiesResponse":                              dto.CitiesResponse{},
+	"AllProvinceRepositoryResponse":               dto.AllProvinceRepositoryResponse{},
+	"AllCityRepositoryResponse":                   dto.AllCityRepositoryResponse{},
+	"UserLoginResponse":                           dto.UserLoginResponse{},
+	"AdminLoginResponse":                          dto.AdminLoginResponse{},
+	"PsychologLoginResponse":                      dto.PsychologLoginResponse{},
+	"RefreshTokenResponse":                        dto.RefreshTokenResponse{},
+	"JWK":                                         dto.JWK{},
+	"JWKSResponse":                                dto.JWKSResponse{},
+	"AllUserResponse":                             dto.AllUserResponse{},
+	"UserPaginationResponse":                      dto.UserPaginationResponse{},
+	"AllUserRepositoryResponse":                   dto.AllUserRepositoryResponse{},
+	"UpdatePasswordResponse":                      dto.UpdatePasswordResponse{},
+	"NewsResponse":                                dto.NewsResponse{},
+	"NewsPaginationResponse":                      dto.NewsPaginationResponse{},
+	"AllNewsRepositoryResponse":                   dto.AllNewsRepositoryResponse{},
+	"MotivationCategoryResponse":                  dto.MotivationCategoryResponse{},
+	"MotivationCategoryPaginationResponse":        dto.MotivationCategoryPaginationResponse{},
+	"AllMotivationCategoryRepositoryResponse":     dto.AllMotivationCategoryRepositoryResponse{},
+	"MotivationResponse":                          dto.MotivationResponse{},
+	"MotivationPaginationResponse":                dto.MotivationPaginationResponse{},
+	"AllMotivationRepositoryResponse":             dto.AllMotivationRepositoryResponse{},
+	"PsychologResponse":                           dto.PsychologResponse{},
+	"PsychologPaginationResponse":                 dto.PsychologPaginationResponse{},
+	"AllPsychologRepositoryResponse":              dto.AllPsychologRepositoryResponse{},
+	"LanguageMasterResponse":                      dto.LanguageMasterResponse{},
+	"AllLanguageMasterRepositoryResponse":         dto.AllLanguageMasterRepositoryResponse{},
+	"AllLanguageMasterResponse":                   dto.AllLanguageMasterResponse{},
+	"SpecializationResponse":                      dto.SpecializationResponse{},
+	"AllSpecializationRepositoryResponse":         dto.AllSpecializationRepositoryResponse{},
+	"AllSpecializationResponse":                   dto.AllSpecializationResponse{},
+	"EducationResponse":                           dto.EducationResponse{},
+	"UserMotivationResponse":                      dto.UserMotivationResponse{},
+	"UserMotivationResponseCustom":                dto.UserMotivationResponseCustom{},
+	"AllUserMotivationRepositoryResponse":         dto.AllUserMotivationRepositoryResponse{},
+	"UserMotivationPaginationResponse":            dto.UserMotivationPaginationResponse{},
+	"UserNewsResponse":                            dto.UserNewsResponse{},
+	"NewsDetailResponse":                          dto.NewsDetailResponse{},
+	"AllUserNewsRepositoryResponse":               dto.AllUserNewsRepositoryResponse{},
+	"UserNewsPaginationResponse":                  dto.UserNewsPaginationResponse{},
+	"PracticeScheduleResponse":                    dto.PracticeScheduleResponse{},
+	"PracticeResponse":                            dto.PracticeResponse{},
+	"AllPracticeRepositoryResponse":               dto.AllPracticeRepositoryResponse{},
+	"AllPracticeResponse":                         dto.AllPracticeResponse{},
+	"AvailableSlotResponse":                       dto.AvailableSlotResponse{},
+	"AllAvailableSlotRepositoryResponse":          dto.AllAvailableSlotRepositoryResponse{},
+	"AllAvailableSlotResponse":                    dto.AllAvailableSlotResponse{},
+	"GenerateAvailableSlotResponse":               dto.GenerateAvailableSlotResponse{},
+	"SlotTemplateResponse":                        dto.SlotTemplateResponse{},
+	"ConsultationResponse":                        dto.ConsultationResponse{},
+	"AllConsultationRepositoryResponse":           dto.AllConsultationRepositoryResponse{},
+	"AllConsultationResponse":                     dto.AllConsultationResponse{},
+	"ConsultationPaginationResponse":              dto.ConsultationPaginationResponse{},
+	"ConsultationResponseForUser":                 dto.ConsultationResponseForUser{},
+	"AllConsultationRepositoryResponseForUser":    dto.AllConsultationRepositoryResponseForUser{},
+	"AllConsultationResponseForUser":              dto.AllConsultationResponseForUser{},
+	"ConsultationPaginationResponseForUser":       dto.ConsultationPaginationResponseForUser{},
+	"UpdateConsultationRequestForUser":            dto.UpdateConsultationRequestForUser{},
+	"ConsultationStatusHistoryResponse":           dto.ConsultationStatusHistoryResponse{},
+	"ChatResponse":                                dto.ChatResponse{},
+	"ChatSuggestions":                             dto.ChatSuggestions{},
+	"ChatPsychologSuggestion":                     dto.ChatPsychologSuggestion{},
+	"ChatNewsSuggestion":                          dto.ChatNewsSuggestion{},
+	"ChatMotivationSuggestion":                    dto.ChatMotivationSuggestion{},
+	"MessageResponse":                             dto.MessageResponse{},
+	"ConversationResponse":                        dto.ConversationResponse{},
+	"AllConversationRepositoryResponse":           dto.AllConversationRepositoryResponse{},
+	"ConversationPaginationResponse":              dto.ConversationPaginationResponse{},
+	"ChatQuotaStatus":                             dto.ChatQuotaStatus{},
+	"AllFlaggedConversationRepositoryResponse":    dto.AllFlaggedConversationRepositoryResponse{},
+	"FlaggedConversationResponse":                 dto.FlaggedConversationResponse{},
+	"FlaggedConversationPaginationResponse":       dto.FlaggedConversationPaginationResponse{},
+	"AllLoginAttemptRepositoryResponse":           dto.AllLoginAttemptRepositoryResponse{},
+	"LoginAttemptResponse":                        dto.LoginAttemptResponse{},
+	"LoginAttemptPaginationResponse":              dto.LoginAttemptPaginationResponse{},
+	"TwoFactorChallengeResponse":                  dto.TwoFactorChallengeResponse{},
+	"TwoFactorSetupResponse":                      dto.TwoFactorSetupResponse{},
+	"TwoFactorStatusResponse":                     dto.TwoFactorStatusResponse{},
+	"PsychologDocumentResponse":                   dto.PsychologDocumentResponse{},
+	"PsychologApplicationResponse":                dto.PsychologApplicationResponse{},
+	"PsychologApplicationPaginationResponse":      dto.PsychologApplicationPaginationResponse{},
+	"PsychologDocumentFileResponse":               dto.PsychologDocumentFileResponse{},
+	"PsychologProfileResponse":                    dto.PsychologProfileResponse{},
+	"PsychologChangeRequestResponse":              dto.PsychologChangeRequestResponse{},
+	"AllPsychologChangeRequestRepositoryResponse": dto.AllPsychologChangeRequestRepositoryResponse{},
+	"PsychologChangeRequestPaginationResponse":    dto.PsychologChangeRequestPaginationResponse{},
+	"ReviewResponse":                              dto.ReviewResponse{},
+	"AllReviewRepositoryResponse":                 dto.AllReviewRepositoryResponse{},
+	"ReviewPaginationResponse":                    dto.ReviewPaginationResponse{},
+	"ReviewModerationResponse":                    dto.ReviewModerationResponse{},
+	"ReviewModerationPaginationResponse":          dto.ReviewModerationPaginationResponse{},
+	"PsychologSearchFacetResponse":                dto.PsychologSearchFacetResponse{},
+	"PsychologSearchFacets":                       dto.PsychologSearchFacets{},
+	"AllPsychologSearchRepositoryResponse":        dto.AllPsychologSearchRepositoryResponse{},
+	"PsychologSearchResponse":                     dto.PsychologSearchResponse{},
+	"PsychologSearchMeta":                         dto.PsychologSearchMeta{},
+	"PsychologSearchPaginationResponse":           dto.PsychologSearchPaginationResponse{},
+	"SearchResultResponse":                        dto.SearchResultResponse{},
+	"SearchCountResponse":                         dto.SearchCountResponse{},
+	"AllSearchRepositoryResponse":                 dto.AllSearchRepositoryResponse{},
+	"SearchMeta":                                  dto.SearchMeta{},
+	"SearchPaginationResponse":                    dto.SearchPaginationResponse{},
+	"PaymentResponse":                             dto.PaymentResponse{},
+	"RefundResponse":                              dto.RefundResponse{},
+	"CancellationResponse":                        dto.CancellationResponse{},
+}
+
+var entityTypes = map[string]any{
+	"AvailableSlot":             entity.AvailableSlot{},
+	"ChatUsage":                 entity.ChatUsage{},
+	"City":                      entity.City{},
+	"Consultation":              entity.Consultation{},
+	"ConsultationStatusHistory": entity.ConsultationStatusHistory{},
+	"Conversation":              entity.Conversation{},
+	"Education":                 entity.Education{},
+	"LanguageMaster":            entity.LanguageMaster{},
+	"LoginAttempt":              entity.LoginAttempt{},
+	"Message":                   entity.Message{},
+	"Motivation":                entity.Motivation{},
+	"MotivationCategory":        entity.MotivationCategory{},
+	"News":                      entity.News{},
+	"NewsDetail":                entity.NewsDetail{},
+	"OneTimeToken":              entity.OneTimeToken{},
+	"Payment":                   entity.Payment{},
+	"PaymentWebhookEvent":       entity.PaymentWebhookEvent{},
+	"Permission":                entity.Permission{},
+	"Practice":                  entity.Practice{},
+	"PracticeSchedule":          entity.PracticeSchedule{},
+	"Province":                  entity.Province{},
+	"Psycholog":                 entity.Psycholog{},
+	"PsychologChangeRequest":    entity.PsychologChangeRequest{},
+	"PsychologDocument":         entity.PsychologDocument{},
+	"PsychologLanguage":         entity.PsychologLanguage{},
+	"PsychologSpecialization":   entity.PsychologSpecialization{},
+	"RefreshToken":              entity.RefreshToken{},
+	"Refund":                    entity.Refund{},
+	"Role":                      entity.Role{},
+	"SlotTemplate":              entity.SlotTemplate{},
+	"Specialization":            entity.Specialization{},
+	"SpecializationDetail":      entity.SpecializationDetail{},
+	"TimeStamp":                 entity.TimeStamp{},
+	"TwoFactor":                 entity.TwoFactor{},
+	"TwoFactorChallenge":        entity.TwoFactorChallenge{},
+	"TwoFactorRecoveryCode":     entity.TwoFactorRecoveryCode{},
+	"User":                      entity.User{},
+	"UserMotivation":            entity.UserMotivation{},
+}
+
+var (
+	bcryptPattern      = regexp.MustCompile(`\$2[aby]?\$\d{2}\$`)
+	credentialField    = regexp.MustCompile(`(?i)password|passwd|hash`)
+	passwordHashType   = reflect.TypeOf(helpers.PasswordHash(""))
+	maxFillDepth       = 8
+	nonResponseDTOName = regexp.MustCompile(`(Request|Filter)$`)
+)
+
+func bcryptHash(t *testing.T) string {
+	t.Helper()
+
+	hash, err := helpers.HashPassword("rahasia-banget-123")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	if !bcryptPattern.MatchString(string(hash)) {
+		t.Fatalf("hash %q does not look like bcrypt", hash)
+	}
+
+	return string(hash)
+}
+
+// fill mengisi seluruh field yang bisa diekspor. Field kredensial (PasswordHash atau
+// bernama password/hash) diisi hash bcrypt asli, field lain diisi nilai biasa.
+func fill(v reflect.Value, fieldName string, hash string, depth int) {
+	if depth > maxFillDepth {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), fieldName, hash, depth+1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fill(v.Field(i), field.Name, hash, depth+1)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if credentialField.MatchString(fieldName) {
+				v.SetBytes([]byte(hash))
+			}
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fill(s.Index(0), fieldName, hash, depth+1)
+		v.Set(s)
+	case reflect.String:
+		if v.Type() == passwordHashType || credentialField.MatchString(fieldName) {
+			v.SetString(hash)
+			return
+		}
+		v.SetString("warasin")
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(helpers.PasswordHash(hash)))
+		}
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	}
+}
+
+func assertNoHashInResponse(t *testing.T, name string, sample any, hash string) {
+	t.Helper()
+
+	v := reflect.New(reflect.TypeOf(sample)).Elem()
+	fill(v, "", hash, 0)
+
+	body, err := json.Marshal(utils.BuildResponseSuccess("ok", v.Interface()))
+	if err != nil {
+		t.Fatalf("%s: marshal: %v", name, err)
+	}
+
+	if loc := bcryptPattern.FindIndex(body); loc != nil {
+		start := loc[0] - 60
+		if start < 0 {
+			start = 0
+		}
+		t.Errorf("%s leaks a bcrypt hash in its JSON: ...%s", name, body[start:loc[1]])
+	}
+}
+
+func TestResponsesNeverContainPasswordHash(t *testing.T) {
+	hash := bcryptHash(t)
+
+	for name, sample := range responseTypes {
+		assertNoHashInResponse(t, "dto."+name, sample, hash)
+	}
+	for name, sample := range entityTypes {
+		assertNoHashInResponse(t, "entity."+name, sample, hash)
+	}
+}
+
+func TestPasswordHashMarshalsToNull(t *testing.T) {
+	hash := helpers.PasswordHash(bcryptHash(t))
+
+	body, err := json.Marshal(map[string]any{
+		"value":   hash,
+		"pointer": &hash,
+		"user":    entity.User{Password: hash},
+		"psy":     entity.Psycholog{Password: hash},
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	if bcryptPattern.Match(body) {
+		t.Errorf("PasswordHash leaked through JSON: %s", body)
+	}
+}
+
+func TestResponseTypesRegistered(t *testing.T) {
+	for _, name := range structTypeNames(t, "dto.go") {
+		if nonResponseDTOName.MatchString(name) {
+			continue
+		}
+		if _, ok := responseTypes[name]; !ok {
+			t.Errorf("dto.%s is not registered in responseTypes", name)
+		}
+	}
+
+	files, err := filepath.Glob("../entity/*.go")
+	if err != nil {
+		t.Fatalf("glob entity: %v", err)
+	}
+	for _, file := range files {
+		for _, name := range structTypeNames(t, file) {
+			if _, ok := entityTypes[name]; !ok {
+				t.Errorf("entity.%s is not registered in entityTypes", name)
+			}
+		}
+	}
+}
+
+func structTypeNames(t *testing.T, path string) []string {
+	t.Helper()
+
+	if strings.HasSuffix(path, "_test.go") {
+		return nil
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatalf("parse %s: %v", path, err)
+	}
+
+	var names []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, ok := ts.Type.(*ast.StructType); ok && ts.Name.IsExported() {
+				names = append(names, ts.Name.Name)
+			}
+		}
+	}
+
+	return names
+}
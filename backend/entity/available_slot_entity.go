@@ -1,17 +1,26 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type AvailableSlot struct {
 	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"slot_id"`
-	Start    string    `json:"slot_start"`
+	Date     string    `gorm:"type:date;uniqueIndex:idx_available_slot_psycholog_session" json:"slot_date"`
+	Start    string    `gorm:"uniqueIndex:idx_available_slot_psycholog_session" json:"slot_start"`
 	End      string    `json:"slot_end"`
 	IsBooked bool      `json:"slot_is_booked"`
+	// slot consultation berbayar ditahan sampai batas ini, lewat dari itu tanpa pembayaran slot dilepas
+	HeldUntil *time.Time `json:"slot_held_until,omitempty"`
 
-	PsychologID *uuid.UUID `gorm:"type:uuid" json:"psy_id"`
-	Psycholog   Psycholog  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	PsychologID    *uuid.UUID   `gorm:"type:uuid;uniqueIndex:idx_available_slot_psycholog_session" json:"psy_id"`
+	Psycholog      Psycholog    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	PracticeID     *uuid.UUID   `gorm:"type:uuid" json:"prac_id"`
+	Practice       Practice     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	SlotTemplateID *uuid.UUID   `gorm:"type:uuid" json:"slot_tmpl_id"`
+	SlotTemplate   SlotTemplate `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 
 	Consuls []Consultation `gorm:"foreignKey:AvailableSlotID"`
 
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+// ChatUsage mencatat pemakaian chat per user per hari untuk kuota harian
+type ChatUsage struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"chat_usage_id"`
+	Date         string    `gorm:"type:date;uniqueIndex:idx_chat_usage_user_date" json:"chat_usage_date"`
+	MessageCount int       `gorm:"default:0" json:"chat_usage_message_count"`
+	TokenCount   int       `gorm:"default:0" json:"chat_usage_token_count"`
+
+	UserID *uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_chat_usage_user_date" json:"user_id"`
+	User   User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	TimeStamp
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -8,8 +10,16 @@ type Consultation struct {
 	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"consul_id"`
 	Date    string    `json:"consul_date"`
 	Rate    int       `json:"consul_rate"`
-	Comment string    `json:"consul_comment"`
-	Status  int       `json:"consul_status"` // 0: upcoming 1: canceled 2: done
+	Comment string    `gorm:"type:text;serializer:encrypted" json:"consul_comment"`
+	Status  int       `json:"consul_status"`               // lihat constants.ENUM_CONSULTATION_STATUS_*
+	Fee     int64     `gorm:"default:0" json:"consul_fee"` // tarif practice saat booking, 0 berarti gratis
+
+	// review hanya bisa diberikan setelah consultation selesai, admin bisa menyembunyikan comment
+	RatedAt              *time.Time `json:"consul_rated_at,omitempty"`
+	ReviewHidden         bool       `gorm:"default:false" json:"consul_review_hidden"`
+	ReviewModerationNote string     `json:"consul_review_moderation_note,omitempty"`
+	ReviewModeratedAt    *time.Time `json:"consul_review_moderated_at,omitempty"`
+	ReviewModeratedByID  *uuid.UUID `gorm:"type:uuid" json:"consul_review_moderated_by_id,omitempty"`
 
 	UserID          *uuid.UUID    `gorm:"type:uuid" json:"user_id"`
 	User            User          `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
@@ -18,5 +28,8 @@ type Consultation struct {
 	AvailableSlotID *uuid.UUID    `gorm:"type:uuid" json:"slot_id"`
 	AvailableSlot   AvailableSlot `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 
+	StatusHistories []ConsultationStatusHistory `gorm:"foreignKey:ConsultationID"`
+	Payments        []Payment                   `gorm:"foreignKey:ConsultationID"`
+
 	TimeStamp
 }
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+type ConsultationStatusHistory struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"consul_hist_id"`
+	FromStatus    *int       `json:"consul_hist_from_status"` // nil saat consultation baru dibuat
+	ToStatus      int        `json:"consul_hist_to_status"`
+	ChangedByRole string     `json:"consul_hist_changed_by_role"`
+	ChangedByID   *uuid.UUID `gorm:"type:uuid" json:"consul_hist_changed_by_id"`
+	Note          string     `json:"consul_hist_note"`
+
+	ConsultationID *uuid.UUID   `gorm:"type:uuid" json:"consul_id"`
+	Consultation   Consultation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	TimeStamp
+}
+
+func (ConsultationStatusHistory) TableName() string {
+	return "consultation_status_history"
+}
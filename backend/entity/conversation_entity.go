@@ -1,9 +1,24 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Conversation struct {
-	ID uuid.UUID `gorm:"type:uuid;primary_key"`
+	ID    uuid.UUID `gorm:"type:uuid;primary_key"`
+	Title string    `json:"title"`
+
+	// ditandai saat terdeteksi bahasa krisis, untuk ditinjau admin
+	IsFlagged  bool       `gorm:"default:false" json:"is_flagged"`
+	FlaggedAt  *time.Time `json:"flagged_at"`
+	FlagReason string     `json:"flag_reason"`
+
+	// ringkasan berjalan dari pesan-pesan lama yang sudah tidak dikirim utuh ke model,
+	// SummarizedCount adalah jumlah pesan terlama yang sudah tercakup di Summary
+	Summary         string `gorm:"type:text;serializer:encrypted" json:"summary"`
+	SummarizedCount int    `gorm:"default:0" json:"summarized_count"`
 
 	UserID *uuid.UUID `gorm:"type:uuid" json:"user_id"`
 	User   User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginAttempt mencatat login gagal per akun (role + email) atau per IP. Satu baris
+// per key, dihapus saat login berhasil, dibuka lewat email unlock atau oleh admin.
+type LoginAttempt struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"login_attempt_id"`
+	Scope           string     `gorm:"uniqueIndex:idx_login_attempt_key" json:"login_attempt_scope"` // account | ip
+	RoleName        string     `gorm:"uniqueIndex:idx_login_attempt_key" json:"login_attempt_role_name"`
+	Identifier      string     `gorm:"uniqueIndex:idx_login_attempt_key" json:"login_attempt_identifier"` // email atau IP
+	FailedCount     int        `gorm:"default:0" json:"login_attempt_failed_count"`
+	LastFailedAt    time.Time  `json:"login_attempt_last_failed_at"`
+	LockedUntil     *time.Time `json:"login_attempt_locked_until"`
+	UnlockTokenHash string     `gorm:"index" json:"-"`
+
+	TimeStamp
+}
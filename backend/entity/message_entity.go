@@ -5,7 +5,7 @@ import "github.com/google/uuid"
 type Message struct {
 	ID      uuid.UUID `gorm:"type:uuid;primary_key"`
 	Sender  string    // "user" or "assistant"
-	Content string
+	Content string    `gorm:"type:text;serializer:encrypted"`
 
 	ConversationID *uuid.UUID   `gorm:"type:uuid" json:"conversation_id"`
 	Conversation   Conversation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OneTimeToken menyimpan hash token sekali pakai (verifikasi email, reset password).
+// Token hanya berlaku sekali: ConsumedAt diisi saat token ditukar atau dibatalkan.
+type OneTimeToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"one_time_token_id"`
+	TokenHash  string     `gorm:"uniqueIndex;not null" json:"-"`
+	Purpose    string     `gorm:"index" json:"one_time_token_purpose"`
+	ExpiresAt  time.Time  `json:"one_time_token_expires_at"`
+	ConsumedAt *time.Time `json:"one_time_token_consumed_at"`
+
+	UserID uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+	User   User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	TimeStamp
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment adalah invoice untuk satu consultation berbayar. Reference adalah id transaksi
+// di payment gateway, dipakai untuk mencocokkan webhook yang masuk.
+type Payment struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"pay_id"`
+	InvoiceNumber string     `gorm:"uniqueIndex;not null" json:"pay_invoice_number"`
+	Amount        int64      `json:"pay_amount"` // dalam rupiah, disalin dari tarif practice saat booking
+	Currency      string     `json:"pay_currency"`
+	Status        string     `gorm:"index" json:"pay_status"` // lihat constants.ENUM_PAYMENT_STATUS_*
+	Gateway       string     `gorm:"uniqueIndex:idx_payment_gateway_reference" json:"pay_gateway"`
+	Reference     string     `gorm:"uniqueIndex:idx_payment_gateway_reference" json:"pay_reference"`
+	CheckoutURL   string     `json:"pay_checkout_url"`
+	ExpiresAt     time.Time  `gorm:"index" json:"pay_expires_at"`
+	PaidAt        *time.Time `json:"pay_paid_at"`
+
+	ConsultationID *uuid.UUID   `gorm:"type:uuid;index" json:"consul_id"`
+	Consultation   Consultation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+
+	Refunds []Refund `gorm:"foreignKey:PaymentID"`
+
+	TimeStamp
+}
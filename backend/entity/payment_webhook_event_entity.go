@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+// PaymentWebhookEvent mencatat event webhook yang sudah diproses agar event yang
+// dikirim ulang oleh gateway tidak diproses dua kali.
+type PaymentWebhookEvent struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"pay_event_id"`
+	Gateway   string    `gorm:"uniqueIndex:idx_payment_webhook_event;not null" json:"pay_event_gateway"`
+	EventID   string    `gorm:"uniqueIndex:idx_payment_webhook_event;not null" json:"pay_event_event_id"`
+	Reference string    `gorm:"index" json:"pay_event_reference"`
+	Status    string    `json:"pay_event_status"`
+	Payload   string    `gorm:"type:text" json:"pay_event_payload"`
+
+	PaymentID *uuid.UUID `gorm:"type:uuid" json:"pay_id"`
+	Payment   Payment    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+
+	TimeStamp
+}
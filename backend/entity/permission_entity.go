@@ -4,7 +4,8 @@ import "github.com/google/uuid"
 
 type Permission struct {
 	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"permission_id"`
-	Endpoint string    `json:"permission_endpoint"`
+	Method   string    `gorm:"default:'*'" json:"permission_method"` // "*" untuk semua method
+	Endpoint string    `json:"permission_endpoint"`                  // path route gin, boleh diakhiri "/*"
 
 	RoleID *uuid.UUID `gorm:"type:uuid" json:"role_id"`
 	Role   Role       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
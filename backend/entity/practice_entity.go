@@ -10,11 +10,13 @@ type Practice struct {
 	Name        string    `json:"prac_name"`
 	Address     string    `json:"prac_address"`
 	PhoneNumber string    `json:"prac_phone_number"`
+	Fee         int64     `gorm:"default:0" json:"prac_fee"` // tarif per sesi dalam rupiah
 
 	PsychologID *uuid.UUID `gorm:"type:uuid" json:"psy_id"`
 	Psycholog   Psycholog  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 
 	PracticeSchedules []PracticeSchedule `gorm:"foreignKey:PracticeID"`
+	SlotTemplates     []SlotTemplate     `gorm:"foreignKey:PracticeID"`
 	Consuls           []Consultation     `gorm:"foreignKey:PracticeID"`
 
 	TimeStamp
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PsychologChangeRequest menampung perubahan field sensitif (STR, email) yang diajukan
+// psycholog sendiri. Nilai baru baru diterapkan ke Psycholog setelah disetujui admin.
+type PsychologChangeRequest struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"psy_change_id"`
+	Field        string     `gorm:"type:varchar(20)" json:"psy_change_field"`
+	OldValue     string     `json:"psy_change_old_value"`
+	NewValue     string     `json:"psy_change_new_value"`
+	Status       string     `gorm:"type:varchar(20);default:pending;index" json:"psy_change_status"`
+	ReviewNote   string     `json:"psy_change_review_note,omitempty"`
+	ReviewedAt   *time.Time `json:"psy_change_reviewed_at,omitempty"`
+	ReviewedByID *uuid.UUID `gorm:"type:uuid" json:"psy_change_reviewed_by_id,omitempty"`
+
+	PsychologID *uuid.UUID `gorm:"type:uuid;index" json:"psy_id"`
+	Psycholog   Psycholog  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	TimeStamp
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+// PsychologDocument menyimpan dokumen pendukung pendaftaran psycholog (STR, lisensi, sertifikat).
+// File disimpan di luar folder assets karena bersifat privat dan hanya bisa diakses admin.
+type PsychologDocument struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"psy_doc_id"`
+	Type         string    `gorm:"type:varchar(20)" json:"psy_doc_type"`
+	FileName     string    `json:"-"`
+	OriginalName string    `json:"psy_doc_original_name"`
+	ContentType  string    `json:"psy_doc_content_type"`
+	Size         int64     `json:"psy_doc_size"`
+
+	PsychologID *uuid.UUID `gorm:"type:uuid;index" json:"psy_id"`
+	Psycholog   Psycholog  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	TimeStamp
+}
@@ -1,21 +1,34 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/Reyysusanto/warasin-web/backend/helpers"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Psycholog struct {
-	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"psy_id"`
-	Name        string    `json:"psy_name"`
-	STRNumber   string    `json:"psy_str_number"`
-	Email       string    `gorm:"unique; not null" json:"psy_email"`
-	Password    string    `json:"psy_password"`
-	WorkYear    string    `gorm:"type:varchar(4)" json:"psy_work_year"`
-	Description string    `json:"psy_description"`
-	PhoneNumber string    `json:"psy_phone_number,omitempty"`
-	Image       string    `json:"psy_image,omitempty"`
+	ID          uuid.UUID            `gorm:"type:uuid;primaryKey" json:"psy_id"`
+	Name        string               `json:"psy_name"`
+	STRNumber   string               `json:"psy_str_number"`
+	Email       string               `gorm:"unique; not null" json:"psy_email"`
+	Password    helpers.PasswordHash `json:"psy_password,omitempty"`
+	WorkYear    string               `gorm:"type:varchar(4)" json:"psy_work_year"`
+	Description string               `json:"psy_description"`
+	PhoneNumber string               `json:"psy_phone_number,omitempty"`
+	Image       string               `json:"psy_image,omitempty"`
+	Gender      *bool                `json:"psy_gender,omitempty"` // false: laki-laki, true: perempuan
+
+	// psycholog hasil self-registration mulai dari pending sampai direview admin
+	Status       string     `gorm:"type:varchar(20);default:approved;index" json:"psy_status"`
+	ReviewNote   string     `json:"psy_review_note,omitempty"`
+	ReviewedAt   *time.Time `json:"psy_reviewed_at,omitempty"`
+	ReviewedByID *uuid.UUID `gorm:"type:uuid" json:"psy_reviewed_by_id,omitempty"`
+
+	// agregat rating dari consultation yang selesai, dihitung ulang setiap ada review baru
+	RatingAverage float64 `gorm:"default:0" json:"psy_rating_average"`
+	RatingCount   int64   `gorm:"default:0" json:"psy_rating_count"`
 
 	CityID *uuid.UUID `gorm:"type:uuid" json:"city_id"`
 	City   City       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
@@ -27,6 +40,8 @@ type Psycholog struct {
 	Educations               []Education               `gorm:"foreignKey:PsychologID"`
 	Practices                []Practice                `gorm:"foreignKey:PsychologID"`
 	AvailableSlot            []AvailableSlot           `gorm:"foreignKey:PsychologID"`
+	SlotTemplates            []SlotTemplate            `gorm:"foreignKey:PsychologID"`
+	Documents                []PsychologDocument       `gorm:"foreignKey:PsychologID"`
 
 	TimeStamp
 }
@@ -39,7 +54,7 @@ func (p *Psycholog) BeforeCreate(tx *gorm.DB) error {
 	}()
 
 	var err error
-	p.Password, err = helpers.HashPassword(p.Password)
+	p.Password, err = helpers.HashPassword(string(p.Password))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken menyimpan hash refresh token. Setiap login membuat family baru dan
+// setiap rotasi menambah token baru di family yang sama, sehingga pemakaian ulang
+// token lama bisa dideteksi dan seluruh family dicabut.
+type RefreshToken struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"refresh_token_id"`
+	TokenHash    string     `gorm:"uniqueIndex;not null" json:"-"`
+	FamilyID     uuid.UUID  `gorm:"type:uuid;index" json:"refresh_token_family_id"`
+	SubjectID    uuid.UUID  `gorm:"type:uuid;index" json:"refresh_token_subject_id"` // id user atau psycholog
+	RoleID       uuid.UUID  `gorm:"type:uuid" json:"role_id"`
+	RoleName     string     `json:"refresh_token_role_name"`
+	ExpiresAt    time.Time  `json:"refresh_token_expires_at"`
+	RevokedAt    *time.Time `json:"refresh_token_revoked_at"`
+	ReplacedByID *uuid.UUID `gorm:"type:uuid" json:"refresh_token_replaced_by_id"`
+
+	TimeStamp
+}
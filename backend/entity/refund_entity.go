@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Refund dibuat saat consultation yang sudah dibayar dibatalkan, besarnya mengikuti
+// kebijakan pembatalan yang berlaku pada saat itu.
+type Refund struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"refund_id"`
+	Amount        int64      `json:"refund_amount"`
+	Percent       int        `json:"refund_percent"`
+	Rule          string     `json:"refund_rule"` // lihat constants.ENUM_CANCELLATION_RULE_*
+	HoursBefore   float64    `json:"refund_hours_before"`
+	Status        string     `gorm:"index" json:"refund_status"` // lihat constants.ENUM_REFUND_STATUS_*
+	Gateway       string     `json:"refund_gateway"`
+	Reference     string     `json:"refund_reference"`
+	FailureReason string     `json:"refund_failure_reason"`
+	ProcessedAt   *time.Time `json:"refund_processed_at"`
+
+	PaymentID      *uuid.UUID   `gorm:"type:uuid;index" json:"pay_id"`
+	Payment        Payment      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ConsultationID *uuid.UUID   `gorm:"type:uuid;index" json:"consul_id"`
+	Consultation   Consultation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+
+	TimeStamp
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+type SlotTemplate struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey" json:"slot_tmpl_id"`
+	Day             string    `json:"slot_tmpl_day"` // Monday - Sunday
+	Start           string    `json:"slot_tmpl_start"`
+	End             string    `json:"slot_tmpl_end"`
+	SessionDuration int       `json:"slot_tmpl_session_duration"` // menit
+	BufferDuration  int       `json:"slot_tmpl_buffer_duration"`  // menit
+
+	PracticeID  *uuid.UUID `gorm:"type:uuid" json:"prac_id"`
+	Practice    Practice   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	PsychologID *uuid.UUID `gorm:"type:uuid" json:"psy_id"`
+	Psycholog   Psycholog  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+
+	AvailableSlots []AvailableSlot `gorm:"foreignKey:SlotTemplateID"`
+
+	TimeStamp
+}
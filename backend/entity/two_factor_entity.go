@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactor menyimpan konfigurasi TOTP milik admin atau psycholog. Secret terisi saat
+// setup, IsEnabled baru true setelah code pertama terverifikasi.
+type TwoFactor struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"two_factor_id"`
+	SubjectID    uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_two_factor_subject" json:"two_factor_subject_id"` // id user (admin) atau psycholog
+	RoleName     string     `gorm:"uniqueIndex:idx_two_factor_subject" json:"two_factor_role_name"`
+	Secret       string     `gorm:"type:text;serializer:encrypted" json:"-"`
+	IsEnabled    bool       `gorm:"default:false" json:"two_factor_is_enabled"`
+	IsRequired   bool       `gorm:"default:false" json:"two_factor_is_required"` // diwajibkan oleh admin
+	EnabledAt    *time.Time `json:"two_factor_enabled_at"`
+	LastUsedStep int64      `json:"-"` // step TOTP terakhir yang dipakai, mencegah replay
+
+	RecoveryCodes []TwoFactorRecoveryCode `gorm:"foreignKey:TwoFactorID"`
+
+	TimeStamp
+}
+
+type TwoFactorRecoveryCode struct {
+	ID       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"two_factor_recovery_code_id"`
+	CodeHash string     `gorm:"index;not null" json:"-"`
+	UsedAt   *time.Time `json:"two_factor_recovery_code_used_at"`
+
+	TwoFactorID uuid.UUID `gorm:"type:uuid;index" json:"two_factor_id"`
+	TwoFactor   TwoFactor `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	TimeStamp
+}
+
+// TwoFactorChallenge adalah tahap kedua login: dibuat setelah password benar dan
+// ditukar dengan access token setelah code TOTP / recovery code valid.
+type TwoFactorChallenge struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"two_factor_challenge_id"`
+	TokenHash   string     `gorm:"uniqueIndex;not null" json:"-"`
+	SubjectID   uuid.UUID  `gorm:"type:uuid;index" json:"two_factor_challenge_subject_id"`
+	RoleID      uuid.UUID  `gorm:"type:uuid" json:"role_id"`
+	RoleName    string     `json:"two_factor_challenge_role_name"`
+	AccountName string     `json:"two_factor_challenge_account_name"`
+	Attempts    int        `gorm:"default:0" json:"two_factor_challenge_attempts"`
+	ExpiresAt   time.Time  `json:"two_factor_challenge_expires_at"`
+	ConsumedAt  *time.Time `json:"two_factor_challenge_consumed_at"`
+
+	TimeStamp
+}
@@ -7,18 +7,18 @@ import (
 )
 
 type User struct {
-	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
-	Name        string    `json:"user_name"`
-	Email       string    `gorm:"unique; not null" json:"user_email"`
-	Password    string    `json:"user_password"`
-	Image       string    `json:"user_image,omitempty"`
-	Gender      *bool     `json:"user_gender,omitempty"`
-	Birthdate   string    `json:"user_birth_date,omitempty"`
-	PhoneNumber string    `json:"user_phone_number,omitempty"`
-	Data01      int       `json:"user_data01,omitempty"`
-	Data02      int       `json:"user_data02,omitempty"`
-	Data03      int       `json:"user_data03,omitempty"`
-	IsVerified  *bool     `json:"user_is_verified"`
+	ID          uuid.UUID            `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Name        string               `json:"user_name"`
+	Email       string               `gorm:"unique; not null" json:"user_email"`
+	Password    helpers.PasswordHash `json:"user_password,omitempty"`
+	Image       string               `json:"user_image,omitempty"`
+	Gender      *bool                `json:"user_gender,omitempty"`
+	Birthdate   string               `gorm:"type:text;serializer:encrypted" json:"user_birth_date,omitempty"`
+	PhoneNumber string               `gorm:"type:text;serializer:encrypted" json:"user_phone_number,omitempty"`
+	Data01      int                  `gorm:"type:text;serializer:encrypted" json:"user_data01,omitempty"`
+	Data02      int                  `gorm:"type:text;serializer:encrypted" json:"user_data02,omitempty"`
+	Data03      int                  `gorm:"type:text;serializer:encrypted" json:"user_data03,omitempty"`
+	IsVerified  *bool                `json:"user_is_verified"`
 
 	CityID *uuid.UUID `gorm:"type:uuid" json:"city_id"`
 	City   City       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
@@ -39,7 +39,7 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	}()
 
 	var err error
-	u.Password, err = helpers.HashPassword(u.Password)
+	u.Password, err = helpers.HashPassword(string(u.Password))
 	if err != nil {
 		return err
 	}
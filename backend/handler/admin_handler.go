@@ -17,9 +17,30 @@ type (
 		// Authentication
 		Login(ctx *gin.Context)
 		RefreshToken(ctx *gin.Context)
+		Logout(ctx *gin.Context)
+		LogoutAll(ctx *gin.Context)
+
+		// Two Factor
+		VerifyTwoFactorLogin(ctx *gin.Context)
+		SetupTwoFactorLogin(ctx *gin.Context)
+		GetTwoFactorStatus(ctx *gin.Context)
+		SetupTwoFactor(ctx *gin.Context)
+		EnableTwoFactor(ctx *gin.Context)
+		DisableTwoFactor(ctx *gin.Context)
+		RegenerateRecoveryCodes(ctx *gin.Context)
+		UpdateTwoFactorRequirement(ctx *gin.Context)
 
 		// Role
 		GetAllRole(ctx *gin.Context)
+		CreateRole(ctx *gin.Context)
+		UpdateRole(ctx *gin.Context)
+		DeleteRole(ctx *gin.Context)
+
+		// Permission
+		CreatePermission(ctx *gin.Context)
+		GetAllPermission(ctx *gin.Context)
+		UpdatePermission(ctx *gin.Context)
+		DeletePermission(ctx *gin.Context)
 
 		// User
 		CreateUser(ctx *gin.Context)
@@ -66,6 +87,30 @@ type (
 
 		// Specialization
 		GetAllSpecialization(ctx *gin.Context)
+
+		// Flagged Conversation
+		GetAllFlaggedConversation(ctx *gin.Context)
+
+		// Login Attempt
+		GetAllLoginAttempt(ctx *gin.Context)
+		ClearLoginAttempt(ctx *gin.Context)
+
+		// Psycholog Application
+		GetAllPsychologApplication(ctx *gin.Context)
+		GetDetailPsychologApplication(ctx *gin.Context)
+		GetPsychologDocument(ctx *gin.Context)
+		ApprovePsychologApplication(ctx *gin.Context)
+		RejectPsychologApplication(ctx *gin.Context)
+
+		// Psycholog Change Request
+		GetAllPsychologChangeRequest(ctx *gin.Context)
+		ApprovePsychologChangeRequest(ctx *gin.Context)
+		RejectPsychologChangeRequest(ctx *gin.Context)
+
+		// Review
+		GetAllReview(ctx *gin.Context)
+		ApproveReview(ctx *gin.Context)
+		HideReview(ctx *gin.Context)
 	}
 
 	AdminHandler struct {
@@ -89,11 +134,11 @@ func (ah *AdminHandler) Login(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
+	payload.IPAddress = ctx.ClientIP()
 
 	result, err := ah.adminService.Login(ctx, payload)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGIN_ADMIN, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortLoginFailed(ctx, dto.MESSAGE_FAILED_LOGIN_ADMIN, err)
 		return
 	}
 
@@ -118,6 +163,166 @@ func (ah *AdminHandler) RefreshToken(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REFRESH_TOKEN, result)
 	ctx.AbortWithStatusJSON(http.StatusOK, res)
 }
+func (ah *AdminHandler) Logout(ctx *gin.Context) {
+	var payload dto.LogoutRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := ah.adminService.Logout(ctx, payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGOUT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGOUT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) LogoutAll(ctx *gin.Context) {
+	if err := ah.adminService.LogoutAll(ctx); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGOUT_ALL, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGOUT_ALL, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Two Factor
+func (ah *AdminHandler) VerifyTwoFactorLogin(ctx *gin.Context) {
+	var payload dto.TwoFactorLoginRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.VerifyTwoFactorLogin(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_VERIFY_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGIN_ADMIN, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) SetupTwoFactorLogin(ctx *gin.Context) {
+	var payload dto.TwoFactorChallengeSetupRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.SetupTwoFactorLogin(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SETUP_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SETUP_TWO_FACTOR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) GetTwoFactorStatus(ctx *gin.Context) {
+	result, err := ah.adminService.GetTwoFactorStatus(ctx)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_TWO_FACTOR_STATUS, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_TWO_FACTOR_STATUS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) SetupTwoFactor(ctx *gin.Context) {
+	result, err := ah.adminService.SetupTwoFactor(ctx)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SETUP_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SETUP_TWO_FACTOR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) EnableTwoFactor(ctx *gin.Context) {
+	var payload dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.EnableTwoFactor(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ENABLE_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_ENABLE_TWO_FACTOR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) DisableTwoFactor(ctx *gin.Context) {
+	var payload dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.DisableTwoFactor(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DISABLE_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DISABLE_TWO_FACTOR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var payload dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.RegenerateRecoveryCodes(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REGENERATE_RECOVERY_CODES, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REGENERATE_RECOVERY_CODES, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) UpdateTwoFactorRequirement(ctx *gin.Context) {
+	var payload dto.UpdateTwoFactorRequirementRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	payload.SubjectID = ctx.Param("id")
+
+	result, err := ah.adminService.UpdateTwoFactorRequirement(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_TWO_FACTOR_REQUIREMENT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_TWO_FACTOR_REQUIREMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
 
 // Role
 func (ah *AdminHandler) GetAllRole(ctx *gin.Context) {
@@ -136,6 +341,137 @@ func (ah *AdminHandler) GetAllRole(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, res)
 }
+func (ah *AdminHandler) CreateRole(ctx *gin.Context) {
+	var payload dto.CreateRoleRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.CreateRole(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_ROLE, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_ROLE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) UpdateRole(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+
+	var payload dto.UpdateRoleRequest
+	payload.ID = idStr
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.UpdateRole(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_ROLE, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_ROLE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) DeleteRole(ctx *gin.Context) {
+	var payload dto.DeleteRoleRequest
+	payload.RoleID = ctx.Param("id")
+
+	result, err := ah.adminService.DeleteRole(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_ROLE, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_ROLE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Permission
+func (ah *AdminHandler) CreatePermission(ctx *gin.Context) {
+	var payload dto.CreatePermissionRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.CreatePermission(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_PERMISSION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_PERMISSION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) GetAllPermission(ctx *gin.Context) {
+	var payload dto.GetAllPermissionRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.GetAllPermission(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_PERMISSION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_GET_LIST_PERMISSION,
+		Data:     result.Data,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) UpdatePermission(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+
+	var payload dto.UpdatePermissionRequest
+	payload.ID = idStr
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.UpdatePermission(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_PERMISSION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_PERMISSION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) DeletePermission(ctx *gin.Context) {
+	var payload dto.DeletePermissionRequest
+	payload.PermissionID = ctx.Param("id")
+
+	result, err := ah.adminService.DeletePermission(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_PERMISSION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_PERMISSION, result)
+	ctx.JSON(http.StatusOK, res)
+}
 
 // User
 func (ah *AdminHandler) CreateUser(ctx *gin.Context) {
@@ -632,6 +968,11 @@ func (ah *AdminHandler) CreatePsycholog(ctx *gin.Context) {
 	payload.Email = ctx.PostForm("email")
 	payload.Password = ctx.PostForm("password")
 	payload.WorkYear = ctx.PostForm("work_year")
+	genderStr := ctx.PostForm("gender")
+	if genderStr != "" {
+		g := genderStr == "true"
+		payload.Gender = &g
+	}
 	payload.Description = ctx.PostForm("description")
 	payload.PhoneNumber = ctx.PostForm("phone_number")
 	fileHeader, err := ctx.FormFile("image")
@@ -711,6 +1052,11 @@ func (ah *AdminHandler) UpdatePsycholog(ctx *gin.Context) {
 	payload.STRNumber = ctx.PostForm("str_number")
 	payload.Email = ctx.PostForm("email")
 	payload.WorkYear = ctx.PostForm("work_year")
+	genderStr := ctx.PostForm("gender")
+	if genderStr != "" {
+		g := genderStr == "true"
+		payload.Gender = &g
+	}
 	payload.Description = ctx.PostForm("description")
 	payload.PhoneNumber = ctx.PostForm("phone_number")
 
@@ -868,3 +1214,285 @@ func (ah *AdminHandler) GetAllSpecialization(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_ALL_SPECIALIZATION, result)
 	ctx.JSON(http.StatusOK, res)
 }
+
+// Flagged Conversation
+func (ah *AdminHandler) GetAllFlaggedConversation(ctx *gin.Context) {
+	var payload dto.PaginationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.GetAllFlaggedConversationWithPagination(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_FLAGGED_CONVERSATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_GET_LIST_FLAGGED_CONVERSATION,
+		Meta:     result.PaginationResponse,
+		Data:     result.Data,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Login Attempt
+func (ah *AdminHandler) GetAllLoginAttempt(ctx *gin.Context) {
+	var payload dto.PaginationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.GetAllLoginAttemptWithPagination(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_LOGIN_ATTEMPT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_GET_LIST_LOGIN_ATTEMPT,
+		Meta:     result.PaginationResponse,
+		Data:     result.Data,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) ClearLoginAttempt(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := ah.adminService.ClearLoginAttempt(ctx.Request.Context(), idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CLEAR_LOGIN_ATTEMPT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CLEAR_LOGIN_ATTEMPT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Psycholog Application
+func (ah *AdminHandler) GetAllPsychologApplication(ctx *gin.Context) {
+	var payload dto.PsychologApplicationPaginationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.GetAllPsychologApplicationWithPagination(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_PSYCHOLOG_APPLICATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_GET_LIST_PSYCHOLOG_APPLICATION,
+		Meta:     result.PaginationResponse,
+		Data:     result.Data,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) GetDetailPsychologApplication(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := ah.adminService.GetDetailPsychologApplication(ctx.Request.Context(), idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DETAIL_PSYCHOLOG_APPLICATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_DETAIL_PSYCHOLOG_APPLICATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) GetPsychologDocument(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := ah.adminService.GetPsychologDocument(ctx.Request.Context(), idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_PSYCHOLOG_DOCUMENT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+		return
+	}
+
+	if result.ContentType != "" {
+		ctx.Header("Content-Type", result.ContentType)
+	}
+	ctx.FileAttachment(result.Path, result.OriginalName)
+}
+func (ah *AdminHandler) ApprovePsychologApplication(ctx *gin.Context) {
+	var payload dto.ReviewPsychologApplicationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	payload.ID = ctx.Param("id")
+
+	result, err := ah.adminService.ApprovePsychologApplication(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_APPROVE_PSYCHOLOG_APPLICATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_APPROVE_PSYCHOLOG_APPLICATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) RejectPsychologApplication(ctx *gin.Context) {
+	var payload dto.ReviewPsychologApplicationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	payload.ID = ctx.Param("id")
+
+	result, err := ah.adminService.RejectPsychologApplication(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REJECT_PSYCHOLOG_APPLICATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REJECT_PSYCHOLOG_APPLICATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Psycholog Change Request
+func (ah *AdminHandler) GetAllPsychologChangeRequest(ctx *gin.Context) {
+	var payload dto.PsychologChangeRequestPaginationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.GetAllPsychologChangeRequestWithPagination(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_PSYCHOLOG_CHANGE_REQUEST, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_GET_LIST_PSYCHOLOG_CHANGE_REQUEST,
+		Meta:     result.PaginationResponse,
+		Data:     result.Data,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) ApprovePsychologChangeRequest(ctx *gin.Context) {
+	var payload dto.ReviewPsychologChangeRequestRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	payload.ID = ctx.Param("id")
+
+	result, err := ah.adminService.ApprovePsychologChangeRequest(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_APPROVE_PSYCHOLOG_CHANGE_REQUEST, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_APPROVE_PSYCHOLOG_CHANGE_REQUEST, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) RejectPsychologChangeRequest(ctx *gin.Context) {
+	var payload dto.ReviewPsychologChangeRequestRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	payload.ID = ctx.Param("id")
+
+	result, err := ah.adminService.RejectPsychologChangeRequest(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REJECT_PSYCHOLOG_CHANGE_REQUEST, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REJECT_PSYCHOLOG_CHANGE_REQUEST, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Review
+func (ah *AdminHandler) GetAllReview(ctx *gin.Context) {
+	var payload dto.ReviewModerationPaginationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ah.adminService.GetAllReviewWithPagination(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_REVIEW, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_GET_LIST_REVIEW,
+		Meta:     result.PaginationResponse,
+		Data:     result.Data,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) ApproveReview(ctx *gin.Context) {
+	var payload dto.ModerateReviewRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	payload.ID = ctx.Param("id")
+
+	result, err := ah.adminService.ApproveReview(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_APPROVE_REVIEW, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_APPROVE_REVIEW, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ah *AdminHandler) HideReview(ctx *gin.Context) {
+	var payload dto.ModerateReviewRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	payload.ID = ctx.Param("id")
+
+	result, err := ah.adminService.HideReview(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_HIDE_REVIEW, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_HIDE_REVIEW, result)
+	ctx.JSON(http.StatusOK, res)
+}
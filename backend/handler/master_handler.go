@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/service"
@@ -17,6 +20,15 @@ type (
 
 		// Psycholog
 		GetDetailPsycholog(ctx *gin.Context)
+
+		// JWKS
+		GetJWKS(ctx *gin.Context)
+
+		// Login Attempt
+		UnlockAccount(ctx *gin.Context)
+
+		// Payment
+		PaymentWebhook(ctx *gin.Context)
 	}
 
 	MasterHandler struct {
@@ -84,3 +96,72 @@ func (mh *MasterHandler) GetDetailPsycholog(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_DETAIL_PSYCHOLOG, result)
 	ctx.JSON(http.StatusOK, res)
 }
+
+// JWKS
+func (mh *MasterHandler) GetJWKS(ctx *gin.Context) {
+	// format JWK Set standar (RFC 7517), tidak dibungkus utils.Response agar bisa dibaca library JWT
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, mh.masterService.GetJWKS(ctx.Request.Context()))
+}
+
+// Login Attempt
+func (mh *MasterHandler) UnlockAccount(ctx *gin.Context) {
+	var payload dto.UnlockAccountRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := mh.masterService.UnlockAccount(ctx.Request.Context(), payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UNLOCK_ACCOUNT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UNLOCK_ACCOUNT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// abortLoginFailed menjawab login gagal, login yang sedang ditahan LoginGuard dijawab
+// 429 dengan header Retry-After
+func abortLoginFailed(ctx *gin.Context, message string, err error) {
+	var blocked *service.LoginBlockedError
+	if errors.As(err, &blocked) {
+		retryAfter := int(math.Ceil(blocked.RetryAfter.Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+		res := utils.BuildResponseFailed(message, err.Error(), gin.H{
+			"retry_after_seconds": retryAfter,
+		})
+		ctx.AbortWithStatusJSON(http.StatusTooManyRequests, res)
+		return
+	}
+
+	res := utils.BuildResponseFailed(message, err.Error(), nil)
+	ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+}
+
+// Payment
+func (mh *MasterHandler) PaymentWebhook(ctx *gin.Context) {
+	// body mentah dibutuhkan untuk verifikasi signature, jangan di-bind ke struct
+	payload, err := ctx.GetRawData()
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := mh.masterService.HandlePaymentWebhook(ctx.Request.Context(), payload, ctx.Request.Header); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, dto.ErrInvalidPaymentSignature) {
+			status = http.StatusUnauthorized
+		}
+
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PAYMENT_WEBHOOK, err.Error(), nil)
+		ctx.AbortWithStatusJSON(status, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_PAYMENT_WEBHOOK, nil)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/service"
 	"github.com/Reyysusanto/warasin-web/backend/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type (
@@ -14,6 +17,28 @@ type (
 		// Authentication
 		Login(ctx *gin.Context)
 		RefreshToken(ctx *gin.Context)
+		Logout(ctx *gin.Context)
+		LogoutAll(ctx *gin.Context)
+
+		// Two Factor
+		VerifyTwoFactorLogin(ctx *gin.Context)
+		SetupTwoFactorLogin(ctx *gin.Context)
+		GetTwoFactorStatus(ctx *gin.Context)
+		SetupTwoFactor(ctx *gin.Context)
+		EnableTwoFactor(ctx *gin.Context)
+		DisableTwoFactor(ctx *gin.Context)
+		RegenerateRecoveryCodes(ctx *gin.Context)
+
+		// Application
+		Apply(ctx *gin.Context)
+
+		// Profile
+		UpdateProfile(ctx *gin.Context)
+		GetAllProfileChangeRequest(ctx *gin.Context)
+		ChangePassword(ctx *gin.Context)
+		CreateEducation(ctx *gin.Context)
+		UpdateEducation(ctx *gin.Context)
+		DeleteEducation(ctx *gin.Context)
 
 		// Practice
 		CreatePractice(ctx *gin.Context)
@@ -23,10 +48,18 @@ type (
 
 		// Available Slot
 		GetAllAvailableSlot(ctx *gin.Context)
+		GenerateAvailableSlot(ctx *gin.Context)
+
+		// Slot Template
+		CreateSlotTemplate(ctx *gin.Context)
+		GetAllSlotTemplate(ctx *gin.Context)
+		UpdateSlotTemplate(ctx *gin.Context)
+		DeleteSlotTemplate(ctx *gin.Context)
 
 		// Consultation
 		GetAllConsultation(ctx *gin.Context)
 		UpdateConsultation(ctx *gin.Context)
+		GetConsultationStatusHistory(ctx *gin.Context)
 	}
 
 	PsychologHandler struct {
@@ -50,11 +83,11 @@ func (ph *PsychologHandler) Login(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
+	payload.IPAddress = ctx.ClientIP()
 
 	result, err := ph.psychologService.Login(ctx, payload)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGIN_PSYCHOLOG, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortLoginFailed(ctx, dto.MESSAGE_FAILED_LOGIN_PSYCHOLOG, err)
 		return
 	}
 
@@ -79,6 +112,370 @@ func (ph *PsychologHandler) RefreshToken(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REFRESH_TOKEN, result)
 	ctx.AbortWithStatusJSON(http.StatusOK, res)
 }
+func (ph *PsychologHandler) Logout(ctx *gin.Context) {
+	var payload dto.LogoutRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := ph.psychologService.Logout(ctx, payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGOUT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGOUT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) LogoutAll(ctx *gin.Context) {
+	if err := ph.psychologService.LogoutAll(ctx); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGOUT_ALL, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGOUT_ALL, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Two Factor
+func (ph *PsychologHandler) VerifyTwoFactorLogin(ctx *gin.Context) {
+	var payload dto.TwoFactorLoginRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ph.psychologService.VerifyTwoFactorLogin(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_VERIFY_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGIN_PSYCHOLOG, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) SetupTwoFactorLogin(ctx *gin.Context) {
+	var payload dto.TwoFactorChallengeSetupRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ph.psychologService.SetupTwoFactorLogin(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SETUP_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SETUP_TWO_FACTOR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) GetTwoFactorStatus(ctx *gin.Context) {
+	result, err := ph.psychologService.GetTwoFactorStatus(ctx)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_TWO_FACTOR_STATUS, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_TWO_FACTOR_STATUS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) SetupTwoFactor(ctx *gin.Context) {
+	result, err := ph.psychologService.SetupTwoFactor(ctx)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SETUP_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SETUP_TWO_FACTOR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) EnableTwoFactor(ctx *gin.Context) {
+	var payload dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ph.psychologService.EnableTwoFactor(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ENABLE_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_ENABLE_TWO_FACTOR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) DisableTwoFactor(ctx *gin.Context) {
+	var payload dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ph.psychologService.DisableTwoFactor(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DISABLE_TWO_FACTOR, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DISABLE_TWO_FACTOR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var payload dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ph.psychologService.RegenerateRecoveryCodes(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REGENERATE_RECOVERY_CODES, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REGENERATE_RECOVERY_CODES, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Application
+func (ph *PsychologHandler) Apply(ctx *gin.Context) {
+	err := ctx.Request.ParseMultipartForm(32 << 20)
+	if err != nil {
+		res := utils.BuildResponseFailed("Failed to parse multipart form", err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	form := ctx.Request.MultipartForm
+	payload := dto.ApplyPsychologRequest{}
+	payload.Name = ctx.PostForm("name")
+	payload.STRNumber = ctx.PostForm("str_number")
+	payload.Email = ctx.PostForm("email")
+	payload.Password = ctx.PostForm("password")
+	payload.WorkYear = ctx.PostForm("work_year")
+	genderStr := ctx.PostForm("gender")
+	if genderStr != "" {
+		g := genderStr == "true"
+		payload.Gender = &g
+	}
+	payload.Description = ctx.PostForm("description")
+	payload.PhoneNumber = ctx.PostForm("phone_number")
+
+	fileHeader, err := ctx.FormFile("image")
+	if err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_OPEN_PHOTO, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		}
+		defer file.Close()
+
+		payload.FileHeader = fileHeader
+		payload.FileReader = file
+	}
+
+	if cityUUID, err := uuid.Parse(ctx.PostForm("city_id")); err == nil {
+		payload.CityID = &cityUUID
+	}
+
+	payload.LanguageMasterIDs = ctx.PostFormArray("language_master")
+
+	payload.SpecializationIDs = ctx.PostFormArray("specialization")
+
+	var educations []dto.EducationRequest
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("education[%d].", i)
+		degree := form.Value[prefix+"degree"]
+		if len(degree) == 0 {
+			break
+		}
+		educations = append(educations, dto.EducationRequest{
+			Degree:         degree[0],
+			Major:          ctx.PostForm(prefix + "major"),
+			Institution:    ctx.PostForm(prefix + "institution"),
+			GraduationYear: ctx.PostForm(prefix + "graduation_year"),
+		})
+	}
+	payload.Educations = educations
+
+	documentFields := map[string]string{
+		"license_document":     constants.ENUM_PSYCHOLOG_DOCUMENT_LICENSE,
+		"str_document":         constants.ENUM_PSYCHOLOG_DOCUMENT_STR,
+		"certificate_document": constants.ENUM_PSYCHOLOG_DOCUMENT_CERTIFICATE,
+	}
+	for field, documentType := range documentFields {
+		for _, documentHeader := range form.File[field] {
+			file, err := documentHeader.Open()
+			if err != nil {
+				res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_OPEN_DOCUMENT, err.Error(), nil)
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+				return
+			}
+			defer file.Close()
+
+			payload.Documents = append(payload.Documents, dto.PsychologDocumentRequest{
+				Type:       documentType,
+				FileHeader: documentHeader,
+				FileReader: file,
+			})
+		}
+	}
+
+	result, err := ph.psychologService.Apply(ctx.Request.Context(), payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_APPLY_PSYCHOLOG, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_APPLY_PSYCHOLOG, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Profile
+func (ph *PsychologHandler) UpdateProfile(ctx *gin.Context) {
+	payload := dto.UpdatePsychologProfileRequest{}
+	payload.Name = ctx.PostForm("name")
+	payload.STRNumber = ctx.PostForm("str_number")
+	payload.Email = ctx.PostForm("email")
+	payload.WorkYear = ctx.PostForm("work_year")
+	genderStr := ctx.PostForm("gender")
+	if genderStr != "" {
+		g := genderStr == "true"
+		payload.Gender = &g
+	}
+	payload.Description = ctx.PostForm("description")
+	payload.PhoneNumber = ctx.PostForm("phone_number")
+
+	fileHeader, err := ctx.FormFile("image")
+	if err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_OPEN_PHOTO, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		}
+		defer file.Close()
+
+		payload.FileHeader = fileHeader
+		payload.FileReader = file
+	}
+
+	if cityIDStr := ctx.PostForm("city_id"); cityIDStr != "" {
+		if cityUUID, err := uuid.Parse(cityIDStr); err == nil {
+			payload.CityID = &cityUUID
+		}
+	}
+
+	payload.LanguageMasterIDs = ctx.PostFormArray("language_master")
+
+	payload.SpecializationIDs = ctx.PostFormArray("specialization")
+
+	result, err := ph.psychologService.UpdateProfile(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_PSYCHOLOG_PROFILE, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_PSYCHOLOG_PROFILE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) GetAllProfileChangeRequest(ctx *gin.Context) {
+	result, err := ph.psychologService.GetAllProfileChangeRequest(ctx)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_PSYCHOLOG_CHANGE_REQUEST, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_PSYCHOLOG_CHANGE_REQUEST, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) ChangePassword(ctx *gin.Context) {
+	var payload dto.ChangePsychologPasswordRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := ph.psychologService.ChangePassword(ctx, payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CHANGE_PASSWORD, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CHANGE_PASSWORD, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) CreateEducation(ctx *gin.Context) {
+	var payload dto.CreateEducationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ph.psychologService.CreateEducation(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_EDUCATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_EDUCATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) UpdateEducation(ctx *gin.Context) {
+	var payload dto.UpdateEducationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	payload.ID = ctx.Param("id")
+
+	result, err := ph.psychologService.UpdateEducation(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_EDUCATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_EDUCATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) DeleteEducation(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := ph.psychologService.DeleteEducation(ctx, idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_EDUCATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_EDUCATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
 
 // Practice
 func (ph *PsychologHandler) CreatePractice(ctx *gin.Context) {
@@ -154,6 +551,80 @@ func (ph *PsychologHandler) GetAllAvailableSlot(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_AVAILABLE_SLOT, result)
 	ctx.JSON(http.StatusOK, res)
 }
+func (ph *PsychologHandler) GenerateAvailableSlot(ctx *gin.Context) {
+	result, err := ph.psychologService.GenerateAvailableSlot(ctx)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GENERATE_AVAILABLE_SLOT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GENERATE_AVAILABLE_SLOT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Slot Template
+func (ph *PsychologHandler) CreateSlotTemplate(ctx *gin.Context) {
+	var payload dto.CreateSlotTemplateRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := ph.psychologService.CreateSlotTemplate(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_SLOT_TEMPLATE, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_SLOT_TEMPLATE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) GetAllSlotTemplate(ctx *gin.Context) {
+	result, err := ph.psychologService.GetAllSlotTemplate(ctx)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_SLOT_TEMPLATE, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_SLOT_TEMPLATE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) UpdateSlotTemplate(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	var payload dto.UpdateSlotTemplateRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	payload.ID = idStr
+	result, err := ph.psychologService.UpdateSlotTemplate(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_SLOT_TEMPLATE, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_SLOT_TEMPLATE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (ph *PsychologHandler) DeleteSlotTemplate(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := ph.psychologService.DeleteSlotTemplate(ctx, idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_SLOT_TEMPLATE, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_SLOT_TEMPLATE, result)
+	ctx.JSON(http.StatusOK, res)
+}
 
 // Consultation
 func (ph *PsychologHandler) GetAllConsultation(ctx *gin.Context) {
@@ -199,3 +670,15 @@ func (ph *PsychologHandler) UpdateConsultation(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_CONSULTATION, result)
 	ctx.JSON(http.StatusOK, res)
 }
+func (ph *PsychologHandler) GetConsultationStatusHistory(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := ph.psychologService.GetConsultationStatusHistory(ctx, idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_CONSULTATION_STATUS_HISTORY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_CONSULTATION_STATUS_HISTORY, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/service"
 	"github.com/Reyysusanto/warasin-web/backend/utils"
@@ -16,6 +18,8 @@ type (
 		Register(ctx *gin.Context)
 		Login(ctx *gin.Context)
 		RefreshToken(ctx *gin.Context)
+		Logout(ctx *gin.Context)
+		LogoutAll(ctx *gin.Context)
 
 		// Forgot Password
 		SendForgotPasswordEmail(ctx *gin.Context)
@@ -44,10 +48,15 @@ type (
 		GetDetailConsultation(ctx *gin.Context)
 		UpdateConsultation(ctx *gin.Context)
 		DeleteConsultation(ctx *gin.Context)
+		GetConsultationStatusHistory(ctx *gin.Context)
+		GetConsultationPayment(ctx *gin.Context)
 
 		// Psycholog
 		GetAllPsycholog(ctx *gin.Context)
 		GetDetailPsycholog(ctx *gin.Context)
+		GetAllPsychologReview(ctx *gin.Context)
+		SearchPsycholog(ctx *gin.Context)
+		Search(ctx *gin.Context)
 
 		// Practice
 		GetAllPractice(ctx *gin.Context)
@@ -65,6 +74,13 @@ type (
 
 		// Chat
 		Chat(ctx *gin.Context)
+		ChatStream(ctx *gin.Context)
+
+		// Conversation
+		GetAllConversation(ctx *gin.Context)
+		GetDetailConversation(ctx *gin.Context)
+		UpdateConversation(ctx *gin.Context)
+		DeleteConversation(ctx *gin.Context)
 	}
 
 	UserHandler struct {
@@ -106,11 +122,11 @@ func (uh *UserHandler) Login(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
+	payload.IPAddress = ctx.ClientIP()
 
 	result, err := uh.userService.Login(ctx, payload)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGIN_USER, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortLoginFailed(ctx, dto.MESSAGE_FAILED_LOGIN_USER, err)
 		return
 	}
 
@@ -135,6 +151,33 @@ func (uh *UserHandler) RefreshToken(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REFRESH_TOKEN, result)
 	ctx.AbortWithStatusJSON(http.StatusOK, res)
 }
+func (uh *UserHandler) Logout(ctx *gin.Context) {
+	var payload dto.LogoutRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := uh.userService.Logout(ctx, payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGOUT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGOUT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+func (uh *UserHandler) LogoutAll(ctx *gin.Context) {
+	if err := uh.userService.LogoutAll(ctx); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGOUT_ALL, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGOUT_ALL, nil)
+	ctx.JSON(http.StatusOK, res)
+}
 
 // Forgot Password
 func (uh *UserHandler) SendForgotPasswordEmail(ctx *gin.Context) {
@@ -449,12 +492,36 @@ func (uh *UserHandler) DeleteConsultation(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	result, err := uh.userService.DeleteConsultation(ctx, idStr)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_CONSULTATION, err.Error(), nil)
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CANCEL_CONSULTATION, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
 
-	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_CONSULTATION, result)
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CANCEL_CONSULTATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (uh *UserHandler) GetConsultationStatusHistory(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := uh.userService.GetConsultationStatusHistory(ctx, idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_CONSULTATION_STATUS_HISTORY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_CONSULTATION_STATUS_HISTORY, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (uh *UserHandler) GetConsultationPayment(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := uh.userService.GetConsultationPayment(ctx, idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_PAYMENT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_PAYMENT, result)
 	ctx.JSON(http.StatusOK, res)
 }
 
@@ -489,6 +556,78 @@ func (uh *UserHandler) GetDetailPsycholog(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_DETAIL_PSYCHOLOG, result)
 	ctx.JSON(http.StatusOK, res)
 }
+func (uh *UserHandler) SearchPsycholog(ctx *gin.Context) {
+	var payload dto.PsychologSearchRequest
+	if err := ctx.ShouldBindQuery(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := uh.userService.SearchPsychologWithPagination(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SEARCH_PSYCHOLOG, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_SEARCH_PSYCHOLOG,
+		Data:     result.Data,
+		Meta:     result.PsychologSearchMeta,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+func (uh *UserHandler) Search(ctx *gin.Context) {
+	var payload dto.SearchRequest
+	if err := ctx.ShouldBindQuery(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := uh.userService.SearchWithPagination(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_SEARCH, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_SEARCH,
+		Data:     result.Data,
+		Meta:     result.SearchMeta,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+func (uh *UserHandler) GetAllPsychologReview(ctx *gin.Context) {
+	var payload dto.PaginationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := uh.userService.GetAllPsychologReviewWithPagination(ctx, payload, ctx.Param("id"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_PSYCHOLOG_REVIEW, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_GET_LIST_PSYCHOLOG_REVIEW,
+		Data:     result.Data,
+		Meta:     result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
 
 // Practice
 func (uh *UserHandler) GetAllPractice(ctx *gin.Context) {
@@ -597,3 +736,117 @@ func (uh *UserHandler) Chat(ctx *gin.Context) {
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_HANDLE_CHAT, result)
 	ctx.JSON(http.StatusOK, res)
 }
+func (uh *UserHandler) ChatStream(ctx *gin.Context) {
+	var req dto.ChatRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil))
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	// hanya stream chat yang ikut dibatalkan saat client disconnect, handler lain
+	// tetap memakai gin.Context agar penulisan ke DB tidak terputus di tengah jalan
+	streamCtx := context.WithValue(ctx.Request.Context(), "Authorization", ctx.GetString("Authorization"))
+
+	result, err := uh.userService.HandleChatStream(streamCtx, req, func(event string, text string) error {
+		if err := ctx.Request.Context().Err(); err != nil {
+			return err
+		}
+
+		// replace membawa balasan utuh yang menggantikan semua delta sebelumnya
+		if event == constants.ENUM_CHAT_STREAM_EVENT_REPLACE {
+			ctx.SSEvent(event, gin.H{"response": text})
+		} else {
+			ctx.SSEvent(event, gin.H{"delta": text})
+		}
+		ctx.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		// client sudah disconnect, tidak ada yang bisa dikirim lagi
+		if ctx.Request.Context().Err() != nil {
+			return
+		}
+
+		ctx.SSEvent("error", utils.BuildResponseFailed(dto.MESSAGE_FAILED_HANDLE_CHAT, err.Error(), result))
+		ctx.Writer.Flush()
+		return
+	}
+
+	ctx.SSEvent("done", utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_HANDLE_CHAT, result))
+	ctx.Writer.Flush()
+}
+
+// Conversation
+func (uh *UserHandler) GetAllConversation(ctx *gin.Context) {
+	var payload dto.PaginationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := uh.userService.GetAllConversationWithPagination(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_CONVERSATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.Response{
+		Status:   true,
+		Messsage: dto.MESSAGE_SUCCESS_GET_LIST_CONVERSATION,
+		Data:     result.Data,
+		Meta:     result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+func (uh *UserHandler) GetDetailConversation(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := uh.userService.GetDetailConversation(ctx, idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DETAIL_CONVERSATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_DETAIL_CONVERSATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (uh *UserHandler) UpdateConversation(ctx *gin.Context) {
+	var payload dto.UpdateConversationRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	payload.ID = ctx.Param("id")
+	result, err := uh.userService.UpdateConversation(ctx, payload)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_CONVERSATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_CONVERSATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+func (uh *UserHandler) DeleteConversation(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	result, err := uh.userService.DeleteConversation(ctx, idStr)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_CONVERSATION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_CONVERSATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"strings"
+)
+
+// kata pemicu untuk mendeteksi user sedang mencari psikolog atau bahan bacaan
+var (
+	psychologIntentKeywords = []string{
+		"psikolog", "psikiater", "konselor", "terapis", "konsultasi", "konseling",
+		"psychologist", "therapist", "counselor", "counseling", "therapy",
+	}
+	contentIntentKeywords = []string{
+		"artikel", "bacaan", "baca", "berita", "motivasi", "kutipan", "quote", "tips",
+		"article", "reading", "motivation",
+	}
+)
+
+var keywordStopwords = map[string]bool{
+	"yang": true, "dan": true, "atau": true, "untuk": true, "dengan": true, "saya": true,
+	"aku": true, "kamu": true, "ingin": true, "mau": true, "bisa": true, "tolong": true,
+	"ada": true, "tidak": true, "gak": true, "nggak": true, "tentang": true, "dari": true,
+	"di": true, "ke": true, "ini": true, "itu": true, "apa": true, "bagaimana": true,
+	"cari": true, "carikan": true, "rekomendasi": true, "sedang": true, "lagi": true,
+	"sangat": true, "banget": true, "juga": true, "sama": true, "punya": true,
+	"want": true, "need": true, "with": true, "about": true, "please": true, "find": true,
+}
+
+// DetectChatIntent mengembalikan apakah pesan meminta rekomendasi psikolog
+// dan/atau konten (artikel & motivasi).
+func DetectChatIntent(message string) (wantsPsycholog bool, wantsContent bool) {
+	text := normalizeCrisisText(message)
+
+	for _, keyword := range psychologIntentKeywords {
+		if strings.Contains(text, " "+keyword) {
+			wantsPsycholog = true
+			break
+		}
+	}
+
+	for _, keyword := range contentIntentKeywords {
+		if strings.Contains(text, " "+keyword) {
+			wantsContent = true
+			break
+		}
+	}
+
+	return wantsPsycholog, wantsContent
+}
+
+// ExtractKeywords mengambil kata bermakna dari pesan untuk pencarian konten,
+// kata pemicu intent dan stopword dibuang.
+func ExtractKeywords(message string) []string {
+	var (
+		keywords []string
+		seen     = map[string]bool{}
+	)
+
+	for _, word := range strings.Fields(normalizeCrisisText(message)) {
+		if len([]rune(word)) < 4 || keywordStopwords[word] || seen[word] || isIntentKeyword(word) {
+			continue
+		}
+
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+
+	return keywords
+}
+
+func isIntentKeyword(word string) bool {
+	for _, keyword := range append(psychologIntentKeywords, contentIntentKeywords...) {
+		if strings.HasPrefix(word, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+)
+
+var consultationStatusNames = map[int]string{
+	constants.ENUM_CONSULTATION_STATUS_REQUESTED:             "requested",
+	constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER:      "canceled_by_user",
+	constants.ENUM_CONSULTATION_STATUS_COMPLETED:             "completed",
+	constants.ENUM_CONSULTATION_STATUS_CONFIRMED:             "confirmed",
+	constants.ENUM_CONSULTATION_STATUS_IN_PROGRESS:           "in_progress",
+	constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG: "canceled_by_psychologist",
+	constants.ENUM_CONSULTATION_STATUS_NO_SHOW:               "no_show",
+	constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT:      "awaiting_payment",
+	constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED:       "payment_expired",
+}
+
+// transisi status yang boleh dilakukan per role: role -> status asal -> status tujuan
+var consultationStatusTransitions = map[string]map[int][]int{
+	constants.ENUM_ROLE_USER: {
+		constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT: {constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER},
+		constants.ENUM_CONSULTATION_STATUS_REQUESTED:        {constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER},
+		constants.ENUM_CONSULTATION_STATUS_CONFIRMED:        {constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER},
+	},
+	constants.ENUM_ROLE_PSYCHOLOG: {
+		constants.ENUM_CONSULTATION_STATUS_REQUESTED: {
+			constants.ENUM_CONSULTATION_STATUS_CONFIRMED,
+			constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG,
+		},
+		constants.ENUM_CONSULTATION_STATUS_CONFIRMED: {
+			constants.ENUM_CONSULTATION_STATUS_IN_PROGRESS,
+			constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG,
+			constants.ENUM_CONSULTATION_STATUS_NO_SHOW,
+		},
+		constants.ENUM_CONSULTATION_STATUS_IN_PROGRESS: {
+			constants.ENUM_CONSULTATION_STATUS_COMPLETED,
+		},
+	},
+	// consultation berbayar baru masuk ke psycholog setelah pembayaran diterima
+	constants.ENUM_CONSULTATION_CHANGED_BY_SYSTEM: {
+		constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT: {
+			constants.ENUM_CONSULTATION_STATUS_REQUESTED,
+			constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED,
+		},
+	},
+}
+
+func IsValidConsultationStatus(status int) bool {
+	_, ok := consultationStatusNames[status]
+	return ok
+}
+
+func ConsultationStatusName(status int) string {
+	return consultationStatusNames[status]
+}
+
+func CanTransitionConsultationStatus(role string, from int, to int) bool {
+	for _, next := range consultationStatusTransitions[role][from] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// status yang dievaluasi kebijakan pembatalan (refund), termasuk no-show
+func IsConsultationStatusCancellation(status int) bool {
+	return status == constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER ||
+		status == constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG ||
+		status == constants.ENUM_CONSULTATION_STATUS_NO_SHOW
+}
+
+// status akhir membebaskan slot agar bisa dibooking ulang
+func IsConsultationStatusReleasingSlot(status int) bool {
+	return status == constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER ||
+		status == constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG ||
+		status == constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED
+}
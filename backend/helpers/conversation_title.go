@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	maxConversationTitleWords = 6
+	maxConversationTitleRunes = 50
+	defaultConversationTitle  = "Percakapan Baru"
+)
+
+// GenerateConversationTitle membuat judul singkat dari pesan pertama pengguna
+func GenerateConversationTitle(message string) string {
+	words := strings.FieldsFunc(message, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\'' && r != '-'
+	})
+	if len(words) == 0 {
+		return defaultConversationTitle
+	}
+
+	if len(words) > maxConversationTitleWords {
+		words = words[:maxConversationTitleWords]
+	}
+
+	title := []rune(strings.Join(words, " "))
+	if len(title) > maxConversationTitleRunes {
+		title = []rune(strings.TrimSpace(string(title[:maxConversationTitleRunes])))
+	}
+
+	title[0] = unicode.ToUpper(title[0])
+
+	return string(title)
+}
@@ -0,0 +1,124 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// daftar frasa krisis (Indonesia & Inggris), dicocokkan setelah teks dinormalisasi
+var crisisKeywords = []string{
+	// Indonesia
+	"bunuh diri",
+	"ingin mati",
+	"pengen mati",
+	"pingin mati",
+	"mau mati saja",
+	"mau mati aja",
+	"lebih baik mati",
+	"mengakhiri hidup",
+	"akhiri hidup",
+	"akhiri hidupku",
+	"tidak ingin hidup",
+	"tidak mau hidup lagi",
+	"gak mau hidup lagi",
+	"nggak mau hidup lagi",
+	"ga mau hidup lagi",
+	"menyakiti diri",
+	"melukai diri",
+	"sayat tangan",
+	"menyayat tangan",
+	"gantung diri",
+	"minum racun",
+	// English
+	"suicide",
+	"suicidal",
+	"kill myself",
+	"end my life",
+	"want to die",
+	"wanna die",
+	"better off dead",
+	"no reason to live",
+	"hurt myself",
+	"self harm",
+	"cutting myself",
+}
+
+var nonAlphaNumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+func normalizeCrisisText(text string) string {
+	text = nonAlphaNumeric.ReplaceAllString(strings.ToLower(text), " ")
+	return " " + strings.TrimSpace(text) + " "
+}
+
+// DetectCrisisLanguage mengembalikan true beserta frasa yang cocok jika teks
+// mengandung indikasi menyakiti diri atau krisis.
+func DetectCrisisLanguage(text string) (bool, string) {
+	normalized := normalizeCrisisText(text)
+	for _, keyword := range crisisKeywords {
+		if strings.Contains(normalized, normalizeCrisisText(keyword)) {
+			return true, keyword
+		}
+	}
+
+	return false, ""
+}
+
+var (
+	crisisWordPattern     = regexp.MustCompile(`[A-Za-z0-9]+`)
+	maxCrisisKeywordWords = countMaxCrisisKeywordWords()
+)
+
+func countMaxCrisisKeywordWords() int {
+	max := 0
+	for _, keyword := range crisisKeywords {
+		if n := len(strings.Fields(normalizeCrisisText(keyword))); n > max {
+			max = n
+		}
+	}
+
+	return max
+}
+
+// CrisisStreamGuard memeriksa balasan model yang dikirim bertahap (stream). Beberapa
+// kata terakhir selalu ditahan sampai ada kata baru, sehingga frasa krisis yang
+// terpotong di antara delta tidak pernah sempat terkirim ke client.
+type CrisisStreamGuard struct {
+	text strings.Builder
+	sent int
+}
+
+// Write menambahkan delta dan mengembalikan bagian teks yang aman dikirim. Jika teks
+// sejauh ini mengandung frasa krisis, crisis bernilai true dan tidak ada teks yang dilepas.
+func (g *CrisisStreamGuard) Write(delta string) (safe string, crisis bool, keyword string) {
+	g.text.WriteString(delta)
+	text := g.text.String()
+
+	if isCrisis, keyword := DetectCrisisLanguage(text); isCrisis {
+		return "", true, keyword
+	}
+
+	// frasa krisis yang baru terbentuk paling jauh dimulai di salah satu kata yang ditahan
+	words := crisisWordPattern.FindAllStringIndex(text, -1)
+	if len(words) <= maxCrisisKeywordWords {
+		return "", false, ""
+	}
+
+	boundary := words[len(words)-maxCrisisKeywordWords][0]
+	if boundary <= g.sent {
+		return "", false, ""
+	}
+
+	safe = text[g.sent:boundary]
+	g.sent = boundary
+
+	return safe, false, ""
+}
+
+// Flush melepas sisa teks yang ditahan, dipanggil setelah stream selesai tanpa krisis
+func (g *CrisisStreamGuard) Flush() string {
+	text := g.text.String()
+	rest := text[g.sent:]
+	g.sent = len(text)
+
+	return rest
+}
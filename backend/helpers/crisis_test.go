@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectCrisisLanguage(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    bool
+		keyword string
+	}{
+		{text: "Aku kepikiran BUNUH-DIRI terus", want: true, keyword: "bunuh diri"},
+		{text: "sometimes I want to die.", want: true, keyword: "want to die"},
+		{text: "Aku lagi sedih karena tugas kuliah", want: false},
+		{text: "the suicides squad movie", want: false},
+	}
+
+	for _, tt := range tests {
+		got, keyword := DetectCrisisLanguage(tt.text)
+		if got != tt.want || keyword != tt.keyword {
+			t.Errorf("DetectCrisisLanguage(%q) = %v, %q; want %v, %q", tt.text, got, keyword, tt.want, tt.keyword)
+		}
+	}
+}
+
+func TestCrisisStreamGuard(t *testing.T) {
+	tests := []struct {
+		name       string
+		deltas     []string
+		wantCrisis bool
+		keyword    string
+	}{
+		{
+			name:   "safe reply is released completely",
+			deltas: []string{"Tarik ", "napas ", "pelan", "-pelan, ", "lalu ceritakan ", "apa yang ", "kamu rasakan."},
+		},
+		{
+			name:       "keyword split across deltas",
+			deltas:     []string{"Kadang ", "orang ", "berpikir ", "untuk ", "bun", "uh ", "di", "ri ", "karena ", "lelah"},
+			wantCrisis: true,
+			keyword:    "bunuh diri",
+		},
+		{
+			name:       "longest keyword one word per delta",
+			deltas:     []string{"Dia ", "bilang ", "nggak ", "mau ", "hidup ", "lagi ", "sejak ", "kemarin"},
+			wantCrisis: true,
+			keyword:    "nggak mau hidup lagi",
+		},
+		{
+			name:       "keyword inside a single delta",
+			deltas:     []string{"Halo, ", "if you want to die please call someone"},
+			wantCrisis: true,
+			keyword:    "want to die",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				guard   CrisisStreamGuard
+				emitted strings.Builder
+				crisis  bool
+				keyword string
+			)
+			for _, delta := range tt.deltas {
+				safe, isCrisis, kw := guard.Write(delta)
+				if isCrisis {
+					crisis, keyword = true, kw
+					break
+				}
+				emitted.WriteString(safe)
+			}
+
+			if crisis != tt.wantCrisis || keyword != tt.keyword {
+				t.Fatalf("crisis = %v, %q; want %v, %q", crisis, keyword, tt.wantCrisis, tt.keyword)
+			}
+
+			if !tt.wantCrisis {
+				emitted.WriteString(guard.Flush())
+				if emitted.String() != strings.Join(tt.deltas, "") {
+					t.Errorf("released %q, want %q", emitted.String(), strings.Join(tt.deltas, ""))
+				}
+				return
+			}
+
+			// tidak ada satu kata pun dari frasa krisis yang sempat dilepas
+			normalized := normalizeCrisisText(emitted.String())
+			for _, word := range strings.Fields(normalizeCrisisText(tt.keyword)) {
+				if strings.Contains(normalized, " "+word) {
+					t.Errorf("released text %q already contains %q from %q", emitted.String(), word, tt.keyword)
+				}
+			}
+		})
+	}
+}
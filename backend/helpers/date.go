@@ -27,3 +27,12 @@ func ValidateAndNormalizeDateString(input string) (string, error) {
 
 	return parsedTime.Format(layoutDate), nil
 }
+
+// ParseSessionStart menggabungkan tanggal consultation dan jam mulai slot
+func ParseSessionStart(date string, clock string, loc *time.Location) (time.Time, error) {
+	if clock == "" {
+		return time.ParseInLocation(layoutDate, date, loc)
+	}
+
+	return time.ParseInLocation(layoutDate+" "+layoutClock, date+" "+clock, loc)
+}
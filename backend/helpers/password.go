@@ -1,15 +1,29 @@
 package helpers
 
 import (
+	"database/sql/driver"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string) (string, error) {
+// PasswordHash adalah hash bcrypt yang tersimpan di database. Nilainya selalu
+// diserialisasi sebagai null sehingga hash tidak mungkin bocor lewat response JSON,
+// sedangkan unmarshal tetap menerima string agar seed JSON bisa mengisi password.
+type PasswordHash string
+
+func (p PasswordHash) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+func (p PasswordHash) Value() (driver.Value, error) {
+	return string(p), nil
+}
+
+func HashPassword(password string) (PasswordHash, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 4)
-	return string(bytes), err
+	return PasswordHash(bytes), err
 }
 
-func CheckPassword(hashPassword string, plainPassword []byte) (bool, error) {
+func CheckPassword(hashPassword PasswordHash, plainPassword []byte) (bool, error) {
 	hashP := []byte(hashPassword)
 	if err := bcrypt.CompareHashAndPassword(hashP, plainPassword); err != nil {
 		return false, err
@@ -0,0 +1,47 @@
+package helpers
+
+import "strings"
+
+var permissionMethods = map[string]bool{
+	"*": true, "GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+}
+
+// IsValidPermissionMethod menerima method HTTP atau "*" untuk semua method
+func IsValidPermissionMethod(method string) bool {
+	return permissionMethods[strings.ToUpper(method)]
+}
+
+// IsValidPermissionEndpoint menerima path route gin (misal /api/v1/user/get-detail-news/:id),
+// grup "/api/v1/user/*", atau "*" untuk semua endpoint
+func IsValidPermissionEndpoint(endpoint string) bool {
+	if endpoint == "*" {
+		return true
+	}
+
+	if !strings.HasPrefix(endpoint, "/") {
+		return false
+	}
+
+	// wildcard hanya boleh di akhir sebagai penanda grup
+	trimmed := strings.TrimSuffix(endpoint, "/*")
+	return !strings.Contains(trimmed, "*")
+}
+
+// MatchPermission mengecek apakah permission (method + endpoint) mengizinkan request.
+// Method kosong atau "*" berarti semua method, endpoint berakhiran "/*" berarti
+// semua route di bawah prefix tersebut.
+func MatchPermission(permMethod string, permEndpoint string, method string, path string) bool {
+	if permMethod != "" && permMethod != "*" && !strings.EqualFold(permMethod, method) {
+		return false
+	}
+
+	if permEndpoint == "*" {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(permEndpoint, "/*"); ok {
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	}
+
+	return permEndpoint == path
+}
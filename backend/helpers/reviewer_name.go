@@ -0,0 +1,20 @@
+package helpers
+
+import "strings"
+
+const anonymousReviewerName = "Anonim"
+
+// MaskReviewerName menyamarkan nama pemberi review, contoh "Budi Santoso" menjadi "B*** S***"
+func MaskReviewerName(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return anonymousReviewerName
+	}
+
+	for i, word := range words {
+		runes := []rune(word)
+		words[i] = string(runes[0]) + "***"
+	}
+
+	return strings.Join(words, " ")
+}
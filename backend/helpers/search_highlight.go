@@ -0,0 +1,19 @@
+package helpers
+
+import (
+	"html"
+	"strings"
+)
+
+// penanda sementara dari ts_headline, diganti setelah snippet di-escape agar isi konten tidak dianggap HTML
+const (
+	SearchHighlightStart = "\x01"
+	SearchHighlightStop  = "\x02"
+)
+
+var searchHighlightReplacer = strings.NewReplacer(SearchHighlightStart, "<mark>", SearchHighlightStop, "</mark>")
+
+// HighlightSnippet meng-escape snippet hasil pencarian lalu menandai kata yang cocok dengan <mark>
+func HighlightSnippet(snippet string) string {
+	return searchHighlightReplacer.Replace(html.EscapeString(snippet))
+}
@@ -0,0 +1,116 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const layoutClock = "15:04"
+
+type TimeRange struct {
+	Start string
+	End   string
+}
+
+func IsValidDayName(day string) bool {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if d.String() == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ClockToMinutes(clock string) (int, error) {
+	t, err := time.Parse(layoutClock, clock)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func MinutesToClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// SplitSessions memecah rentang start-end menjadi sesi berdurasi sessionMinutes
+// dengan jeda bufferMinutes di antara sesi. Sesi yang melewati end dibuang.
+func SplitSessions(start, end string, sessionMinutes, bufferMinutes int) ([]TimeRange, error) {
+	if sessionMinutes <= 0 || bufferMinutes < 0 {
+		return nil, errors.New("invalid session or buffer duration")
+	}
+
+	startMin, err := ClockToMinutes(start)
+	if err != nil {
+		return nil, err
+	}
+
+	endMin, err := ClockToMinutes(end)
+	if err != nil {
+		return nil, err
+	}
+
+	if startMin >= endMin {
+		return nil, errors.New("start must be before end")
+	}
+
+	var sessions []TimeRange
+	for cur := startMin; cur+sessionMinutes <= endMin; cur += sessionMinutes + bufferMinutes {
+		sessions = append(sessions, TimeRange{
+			Start: MinutesToClock(cur),
+			End:   MinutesToClock(cur + sessionMinutes),
+		})
+	}
+
+	return sessions, nil
+}
+
+func IsTimeRangeOverlap(a, b TimeRange) bool {
+	aStart, err := ClockToMinutes(a.Start)
+	if err != nil {
+		return false
+	}
+	aEnd, err := ClockToMinutes(a.End)
+	if err != nil {
+		return false
+	}
+	bStart, err := ClockToMinutes(b.Start)
+	if err != nil {
+		return false
+	}
+	bEnd, err := ClockToMinutes(b.End)
+	if err != nil {
+		return false
+	}
+
+	return aStart < bEnd && bStart < aEnd
+}
+
+func IsTimeRangeWithin(inner, outer TimeRange) bool {
+	innerStart, err := ClockToMinutes(inner.Start)
+	if err != nil {
+		return false
+	}
+	innerEnd, err := ClockToMinutes(inner.End)
+	if err != nil {
+		return false
+	}
+	outerStart, err := ClockToMinutes(outer.Start)
+	if err != nil {
+		return false
+	}
+	outerEnd, err := ClockToMinutes(outer.End)
+	if err != nil {
+		return false
+	}
+
+	return innerStart >= outerStart && innerEnd <= outerEnd
+}
+
+// slot yang masih ditahan untuk pembayaran dianggap kosong lagi setelah batas hold lewat
+func IsSlotHoldExpired(heldUntil *time.Time, now time.Time) bool {
+	return heldUntil != nil && !heldUntil.After(now)
+}
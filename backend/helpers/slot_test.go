@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSessions(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		session int
+		buffer  int
+		want    []TimeRange
+		wantErr bool
+	}{
+		{
+			name:    "sessions fill the window exactly",
+			start:   "08:00",
+			end:     "11:00",
+			session: 60,
+			want:    []TimeRange{{"08:00", "09:00"}, {"09:00", "10:00"}, {"10:00", "11:00"}},
+		},
+		{
+			name:    "buffer between sessions",
+			start:   "09:00",
+			end:     "11:00",
+			session: 50,
+			buffer:  10,
+			want:    []TimeRange{{"09:00", "09:50"}, {"10:00", "10:50"}},
+		},
+		{
+			name:    "session running past end is dropped",
+			start:   "13:00",
+			end:     "15:30",
+			session: 60,
+			buffer:  15,
+			want:    []TimeRange{{"13:00", "14:00"}, {"14:15", "15:15"}},
+		},
+		{
+			name:    "last session ends exactly at end after buffer",
+			start:   "13:00",
+			end:     "15:00",
+			session: 45,
+			buffer:  30,
+			want:    []TimeRange{{"13:00", "13:45"}, {"14:15", "15:00"}},
+		},
+		{
+			name:    "window shorter than one session",
+			start:   "10:00",
+			end:     "10:30",
+			session: 45,
+			want:    nil,
+		},
+		{
+			name:    "non positive session",
+			start:   "08:00",
+			end:     "11:00",
+			session: 0,
+			wantErr: true,
+		},
+		{
+			name:    "negative buffer",
+			start:   "08:00",
+			end:     "11:00",
+			session: 60,
+			buffer:  -5,
+			wantErr: true,
+		},
+		{
+			name:    "start after end",
+			start:   "11:00",
+			end:     "08:00",
+			session: 60,
+			wantErr: true,
+		},
+		{
+			name:    "start equals end",
+			start:   "08:00",
+			end:     "08:00",
+			session: 60,
+			wantErr: true,
+		},
+		{
+			name:    "invalid clock",
+			start:   "8 pagi",
+			end:     "11:00",
+			session: 60,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitSessions(tt.start, tt.end, tt.session, tt.buffer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitSessions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitSessions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"os"
 
 	"github.com/Reyysusanto/warasin-web/backend/cmd"
+	"github.com/Reyysusanto/warasin-web/backend/config"
 	"github.com/Reyysusanto/warasin-web/backend/config/database"
 	"github.com/Reyysusanto/warasin-web/backend/handler"
 	"github.com/Reyysusanto/warasin-web/backend/middleware"
 	"github.com/Reyysusanto/warasin-web/backend/repository"
 	"github.com/Reyysusanto/warasin-web/backend/routes"
 	"github.com/Reyysusanto/warasin-web/backend/service"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,37 +20,101 @@ func main() {
 	db := database.SetUpPostgreSQLConnection()
 	defer database.ClosePostgreSQLConnection(db)
 
+	// serializer enkripsi harus terpasang sebelum command (seed, reencrypt) maupun server jalan
+	encryptionConfig, err := config.NewEncryptionConfig()
+	if err != nil {
+		log.Fatalf("error loading encryption config: %v", err)
+	}
+	utils.RegisterEncryptedSerializer(encryptionConfig)
+
 	if len(os.Args) > 1 {
 		cmd.Command(db)
 		return
 	}
 
+	llmConfig, err := config.NewLLMConfig()
+	if err != nil {
+		log.Fatalf("error loading llm config: %v", err)
+	}
+
+	jwtConfig, err := config.NewJWTConfig()
+	if err != nil {
+		log.Fatalf("error loading jwt config: %v", err)
+	}
+
+	jwtService, err := service.NewJWTService(jwtConfig)
+	if err != nil {
+		log.Fatalf("error loading jwt keys: %v", err)
+	}
+
+	chatLimitConfig, err := config.NewChatLimitConfig()
+	if err != nil {
+		log.Fatalf("error loading chat limit config: %v", err)
+	}
+
+	loginGuardConfig, err := config.NewLoginGuardConfig()
+	if err != nil {
+		log.Fatalf("error loading login guard config: %v", err)
+	}
+
+	paymentConfig, err := config.NewPaymentConfig()
+	if err != nil {
+		log.Fatalf("error loading payment config: %v", err)
+	}
+
+	paymentGateway, err := utils.NewPaymentGateway(paymentConfig)
+	if err != nil {
+		log.Fatalf("error loading payment gateway: %v", err)
+	}
+
+	cancellationConfig, err := config.NewCancellationConfig()
+	if err != nil {
+		log.Fatalf("error loading cancellation config: %v", err)
+	}
+
 	var (
-		jwtService = service.NewJWTService()
+		refreshTokenRepo    = repository.NewRefreshTokenRepository(db)
+		refreshTokenService = service.NewRefreshTokenService(refreshTokenRepo)
+		oneTimeTokenRepo    = repository.NewOneTimeTokenRepository(db)
+		loginAttemptRepo    = repository.NewLoginAttemptRepository(db)
+		loginGuardService   = service.NewLoginGuardService(loginAttemptRepo, loginGuardConfig)
+		oneTimeTokenService = service.NewOneTimeTokenService(oneTimeTokenRepo)
+		twoFactorRepo       = repository.NewTwoFactorRepository(db)
+		twoFactorService    = service.NewTwoFactorService(twoFactorRepo)
+		llmClient           = utils.NewLLMClient(llmConfig)
+		chatBuilder         = utils.NewChatHistoryBuilder(utils.NewApproxTokenizer(), llmConfig.ContextMaxTokens, llmConfig.ContextRecentMessages)
+		paymentRepo         = repository.NewPaymentRepository(db)
+		consultationRepo    = repository.NewConsultationRepository(db)
+		paymentService      = service.NewPaymentService(paymentRepo, consultationRepo, paymentGateway, paymentConfig)
+		cancellationService = service.NewCancellationService(paymentRepo, paymentGateway, cancellationConfig)
 
 		masterRepo    = repository.NewMasterRepository(db)
-		masterService = service.NewMasterService(masterRepo, jwtService)
+		rbacService   = service.NewRBACService(masterRepo)
+		masterService = service.NewMasterService(masterRepo, jwtService, loginGuardService, paymentService)
 		masterHandler = handler.NewMasterHandler(masterService)
 
-		userRepo    = repository.NewUserRepository(db)
-		userService = service.NewUserService(userRepo, masterRepo, jwtService)
-		userHandler = handler.NewUserHandler(userService, masterService)
+		userRepo         = repository.NewUserRepository(db)
+		chatRateLimiter  = utils.NewInMemoryRateLimiter(chatLimitConfig.RatePerMinute, chatLimitConfig.Burst)
+		chatQuotaService = service.NewChatQuotaService(userRepo, chatLimitConfig)
+		userService      = service.NewUserService(userRepo, consultationRepo, masterRepo, jwtService, llmClient, chatBuilder, chatQuotaService, refreshTokenService, loginGuardService, oneTimeTokenService, paymentService, cancellationService)
+		userHandler      = handler.NewUserHandler(userService, masterService)
 
 		adminRepo    = repository.NewAdminRepository(db)
-		adminService = service.NewAdminService(adminRepo, masterRepo, jwtService)
+		adminService = service.NewAdminService(adminRepo, masterRepo, jwtService, refreshTokenService, loginGuardService, rbacService, twoFactorService)
 		adminHandler = handler.NewAdminHandler(adminService, masterService)
 
-		psyRepo    = repository.NewPsychologRepository(db)
-		psyService = service.NewPsychologService(psyRepo, masterRepo, jwtService)
-		psyHandler = handler.NewPsychologHandler(psyService, masterService)
+		psyRepo       = repository.NewPsychologRepository(db)
+		slotGenerator = service.NewSlotGenerator(psyRepo)
+		psyService    = service.NewPsychologService(psyRepo, consultationRepo, masterRepo, jwtService, slotGenerator, refreshTokenService, loginGuardService, twoFactorService, cancellationService)
+		psyHandler    = handler.NewPsychologHandler(psyService, masterService)
 	)
 
 	server := gin.Default()
 	server.Use(middleware.CORSMiddleware())
 
-	routes.User(server, userHandler, masterHandler, jwtService)
-	routes.Admin(server, adminHandler, masterHandler, jwtService)
-	routes.Psycholog(server, psyHandler, masterHandler, jwtService)
+	routes.User(server, userHandler, masterHandler, jwtService, rbacService, chatRateLimiter, chatQuotaService)
+	routes.Admin(server, adminHandler, masterHandler, jwtService, rbacService)
+	routes.Psycholog(server, psyHandler, masterHandler, jwtService, rbacService)
 	routes.Master(server, masterHandler, jwtService)
 
 	server.Static("/assets", "./assets")
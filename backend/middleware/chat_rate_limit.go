@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/service"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ChatRateLimit membatasi request chat per user (token bucket) dan menolak request
+// jika kuota harian sudah habis. Dipasang setelah Authentication karena butuh user_id.
+func ChatRateLimit(limiter utils.RateLimiter, quotaService service.IChatQuotaService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID := ctx.GetString("user_id")
+		if userID == "" {
+			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_TOKEN_NOT_FOUND, nil)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		}
+
+		allowed, retryAfter := limiter.Allow(userID)
+		if !allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CHAT_RATE_LIMITED, dto.ErrChatRateLimited.Error(), gin.H{
+				"retry_after_seconds": int(retryAfter / time.Second),
+			})
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, res)
+			return
+		}
+
+		status, err := quotaService.CheckQuota(ctx.Request.Context(), userID)
+		if errors.Is(err, dto.ErrGetChatUsage) {
+			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		}
+
+		ctx.Header("X-Chat-Messages-Remaining", strconv.Itoa(status.MessagesRemaining))
+		ctx.Header("X-Chat-Tokens-Remaining", strconv.Itoa(status.TokensRemaining))
+
+		if err != nil {
+			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CHAT_QUOTA_EXCEEDED, err.Error(), status)
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, res)
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func RouteAccessControl(jwtService service.IJWTService) gin.HandlerFunc {
+// RouteAccessControl mengecek role dari token terhadap permission role tersebut di
+// database (method + path route), sehingga perubahan permission langsung berlaku.
+func RouteAccessControl(jwtService service.IJWTService, rbacService service.IRBACService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -47,31 +49,18 @@ func RouteAccessControl(jwtService service.IJWTService) gin.HandlerFunc {
 			return
 		}
 
-		requestedPath := ctx.FullPath()
-		rawAllowed, ok := claims["endpoints"].([]interface{})
-		if !ok {
-			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_INAVLID_ENPOINTS_TOKEN, nil)
-			ctx.AbortWithStatusJSON(http.StatusForbidden, res)
+		roleID, ok := claims["role_id"].(string)
+		if !ok || roleID == "" {
+			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_TOKEN_NOT_VALID, nil)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
 			return
 		}
 
-		allowedPath := make([]string, len(rawAllowed))
-		for i, v := range rawAllowed {
-			strVal, ok := v.(string)
-			if !ok {
-				res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_INAVLID_ROUTE_FORMAT_TOKEN, nil)
-				ctx.AbortWithStatusJSON(http.StatusForbidden, res)
-				return
-			}
-			allowedPath[i] = strVal
-		}
-
-		hasAccess := false
-		for _, route := range allowedPath {
-			if route == requestedPath {
-				hasAccess = true
-				break
-			}
+		hasAccess, err := rbacService.HasAccess(ctx.Request.Context(), roleID, ctx.Request.Method, ctx.FullPath())
+		if err != nil {
+			res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrGetPermissionsByRoleID.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
 		}
 
 		if !hasAccess {
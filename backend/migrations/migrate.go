@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/entity"
 
 	"gorm.io/gorm"
@@ -15,7 +16,13 @@ func Migrate(db *gorm.DB) error {
 		&entity.User{},
 		&entity.Psycholog{},
 		&entity.Consultation{},
+		&entity.ConsultationStatusHistory{},
+		&entity.Payment{},
+		&entity.PaymentWebhookEvent{},
+		&entity.Refund{},
 		&entity.Education{},
+		&entity.PsychologDocument{},
+		&entity.PsychologChangeRequest{},
 
 		&entity.MotivationCategory{},
 		&entity.Motivation{},
@@ -33,13 +40,44 @@ func Migrate(db *gorm.DB) error {
 
 		&entity.Practice{},
 		&entity.PracticeSchedule{},
+		&entity.SlotTemplate{},
 		&entity.AvailableSlot{},
 
 		&entity.Conversation{},
 		&entity.Message{},
+		&entity.ChatUsage{},
+
+		&entity.RefreshToken{},
+		&entity.OneTimeToken{},
+		&entity.LoginAttempt{},
+		&entity.TwoFactor{},
+		&entity.TwoFactorRecoveryCode{},
+		&entity.TwoFactorChallenge{},
 	); err != nil {
 		return err
 	}
 
+	if err := migrateSearchVectors(db); err != nil {
+		return err
+	}
+
+	// isi agregat rating dari consultation lama yang sudah memiliki rate
+	if err := db.Exec(`
+		UPDATE psychologs SET
+			rating_average = agg.average,
+			rating_count = agg.total
+		FROM (
+			SELECT available_slots.psycholog_id, ROUND(AVG(consultations.rate)::numeric, 2) AS average, COUNT(consultations.id) AS total
+			FROM consultations
+			JOIN available_slots ON available_slots.id = consultations.available_slot_id
+			WHERE consultations.status = ? AND consultations.rate > 0 AND consultations.deleted_at IS NULL
+			GROUP BY available_slots.psycholog_id
+		) AS agg
+		WHERE psychologs.id = agg.psycholog_id`,
+		constants.ENUM_CONSULTATION_STATUS_COMPLETED,
+	).Error; err != nil {
+		return err
+	}
+
 	return nil
 }
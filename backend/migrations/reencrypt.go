@@ -0,0 +1,159 @@
+package migrations
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const reencryptBatchSize = 500
+
+// Reencrypt mengenkripsi ulang semua field bertag serializer:encrypted dengan key
+// aktif. Dipakai setelah rotasi key dan untuk mengenkripsi baris lama yang masih plain text.
+func Reencrypt(db *gorm.DB) error {
+	models := []interface{}{
+		&entity.User{},
+		&entity.Consultation{},
+		&entity.Conversation{},
+		&entity.Message{},
+		&entity.TwoFactor{},
+	}
+
+	fc, err := utils.GetFieldCipher()
+	if err != nil {
+		return err
+	}
+
+	for _, model := range models {
+		total, err := reencryptModel(db, fc, model)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("reencrypt %T: %d row updated", model, total)
+	}
+
+	return nil
+}
+
+func reencryptModel(db *gorm.DB, fc *utils.FieldCipher, model interface{}) (int, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+
+	var fields []*schema.Field
+	for _, field := range stmt.Schema.Fields {
+		if field.TagSettings["SERIALIZER"] == "encrypted" {
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == 0 {
+		return 0, nil
+	}
+
+	columns := []string{"id"}
+	for _, field := range fields {
+		columns = append(columns, field.DBName)
+	}
+
+	// baca dan tulis nilai mentah lewat Table() agar tidak melewati serializer
+	total := 0
+	lastID := ""
+	for {
+		rows, err := db.Table(stmt.Schema.Table).
+			Select(columns).
+			Where("id::text > ?", lastID).
+			Order("id::text").
+			Limit(reencryptBatchSize).
+			Rows()
+		if err != nil {
+			return total, err
+		}
+
+		type rawRow struct {
+			id     string
+			values []sql.NullString
+		}
+
+		var batch []rawRow
+		for rows.Next() {
+			row := rawRow{values: make([]sql.NullString, len(fields))}
+			dest := []interface{}{&row.id}
+			for i := range row.values {
+				dest = append(dest, &row.values[i])
+			}
+
+			if err := rows.Scan(dest...); err != nil {
+				rows.Close()
+				return total, err
+			}
+
+			batch = append(batch, row)
+		}
+		rows.Close()
+
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		for _, row := range batch {
+			lastID = row.id
+
+			updates := map[string]interface{}{}
+			for i, field := range fields {
+				raw := row.values[i].String
+				if raw == "" || fc.IsActiveKey(raw) {
+					continue
+				}
+
+				plaintext, err := reencryptPlaintext(fc, field, raw)
+				if err != nil {
+					return total, fmt.Errorf("%s.%s id %s: %w", stmt.Schema.Table, field.DBName, row.id, err)
+				}
+
+				encrypted, err := fc.Encrypt(plaintext)
+				if err != nil {
+					return total, err
+				}
+
+				updates[field.DBName] = encrypted
+			}
+
+			if len(updates) == 0 {
+				continue
+			}
+
+			if err := db.Table(stmt.Schema.Table).Where("id = ?", row.id).UpdateColumns(updates).Error; err != nil {
+				return total, err
+			}
+			total++
+		}
+	}
+}
+
+// reencryptPlaintext mengembalikan isi field dalam bentuk JSON, sama seperti yang
+// ditulis EncryptedSerializer
+func reencryptPlaintext(fc *utils.FieldCipher, field *schema.Field, raw string) ([]byte, error) {
+	if utils.IsEncryptedValue(raw) {
+		return fc.Decrypt(raw)
+	}
+
+	if field.FieldType.Kind() == reflect.String {
+		return json.Marshal(raw)
+	}
+
+	if !json.Valid([]byte(raw)) {
+		return nil, fmt.Errorf("plain text value is not valid for %s", field.FieldType)
+	}
+
+	return []byte(raw), nil
+}
@@ -7,10 +7,19 @@ import (
 
 func Rollback(db *gorm.DB) error {
 	tables := []interface{}{
+		&entity.RefreshToken{},
+		&entity.OneTimeToken{},
+		&entity.LoginAttempt{},
+		&entity.TwoFactorRecoveryCode{},
+		&entity.TwoFactor{},
+		&entity.TwoFactorChallenge{},
+
+		&entity.ChatUsage{},
 		&entity.Conversation{},
 		&entity.Message{},
 
 		&entity.AvailableSlot{},
+		&entity.SlotTemplate{},
 		&entity.PracticeSchedule{},
 		&entity.Practice{},
 
@@ -28,7 +37,13 @@ func Rollback(db *gorm.DB) error {
 		&entity.Motivation{},
 		&entity.MotivationCategory{},
 
+		&entity.PsychologChangeRequest{},
+		&entity.PsychologDocument{},
 		&entity.Education{},
+		&entity.ConsultationStatusHistory{},
+		&entity.Refund{},
+		&entity.PaymentWebhookEvent{},
+		&entity.Payment{},
 		&entity.Consultation{},
 		&entity.Psycholog{},
 		&entity.User{},
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"gorm.io/gorm"
+)
+
+// kolom search_vector per tabel: kolom sumber beserta bobotnya (A paling relevan)
+var searchVectorSources = []struct {
+	table   string
+	columns [][2]string
+}{
+	{table: "news", columns: [][2]string{{"title", "A"}, {"body", "B"}}},
+	{table: "motivations", columns: [][2]string{{"content", "A"}, {"author", "B"}}},
+	{table: "psychologs", columns: [][2]string{{"name", "A"}, {"description", "B"}}},
+}
+
+// migrateSearchVectors menambahkan kolom tsvector hasil generate beserta index GIN.
+// Kolom STORED langsung dihitung untuk baris yang sudah ada sehingga tidak perlu backfill manual.
+func migrateSearchVectors(db *gorm.DB) error {
+	for _, source := range searchVectorSources {
+		expr := ""
+		for i, column := range source.columns {
+			if i > 0 {
+				expr += " || "
+			}
+			expr += fmt.Sprintf("setweight(to_tsvector('%s', coalesce(%s, '')), '%s')", constants.ENUM_SEARCH_TEXT_CONFIG, column[0], column[1])
+		}
+
+		statements := []string{
+			fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (%s) STORED", source.table, expr),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_search_vector ON %s USING GIN (search_vector)", source.table, source.table),
+		}
+
+		for _, statement := range statements {
+			if err := db.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
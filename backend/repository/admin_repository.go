@@ -4,7 +4,9 @@ import (
 	"context"
 	"math"
 	"strings"
+	"time"
 
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/entity"
 	"github.com/google/uuid"
@@ -13,11 +15,13 @@ import (
 
 type (
 	IAdminRepository interface {
+		// Transaction
+		RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
 		// Get
 		GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error)
 		GetUserByID(ctx context.Context, tx *gorm.DB, userID string) (entity.User, error)
 		GetRoleByID(ctx context.Context, tx *gorm.DB, roleID string) (entity.Role, error)
-		GetPermissionsByRoleID(ctx context.Context, tx *gorm.DB, roleID string) ([]string, error)
 		GetAllUserWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllUserRepositoryResponse, error)
 		GetAllNewsWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllNewsRepositoryResponse, error)
 		GetNewsByID(ctx context.Context, tx *gorm.DB, newsID string) (entity.News, error)
@@ -29,6 +33,9 @@ type (
 		GetMotivationByID(ctx context.Context, tx *gorm.DB, motivationID string) (entity.Motivation, error)
 		GetMotivationByContent(ctx context.Context, tx *gorm.DB, content string) (bool, entity.Motivation, error)
 		GetAllRole(ctx context.Context, tx *gorm.DB) (dto.AllRoleRepositoryResponse, error)
+		GetRoleByName(ctx context.Context, tx *gorm.DB, name string) (entity.Role, bool, error)
+		GetAllPermission(ctx context.Context, tx *gorm.DB, roleID string) ([]entity.Permission, error)
+		GetPermissionByID(ctx context.Context, tx *gorm.DB, permissionID string) (entity.Permission, bool, error)
 		GetAllPsychologWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllPsychologRepositoryResponse, error)
 		GetAllUserMotivationWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllUserMotivationRepositoryResponse, error)
 		GetAllUserNewsWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllUserNewsRepositoryResponse, error)
@@ -37,6 +44,14 @@ type (
 		GetEducationByID(ctx context.Context, tx *gorm.DB, eduID string) (entity.Education, bool, error)
 		GetAllLanguageMaster(ctx context.Context, tx *gorm.DB) (dto.AllLanguageMasterRepositoryResponse, error)
 		GetAllSpecialization(ctx context.Context, tx *gorm.DB) (dto.AllSpecializationRepositoryResponse, error)
+		GetAllFlaggedConversationWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllFlaggedConversationRepositoryResponse, error)
+		GetAllPsychologApplicationWithPagination(ctx context.Context, tx *gorm.DB, req dto.PsychologApplicationPaginationRequest) (dto.AllPsychologRepositoryResponse, error)
+		GetPsychologApplicationByID(ctx context.Context, tx *gorm.DB, psychologID string) (entity.Psycholog, bool, error)
+		GetPsychologDocumentByID(ctx context.Context, tx *gorm.DB, documentID string) (entity.PsychologDocument, bool, error)
+		GetAllPsychologChangeRequestWithPagination(ctx context.Context, tx *gorm.DB, req dto.PsychologChangeRequestPaginationRequest) (dto.AllPsychologChangeRequestRepositoryResponse, error)
+		GetPsychologChangeRequestByID(ctx context.Context, tx *gorm.DB, changeRequestID string) (entity.PsychologChangeRequest, bool, error)
+		GetAllReviewWithPagination(ctx context.Context, tx *gorm.DB, req dto.ReviewModerationPaginationRequest) (dto.AllReviewRepositoryResponse, error)
+		GetReviewByID(ctx context.Context, tx *gorm.DB, consulID string) (entity.Consultation, bool, error)
 
 		// Create
 		CreateUser(ctx context.Context, tx *gorm.DB, user entity.User) error
@@ -47,6 +62,8 @@ type (
 		CreatePsychologLanguages(ctx context.Context, tx *gorm.DB, psychologLanguages []entity.PsychologLanguage) error
 		CreatePsychologSpecializations(ctx context.Context, tx *gorm.DB, psychologSpecializations []entity.PsychologSpecialization) error
 		CreateEducations(ctx context.Context, tx *gorm.DB, educations []entity.Education) error
+		CreateRole(ctx context.Context, tx *gorm.DB, role entity.Role) error
+		CreatePermission(ctx context.Context, tx *gorm.DB, permission entity.Permission) error
 
 		// Update
 		UpdateUser(ctx context.Context, tx *gorm.DB, user entity.User) error
@@ -54,6 +71,12 @@ type (
 		UpdateMotivationCategory(ctx context.Context, tx *gorm.DB, motivationCategory entity.MotivationCategory) error
 		UpdateMotivation(ctx context.Context, tx *gorm.DB, motivation entity.Motivation) error
 		UpdatePsycholog(ctx context.Context, tx *gorm.DB, psycholog entity.Psycholog) error
+		UpdateRole(ctx context.Context, tx *gorm.DB, role entity.Role) error
+		UpdatePermission(ctx context.Context, tx *gorm.DB, permission entity.Permission) error
+		UpdatePsychologReview(ctx context.Context, tx *gorm.DB, psychologID uuid.UUID, fromStatus string, toStatus string, note string, reviewerID uuid.UUID) (bool, error)
+		UpdatePsychologChangeRequestReview(ctx context.Context, tx *gorm.DB, changeRequestID uuid.UUID, toStatus string, note string, reviewerID uuid.UUID) (bool, error)
+		UpdatePsychologSensitiveField(ctx context.Context, tx *gorm.DB, psychologID uuid.UUID, field string, value string) error
+		UpdateReviewModeration(ctx context.Context, tx *gorm.DB, consulID uuid.UUID, hidden bool, note string, moderatorID uuid.UUID) error
 
 		// Delete
 		DeleteUserByID(ctx context.Context, tx *gorm.DB, userID string) error
@@ -64,6 +87,9 @@ type (
 		DeletePsychologLanguageByPsychologID(ctx context.Context, tx *gorm.DB, psychologID string) error
 		DeletePsychologSpecializationByPsychologID(ctx context.Context, tx *gorm.DB, psychologID string) error
 		DeleteEducationByPsychologID(ctx context.Context, tx *gorm.DB, psychologID string) error
+		DeleteRoleByID(ctx context.Context, tx *gorm.DB, roleID string) error
+		DeletePermissionByID(ctx context.Context, tx *gorm.DB, permissionID string) error
+		DeletePermissionByRoleID(ctx context.Context, tx *gorm.DB, roleID string) error
 	}
 
 	AdminRepository struct {
@@ -77,6 +103,11 @@ func NewAdminRepository(db *gorm.DB) *AdminRepository {
 	}
 }
 
+// Transaction
+func (ar *AdminRepository) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return ar.db.WithContext(ctx).Transaction(fn)
+}
+
 // Get
 func (ar *AdminRepository) GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error) {
 	if tx == nil {
@@ -114,18 +145,6 @@ func (ar *AdminRepository) GetRoleByID(ctx context.Context, tx *gorm.DB, roleID
 
 	return role, nil
 }
-func (ar *AdminRepository) GetPermissionsByRoleID(ctx context.Context, tx *gorm.DB, roleID string) ([]string, error) {
-	if tx == nil {
-		tx = ar.db
-	}
-
-	var endpoints []string
-	if err := tx.WithContext(ctx).Table("permissions").Where("role_id = ?", roleID).Pluck("endpoint", &endpoints).Error; err != nil {
-		return []string{}, err
-	}
-
-	return endpoints, nil
-}
 func (ar *AdminRepository) GetAllUserWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllUserRepositoryResponse, error) {
 	if tx == nil {
 		tx = ar.db
@@ -151,9 +170,9 @@ func (ar *AdminRepository) GetAllUserWithPagination(ctx context.Context, tx *gor
 	query := tx.WithContext(ctx).Model(&entity.User{}).Where("role_id IN (?)", adminIDs)
 
 	if req.Search != "" {
+		// phone_number terenkripsi sehingga tidak bisa dicari dengan LIKE
 		searchValue := "%" + strings.ToLower(req.Search) + "%"
-		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ? OR LOWER(phone_number) LIKE ?",
-			searchValue, searchValue, searchValue)
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", searchValue, searchValue)
 	}
 
 	query = query.Preload("City.Province").Preload("Role")
@@ -401,6 +420,47 @@ func (ar *AdminRepository) GetAllRole(ctx context.Context, tx *gorm.DB) (dto.All
 		Roles: roles,
 	}, err
 }
+func (ar *AdminRepository) GetRoleByName(ctx context.Context, tx *gorm.DB, name string) (entity.Role, bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var role entity.Role
+	if err := tx.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Take(&role).Error; err != nil {
+		return entity.Role{}, false, err
+	}
+
+	return role, true, nil
+}
+func (ar *AdminRepository) GetAllPermission(ctx context.Context, tx *gorm.DB, roleID string) ([]entity.Permission, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.Permission{})
+	if roleID != "" {
+		query = query.Where("role_id = ?", roleID)
+	}
+
+	var permissions []entity.Permission
+	if err := query.Order("endpoint ASC").Find(&permissions).Error; err != nil {
+		return []entity.Permission{}, err
+	}
+
+	return permissions, nil
+}
+func (ar *AdminRepository) GetPermissionByID(ctx context.Context, tx *gorm.DB, permissionID string) (entity.Permission, bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var permission entity.Permission
+	if err := tx.WithContext(ctx).Where("id = ?", permissionID).Take(&permission).Error; err != nil {
+		return entity.Permission{}, false, err
+	}
+
+	return permission, true, nil
+}
 func (ar *AdminRepository) GetAllPsychologWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllPsychologRepositoryResponse, error) {
 	if tx == nil {
 		tx = ar.db
@@ -423,7 +483,8 @@ func (ar *AdminRepository) GetAllPsychologWithPagination(ctx context.Context, tx
 		Preload("City.Province").
 		Preload("PsychologLanguages.LanguageMaster").
 		Preload("PsychologSpecializations.Specialization").
-		Preload("Educations")
+		Preload("Educations").
+		Where("status = ?", constants.ENUM_PSYCHOLOG_STATUS_APPROVED)
 
 	if req.Search != "" {
 		searchValue := "%" + strings.ToLower(req.Search) + "%"
@@ -615,6 +676,279 @@ func (ar *AdminRepository) GetAllSpecialization(ctx context.Context, tx *gorm.DB
 		Specializations: specializations,
 	}, nil
 }
+func (ar *AdminRepository) GetAllFlaggedConversationWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllFlaggedConversationRepositoryResponse, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var (
+		conversations []entity.Conversation
+		err           error
+		count         int64
+	)
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.Conversation{}).
+		Where("is_flagged = ?", true).
+		Preload("User.Role").
+		Preload("User.City.Province").
+		Preload("Messages", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		})
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.AllFlaggedConversationRepositoryResponse{}, err
+	}
+
+	if err := query.Order("flagged_at DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&conversations).Error; err != nil {
+		return dto.AllFlaggedConversationRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllFlaggedConversationRepositoryResponse{
+		Conversations: conversations,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+func (ar *AdminRepository) GetAllPsychologApplicationWithPagination(ctx context.Context, tx *gorm.DB, req dto.PsychologApplicationPaginationRequest) (dto.AllPsychologRepositoryResponse, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var psychologs []entity.Psycholog
+	var err error
+	var count int64
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.Psycholog{}).
+		Preload("Role").
+		Preload("City.Province").
+		Preload("PsychologLanguages.LanguageMaster").
+		Preload("PsychologSpecializations.Specialization").
+		Preload("Educations").
+		Preload("Documents")
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if req.Search != "" {
+		searchValue := "%" + strings.ToLower(req.Search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ? OR str_number LIKE ?", searchValue, searchValue, searchValue)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.AllPsychologRepositoryResponse{}, err
+	}
+
+	// aplikasi terlama tampil lebih dulu agar antrean review berjalan FIFO
+	if err := query.Order("created_at ASC").Scopes(Paginate(req.Page, req.PerPage)).Find(&psychologs).Error; err != nil {
+		return dto.AllPsychologRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllPsychologRepositoryResponse{
+		Psychologs: psychologs,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+func (ar *AdminRepository) GetPsychologApplicationByID(ctx context.Context, tx *gorm.DB, psychologID string) (entity.Psycholog, bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.Psycholog{}).
+		Preload("Role").
+		Preload("City.Province").
+		Preload("PsychologLanguages.LanguageMaster").
+		Preload("PsychologSpecializations.Specialization").
+		Preload("Educations").
+		Preload("Documents")
+
+	var psycholog entity.Psycholog
+	if err := query.Where("id = ?", psychologID).Take(&psycholog).Error; err != nil {
+		return entity.Psycholog{}, false, err
+	}
+
+	return psycholog, true, nil
+}
+func (ar *AdminRepository) GetPsychologDocumentByID(ctx context.Context, tx *gorm.DB, documentID string) (entity.PsychologDocument, bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var document entity.PsychologDocument
+	if err := tx.WithContext(ctx).Where("id = ?", documentID).Take(&document).Error; err != nil {
+		return entity.PsychologDocument{}, false, err
+	}
+
+	return document, true, nil
+}
+func (ar *AdminRepository) GetAllPsychologChangeRequestWithPagination(ctx context.Context, tx *gorm.DB, req dto.PsychologChangeRequestPaginationRequest) (dto.AllPsychologChangeRequestRepositoryResponse, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var changeRequests []entity.PsychologChangeRequest
+	var err error
+	var count int64
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.PsychologChangeRequest{}).
+		Preload("Psycholog")
+
+	if req.Status != "" {
+		query = query.Where("psycholog_change_requests.status = ?", req.Status)
+	}
+
+	if req.Search != "" {
+		searchValue := "%" + strings.ToLower(req.Search) + "%"
+		query = query.Joins("JOIN psychologs ON psychologs.id = psycholog_change_requests.psycholog_id").
+			Where("LOWER(psychologs.name) LIKE ? OR LOWER(psychologs.email) LIKE ?", searchValue, searchValue)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.AllPsychologChangeRequestRepositoryResponse{}, err
+	}
+
+	if err := query.Order("psycholog_change_requests.created_at ASC").Scopes(Paginate(req.Page, req.PerPage)).Find(&changeRequests).Error; err != nil {
+		return dto.AllPsychologChangeRequestRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllPsychologChangeRequestRepositoryResponse{
+		ChangeRequests: changeRequests,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+func (ar *AdminRepository) GetPsychologChangeRequestByID(ctx context.Context, tx *gorm.DB, changeRequestID string) (entity.PsychologChangeRequest, bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var changeRequest entity.PsychologChangeRequest
+	if err := tx.WithContext(ctx).Preload("Psycholog").Where("id = ?", changeRequestID).Take(&changeRequest).Error; err != nil {
+		return entity.PsychologChangeRequest{}, false, err
+	}
+
+	return changeRequest, true, nil
+}
+func (ar *AdminRepository) GetAllReviewWithPagination(ctx context.Context, tx *gorm.DB, req dto.ReviewModerationPaginationRequest) (dto.AllReviewRepositoryResponse, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var consultations []entity.Consultation
+	var err error
+	var count int64
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	// hanya consultation selesai yang memiliki comment yang perlu dimoderasi
+	query := tx.WithContext(ctx).Model(&entity.Consultation{}).
+		Preload("User").
+		Preload("AvailableSlot.Psycholog").
+		Where("consultations.status = ? AND consultations.rate > 0", constants.ENUM_CONSULTATION_STATUS_COMPLETED).
+		Where("COALESCE(consultations.comment, '') <> ''")
+
+	switch req.Status {
+	case constants.ENUM_REVIEW_STATUS_UNMODERATED:
+		query = query.Where("consultations.review_moderated_at IS NULL")
+	case constants.ENUM_REVIEW_STATUS_VISIBLE:
+		query = query.Where("consultations.review_moderated_at IS NOT NULL AND consultations.review_hidden = ?", false)
+	case constants.ENUM_REVIEW_STATUS_HIDDEN:
+		query = query.Where("consultations.review_hidden = ?", true)
+	}
+
+	if req.Search != "" {
+		searchValue := "%" + strings.ToLower(req.Search) + "%"
+		query = query.Joins("JOIN available_slots ON available_slots.id = consultations.available_slot_id").
+			Joins("JOIN psychologs ON psychologs.id = available_slots.psycholog_id").
+			Where("LOWER(psychologs.name) LIKE ?", searchValue)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.AllReviewRepositoryResponse{}, err
+	}
+
+	if err := query.Order("COALESCE(consultations.rated_at, consultations.updated_at) ASC").Scopes(Paginate(req.Page, req.PerPage)).Find(&consultations).Error; err != nil {
+		return dto.AllReviewRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllReviewRepositoryResponse{
+		Consultations: consultations,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+func (ar *AdminRepository) GetReviewByID(ctx context.Context, tx *gorm.DB, consulID string) (entity.Consultation, bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var consultation entity.Consultation
+	if err := tx.WithContext(ctx).
+		Preload("User").
+		Preload("AvailableSlot.Psycholog").
+		Where("id = ? AND status = ? AND rate > 0", consulID, constants.ENUM_CONSULTATION_STATUS_COMPLETED).
+		Where("COALESCE(comment, '') <> ''").
+		Take(&consultation).Error; err != nil {
+		return entity.Consultation{}, false, err
+	}
+
+	return consultation, true, nil
+}
 
 // Create
 func (ar *AdminRepository) CreateUser(ctx context.Context, tx *gorm.DB, user entity.User) error {
@@ -673,6 +1007,20 @@ func (ar *AdminRepository) CreateEducations(ctx context.Context, tx *gorm.DB, ed
 
 	return tx.WithContext(ctx).Create(educations).Error
 }
+func (ar *AdminRepository) CreateRole(ctx context.Context, tx *gorm.DB, role entity.Role) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Create(&role).Error
+}
+func (ar *AdminRepository) CreatePermission(ctx context.Context, tx *gorm.DB, permission entity.Permission) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Create(&permission).Error
+}
 
 // Update
 func (ar *AdminRepository) UpdateUser(ctx context.Context, tx *gorm.DB, user entity.User) error {
@@ -708,7 +1056,92 @@ func (ar *AdminRepository) UpdatePsycholog(ctx context.Context, tx *gorm.DB, psy
 		tx = ar.db
 	}
 
-	return tx.WithContext(ctx).Where("id = ?", psycholog.ID).Updates(&psycholog).Error
+	// agregat rating hanya diubah lewat RecalculatePsychologRating
+	return tx.WithContext(ctx).Where("id = ?", psycholog.ID).Omit("rating_average", "rating_count").Updates(&psycholog).Error
+}
+func (ar *AdminRepository) UpdateRole(ctx context.Context, tx *gorm.DB, role entity.Role) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", role.ID).Updates(&role).Error
+}
+func (ar *AdminRepository) UpdatePermission(ctx context.Context, tx *gorm.DB, permission entity.Permission) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", permission.ID).Updates(&permission).Error
+}
+
+// UpdatePsychologReview hanya mengubah status jika status saat ini masih fromStatus,
+// sehingga dua admin tidak bisa mereview aplikasi yang sama bersamaan.
+func (ar *AdminRepository) UpdatePsychologReview(ctx context.Context, tx *gorm.DB, psychologID uuid.UUID, fromStatus string, toStatus string, note string, reviewerID uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	now := time.Now()
+	result := tx.WithContext(ctx).
+		Model(&entity.Psycholog{}).
+		Where("id = ? AND status = ?", psychologID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":         toStatus,
+			"review_note":    note,
+			"reviewed_at":    &now,
+			"reviewed_by_id": reviewerID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+func (ar *AdminRepository) UpdatePsychologChangeRequestReview(ctx context.Context, tx *gorm.DB, changeRequestID uuid.UUID, toStatus string, note string, reviewerID uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	now := time.Now()
+	result := tx.WithContext(ctx).
+		Model(&entity.PsychologChangeRequest{}).
+		Where("id = ? AND status = ?", changeRequestID, constants.ENUM_CHANGE_REQUEST_STATUS_PENDING).
+		Updates(map[string]interface{}{
+			"status":         toStatus,
+			"review_note":    note,
+			"reviewed_at":    &now,
+			"reviewed_by_id": reviewerID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+func (ar *AdminRepository) UpdatePsychologSensitiveField(ctx context.Context, tx *gorm.DB, psychologID uuid.UUID, field string, value string) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.Psycholog{}).
+		Where("id = ?", psychologID).
+		Update(field, value).Error
+}
+func (ar *AdminRepository) UpdateReviewModeration(ctx context.Context, tx *gorm.DB, consulID uuid.UUID, hidden bool, note string, moderatorID uuid.UUID) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.Consultation{}).
+		Where("id = ?", consulID).
+		Updates(map[string]interface{}{
+			"review_hidden":          hidden,
+			"review_moderation_note": note,
+			"review_moderated_at":    time.Now(),
+			"review_moderated_by_id": moderatorID,
+		}).Error
 }
 
 // Delete
@@ -768,3 +1201,24 @@ func (ar *AdminRepository) DeleteEducationByPsychologID(ctx context.Context, tx
 
 	return tx.WithContext(ctx).Where("psycholog_id = ?", psychologID).Delete(&entity.Education{}).Error
 }
+func (ar *AdminRepository) DeleteRoleByID(ctx context.Context, tx *gorm.DB, roleID string) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", roleID).Delete(&entity.Role{}).Error
+}
+func (ar *AdminRepository) DeletePermissionByID(ctx context.Context, tx *gorm.DB, permissionID string) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", permissionID).Delete(&entity.Permission{}).Error
+}
+func (ar *AdminRepository) DeletePermissionByRoleID(ctx context.Context, tx *gorm.DB, roleID string) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Where("role_id = ?", roleID).Delete(&entity.Permission{}).Error
+}
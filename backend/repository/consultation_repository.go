@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// IConsultationRepository dipakai bersama oleh user, psycholog dan payment untuk
+	// perpindahan status consultation beserta riwayatnya
+	IConsultationRepository interface {
+		// Get
+		GetConsultationStatusHistory(ctx context.Context, tx *gorm.DB, consulID string) ([]entity.ConsultationStatusHistory, error)
+
+		// Create
+		CreateConsultationStatusHistory(ctx context.Context, tx *gorm.DB, history entity.ConsultationStatusHistory) error
+
+		// Update
+		UpdateConsultationStatus(ctx context.Context, tx *gorm.DB, consulID uuid.UUID, fromStatus int, toStatus int) (bool, error)
+	}
+
+	ConsultationRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewConsultationRepository(db *gorm.DB) *ConsultationRepository {
+	return &ConsultationRepository{
+		db: db,
+	}
+}
+
+// Get
+func (cr *ConsultationRepository) GetConsultationStatusHistory(ctx context.Context, tx *gorm.DB, consulID string) ([]entity.ConsultationStatusHistory, error) {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	var histories []entity.ConsultationStatusHistory
+	if err := tx.WithContext(ctx).Model(&entity.ConsultationStatusHistory{}).
+		Where("consultation_id = ?", consulID).
+		Order("created_at ASC").
+		Find(&histories).Error; err != nil {
+		return []entity.ConsultationStatusHistory{}, err
+	}
+
+	return histories, nil
+}
+
+// Create
+func (cr *ConsultationRepository) CreateConsultationStatusHistory(ctx context.Context, tx *gorm.DB, history entity.ConsultationStatusHistory) error {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	return tx.WithContext(ctx).Create(&history).Error
+}
+
+// Update
+
+// UpdateConsultationStatus hanya mengubah status jika status saat ini masih fromStatus.
+// Return false berarti status sudah diubah request lain lebih dulu.
+func (cr *ConsultationRepository) UpdateConsultationStatus(ctx context.Context, tx *gorm.DB, consulID uuid.UUID, fromStatus int, toStatus int) (bool, error) {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.Consultation{}).
+		Where("id = ? AND status = ?", consulID, fromStatus).
+		Update("status", toStatus)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	ILoginAttemptRepository interface {
+		// Get
+		GetLoginAttempt(ctx context.Context, tx *gorm.DB, scope string, roleName string, identifier string) (entity.LoginAttempt, bool, error)
+		GetLoginAttemptByID(ctx context.Context, tx *gorm.DB, attemptID string) (entity.LoginAttempt, bool, error)
+		GetLoginAttemptByUnlockTokenHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.LoginAttempt, bool, error)
+		GetAllLoginAttemptWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllLoginAttemptRepositoryResponse, error)
+
+		// Create
+		IncrementLoginAttempt(ctx context.Context, tx *gorm.DB, scope string, roleName string, identifier string, windowStart time.Time) (entity.LoginAttempt, error)
+
+		// Update
+		LockLoginAttempt(ctx context.Context, tx *gorm.DB, attemptID uuid.UUID, lockedUntil time.Time, unlockTokenHash string) error
+
+		// Delete
+		DeleteLoginAttempt(ctx context.Context, tx *gorm.DB, scope string, roleName string, identifier string) error
+		DeleteLoginAttemptByID(ctx context.Context, tx *gorm.DB, attemptID string) error
+	}
+
+	LoginAttemptRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+// Get
+func (lr *LoginAttemptRepository) GetLoginAttempt(ctx context.Context, tx *gorm.DB, scope string, roleName string, identifier string) (entity.LoginAttempt, bool, error) {
+	if tx == nil {
+		tx = lr.db
+	}
+
+	var attempt entity.LoginAttempt
+	if err := tx.WithContext(ctx).Where("scope = ? AND role_name = ? AND identifier = ?", scope, roleName, identifier).Take(&attempt).Error; err != nil {
+		return entity.LoginAttempt{}, false, err
+	}
+
+	return attempt, true, nil
+}
+func (lr *LoginAttemptRepository) GetLoginAttemptByID(ctx context.Context, tx *gorm.DB, attemptID string) (entity.LoginAttempt, bool, error) {
+	if tx == nil {
+		tx = lr.db
+	}
+
+	var attempt entity.LoginAttempt
+	if err := tx.WithContext(ctx).Where("id = ?", attemptID).Take(&attempt).Error; err != nil {
+		return entity.LoginAttempt{}, false, err
+	}
+
+	return attempt, true, nil
+}
+func (lr *LoginAttemptRepository) GetLoginAttemptByUnlockTokenHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.LoginAttempt, bool, error) {
+	if tx == nil {
+		tx = lr.db
+	}
+
+	var attempt entity.LoginAttempt
+	if err := tx.WithContext(ctx).Where("unlock_token_hash = ? AND unlock_token_hash <> ''", tokenHash).Take(&attempt).Error; err != nil {
+		return entity.LoginAttempt{}, false, err
+	}
+
+	return attempt, true, nil
+}
+func (lr *LoginAttemptRepository) GetAllLoginAttemptWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllLoginAttemptRepositoryResponse, error) {
+	if tx == nil {
+		tx = lr.db
+	}
+
+	var (
+		attempts []entity.LoginAttempt
+		err      error
+		count    int64
+	)
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.LoginAttempt{})
+	if req.Search != "" {
+		query = query.Where("identifier ILIKE ?", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.AllLoginAttemptRepositoryResponse{}, err
+	}
+
+	// akun yang sedang terkunci tampil paling atas
+	if err := query.Order("locked_until DESC NULLS LAST").Order("last_failed_at DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&attempts).Error; err != nil {
+		return dto.AllLoginAttemptRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllLoginAttemptRepositoryResponse{
+		LoginAttempts: attempts,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+
+// Create
+
+// IncrementLoginAttempt menambah hitungan gagal secara atomik (upsert). Hitungan mulai
+// dari 1 lagi jika gagal terakhir sudah di luar window atau masa lock sudah lewat.
+func (lr *LoginAttemptRepository) IncrementLoginAttempt(ctx context.Context, tx *gorm.DB, scope string, roleName string, identifier string, windowStart time.Time) (entity.LoginAttempt, error) {
+	if tx == nil {
+		tx = lr.db
+	}
+
+	now := time.Now()
+	attempt := entity.LoginAttempt{
+		ID:           uuid.New(),
+		Scope:        scope,
+		RoleName:     roleName,
+		Identifier:   identifier,
+		FailedCount:  1,
+		LastFailedAt: now,
+	}
+
+	expired := "login_attempts.last_failed_at < ? OR login_attempts.locked_until < ?"
+	err := tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scope"}, {Name: "role_name"}, {Name: "identifier"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failed_count":      gorm.Expr("CASE WHEN "+expired+" THEN 1 ELSE login_attempts.failed_count + 1 END", windowStart, now),
+			"locked_until":      gorm.Expr("CASE WHEN login_attempts.locked_until < ? THEN NULL ELSE login_attempts.locked_until END", now),
+			"unlock_token_hash": gorm.Expr("CASE WHEN login_attempts.locked_until < ? THEN '' ELSE login_attempts.unlock_token_hash END", now),
+			"last_failed_at":    now,
+			"updated_at":        now,
+		}),
+	}, clause.Returning{}).Create(&attempt).Error
+	if err != nil {
+		return entity.LoginAttempt{}, err
+	}
+
+	return attempt, nil
+}
+
+// Update
+func (lr *LoginAttemptRepository) LockLoginAttempt(ctx context.Context, tx *gorm.DB, attemptID uuid.UUID, lockedUntil time.Time, unlockTokenHash string) error {
+	if tx == nil {
+		tx = lr.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.LoginAttempt{}).
+		Where("id = ?", attemptID).
+		Updates(map[string]interface{}{
+			"locked_until":      lockedUntil,
+			"unlock_token_hash": unlockTokenHash,
+		}).Error
+}
+
+// Delete
+func (lr *LoginAttemptRepository) DeleteLoginAttempt(ctx context.Context, tx *gorm.DB, scope string, roleName string, identifier string) error {
+	if tx == nil {
+		tx = lr.db
+	}
+
+	// hard delete agar unique key bisa dipakai lagi oleh upsert
+	return tx.WithContext(ctx).Unscoped().Where("scope = ? AND role_name = ? AND identifier = ?", scope, roleName, identifier).Delete(&entity.LoginAttempt{}).Error
+}
+func (lr *LoginAttemptRepository) DeleteLoginAttemptByID(ctx context.Context, tx *gorm.DB, attemptID string) error {
+	if tx == nil {
+		tx = lr.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Where("id = ?", attemptID).Delete(&entity.LoginAttempt{}).Error
+}
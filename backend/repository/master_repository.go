@@ -20,6 +20,8 @@ type (
 		// Psycholog
 		GetPsychologByID(ctx context.Context, tx *gorm.DB, psychologID string) (entity.Psycholog, bool, error)
 		GetPsychologByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.Psycholog, bool, error)
+		// Permission
+		GetPermissionsByRoleID(ctx context.Context, tx *gorm.DB, roleID string) ([]entity.Permission, error)
 	}
 
 	MasterRepository struct {
@@ -139,3 +141,17 @@ func (mr *MasterRepository) GetPsychologByEmail(ctx context.Context, tx *gorm.DB
 
 	return psycholog, true, nil
 }
+
+// Permission
+func (mr *MasterRepository) GetPermissionsByRoleID(ctx context.Context, tx *gorm.DB, roleID string) ([]entity.Permission, error) {
+	if tx == nil {
+		tx = mr.db
+	}
+
+	var permissions []entity.Permission
+	if err := tx.WithContext(ctx).Where("role_id = ?", roleID).Find(&permissions).Error; err != nil {
+		return []entity.Permission{}, err
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	IOneTimeTokenRepository interface {
+		// Get
+		GetOneTimeTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string, purpose string) (entity.OneTimeToken, bool, error)
+
+		// Create
+		CreateOneTimeToken(ctx context.Context, tx *gorm.DB, token entity.OneTimeToken) error
+
+		// Update
+		ConsumeOneTimeToken(ctx context.Context, tx *gorm.DB, tokenID uuid.UUID) (bool, error)
+		ConsumeOneTimeTokenByUserID(ctx context.Context, tx *gorm.DB, userID uuid.UUID, purpose string) error
+	}
+
+	OneTimeTokenRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewOneTimeTokenRepository(db *gorm.DB) *OneTimeTokenRepository {
+	return &OneTimeTokenRepository{
+		db: db,
+	}
+}
+
+// Get
+func (otr *OneTimeTokenRepository) GetOneTimeTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string, purpose string) (entity.OneTimeToken, bool, error) {
+	if tx == nil {
+		tx = otr.db
+	}
+
+	var token entity.OneTimeToken
+	if err := tx.WithContext(ctx).Where("token_hash = ? AND purpose = ?", tokenHash, purpose).Take(&token).Error; err != nil {
+		return entity.OneTimeToken{}, false, err
+	}
+
+	return token, true, nil
+}
+
+// Create
+func (otr *OneTimeTokenRepository) CreateOneTimeToken(ctx context.Context, tx *gorm.DB, token entity.OneTimeToken) error {
+	if tx == nil {
+		tx = otr.db
+	}
+
+	return tx.WithContext(ctx).Create(&token).Error
+}
+
+// Update
+
+// ConsumeOneTimeToken menandai token terpakai hanya jika belum terpakai dan belum
+// kedaluwarsa, sehingga dua request paralel dengan token yang sama tidak bisa sama-sama berhasil.
+func (otr *OneTimeTokenRepository) ConsumeOneTimeToken(ctx context.Context, tx *gorm.DB, tokenID uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = otr.db
+	}
+
+	now := time.Now()
+	result := tx.WithContext(ctx).
+		Model(&entity.OneTimeToken{}).
+		Where("id = ? AND consumed_at IS NULL AND expires_at > ?", tokenID, now).
+		Update("consumed_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+func (otr *OneTimeTokenRepository) ConsumeOneTimeTokenByUserID(ctx context.Context, tx *gorm.DB, userID uuid.UUID, purpose string) error {
+	if tx == nil {
+		tx = otr.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.OneTimeToken{}).
+		Where("user_id = ? AND purpose = ? AND consumed_at IS NULL", userID, purpose).
+		Update("consumed_at", time.Now()).Error
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	IPaymentRepository interface {
+		// Transaction
+		RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
+		// Get
+		GetPaymentByReference(ctx context.Context, tx *gorm.DB, gateway string, reference string) (entity.Payment, bool, error)
+		GetPendingPaymentByConsultationID(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) (entity.Payment, bool, error)
+		GetOverduePendingPayments(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]entity.Payment, error)
+		GetPaidPaymentByConsultationID(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) (entity.Payment, bool, error)
+		GetRetryableRefunds(ctx context.Context, tx *gorm.DB, pendingBefore time.Time, limit int) ([]entity.Refund, error)
+
+		// Create
+		CreatePaymentWebhookEvent(ctx context.Context, tx *gorm.DB, event entity.PaymentWebhookEvent) (bool, error)
+		CreateRefund(ctx context.Context, tx *gorm.DB, refund entity.Refund) error
+
+		// Update
+		UpdatePaymentStatus(ctx context.Context, tx *gorm.DB, paymentID uuid.UUID, fromStatus string, toStatus string, paidAt *time.Time) (bool, error)
+		ReleaseSlotHold(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, booked bool) error
+		UpdateRefund(ctx context.Context, tx *gorm.DB, refund entity.Refund) error
+	}
+
+	PaymentRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{
+		db: db,
+	}
+}
+
+// Transaction
+func (pr *PaymentRepository) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return pr.db.WithContext(ctx).Transaction(fn)
+}
+
+// Get
+func (pr *PaymentRepository) GetPaymentByReference(ctx context.Context, tx *gorm.DB, gateway string, reference string) (entity.Payment, bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var payment entity.Payment
+	if err := tx.WithContext(ctx).Preload("Consultation").Where("gateway = ? AND reference = ?", gateway, reference).Take(&payment).Error; err != nil {
+		return entity.Payment{}, false, err
+	}
+
+	return payment, true, nil
+}
+func (pr *PaymentRepository) GetPendingPaymentByConsultationID(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) (entity.Payment, bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var payment entity.Payment
+	if err := tx.WithContext(ctx).Preload("Consultation").
+		Where("consultation_id = ? AND status = ?", consulID, constants.ENUM_PAYMENT_STATUS_PENDING).
+		Order("created_at DESC").
+		Take(&payment).Error; err != nil {
+		return entity.Payment{}, false, err
+	}
+
+	return payment, true, nil
+}
+func (pr *PaymentRepository) GetOverduePendingPayments(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]entity.Payment, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var payments []entity.Payment
+	if err := tx.WithContext(ctx).Preload("Consultation").
+		Where("status = ? AND expires_at <= ?", constants.ENUM_PAYMENT_STATUS_PENDING, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&payments).Error; err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+func (pr *PaymentRepository) GetPaidPaymentByConsultationID(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) (entity.Payment, bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var payment entity.Payment
+	if err := tx.WithContext(ctx).
+		Where("consultation_id = ? AND status = ?", consulID, constants.ENUM_PAYMENT_STATUS_PAID).
+		Order("paid_at DESC").
+		Take(&payment).Error; err != nil {
+		return entity.Payment{}, false, err
+	}
+
+	return payment, true, nil
+}
+
+// GetRetryableRefunds mengambil refund yang gagal, atau masih pending sejak sebelum
+// pendingBefore (proses setelah pembatalan kemungkinan terhenti di tengah jalan).
+func (pr *PaymentRepository) GetRetryableRefunds(ctx context.Context, tx *gorm.DB, pendingBefore time.Time, limit int) ([]entity.Refund, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var refunds []entity.Refund
+	if err := tx.WithContext(ctx).Preload("Payment").
+		Where("status = ? OR (status = ? AND created_at <= ?)", constants.ENUM_REFUND_STATUS_FAILED, constants.ENUM_REFUND_STATUS_PENDING, pendingBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+// Create
+
+// CreatePaymentWebhookEvent mencatat event dari gateway.
+// Return false berarti event dengan id yang sama sudah pernah diproses.
+func (pr *PaymentRepository) CreatePaymentWebhookEvent(ctx context.Context, tx *gorm.DB, event entity.PaymentWebhookEvent) (bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	result := tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "gateway"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(&event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+func (pr *PaymentRepository) CreateRefund(ctx context.Context, tx *gorm.DB, refund entity.Refund) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Create(&refund).Error
+}
+
+// Update
+
+// UpdatePaymentStatus hanya mengubah status jika status saat ini masih fromStatus.
+// Return false berarti payment sudah diproses lebih dulu.
+func (pr *PaymentRepository) UpdatePaymentStatus(ctx context.Context, tx *gorm.DB, paymentID uuid.UUID, fromStatus string, toStatus string, paidAt *time.Time) (bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	updates := map[string]interface{}{
+		"status": toStatus,
+	}
+	if paidAt != nil {
+		updates["paid_at"] = paidAt
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.Payment{}).
+		Where("id = ? AND status = ?", paymentID, fromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseSlotHold menghapus hold slot, booked=true saat pembayaran diterima dan
+// booked=false saat pembayaran gagal atau kedaluwarsa.
+func (pr *PaymentRepository) ReleaseSlotHold(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, booked bool) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.AvailableSlot{}).
+		Where("id = ?", slotID).
+		Updates(map[string]interface{}{
+			"is_booked":  booked,
+			"held_until": nil,
+		}).Error
+}
+func (pr *PaymentRepository) UpdateRefund(ctx context.Context, tx *gorm.DB, refund entity.Refund) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	// hanya hasil pemrosesan di gateway yang boleh berubah, nominal refund tetap
+	return tx.WithContext(ctx).
+		Model(&entity.Refund{}).
+		Where("id = ?", refund.ID).
+		Select("status", "reference", "failure_reason", "processed_at", "updated_at").
+		Updates(&refund).Error
+}
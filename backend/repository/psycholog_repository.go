@@ -4,35 +4,70 @@ import (
 	"context"
 	"math"
 
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
 	IPsychologRepository interface {
+		// Transaction
+		RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
 		// GET / Read
-		GetPermissionsByRoleID(ctx context.Context, tx *gorm.DB, roleID string) ([]string, bool, error)
 		GetRoleByID(ctx context.Context, tx *gorm.DB, roleID string) (entity.Role, bool, error)
+		GetRoleByName(ctx context.Context, tx *gorm.DB, name string) (entity.Role, bool, error)
+		GetLanguageMasterByID(ctx context.Context, tx *gorm.DB, languageMasterID string) (entity.LanguageMaster, bool, error)
+		GetSpecializationByID(ctx context.Context, tx *gorm.DB, specializationID string) (entity.Specialization, bool, error)
 		GetAllPractice(ctx context.Context, tx *gorm.DB, psyID string) (dto.AllPracticeRepositoryResponse, error)
 		GetAllAvailableSlot(ctx context.Context, tx *gorm.DB, psyID string) (dto.AllAvailableSlotRepositoryResponse, error)
 		GetAllConsultationWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest, psychologID string) (dto.AllConsultationRepositoryResponse, error)
 		GetPracticeByID(ctx context.Context, tx *gorm.DB, practiceID string) (entity.Practice, bool, error)
 		GetConsultationByID(ctx context.Context, tx *gorm.DB, consulID string) (entity.Consultation, bool, error)
 		GetPsychologByID(ctx context.Context, tx *gorm.DB, psyID string) (entity.Psycholog, bool, error)
+		GetAllSlotTemplate(ctx context.Context, tx *gorm.DB, psyID string) ([]entity.SlotTemplate, error)
+		GetSlotTemplateByID(ctx context.Context, tx *gorm.DB, templateID string) (entity.SlotTemplate, bool, error)
+		GetAllPsychologIDWithSlotTemplate(ctx context.Context, tx *gorm.DB) ([]string, error)
+		GetAvailableSlotByDateRange(ctx context.Context, tx *gorm.DB, psyID string, from string, to string) ([]entity.AvailableSlot, error)
+		GetEducationByID(ctx context.Context, tx *gorm.DB, educationID string) (entity.Education, bool, error)
+		GetPendingChangeRequestByField(ctx context.Context, tx *gorm.DB, psyID string, field string) (entity.PsychologChangeRequest, bool, error)
+		GetAllPsychologChangeRequest(ctx context.Context, tx *gorm.DB, psyID string, status string) ([]entity.PsychologChangeRequest, error)
 
 		// POST / Create
 		CreatePractice(ctx context.Context, tx *gorm.DB, practice entity.Practice) error
 		CreatePracticeSchedule(ctx context.Context, tx *gorm.DB, schedules []entity.PracticeSchedule) error
-		CreateAvailableSlots(ctx context.Context, tx *gorm.DB, slots []entity.AvailableSlot) error
+		CreateAvailableSlots(ctx context.Context, tx *gorm.DB, slots []entity.AvailableSlot) (int, error)
+		CreateSlotTemplate(ctx context.Context, tx *gorm.DB, template entity.SlotTemplate) error
+		CreatePsycholog(ctx context.Context, tx *gorm.DB, psycholog entity.Psycholog) error
+		CreatePsychologLanguages(ctx context.Context, tx *gorm.DB, psychologLanguages []entity.PsychologLanguage) error
+		CreatePsychologSpecializations(ctx context.Context, tx *gorm.DB, psychologSpecializations []entity.PsychologSpecialization) error
+		CreateEducations(ctx context.Context, tx *gorm.DB, educations []entity.Education) error
+		CreatePsychologDocuments(ctx context.Context, tx *gorm.DB, documents []entity.PsychologDocument) error
+		CreatePsychologChangeRequest(ctx context.Context, tx *gorm.DB, changeRequest entity.PsychologChangeRequest) error
 
 		// PATCH / Update
 		UpdatePractice(ctx context.Context, tx *gorm.DB, practice entity.Practice) error
 		UpdateConsultation(ctx context.Context, tx *gorm.DB, consultation entity.Consultation) error
+		UpdateSlotTemplate(ctx context.Context, tx *gorm.DB, template entity.SlotTemplate) error
+		UpdateStatusBookSlot(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, statusBook bool) error
+		UpdatePsycholog(ctx context.Context, tx *gorm.DB, psycholog entity.Psycholog) error
+		UpdatePsychologPassword(ctx context.Context, tx *gorm.DB, psyID string, password helpers.PasswordHash) error
+		UpdateEducation(ctx context.Context, tx *gorm.DB, education entity.Education) error
 
 		// DELETE / Delete
 		DeletePracticeSchedule(ctx context.Context, tx *gorm.DB, practiceID string) error
 		DeletePracticeByID(ctx context.Context, tx *gorm.DB, practiceID string) error
+		DeleteSlotTemplateByID(ctx context.Context, tx *gorm.DB, templateID string) error
+		DeleteSlotTemplateByPracticeID(ctx context.Context, tx *gorm.DB, practiceID string) error
+		DeleteUnbookedSlotByTemplateID(ctx context.Context, tx *gorm.DB, templateID string, fromDate string) error
+		DeleteUnbookedSlotByPracticeID(ctx context.Context, tx *gorm.DB, practiceID string, fromDate string) error
+		DeletePsychologLanguageByPsychologID(ctx context.Context, tx *gorm.DB, psyID string) error
+		DeletePsychologSpecializationByPsychologID(ctx context.Context, tx *gorm.DB, psyID string) error
+		DeleteEducationByID(ctx context.Context, tx *gorm.DB, educationID string) error
 	}
 
 	PsychologRepository struct {
@@ -46,31 +81,60 @@ func NewPsychologRepository(db *gorm.DB) *PsychologRepository {
 	}
 }
 
+// Transaction
+func (pr *PsychologRepository) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return pr.db.WithContext(ctx).Transaction(fn)
+}
+
 // Get
-func (pr *PsychologRepository) GetPermissionsByRoleID(ctx context.Context, tx *gorm.DB, roleID string) ([]string, bool, error) {
+func (pr *PsychologRepository) GetRoleByID(ctx context.Context, tx *gorm.DB, roleID string) (entity.Role, bool, error) {
 	if tx == nil {
 		tx = pr.db
 	}
 
-	var endpoints []string
-	if err := tx.WithContext(ctx).Table("permissions").Where("role_id = ?", roleID).Pluck("endpoint", &endpoints).Error; err != nil {
-		return []string{}, false, err
+	var role entity.Role
+	if err := tx.WithContext(ctx).Where("id = ?", roleID).Take(&role).Error; err != nil {
+		return entity.Role{}, false, err
 	}
 
-	return endpoints, true, nil
+	return role, true, nil
 }
-func (pr *PsychologRepository) GetRoleByID(ctx context.Context, tx *gorm.DB, roleID string) (entity.Role, bool, error) {
+func (pr *PsychologRepository) GetRoleByName(ctx context.Context, tx *gorm.DB, name string) (entity.Role, bool, error) {
 	if tx == nil {
 		tx = pr.db
 	}
 
 	var role entity.Role
-	if err := tx.WithContext(ctx).Where("id = ?", roleID).Take(&role).Error; err != nil {
+	if err := tx.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Take(&role).Error; err != nil {
 		return entity.Role{}, false, err
 	}
 
 	return role, true, nil
 }
+func (pr *PsychologRepository) GetLanguageMasterByID(ctx context.Context, tx *gorm.DB, languageMasterID string) (entity.LanguageMaster, bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var languageMaster entity.LanguageMaster
+	if err := tx.WithContext(ctx).Where("id = ?", languageMasterID).Take(&languageMaster).Error; err != nil {
+		return entity.LanguageMaster{}, false, err
+	}
+
+	return languageMaster, true, nil
+}
+func (pr *PsychologRepository) GetSpecializationByID(ctx context.Context, tx *gorm.DB, specializationID string) (entity.Specialization, bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var specialization entity.Specialization
+	if err := tx.WithContext(ctx).Where("id = ?", specializationID).Take(&specialization).Error; err != nil {
+		return entity.Specialization{}, false, err
+	}
+
+	return specialization, true, nil
+}
 func (pr *PsychologRepository) GetAllPractice(ctx context.Context, tx *gorm.DB, psyID string) (dto.AllPracticeRepositoryResponse, error) {
 	if tx == nil {
 		tx = pr.db
@@ -219,6 +283,109 @@ func (pr *PsychologRepository) GetPsychologByID(ctx context.Context, tx *gorm.DB
 
 	return psy, true, nil
 }
+func (pr *PsychologRepository) GetAllSlotTemplate(ctx context.Context, tx *gorm.DB, psyID string) ([]entity.SlotTemplate, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var templates []entity.SlotTemplate
+	if err := tx.WithContext(ctx).Model(&entity.SlotTemplate{}).Where("psycholog_id = ?", psyID).
+		Preload("Practice.PracticeSchedules").
+		Order("day ASC, start ASC").
+		Find(&templates).Error; err != nil {
+		return []entity.SlotTemplate{}, err
+	}
+
+	return templates, nil
+}
+func (pr *PsychologRepository) GetSlotTemplateByID(ctx context.Context, tx *gorm.DB, templateID string) (entity.SlotTemplate, bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var template entity.SlotTemplate
+	if err := tx.WithContext(ctx).Model(&entity.SlotTemplate{}).
+		Preload("Practice.PracticeSchedules").
+		Where("id = ?", templateID).
+		Take(&template).Error; err != nil {
+		return entity.SlotTemplate{}, false, err
+	}
+
+	return template, true, nil
+}
+func (pr *PsychologRepository) GetAllPsychologIDWithSlotTemplate(ctx context.Context, tx *gorm.DB) ([]string, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var psyIDs []string
+	if err := tx.WithContext(ctx).Model(&entity.SlotTemplate{}).
+		Distinct("psycholog_id").
+		Where("psycholog_id IS NOT NULL").
+		Pluck("psycholog_id", &psyIDs).Error; err != nil {
+		return []string{}, err
+	}
+
+	return psyIDs, nil
+}
+func (pr *PsychologRepository) GetAvailableSlotByDateRange(ctx context.Context, tx *gorm.DB, psyID string, from string, to string) ([]entity.AvailableSlot, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var slots []entity.AvailableSlot
+	if err := tx.WithContext(ctx).Model(&entity.AvailableSlot{}).
+		Where("psycholog_id = ? AND date >= ? AND date <= ?", psyID, from, to).
+		Order("date ASC, start ASC").
+		Find(&slots).Error; err != nil {
+		return []entity.AvailableSlot{}, err
+	}
+
+	return slots, nil
+}
+func (pr *PsychologRepository) GetEducationByID(ctx context.Context, tx *gorm.DB, educationID string) (entity.Education, bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var education entity.Education
+	if err := tx.WithContext(ctx).Where("id = ?", educationID).Take(&education).Error; err != nil {
+		return entity.Education{}, false, err
+	}
+
+	return education, true, nil
+}
+func (pr *PsychologRepository) GetPendingChangeRequestByField(ctx context.Context, tx *gorm.DB, psyID string, field string) (entity.PsychologChangeRequest, bool, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	var changeRequest entity.PsychologChangeRequest
+	if err := tx.WithContext(ctx).
+		Where("psycholog_id = ? AND field = ? AND status = ?", psyID, field, constants.ENUM_CHANGE_REQUEST_STATUS_PENDING).
+		Take(&changeRequest).Error; err != nil {
+		return entity.PsychologChangeRequest{}, false, err
+	}
+
+	return changeRequest, true, nil
+}
+func (pr *PsychologRepository) GetAllPsychologChangeRequest(ctx context.Context, tx *gorm.DB, psyID string, status string) ([]entity.PsychologChangeRequest, error) {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.PsychologChangeRequest{}).Where("psycholog_id = ?", psyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var changeRequests []entity.PsychologChangeRequest
+	if err := query.Order("created_at DESC").Find(&changeRequests).Error; err != nil {
+		return []entity.PsychologChangeRequest{}, err
+	}
+
+	return changeRequests, nil
+}
 
 // Post / Create
 func (pr *PsychologRepository) CreatePractice(ctx context.Context, tx *gorm.DB, practice entity.Practice) error {
@@ -235,12 +402,72 @@ func (pr *PsychologRepository) CreatePracticeSchedule(ctx context.Context, tx *g
 
 	return tx.WithContext(ctx).Create(&schedules).Error
 }
-func (pr *PsychologRepository) CreateAvailableSlots(ctx context.Context, tx *gorm.DB, slots []entity.AvailableSlot) error {
+
+// CreateAvailableSlots melewati slot yang sudah ada (psycholog, tanggal, jam mulai sama)
+// sehingga generator yang berjalan bersamaan tidak membuat duplikat
+func (pr *PsychologRepository) CreateAvailableSlots(ctx context.Context, tx *gorm.DB, slots []entity.AvailableSlot) (int, error) {
 	if tx == nil {
 		tx = pr.db
 	}
 
-	return tx.WithContext(ctx).Create(&slots).Error
+	result := tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}, {Name: "start"}, {Name: "psycholog_id"}},
+		DoNothing: true,
+	}).Create(&slots)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+func (pr *PsychologRepository) CreateSlotTemplate(ctx context.Context, tx *gorm.DB, template entity.SlotTemplate) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Create(&template).Error
+}
+func (pr *PsychologRepository) CreatePsycholog(ctx context.Context, tx *gorm.DB, psycholog entity.Psycholog) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Create(&psycholog).Error
+}
+func (pr *PsychologRepository) CreatePsychologLanguages(ctx context.Context, tx *gorm.DB, psychologLanguages []entity.PsychologLanguage) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Create(&psychologLanguages).Error
+}
+func (pr *PsychologRepository) CreatePsychologSpecializations(ctx context.Context, tx *gorm.DB, psychologSpecializations []entity.PsychologSpecialization) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Create(&psychologSpecializations).Error
+}
+func (pr *PsychologRepository) CreateEducations(ctx context.Context, tx *gorm.DB, educations []entity.Education) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Create(&educations).Error
+}
+func (pr *PsychologRepository) CreatePsychologDocuments(ctx context.Context, tx *gorm.DB, documents []entity.PsychologDocument) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Create(&documents).Error
+}
+func (pr *PsychologRepository) CreatePsychologChangeRequest(ctx context.Context, tx *gorm.DB, changeRequest entity.PsychologChangeRequest) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Create(&changeRequest).Error
 }
 
 // PATCH / Update
@@ -249,14 +476,71 @@ func (pr *PsychologRepository) UpdatePractice(ctx context.Context, tx *gorm.DB,
 		tx = pr.db
 	}
 
-	return tx.WithContext(ctx).Where("id = ?", practice.ID).Updates(&practice).Error
+	// fee di-select eksplisit agar praktik gratis (fee 0) tetap tersimpan
+	return tx.WithContext(ctx).
+		Select("type", "name", "address", "phone_number", "fee", "updated_at").
+		Where("id = ?", practice.ID).
+		Updates(&practice).Error
 }
 func (pr *PsychologRepository) UpdateConsultation(ctx context.Context, tx *gorm.DB, consultation entity.Consultation) error {
 	if tx == nil {
 		tx = pr.db
 	}
 
-	return tx.WithContext(ctx).Where("id = ?", consultation.ID).Updates(&consultation).Error
+	// status hanya boleh diubah lewat UpdateConsultationStatus
+	return tx.WithContext(ctx).Where("id = ?", consultation.ID).Omit("status").Updates(&consultation).Error
+}
+func (pr *PsychologRepository) UpdateSlotTemplate(ctx context.Context, tx *gorm.DB, template entity.SlotTemplate) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.SlotTemplate{}).Where("id = ?", template.ID).
+		Select("day", "start", "end", "session_duration", "buffer_duration").
+		Updates(&template).Error
+}
+func (pr *PsychologRepository) UpdateStatusBookSlot(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, statusBook bool) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	// hold pembayaran ikut dihapus, status booking diatur langsung di sini
+	return tx.WithContext(ctx).
+		Model(&entity.AvailableSlot{}).
+		Where("id = ?", slotID).
+		Updates(map[string]interface{}{
+			"is_booked":  statusBook,
+			"held_until": nil,
+		}).Error
+}
+func (pr *PsychologRepository) UpdatePsycholog(ctx context.Context, tx *gorm.DB, psycholog entity.Psycholog) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	// relasi (bahasa, spesialisasi, pendidikan) dikelola lewat method masing-masing,
+	// agregat rating hanya diubah lewat RecalculatePsychologRating
+	return tx.WithContext(ctx).
+		Omit(clause.Associations, "password", "email", "str_number", "status", "rating_average", "rating_count").
+		Where("id = ?", psycholog.ID).
+		Updates(&psycholog).Error
+}
+func (pr *PsychologRepository) UpdatePsychologPassword(ctx context.Context, tx *gorm.DB, psyID string, password helpers.PasswordHash) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.Psycholog{}).
+		Where("id = ?", psyID).
+		Update("password", password).Error
+}
+func (pr *PsychologRepository) UpdateEducation(ctx context.Context, tx *gorm.DB, education entity.Education) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Omit(clause.Associations).Where("id = ?", education.ID).Updates(&education).Error
 }
 
 // DELETE / Delete
@@ -274,3 +558,56 @@ func (pr *PsychologRepository) DeletePracticeByID(ctx context.Context, tx *gorm.
 
 	return tx.WithContext(ctx).Where("id = ?", practiceID).Delete(&entity.Practice{}).Error
 }
+func (pr *PsychologRepository) DeleteSlotTemplateByID(ctx context.Context, tx *gorm.DB, templateID string) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", templateID).Delete(&entity.SlotTemplate{}).Error
+}
+func (pr *PsychologRepository) DeleteSlotTemplateByPracticeID(ctx context.Context, tx *gorm.DB, practiceID string) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Where("practice_id = ?", practiceID).Delete(&entity.SlotTemplate{}).Error
+}
+func (pr *PsychologRepository) DeleteUnbookedSlotByTemplateID(ctx context.Context, tx *gorm.DB, templateID string, fromDate string) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).
+		Where("slot_template_id = ? AND is_booked = ? AND date >= ?", templateID, false, fromDate).
+		Delete(&entity.AvailableSlot{}).Error
+}
+func (pr *PsychologRepository) DeleteUnbookedSlotByPracticeID(ctx context.Context, tx *gorm.DB, practiceID string, fromDate string) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).
+		Where("practice_id = ? AND is_booked = ? AND date >= ?", practiceID, false, fromDate).
+		Delete(&entity.AvailableSlot{}).Error
+}
+func (pr *PsychologRepository) DeletePsychologLanguageByPsychologID(ctx context.Context, tx *gorm.DB, psyID string) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Where("psycholog_id = ?", psyID).Delete(&entity.PsychologLanguage{}).Error
+}
+func (pr *PsychologRepository) DeletePsychologSpecializationByPsychologID(ctx context.Context, tx *gorm.DB, psyID string) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Where("psycholog_id = ?", psyID).Delete(&entity.PsychologSpecialization{}).Error
+}
+func (pr *PsychologRepository) DeleteEducationByID(ctx context.Context, tx *gorm.DB, educationID string) error {
+	if tx == nil {
+		tx = pr.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", educationID).Delete(&entity.Education{}).Error
+}
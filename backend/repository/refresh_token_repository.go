@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	IRefreshTokenRepository interface {
+		// Transaction
+		RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
+		// Get
+		GetRefreshTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.RefreshToken, bool, error)
+
+		// Create
+		CreateRefreshToken(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) error
+
+		// Update
+		RotateRefreshToken(ctx context.Context, tx *gorm.DB, tokenID uuid.UUID, replacedByID uuid.UUID) (bool, error)
+		RevokeRefreshTokenFamily(ctx context.Context, tx *gorm.DB, familyID uuid.UUID) error
+		RevokeRefreshTokenBySubject(ctx context.Context, tx *gorm.DB, subjectID uuid.UUID, roleName string) error
+	}
+
+	RefreshTokenRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db: db,
+	}
+}
+
+// Transaction
+func (rr *RefreshTokenRepository) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return rr.db.WithContext(ctx).Transaction(fn)
+}
+
+// Get
+func (rr *RefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.RefreshToken, bool, error) {
+	if tx == nil {
+		tx = rr.db
+	}
+
+	var token entity.RefreshToken
+	if err := tx.WithContext(ctx).Where("token_hash = ?", tokenHash).Take(&token).Error; err != nil {
+		return entity.RefreshToken{}, false, err
+	}
+
+	return token, true, nil
+}
+
+// Create
+func (rr *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) error {
+	if tx == nil {
+		tx = rr.db
+	}
+
+	return tx.WithContext(ctx).Create(&token).Error
+}
+
+// Update
+
+// RotateRefreshToken mencabut token hanya jika belum dicabut, sehingga dua request
+// paralel dengan token yang sama tidak bisa sama-sama berhasil.
+func (rr *RefreshTokenRepository) RotateRefreshToken(ctx context.Context, tx *gorm.DB, tokenID uuid.UUID, replacedByID uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = rr.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", tokenID).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"replaced_by_id": replacedByID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+func (rr *RefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, tx *gorm.DB, familyID uuid.UUID) error {
+	if tx == nil {
+		tx = rr.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+func (rr *RefreshTokenRepository) RevokeRefreshTokenBySubject(ctx context.Context, tx *gorm.DB, subjectID uuid.UUID, roleName string) error {
+	if tx == nil {
+		tx = rr.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.RefreshToken{}).
+		Where("subject_id = ? AND role_name = ? AND revoked_at IS NULL", subjectID, roleName).
+		Update("revoked_at", time.Now()).Error
+}
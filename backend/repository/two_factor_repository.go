@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	ITwoFactorRepository interface {
+		// Transaction
+		RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
+		// Get
+		GetTwoFactorBySubject(ctx context.Context, tx *gorm.DB, subjectID uuid.UUID, roleName string) (entity.TwoFactor, bool, error)
+		GetTwoFactorChallengeByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.TwoFactorChallenge, bool, error)
+		GetUnusedRecoveryCode(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID, codeHash string) (entity.TwoFactorRecoveryCode, bool, error)
+		CountUnusedRecoveryCode(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID) (int64, error)
+
+		// Create
+		CreateTwoFactorRecoveryCodes(ctx context.Context, tx *gorm.DB, codes []entity.TwoFactorRecoveryCode) error
+		CreateTwoFactorChallenge(ctx context.Context, tx *gorm.DB, challenge entity.TwoFactorChallenge) error
+
+		// Update
+		SaveTwoFactor(ctx context.Context, tx *gorm.DB, twoFactor entity.TwoFactor) error
+		UpdateTwoFactorLastUsedStep(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID, step int64) (bool, error)
+		UseRecoveryCode(ctx context.Context, tx *gorm.DB, codeID uuid.UUID) (bool, error)
+		IncrementTwoFactorChallengeAttempts(ctx context.Context, tx *gorm.DB, challengeID uuid.UUID) error
+		ConsumeTwoFactorChallenge(ctx context.Context, tx *gorm.DB, challengeID uuid.UUID) (bool, error)
+
+		// Delete
+		DeleteTwoFactorRecoveryCodes(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID) error
+	}
+
+	TwoFactorRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTwoFactorRepository(db *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		db: db,
+	}
+}
+
+// Transaction
+func (tr *TwoFactorRepository) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return tr.db.WithContext(ctx).Transaction(fn)
+}
+
+// Get
+func (tr *TwoFactorRepository) GetTwoFactorBySubject(ctx context.Context, tx *gorm.DB, subjectID uuid.UUID, roleName string) (entity.TwoFactor, bool, error) {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	var twoFactor entity.TwoFactor
+	if err := tx.WithContext(ctx).Where("subject_id = ? AND role_name = ?", subjectID, roleName).Take(&twoFactor).Error; err != nil {
+		return entity.TwoFactor{}, false, err
+	}
+
+	return twoFactor, true, nil
+}
+func (tr *TwoFactorRepository) GetTwoFactorChallengeByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.TwoFactorChallenge, bool, error) {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	var challenge entity.TwoFactorChallenge
+	if err := tx.WithContext(ctx).Where("token_hash = ?", tokenHash).Take(&challenge).Error; err != nil {
+		return entity.TwoFactorChallenge{}, false, err
+	}
+
+	return challenge, true, nil
+}
+func (tr *TwoFactorRepository) GetUnusedRecoveryCode(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID, codeHash string) (entity.TwoFactorRecoveryCode, bool, error) {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	var code entity.TwoFactorRecoveryCode
+	if err := tx.WithContext(ctx).Where("two_factor_id = ? AND code_hash = ? AND used_at IS NULL", twoFactorID, codeHash).Take(&code).Error; err != nil {
+		return entity.TwoFactorRecoveryCode{}, false, err
+	}
+
+	return code, true, nil
+}
+func (tr *TwoFactorRepository) CountUnusedRecoveryCode(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID) (int64, error) {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.TwoFactorRecoveryCode{}).Where("two_factor_id = ? AND used_at IS NULL", twoFactorID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Create
+func (tr *TwoFactorRepository) CreateTwoFactorRecoveryCodes(ctx context.Context, tx *gorm.DB, codes []entity.TwoFactorRecoveryCode) error {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	return tx.WithContext(ctx).Create(&codes).Error
+}
+func (tr *TwoFactorRepository) CreateTwoFactorChallenge(ctx context.Context, tx *gorm.DB, challenge entity.TwoFactorChallenge) error {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	return tx.WithContext(ctx).Create(&challenge).Error
+}
+
+// Update
+func (tr *TwoFactorRepository) SaveTwoFactor(ctx context.Context, tx *gorm.DB, twoFactor entity.TwoFactor) error {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	// Save ikut menyimpan nilai false/kosong, misal saat 2FA dinonaktifkan
+	return tx.WithContext(ctx).Omit("RecoveryCodes").Save(&twoFactor).Error
+}
+
+// UpdateTwoFactorLastUsedStep hanya berhasil jika step lebih baru dari yang terakhir
+// dipakai, sehingga code yang sama tidak bisa dipakai dua kali
+func (tr *TwoFactorRepository) UpdateTwoFactorLastUsedStep(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID, step int64) (bool, error) {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.TwoFactor{}).
+		Where("id = ? AND last_used_step < ?", twoFactorID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+func (tr *TwoFactorRepository) UseRecoveryCode(ctx context.Context, tx *gorm.DB, codeID uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.TwoFactorRecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", codeID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+func (tr *TwoFactorRepository) IncrementTwoFactorChallengeAttempts(ctx context.Context, tx *gorm.DB, challengeID uuid.UUID) error {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.TwoFactorChallenge{}).
+		Where("id = ?", challengeID).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+func (tr *TwoFactorRepository) ConsumeTwoFactorChallenge(ctx context.Context, tx *gorm.DB, challengeID uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.TwoFactorChallenge{}).
+		Where("id = ? AND consumed_at IS NULL", challengeID).
+		Update("consumed_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Delete
+func (tr *TwoFactorRepository) DeleteTwoFactorRecoveryCodes(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID) error {
+	if tx == nil {
+		tx = tr.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Where("two_factor_id = ?", twoFactorID).Delete(&entity.TwoFactorRecoveryCode{}).Error
+}
@@ -2,23 +2,31 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
 	"strings"
+	"time"
 
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
 	IUserRepository interface {
+		// Transaction
+		RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
 		// Get
 		GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error)
 		GetUserByPassword(ctx context.Context, tx *gorm.DB, password string) (entity.User, bool, error)
 		GetUserByID(ctx context.Context, tx *gorm.DB, userID string) (entity.User, bool, error)
 		GetRoleByName(ctx context.Context, tx *gorm.DB, roleName string) (entity.Role, bool, error)
-		GetPermissionsByRoleID(ctx context.Context, tx *gorm.DB, roleID string) ([]string, bool, error)
 		GetRoleByID(ctx context.Context, tx *gorm.DB, roleID string) (entity.Role, bool, error)
 		GetAllNewsWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllNewsRepositoryResponse, error)
 		GetNewsByID(ctx context.Context, tx *gorm.DB, newsID string) (entity.News, bool, error)
@@ -37,22 +45,40 @@ type (
 		GetUserMotivationByUserAndMotivationID(ctx context.Context, tx *gorm.DB, userID string, motivationID string) (entity.UserMotivation, bool, error)
 		GetAllUserMotivation(ctx context.Context, tx *gorm.DB, userID string) ([]entity.UserMotivation, error)
 		GetMessagesByConversationID(ctx context.Context, convoID uuid.UUID) ([]entity.Message, error)
+		GetConversationByID(ctx context.Context, tx *gorm.DB, convoID string) (entity.Conversation, bool, error)
+		GetAllConversationWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest, userID string) (dto.AllConversationRepositoryResponse, error)
+		GetPsychologSuggestions(ctx context.Context, tx *gorm.DB, message string, limit int) ([]entity.Psycholog, error)
+		GetNewsSuggestions(ctx context.Context, tx *gorm.DB, keywords []string, limit int) ([]entity.News, error)
+		GetMotivationSuggestions(ctx context.Context, tx *gorm.DB, keywords []string, limit int) ([]entity.Motivation, error)
+		GetChatUsage(ctx context.Context, tx *gorm.DB, userID string, date string) (entity.ChatUsage, bool, error)
+		GetAllReviewByPsychologIDWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest, psyID string) (dto.AllReviewRepositoryResponse, error)
+		SearchPsychologWithPagination(ctx context.Context, tx *gorm.DB, req dto.PsychologSearchRequest) (dto.AllPsychologSearchRepositoryResponse, error)
+		SearchWithPagination(ctx context.Context, tx *gorm.DB, req dto.SearchRequest) (dto.AllSearchRepositoryResponse, error)
+		GetLatestPaymentByConsultationID(ctx context.Context, tx *gorm.DB, consulID string) (entity.Payment, bool, error)
 
 		// Create
 		RegisterUser(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error)
 		CreateConsultation(ctx context.Context, tx *gorm.DB, consultation entity.Consultation) error
+		CreatePayment(ctx context.Context, tx *gorm.DB, payment entity.Payment) error
 		CreateNewsDetail(ctx context.Context, tx *gorm.DB, newsDetail entity.NewsDetail) error
 		CreateUserMotivation(ctx context.Context, tx *gorm.DB, userMotivation entity.UserMotivation) error
 		CreateConversation(ctx context.Context, tx *gorm.DB, convo entity.Conversation) error
 		SaveMessage(ctx context.Context, tx *gorm.DB, msg entity.Message) error
+		IncrementChatUsage(ctx context.Context, tx *gorm.DB, userID uuid.UUID, date string, messages int, tokens int) error
 
 		// Update
 		UpdateUser(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error)
 		UpdateStatusBookSlot(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, statusBook bool) error
+		BookAvailableSlot(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, heldUntil *time.Time) (bool, error)
 		UpdateConsultation(ctx context.Context, tx *gorm.DB, consultation entity.Consultation) error
+		FlagConversation(ctx context.Context, tx *gorm.DB, convoID uuid.UUID, reason string) error
+		UpdateConversationTitle(ctx context.Context, tx *gorm.DB, convoID uuid.UUID, title string) error
+		UpdateConversationSummary(ctx context.Context, tx *gorm.DB, convoID uuid.UUID, summary string, summarizedCount int) error
+		ResetReviewModeration(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) error
+		RecalculatePsychologRating(ctx context.Context, tx *gorm.DB, psyID uuid.UUID) error
 
 		// Delete
-		DeleteConsultation(ctx context.Context, tx *gorm.DB, consulID string) error
+		DeleteConversationByID(ctx context.Context, tx *gorm.DB, convoID uuid.UUID) error
 	}
 
 	UserRepository struct {
@@ -66,6 +92,11 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	}
 }
 
+// Transaction
+func (ur *UserRepository) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return ur.db.WithContext(ctx).Transaction(fn)
+}
+
 // Get
 func (ur *UserRepository) GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error) {
 	if tx == nil {
@@ -127,18 +158,6 @@ func (ur *UserRepository) GetRoleByID(ctx context.Context, tx *gorm.DB, roleID s
 
 	return role, true, nil
 }
-func (ur *UserRepository) GetPermissionsByRoleID(ctx context.Context, tx *gorm.DB, roleID string) ([]string, bool, error) {
-	if tx == nil {
-		tx = ur.db
-	}
-
-	var endpoints []string
-	if err := tx.WithContext(ctx).Table("permissions").Where("role_id = ?", roleID).Pluck("endpoint", &endpoints).Error; err != nil {
-		return []string{}, false, err
-	}
-
-	return endpoints, true, nil
-}
 func (ur *UserRepository) GetAllNewsWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.AllNewsRepositoryResponse, error) {
 	if tx == nil {
 		tx = ur.db
@@ -159,15 +178,14 @@ func (ur *UserRepository) GetAllNewsWithPagination(ctx context.Context, tx *gorm
 	query := tx.WithContext(ctx).Model(&entity.News{})
 
 	if req.Search != "" {
-		searchValue := "%" + strings.ToLower(req.Search) + "%"
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(body) LIKE ?", searchValue, searchValue)
+		query = query.Where("search_vector @@ websearch_to_tsquery(?, ?)", constants.ENUM_SEARCH_TEXT_CONFIG, req.Search)
 	}
 
 	if err := query.Count(&count).Error; err != nil {
 		return dto.AllNewsRepositoryResponse{}, err
 	}
 
-	if err := query.Order("created_at DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&news).Error; err != nil {
+	if err := query.Scopes(searchRankOrder(req.Search)).Order("created_at DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&news).Error; err != nil {
 		return dto.AllNewsRepositoryResponse{}, err
 	}
 
@@ -215,15 +233,14 @@ func (ur *UserRepository) GetAllMotivationWithPagination(ctx context.Context, tx
 	query := tx.WithContext(ctx).Model(&entity.Motivation{}).Preload("MotivationCategory")
 
 	if req.Search != "" {
-		searchValue := "%" + strings.ToLower(req.Search) + "%"
-		query = query.Where("LOWER(author) LIKE ? OR LOWER(content) LIKE ?", searchValue, searchValue)
+		query = query.Where("motivations.search_vector @@ websearch_to_tsquery(?, ?)", constants.ENUM_SEARCH_TEXT_CONFIG, req.Search)
 	}
 
 	if err := query.Count(&count).Error; err != nil {
 		return dto.AllMotivationRepositoryResponse{}, err
 	}
 
-	if err := query.Order("created_at DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&motivations).Error; err != nil {
+	if err := query.Scopes(searchRankOrder(req.Search)).Order("created_at DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&motivations).Error; err != nil {
 		return dto.AllMotivationRepositoryResponse{}, err
 	}
 
@@ -368,30 +385,35 @@ func (ur *UserRepository) GetAllPsycholog(ctx context.Context, tx *gorm.DB, filt
 		Preload("City.Province").
 		Preload("PsychologLanguages.LanguageMaster").
 		Preload("PsychologSpecializations.Specialization").
-		Preload("Educations")
+		Preload("Educations").
+		Where("psychologs.status = ?", constants.ENUM_PSYCHOLOG_STATUS_APPROVED)
 
 	if filter.Name != "" {
-		query = query.Where("name ILIKE ?", "%"+filter.Name+"%")
+		query = query.Where("psychologs.name ILIKE ?", "%"+filter.Name+"%")
 	}
 
+	// filter relasi memakai subquery agar psycholog tidak terduplikasi oleh join
 	if filter.City != "" {
-		query = query.Joins("JOIN cities ON cities.id = psychologs.city_id").
-			Where("cities.name ILIKE ?", "%"+filter.City+"%")
+		query = query.Where("psychologs.city_id IN (SELECT id FROM cities WHERE name ILIKE ?)", "%"+filter.City+"%")
 	}
 
 	if filter.Province != "" {
-		query = query.Joins("JOIN cities ON id = psychologs.city_id").
-			Joins("JOIN provinces ON provinces.id = cities.province_id").
-			Where("provinces.name ILIKE ?", "%"+filter.Province+"%")
+		query = query.Where(`psychologs.city_id IN (
+			SELECT cities.id FROM cities
+			JOIN provinces ON provinces.id = cities.province_id
+			WHERE provinces.name ILIKE ?)`, "%"+filter.Province+"%")
 	}
 
 	if filter.Specialization != "" {
-		query = query.Joins("JOIN psycholog_specializations ON psycholog_specializations.psycholog_id = psychologs.id").
-			Joins("JOIN specializations ON specializations.id = psycholog_specializations.specialization_id").
-			Where("specializations.name ILIKE ?", "%"+filter.Specialization+"%")
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM psycholog_specializations
+			JOIN specializations ON specializations.id = psycholog_specializations.specialization_id
+			WHERE psycholog_specializations.psycholog_id = psychologs.id
+				AND psycholog_specializations.deleted_at IS NULL
+				AND specializations.name ILIKE ?)`, "%"+filter.Specialization+"%")
 	}
 
-	if err := query.Order("created_at DESC").Find(&psychologs).Error; err != nil {
+	if err := query.Order("psychologs.created_at DESC").Find(&psychologs).Error; err != nil {
 		return []entity.Psycholog{}, err
 	}
 
@@ -410,7 +432,7 @@ func (ur *UserRepository) GetPsychologByID(ctx context.Context, tx *gorm.DB, psy
 		Preload("Educations")
 
 	var psy entity.Psycholog
-	if err := query.Where("id = ?", psyID).Take(&psy).Error; err != nil {
+	if err := query.Where("id = ? AND status = ?", psyID, constants.ENUM_PSYCHOLOG_STATUS_APPROVED).Take(&psy).Error; err != nil {
 		return entity.Psycholog{}, false, err
 	}
 
@@ -450,10 +472,11 @@ func (ur *UserRepository) GetAllAvailableSlot(ctx context.Context, tx *gorm.DB,
 	)
 
 	query := tx.WithContext(ctx).Model(&entity.AvailableSlot{}).Where("psycholog_id = ?", psyID).
+		Where("date IS NULL OR date >= CURRENT_DATE").
 		Preload("Psycholog.Role").
 		Preload("Psycholog.City.Province")
 
-	if err := query.Order("created_at DESC").Find(&availableSlots).Error; err != nil {
+	if err := query.Order("date ASC").Order("start ASC").Find(&availableSlots).Error; err != nil {
 		return dto.AllAvailableSlotRepositoryResponse{}, err
 	}
 
@@ -539,6 +562,420 @@ func (ur *UserRepository) GetMessagesByConversationID(ctx context.Context, convo
 	}
 	return messages, nil
 }
+func (ur *UserRepository) GetConversationByID(ctx context.Context, tx *gorm.DB, convoID string) (entity.Conversation, bool, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var convo entity.Conversation
+	if err := tx.WithContext(ctx).Model(&entity.Conversation{}).
+		Where("id = ?", convoID).
+		Take(&convo).Error; err != nil {
+		return entity.Conversation{}, false, err
+	}
+
+	return convo, true, nil
+}
+func (ur *UserRepository) GetAllConversationWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest, userID string) (dto.AllConversationRepositoryResponse, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var (
+		conversations []entity.Conversation
+		err           error
+		count         int64
+	)
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.Conversation{}).Where("user_id = ?", userID)
+
+	if req.Search != "" {
+		searchValue := "%" + strings.ToLower(req.Search) + "%"
+		query = query.Where("LOWER(title) LIKE ?", searchValue)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.AllConversationRepositoryResponse{}, err
+	}
+
+	if err := query.Order("updated_at DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&conversations).Error; err != nil {
+		return dto.AllConversationRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllConversationRepositoryResponse{
+		Conversations: conversations,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+func (ur *UserRepository) GetPsychologSuggestions(ctx context.Context, tx *gorm.DB, message string, limit int) ([]entity.Psycholog, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	// skor = jumlah spesialisasi, kota, dan bahasa psikolog yang disebut di pesan user,
+	// psikolog dengan kecocokan terbanyak muncul lebih dulu
+	score := `(CASE WHEN EXISTS (
+			SELECT 1 FROM psycholog_specializations ps JOIN specializations s ON s.id = ps.specialization_id
+			WHERE ps.psycholog_id = psychologs.id AND s.name <> '' AND ? LIKE '%' || LOWER(s.name) || '%'
+		) THEN 1 ELSE 0 END)
+		+ (CASE WHEN EXISTS (
+			SELECT 1 FROM cities c
+			WHERE c.id = psychologs.city_id AND c.name <> '' AND ? LIKE '%' || LOWER(c.name) || '%'
+		) THEN 1 ELSE 0 END)
+		+ (CASE WHEN EXISTS (
+			SELECT 1 FROM psycholog_languages pl JOIN language_masters lm ON lm.id = pl.language_master_id
+			WHERE pl.psycholog_id = psychologs.id AND lm.name <> '' AND ? LIKE '%' || LOWER(lm.name) || '%'
+		) THEN 1 ELSE 0 END) DESC`
+
+	msg := strings.ToLower(message)
+
+	var psychologs []entity.Psycholog
+	if err := tx.WithContext(ctx).Model(&entity.Psycholog{}).
+		Preload("City").
+		Preload("PsychologLanguages.LanguageMaster").
+		Preload("PsychologSpecializations.Specialization").
+		Where("psychologs.status = ?", constants.ENUM_PSYCHOLOG_STATUS_APPROVED).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: score, Vars: []interface{}{msg, msg, msg}, WithoutParentheses: true}}).
+		Order("psychologs.created_at DESC").
+		Limit(limit).
+		Find(&psychologs).Error; err != nil {
+		return []entity.Psycholog{}, err
+	}
+
+	return psychologs, nil
+}
+func (ur *UserRepository) GetNewsSuggestions(ctx context.Context, tx *gorm.DB, keywords []string, limit int) ([]entity.News, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.News{})
+	if len(keywords) > 0 {
+		cond := tx.Where("1 = 0")
+		for _, keyword := range keywords {
+			cond = cond.Or("title ILIKE ?", "%"+keyword+"%").Or("body ILIKE ?", "%"+keyword+"%")
+		}
+		query = query.Where(cond)
+	}
+
+	var news []entity.News
+	if err := query.Order("date DESC").Limit(limit).Find(&news).Error; err != nil {
+		return []entity.News{}, err
+	}
+
+	return news, nil
+}
+func (ur *UserRepository) GetMotivationSuggestions(ctx context.Context, tx *gorm.DB, keywords []string, limit int) ([]entity.Motivation, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.Motivation{}).
+		Preload("MotivationCategory").
+		Joins("LEFT JOIN motivation_categories ON motivation_categories.id = motivations.motivation_category_id")
+	if len(keywords) > 0 {
+		cond := tx.Where("1 = 0")
+		for _, keyword := range keywords {
+			cond = cond.Or("motivations.content ILIKE ?", "%"+keyword+"%").Or("motivation_categories.name ILIKE ?", "%"+keyword+"%")
+		}
+		query = query.Where(cond)
+	}
+
+	var motivations []entity.Motivation
+	if err := query.Order("motivations.created_at DESC").Limit(limit).Find(&motivations).Error; err != nil {
+		return []entity.Motivation{}, err
+	}
+
+	return motivations, nil
+}
+func (ur *UserRepository) GetChatUsage(ctx context.Context, tx *gorm.DB, userID string, date string) (entity.ChatUsage, bool, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var usage entity.ChatUsage
+	if err := tx.WithContext(ctx).Where("user_id = ? AND date = ?", userID, date).Take(&usage).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ChatUsage{}, false, nil
+		}
+		return entity.ChatUsage{}, false, err
+	}
+
+	return usage, true, nil
+}
+func (ur *UserRepository) GetAllReviewByPsychologIDWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest, psyID string) (dto.AllReviewRepositoryResponse, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var (
+		consultations []entity.Consultation
+		err           error
+		count         int64
+	)
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.Consultation{}).
+		Joins("JOIN available_slots ON available_slots.id = consultations.available_slot_id").
+		Where("available_slots.psycholog_id = ?", psyID).
+		Where("consultations.status = ? AND consultations.rate > 0", constants.ENUM_CONSULTATION_STATUS_COMPLETED)
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.AllReviewRepositoryResponse{}, err
+	}
+
+	if err := query.Preload("User").
+		Order("COALESCE(consultations.rated_at, consultations.updated_at) DESC").
+		Scopes(Paginate(req.Page, req.PerPage)).
+		Find(&consultations).Error; err != nil {
+		return dto.AllReviewRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllReviewRepositoryResponse{
+		Consultations: consultations,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+func (ur *UserRepository) SearchPsychologWithPagination(ctx context.Context, tx *gorm.DB, req dto.PsychologSearchRequest) (dto.AllPsychologSearchRepositoryResponse, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var (
+		psychologs []entity.Psycholog
+		nextSlots  []entity.AvailableSlot
+		facets     dto.PsychologSearchFacets
+		err        error
+		count      int64
+	)
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	db := tx.WithContext(ctx)
+
+	query := db.Model(&entity.Psycholog{}).Scopes(psychologSearchFilter(req, ""))
+	if err := query.Count(&count).Error; err != nil {
+		return dto.AllPsychologSearchRepositoryResponse{}, err
+	}
+
+	if err := query.
+		Preload("Role").
+		Preload("City.Province").
+		Preload("PsychologLanguages.LanguageMaster").
+		Preload("PsychologSpecializations.Specialization").
+		Preload("Educations").
+		Preload("Practices").
+		Order(psychologSearchOrder(req.Sort)).
+		Order("psychologs.id").
+		Scopes(Paginate(req.Page, req.PerPage)).
+		Find(&psychologs).Error; err != nil {
+		return dto.AllPsychologSearchRepositoryResponse{}, err
+	}
+
+	if len(psychologs) > 0 {
+		psyIDs := make([]uuid.UUID, 0, len(psychologs))
+		for _, psy := range psychologs {
+			psyIDs = append(psyIDs, psy.ID)
+		}
+
+		// slot kosong terdekat per psycholog
+		if err := db.Raw(`
+			SELECT DISTINCT ON (psycholog_id) * FROM available_slots
+			WHERE psycholog_id IN ? AND is_booked = false AND deleted_at IS NULL
+				AND (date + start::time) >= LOCALTIMESTAMP
+			ORDER BY psycholog_id, date, start`, psyIDs).
+			Scan(&nextSlots).Error; err != nil {
+			return dto.AllPsychologSearchRepositoryResponse{}, err
+		}
+	}
+
+	// facet dihitung tanpa filter dimensinya sendiri agar pilihan lain tetap terlihat
+	if err := db.Table("psycholog_languages").
+		Select("language_masters.id::text AS value, language_masters.name AS label, COUNT(DISTINCT psycholog_languages.psycholog_id) AS count").
+		Joins("JOIN language_masters ON language_masters.id = psycholog_languages.language_master_id").
+		Where("psycholog_languages.deleted_at IS NULL").
+		Where("psycholog_languages.psycholog_id IN (?)", db.Model(&entity.Psycholog{}).Scopes(psychologSearchFilter(req, "language")).Select("psychologs.id")).
+		Group("language_masters.id, language_masters.name").
+		Order("count DESC, label ASC").
+		Scan(&facets.Languages).Error; err != nil {
+		return dto.AllPsychologSearchRepositoryResponse{}, err
+	}
+
+	if err := db.Table("psycholog_specializations").
+		Select("specializations.id::text AS value, specializations.name AS label, COUNT(DISTINCT psycholog_specializations.psycholog_id) AS count").
+		Joins("JOIN specializations ON specializations.id = psycholog_specializations.specialization_id").
+		Where("psycholog_specializations.deleted_at IS NULL").
+		Where("psycholog_specializations.psycholog_id IN (?)", db.Model(&entity.Psycholog{}).Scopes(psychologSearchFilter(req, "specialization")).Select("psychologs.id")).
+		Group("specializations.id, specializations.name").
+		Order("count DESC, label ASC").
+		Scan(&facets.Specializations).Error; err != nil {
+		return dto.AllPsychologSearchRepositoryResponse{}, err
+	}
+
+	if err := db.Table("practices").
+		Select("practices.type AS value, practices.type AS label, COUNT(DISTINCT practices.psycholog_id) AS count").
+		Where("practices.deleted_at IS NULL").
+		Where("practices.psycholog_id IN (?)", db.Model(&entity.Psycholog{}).Scopes(psychologSearchFilter(req, "practice_type")).Select("psychologs.id")).
+		Group("practices.type").
+		Order("count DESC").
+		Scan(&facets.PracticeTypes).Error; err != nil {
+		return dto.AllPsychologSearchRepositoryResponse{}, err
+	}
+
+	if err := db.Model(&entity.Psycholog{}).
+		Scopes(psychologSearchFilter(req, "gender")).
+		Select("CASE WHEN psychologs.gender THEN 'true' ELSE 'false' END AS value, COUNT(*) AS count").
+		Where("psychologs.gender IS NOT NULL").
+		Group("psychologs.gender").
+		Scan(&facets.Genders).Error; err != nil {
+		return dto.AllPsychologSearchRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllPsychologSearchRepositoryResponse{
+		Psychologs: psychologs,
+		NextSlots:  nextSlots,
+		Facets:     facets,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+func (ur *UserRepository) SearchWithPagination(ctx context.Context, tx *gorm.DB, req dto.SearchRequest) (dto.AllSearchRepositoryResponse, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var (
+		results []dto.SearchResultResponse
+		counts  []dto.SearchCountResponse
+		err     error
+		count   int64
+	)
+
+	if req.PerPage == 0 {
+		req.PerPage = 10
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	var (
+		parts []string
+		args  []interface{}
+	)
+	for _, searchType := range req.Types {
+		source, ok := searchSources[searchType]
+		if !ok {
+			continue
+		}
+
+		part := fmt.Sprintf(`SELECT '%s' AS type, %s.id, %s AS title, coalesce(%s, '') AS body, ts_rank(%s.search_vector, query) AS rank
+			FROM %s, websearch_to_tsquery(?, ?) AS query
+			WHERE %s.search_vector @@ query AND %s.deleted_at IS NULL`,
+			searchType, source.table, source.title, source.body, source.table, source.table, source.table, source.table)
+		args = append(args, constants.ENUM_SEARCH_TEXT_CONFIG, req.Search)
+
+		if source.table == "psychologs" {
+			part += " AND psychologs.status = ?"
+			args = append(args, constants.ENUM_PSYCHOLOG_STATUS_APPROVED)
+		}
+
+		parts = append(parts, part)
+	}
+
+	if len(parts) == 0 {
+		return dto.AllSearchRepositoryResponse{}, errors.New("no search source")
+	}
+
+	union := strings.Join(parts, " UNION ALL ")
+	db := tx.WithContext(ctx)
+
+	if err := db.Raw("SELECT results.type, COUNT(*) AS count FROM ("+union+") AS results GROUP BY results.type", args...).
+		Scan(&counts).Error; err != nil {
+		return dto.AllSearchRepositoryResponse{}, err
+	}
+
+	for _, c := range counts {
+		count += c.Count
+	}
+
+	// snippet hanya dibuat untuk baris di halaman ini karena ts_headline mahal
+	pageArgs := []interface{}{constants.ENUM_SEARCH_TEXT_CONFIG, constants.ENUM_SEARCH_TEXT_CONFIG, req.Search, searchHeadlineOptions}
+	pageArgs = append(pageArgs, args...)
+	pageArgs = append(pageArgs, req.PerPage, (req.Page-1)*req.PerPage)
+	if err := db.Raw(`SELECT results.type, results.id, results.title, ts_headline(?::regconfig, results.body, websearch_to_tsquery(?, ?), ?) AS snippet, results.rank
+		FROM (`+union+` ORDER BY rank DESC, id LIMIT ? OFFSET ?) AS results
+		ORDER BY results.rank DESC, results.id`, pageArgs...).
+		Scan(&results).Error; err != nil {
+		return dto.AllSearchRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.AllSearchRepositoryResponse{
+		Results: results,
+		Counts:  counts,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, err
+}
+func (ur *UserRepository) GetLatestPaymentByConsultationID(ctx context.Context, tx *gorm.DB, consulID string) (entity.Payment, bool, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var payment entity.Payment
+	if err := tx.WithContext(ctx).Preload("Refunds").Where("consultation_id = ?", consulID).Order("created_at DESC").Take(&payment).Error; err != nil {
+		return entity.Payment{}, false, err
+	}
+
+	return payment, true, nil
+}
 
 // Create
 func (ur *UserRepository) RegisterUser(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error) {
@@ -560,6 +997,13 @@ func (ur *UserRepository) CreateConsultation(ctx context.Context, tx *gorm.DB, c
 
 	return tx.WithContext(ctx).Create(&consultation).Error
 }
+func (ur *UserRepository) CreatePayment(ctx context.Context, tx *gorm.DB, payment entity.Payment) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Create(&payment).Error
+}
 func (ur *UserRepository) CreateNewsDetail(ctx context.Context, tx *gorm.DB, newsDetail entity.NewsDetail) error {
 	if tx == nil {
 		tx = ur.db
@@ -579,14 +1023,45 @@ func (ur *UserRepository) CreateConversation(ctx context.Context, tx *gorm.DB, c
 		tx = ur.db
 	}
 
-	return ur.db.WithContext(ctx).Create(&convo).Error
+	return tx.WithContext(ctx).Create(&convo).Error
 }
 func (ur *UserRepository) SaveMessage(ctx context.Context, tx *gorm.DB, msg entity.Message) error {
 	if tx == nil {
 		tx = ur.db
 	}
 
-	return ur.db.WithContext(ctx).Create(&msg).Error
+	if err := tx.WithContext(ctx).Create(&msg).Error; err != nil {
+		return err
+	}
+
+	// updated_at conversation dipakai untuk urutan daftar percakapan terbaru
+	return tx.WithContext(ctx).
+		Model(&entity.Conversation{}).
+		Where("id = ?", msg.ConversationID).
+		Update("updated_at", time.Now()).Error
+}
+func (ur *UserRepository) IncrementChatUsage(ctx context.Context, tx *gorm.DB, userID uuid.UUID, date string, messages int, tokens int) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	usage := entity.ChatUsage{
+		ID:           uuid.New(),
+		Date:         date,
+		MessageCount: messages,
+		TokenCount:   tokens,
+		UserID:       &userID,
+	}
+
+	// upsert agar request paralel dari user yang sama tetap dijumlahkan dengan benar
+	return tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"message_count": gorm.Expr("chat_usages.message_count + ?", messages),
+			"token_count":   gorm.Expr("chat_usages.token_count + ?", tokens),
+			"updated_at":    time.Now(),
+		}),
+	}).Create(&usage).Error
 }
 
 // Update
@@ -606,24 +1081,259 @@ func (ur *UserRepository) UpdateStatusBookSlot(ctx context.Context, tx *gorm.DB,
 		tx = ur.db
 	}
 
+	// hold pembayaran ikut dihapus, status booking diatur langsung di sini
 	return tx.WithContext(ctx).
 		Model(&entity.AvailableSlot{}).
 		Where("id = ?", slotID).
-		Update("is_booked", statusBook).Error
+		Updates(map[string]interface{}{
+			"is_booked":  statusBook,
+			"held_until": nil,
+		}).Error
+}
+
+// BookAvailableSlot menandai slot sebagai booked hanya jika belum dibooking.
+// heldUntil diisi untuk consultation berbayar yang masih menunggu pembayaran.
+// Return false berarti slot sudah diambil request lain lebih dulu.
+func (ur *UserRepository) BookAvailableSlot(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, heldUntil *time.Time) (bool, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.AvailableSlot{}).
+		Where("id = ? AND is_booked = ?", slotID, false).
+		Updates(map[string]interface{}{
+			"is_booked":  true,
+			"held_until": heldUntil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
 }
 func (ur *UserRepository) UpdateConsultation(ctx context.Context, tx *gorm.DB, consultation entity.Consultation) error {
 	if tx == nil {
 		tx = ur.db
 	}
 
-	return tx.WithContext(ctx).Where("id = ?", consultation.ID).Updates(&consultation).Error
+	// status hanya boleh diubah lewat UpdateConsultationStatus
+	return tx.WithContext(ctx).Where("id = ?", consultation.ID).Omit("status").Updates(&consultation).Error
+}
+func (ur *UserRepository) FlagConversation(ctx context.Context, tx *gorm.DB, convoID uuid.UUID, reason string) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.Conversation{}).
+		Where("id = ?", convoID).
+		Updates(map[string]interface{}{
+			"is_flagged":  true,
+			"flagged_at":  time.Now(),
+			"flag_reason": reason,
+		}).Error
+}
+func (ur *UserRepository) UpdateConversationSummary(ctx context.Context, tx *gorm.DB, convoID uuid.UUID, summary string, summarizedCount int) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	// UpdateColumns agar updated_at tidak berubah, ringkasan bukan aktivitas user.
+	// Update lewat struct agar summary melewati serializer enkripsi.
+	return tx.WithContext(ctx).
+		Model(&entity.Conversation{ID: convoID}).
+		Select("summary", "summarized_count").
+		UpdateColumns(entity.Conversation{
+			Summary:         summary,
+			SummarizedCount: summarizedCount,
+		}).Error
+}
+func (ur *UserRepository) UpdateConversationTitle(ctx context.Context, tx *gorm.DB, convoID uuid.UUID, title string) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.Conversation{}).
+		Where("id = ?", convoID).
+		Update("title", title).Error
+}
+func (ur *UserRepository) ResetReviewModeration(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	// comment baru harus dimoderasi ulang oleh admin
+	return tx.WithContext(ctx).
+		Model(&entity.Consultation{}).
+		Where("id = ?", consulID).
+		Updates(map[string]interface{}{
+			"review_hidden":          false,
+			"review_moderation_note": "",
+			"review_moderated_at":    nil,
+			"review_moderated_by_id": nil,
+		}).Error
+}
+
+// RecalculatePsychologRating menghitung ulang rata-rata dan jumlah rating psycholog
+// dari consultation yang sudah selesai.
+func (ur *UserRepository) RecalculatePsychologRating(ctx context.Context, tx *gorm.DB, psyID uuid.UUID) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Exec(`
+		UPDATE psychologs SET
+			rating_average = agg.average,
+			rating_count = agg.total
+		FROM (
+			SELECT COALESCE(ROUND(AVG(consultations.rate)::numeric, 2), 0) AS average, COUNT(consultations.id) AS total
+			FROM consultations
+			JOIN available_slots ON available_slots.id = consultations.available_slot_id
+			WHERE available_slots.psycholog_id = ?
+				AND consultations.status = ?
+				AND consultations.rate > 0
+				AND consultations.deleted_at IS NULL
+		) AS agg
+		WHERE psychologs.id = ?`,
+		psyID, constants.ENUM_CONSULTATION_STATUS_COMPLETED, psyID,
+	).Error
 }
 
 // Delete
-func (ur *UserRepository) DeleteConsultation(ctx context.Context, tx *gorm.DB, consulID string) error {
+func (ur *UserRepository) DeleteConversationByID(ctx context.Context, tx *gorm.DB, convoID uuid.UUID) error {
 	if tx == nil {
 		tx = ur.db
 	}
 
-	return tx.WithContext(ctx).Where("id = ?", consulID).Delete(&entity.Consultation{}).Error
+	if err := tx.WithContext(ctx).Where("conversation_id = ?", convoID).Delete(&entity.Message{}).Error; err != nil {
+		return err
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", convoID).Delete(&entity.Conversation{}).Error
+}
+
+// psychologSearchFilter menerapkan filter pencarian psycholog. Filter milik dimensi
+// skipFacet dilewati supaya facet dimensi tersebut tetap menghitung semua pilihannya.
+func psychologSearchFilter(req dto.PsychologSearchRequest, skipFacet string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("psychologs.status = ?", constants.ENUM_PSYCHOLOG_STATUS_APPROVED)
+
+		if req.Search != "" {
+			searchValue := "%" + req.Search + "%"
+			db = db.Where("(psychologs.name ILIKE ? OR psychologs.description ILIKE ?)", searchValue, searchValue)
+		}
+
+		if req.CityID != "" {
+			db = db.Where("psychologs.city_id = ?", req.CityID)
+		}
+
+		if req.ProvinceID != "" {
+			db = db.Where("psychologs.city_id IN (SELECT id FROM cities WHERE province_id = ?)", req.ProvinceID)
+		}
+
+		if req.Gender != nil && skipFacet != "gender" {
+			db = db.Where("psychologs.gender = ?", *req.Gender)
+		}
+
+		if len(req.LanguageIDs) > 0 && skipFacet != "language" {
+			db = db.Where(`EXISTS (
+				SELECT 1 FROM psycholog_languages
+				WHERE psycholog_languages.psycholog_id = psychologs.id
+					AND psycholog_languages.deleted_at IS NULL
+					AND psycholog_languages.language_master_id IN ?)`, req.LanguageIDs)
+		}
+
+		if len(req.SpecializationIDs) > 0 && skipFacet != "specialization" {
+			db = db.Where(`EXISTS (
+				SELECT 1 FROM psycholog_specializations
+				WHERE psycholog_specializations.psycholog_id = psychologs.id
+					AND psycholog_specializations.deleted_at IS NULL
+					AND psycholog_specializations.specialization_id IN ?)`, req.SpecializationIDs)
+		}
+
+		// tipe praktik dan rentang tarif harus terpenuhi oleh praktik yang sama
+		practiceConds := []string{"practices.psycholog_id = psychologs.id", "practices.deleted_at IS NULL"}
+		var practiceArgs []interface{}
+		if len(req.PracticeTypes) > 0 && skipFacet != "practice_type" {
+			practiceConds = append(practiceConds, "practices.type IN ?")
+			practiceArgs = append(practiceArgs, req.PracticeTypes)
+		}
+
+		if req.MinFee != nil {
+			practiceConds = append(practiceConds, "practices.fee >= ?")
+			practiceArgs = append(practiceArgs, *req.MinFee)
+		}
+
+		if req.MaxFee != nil {
+			practiceConds = append(practiceConds, "practices.fee <= ?")
+			practiceArgs = append(practiceArgs, *req.MaxFee)
+		}
+
+		if len(practiceArgs) > 0 {
+			db = db.Where("EXISTS (SELECT 1 FROM practices WHERE "+strings.Join(practiceConds, " AND ")+")", practiceArgs...)
+		}
+
+		if req.AvailableWithin > 0 {
+			db = db.Where(`EXISTS (
+				SELECT 1 FROM available_slots
+				WHERE available_slots.psycholog_id = psychologs.id
+					AND available_slots.is_booked = false
+					AND available_slots.deleted_at IS NULL
+					AND (available_slots.date + available_slots.start::time) >= LOCALTIMESTAMP
+					AND available_slots.date <= CURRENT_DATE + ?::int)`, req.AvailableWithin)
+		}
+
+		return db
+	}
+}
+
+func psychologSearchOrder(sort string) string {
+	switch sort {
+	case constants.ENUM_PSYCHOLOG_SORT_RATING:
+		return "psychologs.rating_average DESC, psychologs.rating_count DESC"
+	case constants.ENUM_PSYCHOLOG_SORT_EXPERIENCE:
+		// work_year adalah tahun mulai praktik, semakin kecil semakin berpengalaman
+		return "CASE WHEN psychologs.work_year ~ '^[0-9]{4}$' THEN psychologs.work_year::int END ASC NULLS LAST"
+	case constants.ENUM_PSYCHOLOG_SORT_SOONEST_SLOT:
+		return `(
+			SELECT MIN(available_slots.date + available_slots.start::time) FROM available_slots
+			WHERE available_slots.psycholog_id = psychologs.id
+				AND available_slots.is_booked = false
+				AND available_slots.deleted_at IS NULL
+				AND (available_slots.date + available_slots.start::time) >= LOCALTIMESTAMP
+		) ASC NULLS LAST`
+	default:
+		return "psychologs.created_at DESC"
+	}
+}
+
+// sumber pencarian gabungan, semua tabel memiliki kolom search_vector dari migrasi
+var searchSources = map[string]struct {
+	table string
+	title string
+	body  string
+}{
+	constants.ENUM_SEARCH_TYPE_NEWS:       {table: "news", title: "news.title", body: "news.body"},
+	constants.ENUM_SEARCH_TYPE_MOTIVATION: {table: "motivations", title: "motivations.author", body: "motivations.content"},
+	constants.ENUM_SEARCH_TYPE_PSYCHOLOG:  {table: "psychologs", title: "psychologs.name", body: "psychologs.description"},
+}
+
+var searchHeadlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=35, MinWords=15, MaxFragments=2",
+	helpers.SearchHighlightStart, helpers.SearchHighlightStop)
+
+// searchRankOrder mengurutkan hasil berdasarkan relevansi full-text jika ada kata kunci
+func searchRankOrder(search string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search == "" {
+			return db
+		}
+
+		return db.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(search_vector, websearch_to_tsquery(?, ?)) DESC",
+			Vars:               []interface{}{constants.ENUM_SEARCH_TEXT_CONFIG, search},
+			WithoutParentheses: true,
+		}})
+	}
 }
@@ -0,0 +1,115 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/migrations"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// jalankan dengan make test-integration, TEST_DATABASE_URL harus mengarah ke database kosong
+// karena seluruh migrasi dijalankan ke database tersebut
+func setUpIntegrationDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("connect postgres: %v", err)
+	}
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if dbSQL, err := db.DB(); err == nil {
+			dbSQL.Close()
+		}
+	})
+
+	return db
+}
+
+func TestBookAvailableSlotConcurrent(t *testing.T) {
+	const n = 20
+
+	db := setUpIntegrationDB(t)
+	ur := NewUserRepository(db)
+
+	slot := entity.AvailableSlot{
+		ID:    uuid.New(),
+		Date:  "2026-11-02",
+		Start: "09:00",
+		End:   "10:00",
+	}
+	if err := db.Create(&slot).Error; err != nil {
+		t.Fatalf("create slot: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Unscoped().Delete(&entity.AvailableSlot{}, "id = ?", slot.ID)
+	})
+
+	// semua transaksi dimulai bersamaan sehingga UPDATE kondisional yang menentukan pemenangnya
+	var (
+		start sync.WaitGroup
+		wg    sync.WaitGroup
+		won   = make([]bool, n)
+		errs  = make([]error, n)
+	)
+	start.Add(1)
+	heldUntil := time.Now().Add(15 * time.Minute)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			start.Wait()
+
+			errs[i] = ur.RunInTransaction(context.Background(), func(tx *gorm.DB) error {
+				var err error
+				won[i], err = ur.BookAvailableSlot(context.Background(), tx, slot.ID, &heldUntil)
+				return err
+			})
+		}(i)
+	}
+	start.Done()
+	wg.Wait()
+
+	winners := 0
+	for i := range won {
+		if errs[i] != nil {
+			t.Errorf("unexpected error: %v", errs[i])
+		}
+		if won[i] {
+			winners++
+		}
+	}
+	if winners != 1 {
+		t.Errorf("expected exactly 1 request to book the slot, got %d", winners)
+	}
+
+	var stored entity.AvailableSlot
+	if err := db.First(&stored, "id = ?", slot.ID).Error; err != nil {
+		t.Fatalf("reload slot: %v", err)
+	}
+	if !stored.IsBooked || stored.HeldUntil == nil {
+		t.Errorf("expected slot booked with a hold, got booked=%v held_until=%v", stored.IsBooked, stored.HeldUntil)
+	}
+
+	// slot yang sudah dibooking tidak bisa diambil lagi di luar transaksi
+	if ok, err := ur.BookAvailableSlot(context.Background(), nil, slot.ID, nil); err != nil || ok {
+		t.Errorf("BookAvailableSlot() on a booked slot = %v, %v", ok, err)
+	}
+}
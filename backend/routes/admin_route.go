@@ -7,17 +7,40 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func Admin(route *gin.Engine, adminHandler handler.IAdminHandler, masterHandler handler.IMasterHandler, jwtService service.IJWTService) {
+func Admin(route *gin.Engine, adminHandler handler.IAdminHandler, masterHandler handler.IMasterHandler, jwtService service.IJWTService, rbacService service.IRBACService) {
 	routes := route.Group("/api/v1/admin")
 	{
 		// Authentication
 		routes.POST("/login", adminHandler.Login)
 		routes.POST("/refresh-token", adminHandler.RefreshToken)
+		routes.POST("/logout", adminHandler.Logout)
+		routes.POST("/login/two-factor", adminHandler.VerifyTwoFactorLogin)
+		routes.POST("/login/two-factor/setup", adminHandler.SetupTwoFactorLogin)
 
-		routes.Use(middleware.Authentication(jwtService), middleware.RouteAccessControl(jwtService))
+		routes.Use(middleware.Authentication(jwtService), middleware.RouteAccessControl(jwtService, rbacService))
 		{
-			// Get All Role
+			// Authentication
+			routes.POST("/logout-all", adminHandler.LogoutAll)
+
+			// Two Factor
+			routes.GET("/get-two-factor-status", adminHandler.GetTwoFactorStatus)
+			routes.POST("/setup-two-factor", adminHandler.SetupTwoFactor)
+			routes.POST("/enable-two-factor", adminHandler.EnableTwoFactor)
+			routes.POST("/disable-two-factor", adminHandler.DisableTwoFactor)
+			routes.POST("/regenerate-recovery-codes", adminHandler.RegenerateRecoveryCodes)
+			routes.PATCH("/update-two-factor-requirement/:id", adminHandler.UpdateTwoFactorRequirement)
+
+			// CRUD Role
+			routes.POST("/create-role", adminHandler.CreateRole)
 			routes.GET("/get-all-role", adminHandler.GetAllRole)
+			routes.PATCH("/update-role/:id", adminHandler.UpdateRole)
+			routes.DELETE("/delete-role/:id", adminHandler.DeleteRole)
+
+			// CRUD Permission
+			routes.POST("/create-permission", adminHandler.CreatePermission)
+			routes.GET("/get-all-permission", adminHandler.GetAllPermission)
+			routes.PATCH("/update-permission/:id", adminHandler.UpdatePermission)
+			routes.DELETE("/delete-permission/:id", adminHandler.DeletePermission)
 
 			// CRUD User
 			routes.POST("/create-user", adminHandler.CreateUser)
@@ -65,6 +88,30 @@ func Admin(route *gin.Engine, adminHandler handler.IAdminHandler, masterHandler
 
 			// Specialization
 			routes.GET("/get-all-specialization", adminHandler.GetAllSpecialization)
+
+			// Flagged Conversation
+			routes.GET("/get-all-flagged-conversation", adminHandler.GetAllFlaggedConversation)
+
+			// Login Attempt
+			routes.GET("/get-all-login-attempt", adminHandler.GetAllLoginAttempt)
+			routes.DELETE("/clear-login-attempt/:id", adminHandler.ClearLoginAttempt)
+
+			// Psycholog Application
+			routes.GET("/get-all-psycholog-application", adminHandler.GetAllPsychologApplication)
+			routes.GET("/get-detail-psycholog-application/:id", adminHandler.GetDetailPsychologApplication)
+			routes.GET("/get-psycholog-document/:id", adminHandler.GetPsychologDocument)
+			routes.PATCH("/approve-psycholog-application/:id", adminHandler.ApprovePsychologApplication)
+			routes.PATCH("/reject-psycholog-application/:id", adminHandler.RejectPsychologApplication)
+
+			// Psycholog Change Request
+			routes.GET("/get-all-psycholog-change-request", adminHandler.GetAllPsychologChangeRequest)
+			routes.PATCH("/approve-psycholog-change-request/:id", adminHandler.ApprovePsychologChangeRequest)
+			routes.PATCH("/reject-psycholog-change-request/:id", adminHandler.RejectPsychologChangeRequest)
+
+			// Review
+			routes.GET("/get-all-review", adminHandler.GetAllReview)
+			routes.PATCH("/approve-review/:id", adminHandler.ApproveReview)
+			routes.PATCH("/hide-review/:id", adminHandler.HideReview)
 		}
 	}
 }
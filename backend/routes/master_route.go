@@ -12,5 +12,14 @@ func Master(route *gin.Engine, masterHandler handler.IMasterHandler, jwtService
 		// Get Province & City
 		routes.GET("/get-all-province", masterHandler.GetAllProvince)
 		routes.GET("/get-all-city", masterHandler.GetAllCity)
+
+		// Login Attempt
+		routes.GET("/unlock-account", masterHandler.UnlockAccount)
+
+		// Payment
+		routes.POST("/payment-webhook", masterHandler.PaymentWebhook)
 	}
+
+	// JWKS
+	route.GET("/.well-known/jwks.json", masterHandler.GetJWKS)
 }
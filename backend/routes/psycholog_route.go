@@ -7,16 +7,38 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func Psycholog(route *gin.Engine, psychologHandler handler.IPsychologHandler, masterHandler handler.IMasterHandler, jwtService service.IJWTService) {
+func Psycholog(route *gin.Engine, psychologHandler handler.IPsychologHandler, masterHandler handler.IMasterHandler, jwtService service.IJWTService, rbacService service.IRBACService) {
 	routes := route.Group("/api/v1/psycholog")
 	{
 		routes.POST("/login", psychologHandler.Login)
 		routes.POST("/refresh-token", psychologHandler.RefreshToken)
-		routes.Use(middleware.Authentication(jwtService), middleware.RouteAccessControl(jwtService))
+		routes.POST("/logout", psychologHandler.Logout)
+		routes.POST("/login/two-factor", psychologHandler.VerifyTwoFactorLogin)
+		routes.POST("/login/two-factor/setup", psychologHandler.SetupTwoFactorLogin)
+		routes.POST("/apply", psychologHandler.Apply)
+		routes.Use(middleware.Authentication(jwtService), middleware.RouteAccessControl(jwtService, rbacService))
 		{
+			// Authentication
+			routes.POST("/logout-all", psychologHandler.LogoutAll)
+
+			// Two Factor
+			routes.GET("/get-two-factor-status", psychologHandler.GetTwoFactorStatus)
+			routes.POST("/setup-two-factor", psychologHandler.SetupTwoFactor)
+			routes.POST("/enable-two-factor", psychologHandler.EnableTwoFactor)
+			routes.POST("/disable-two-factor", psychologHandler.DisableTwoFactor)
+			routes.POST("/regenerate-recovery-codes", psychologHandler.RegenerateRecoveryCodes)
+
 			// Psycholog
 			routes.GET("/get-detail-psycholog", masterHandler.GetDetailPsycholog)
 
+			// Profile
+			routes.PATCH("/update-profile", psychologHandler.UpdateProfile)
+			routes.GET("/get-all-profile-change-request", psychologHandler.GetAllProfileChangeRequest)
+			routes.PATCH("/change-password", psychologHandler.ChangePassword)
+			routes.POST("/create-education", psychologHandler.CreateEducation)
+			routes.PATCH("/update-education/:id", psychologHandler.UpdateEducation)
+			routes.DELETE("/delete-education/:id", psychologHandler.DeleteEducation)
+
 			// Practice
 			routes.POST("/create-practice", psychologHandler.CreatePractice)
 			routes.GET("/get-all-practice", psychologHandler.GetAllPractice)
@@ -25,10 +47,18 @@ func Psycholog(route *gin.Engine, psychologHandler handler.IPsychologHandler, ma
 
 			// Available Slot
 			routes.GET("/get-all-available-slot", psychologHandler.GetAllAvailableSlot)
+			routes.POST("/generate-available-slot", psychologHandler.GenerateAvailableSlot)
+
+			// Slot Template
+			routes.POST("/create-slot-template", psychologHandler.CreateSlotTemplate)
+			routes.GET("/get-all-slot-template", psychologHandler.GetAllSlotTemplate)
+			routes.PATCH("/update-slot-template/:id", psychologHandler.UpdateSlotTemplate)
+			routes.DELETE("/delete-slot-template/:id", psychologHandler.DeleteSlotTemplate)
 
 			// Consultation
 			routes.GET("/get-all-consultation", psychologHandler.GetAllConsultation)
 			routes.PATCH("/update-consultation/:id", psychologHandler.UpdateConsultation)
+			routes.GET("/get-consultation-status-history/:id", psychologHandler.GetConsultationStatusHistory)
 		}
 	}
 }
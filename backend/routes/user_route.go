@@ -4,16 +4,18 @@ import (
 	"github.com/Reyysusanto/warasin-web/backend/handler"
 	"github.com/Reyysusanto/warasin-web/backend/middleware"
 	"github.com/Reyysusanto/warasin-web/backend/service"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func User(route *gin.Engine, userHandler handler.IUserHandler, masterHandler handler.IMasterHandler, jwtService service.IJWTService) {
+func User(route *gin.Engine, userHandler handler.IUserHandler, masterHandler handler.IMasterHandler, jwtService service.IJWTService, rbacService service.IRBACService, chatRateLimiter utils.RateLimiter, chatQuotaService service.IChatQuotaService) {
 	routes := route.Group("/api/v1/user")
 	{
 		// Authentication
 		routes.POST("/register", userHandler.Register)
 		routes.POST("/login", userHandler.Login)
 		routes.POST("/refresh-token", userHandler.RefreshToken)
+		routes.POST("/logout", userHandler.Logout)
 
 		// Forgot Password
 		routes.POST("/send-forgot-password-email", userHandler.SendForgotPasswordEmail)
@@ -24,8 +26,11 @@ func User(route *gin.Engine, userHandler handler.IUserHandler, masterHandler han
 		routes.POST("/send-verification-email", userHandler.SendVerificationEmail)
 		routes.GET("/verify-email", userHandler.VerifyEmail)
 
-		routes.Use(middleware.Authentication(jwtService), middleware.RouteAccessControl(jwtService))
+		routes.Use(middleware.Authentication(jwtService), middleware.RouteAccessControl(jwtService, rbacService))
 		{
+			// Authentication
+			routes.POST("/logout-all", userHandler.LogoutAll)
+
 			// User
 			routes.GET("/get-detail-user", userHandler.GetDetailUser)
 			routes.PATCH("/update-user", userHandler.UpdateUser)
@@ -44,10 +49,17 @@ func User(route *gin.Engine, userHandler handler.IUserHandler, masterHandler han
 			routes.GET("get-detail-consultation/:id", userHandler.GetDetailConsultation)
 			routes.PATCH("update-consultation/:id", userHandler.UpdateConsultation)
 			routes.DELETE("delete-consultation/:id", userHandler.DeleteConsultation)
+			routes.GET("get-consultation-status-history/:id", userHandler.GetConsultationStatusHistory)
+			routes.GET("get-consultation-payment/:id", userHandler.GetConsultationPayment)
 
 			// Psycholog
 			routes.GET("get-all-psycholog", userHandler.GetAllPsycholog)
 			routes.GET("get-detail-psycholog/:id", userHandler.GetDetailPsycholog)
+			routes.GET("get-all-psycholog-review/:id", userHandler.GetAllPsychologReview)
+			routes.GET("search-psycholog", userHandler.SearchPsycholog)
+
+			// Search
+			routes.GET("search", userHandler.Search)
 
 			// Practice
 			routes.GET("/get-all-practice/:psyID", userHandler.GetAllPractice)
@@ -64,7 +76,14 @@ func User(route *gin.Engine, userHandler handler.IUserHandler, masterHandler han
 			routes.GET("get-all-user-motivation", userHandler.GetAllUserMotivation)
 
 			// chat
-			routes.POST("/chat", userHandler.Chat)
+			routes.POST("/chat", middleware.ChatRateLimit(chatRateLimiter, chatQuotaService), userHandler.Chat)
+			routes.POST("/chat/stream", middleware.ChatRateLimit(chatRateLimiter, chatQuotaService), userHandler.ChatStream)
+
+			// Conversation
+			routes.GET("/get-all-conversation", userHandler.GetAllConversation)
+			routes.GET("/get-detail-conversation/:id", userHandler.GetDetailConversation)
+			routes.PATCH("/update-conversation/:id", userHandler.UpdateConversation)
+			routes.DELETE("/delete-conversation/:id", userHandler.DeleteConversation)
 		}
 	}
 }
@@ -8,12 +8,14 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/entity"
 	"github.com/Reyysusanto/warasin-web/backend/helpers"
 	"github.com/Reyysusanto/warasin-web/backend/repository"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type (
@@ -21,9 +23,30 @@ type (
 		// Authentication
 		Login(ctx context.Context, req dto.AdminLoginRequest) (dto.AdminLoginResponse, error)
 		RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.RefreshTokenResponse, error)
-
-		// Get Role
+		Logout(ctx context.Context, req dto.LogoutRequest) error
+		LogoutAll(ctx context.Context) error
+
+		// Two Factor
+		VerifyTwoFactorLogin(ctx context.Context, req dto.TwoFactorLoginRequest) (dto.AdminLoginResponse, error)
+		SetupTwoFactorLogin(ctx context.Context, req dto.TwoFactorChallengeSetupRequest) (dto.TwoFactorSetupResponse, error)
+		GetTwoFactorStatus(ctx context.Context) (dto.TwoFactorStatusResponse, error)
+		SetupTwoFactor(ctx context.Context) (dto.TwoFactorSetupResponse, error)
+		EnableTwoFactor(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+		DisableTwoFactor(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+		RegenerateRecoveryCodes(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+		UpdateTwoFactorRequirement(ctx context.Context, req dto.UpdateTwoFactorRequirementRequest) (dto.TwoFactorStatusResponse, error)
+
+		// Role
 		GetAllRole(ctx context.Context) (dto.RolePaginationResponse, error)
+		CreateRole(ctx context.Context, req dto.CreateRoleRequest) (dto.RoleResponse, error)
+		UpdateRole(ctx context.Context, req dto.UpdateRoleRequest) (dto.RoleResponse, error)
+		DeleteRole(ctx context.Context, req dto.DeleteRoleRequest) (dto.RoleResponse, error)
+
+		// Permission
+		CreatePermission(ctx context.Context, req dto.CreatePermissionRequest) (dto.PermissionResponse, error)
+		GetAllPermission(ctx context.Context, req dto.GetAllPermissionRequest) (dto.AllPermissionResponse, error)
+		UpdatePermission(ctx context.Context, req dto.UpdatePermissionRequest) (dto.PermissionResponse, error)
+		DeletePermission(ctx context.Context, req dto.DeletePermissionRequest) (dto.PermissionResponse, error)
 
 		// User
 		CreateUser(ctx context.Context, req dto.CreateUserRequest) (dto.AllUserResponse, error)
@@ -70,27 +93,68 @@ type (
 
 		// Specialization
 		GetAllSpecialization(ctx context.Context) (dto.AllSpecializationResponse, error)
+
+		// Flagged Conversation
+		GetAllFlaggedConversationWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.FlaggedConversationPaginationResponse, error)
+
+		// Login Attempt
+		GetAllLoginAttemptWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.LoginAttemptPaginationResponse, error)
+		ClearLoginAttempt(ctx context.Context, attemptID string) (dto.LoginAttemptResponse, error)
+
+		// Psycholog Application
+		GetAllPsychologApplicationWithPagination(ctx context.Context, req dto.PsychologApplicationPaginationRequest) (dto.PsychologApplicationPaginationResponse, error)
+		GetDetailPsychologApplication(ctx context.Context, psychologID string) (dto.PsychologApplicationResponse, error)
+		GetPsychologDocument(ctx context.Context, documentID string) (dto.PsychologDocumentFileResponse, error)
+		ApprovePsychologApplication(ctx context.Context, req dto.ReviewPsychologApplicationRequest) (dto.PsychologApplicationResponse, error)
+		RejectPsychologApplication(ctx context.Context, req dto.ReviewPsychologApplicationRequest) (dto.PsychologApplicationResponse, error)
+
+		// Psycholog Change Request
+		GetAllPsychologChangeRequestWithPagination(ctx context.Context, req dto.PsychologChangeRequestPaginationRequest) (dto.PsychologChangeRequestPaginationResponse, error)
+		ApprovePsychologChangeRequest(ctx context.Context, req dto.ReviewPsychologChangeRequestRequest) (dto.PsychologChangeRequestResponse, error)
+		RejectPsychologChangeRequest(ctx context.Context, req dto.ReviewPsychologChangeRequestRequest) (dto.PsychologChangeRequestResponse, error)
+
+		// Review
+		GetAllReviewWithPagination(ctx context.Context, req dto.ReviewModerationPaginationRequest) (dto.ReviewModerationPaginationResponse, error)
+		ApproveReview(ctx context.Context, req dto.ModerateReviewRequest) (dto.ReviewModerationResponse, error)
+		HideReview(ctx context.Context, req dto.ModerateReviewRequest) (dto.ReviewModerationResponse, error)
 	}
 
 	AdminService struct {
 		adminRepo  repository.IAdminRepository
 		masterRepo repository.IMasterRepository
 		jwtService IJWTService
+
+		refreshTokenService IRefreshTokenService
+		loginGuard          ILoginGuardService
+		rbacService         IRBACService
+		twoFactorService    ITwoFactorService
 	}
 )
 
-func NewAdminService(adminRepo repository.IAdminRepository, masterRepo repository.IMasterRepository, jwtService IJWTService) *AdminService {
+func NewAdminService(adminRepo repository.IAdminRepository, masterRepo repository.IMasterRepository, jwtService IJWTService, refreshTokenService IRefreshTokenService, loginGuard ILoginGuardService, rbacService IRBACService, twoFactorService ITwoFactorService) *AdminService {
 	return &AdminService{
 		adminRepo:  adminRepo,
 		masterRepo: masterRepo,
 		jwtService: jwtService,
+
+		refreshTokenService: refreshTokenService,
+		loginGuard:          loginGuard,
+		rbacService:         rbacService,
+		twoFactorService:    twoFactorService,
 	}
 }
 
 // Authentication
 func (as *AdminService) Login(ctx context.Context, req dto.AdminLoginRequest) (dto.AdminLoginResponse, error) {
+	if err := as.loginGuard.Check(ctx, constants.ENUM_ROLE_ADMIN, req.Email, req.IPAddress); err != nil {
+		return dto.AdminLoginResponse{}, err
+	}
+
 	user, flag, err := as.adminRepo.GetUserByEmail(ctx, nil, req.Email)
 	if !flag || err != nil {
+		if err := as.loginGuard.RecordFailure(ctx, constants.ENUM_ROLE_ADMIN, req.Email, req.IPAddress, false); err != nil {
+			return dto.AdminLoginResponse{}, err
+		}
 		return dto.AdminLoginResponse{}, dto.ErrEmailNotFound
 	}
 
@@ -100,60 +164,173 @@ func (as *AdminService) Login(ctx context.Context, req dto.AdminLoginRequest) (d
 
 	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
 	if err != nil || !checkPassword {
+		if err := as.loginGuard.RecordFailure(ctx, constants.ENUM_ROLE_ADMIN, req.Email, req.IPAddress, true); err != nil {
+			return dto.AdminLoginResponse{}, err
+		}
 		return dto.AdminLoginResponse{}, dto.ErrPasswordNotMatch
 	}
 
-	endpoints, err := as.adminRepo.GetPermissionsByRoleID(ctx, nil, user.RoleID.String())
+	if err := as.loginGuard.RecordSuccess(ctx, constants.ENUM_ROLE_ADMIN, req.Email); err != nil {
+		return dto.AdminLoginResponse{}, err
+	}
+
+	challenge, required, err := as.twoFactorService.StartChallenge(ctx, user.ID.String(), user.RoleID.String(), constants.ENUM_ROLE_ADMIN, user.Email)
 	if err != nil {
-		return dto.AdminLoginResponse{}, dto.ErrGetPermissionsByRoleID
+		return dto.AdminLoginResponse{}, err
+	}
+
+	if required {
+		return dto.AdminLoginResponse{
+			TwoFactorRequired:      true,
+			TwoFactorSetupRequired: challenge.SetupRequired,
+			ChallengeToken:         challenge.Token,
+			ChallengeExpiresIn:     challenge.ExpiresIn,
+		}, nil
 	}
 
-	accessToken, refreshToken, err := as.jwtService.GenerateToken(user.ID.String(), user.RoleID.String(), endpoints)
+	accessToken, err := as.jwtService.GenerateAccessToken(user.ID.String(), user.RoleID.String())
 	if err != nil {
 		return dto.AdminLoginResponse{}, dto.ErrGenerateToken
 	}
 
+	refreshToken, err := as.refreshTokenService.Issue(ctx, user.ID.String(), user.RoleID.String(), constants.ENUM_ROLE_ADMIN)
+	if err != nil {
+		return dto.AdminLoginResponse{}, err
+	}
+
 	return dto.AdminLoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}, nil
 }
 func (as *AdminService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.RefreshTokenResponse, error) {
-	_, err := as.jwtService.ValidateToken(req.RefreshToken)
+	token, refreshToken, err := as.refreshTokenService.Rotate(ctx, req.RefreshToken, constants.ENUM_ROLE_ADMIN)
+	if err != nil {
+		return dto.RefreshTokenResponse{}, err
+	}
+
+	accessToken, err := as.jwtService.GenerateAccessToken(token.SubjectID.String(), token.RoleID.String())
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrValidateToken
+		return dto.RefreshTokenResponse{}, dto.ErrGenerateAccessToken
 	}
 
-	userID, err := as.jwtService.GetUserIDByToken(req.RefreshToken)
+	return dto.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+func (as *AdminService) Logout(ctx context.Context, req dto.LogoutRequest) error {
+	return as.refreshTokenService.Revoke(ctx, req.RefreshToken, constants.ENUM_ROLE_ADMIN)
+}
+func (as *AdminService) LogoutAll(ctx context.Context) error {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := as.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetUserIDFromToken
+		return dto.ErrGetUserIDFromToken
 	}
 
-	roleID, err := as.jwtService.GetRoleIDByToken(req.RefreshToken)
+	return as.refreshTokenService.RevokeAll(ctx, userID, constants.ENUM_ROLE_ADMIN)
+}
+
+// Two Factor
+func (as *AdminService) VerifyTwoFactorLogin(ctx context.Context, req dto.TwoFactorLoginRequest) (dto.AdminLoginResponse, error) {
+	challenge, recoveryCodes, err := as.twoFactorService.VerifyChallenge(ctx, constants.ENUM_ROLE_ADMIN, req)
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetRoleFromToken
+		return dto.AdminLoginResponse{}, err
 	}
 
-	role, err := as.adminRepo.GetRoleByID(ctx, nil, roleID)
+	accessToken, err := as.jwtService.GenerateAccessToken(challenge.SubjectID.String(), challenge.RoleID.String())
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetRoleFromID
+		return dto.AdminLoginResponse{}, dto.ErrGenerateToken
 	}
 
-	if role.Name != "admin" {
-		return dto.RefreshTokenResponse{}, dto.ErrDeniedAccess
+	refreshToken, err := as.refreshTokenService.Issue(ctx, challenge.SubjectID.String(), challenge.RoleID.String(), constants.ENUM_ROLE_ADMIN)
+	if err != nil {
+		return dto.AdminLoginResponse{}, err
 	}
 
-	permissions, err := as.adminRepo.GetPermissionsByRoleID(ctx, nil, roleID)
+	return dto.AdminLoginResponse{
+		AccessToken:   accessToken,
+		RefreshToken:  refreshToken,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+func (as *AdminService) SetupTwoFactorLogin(ctx context.Context, req dto.TwoFactorChallengeSetupRequest) (dto.TwoFactorSetupResponse, error) {
+	return as.twoFactorService.SetupFromChallenge(ctx, constants.ENUM_ROLE_ADMIN, req)
+}
+func (as *AdminService) GetTwoFactorStatus(ctx context.Context) (dto.TwoFactorStatusResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := as.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetPermissionsByRoleID
+		return dto.TwoFactorStatusResponse{}, dto.ErrGetUserIDFromToken
 	}
 
-	accessToken, _, err := as.jwtService.GenerateToken(userID, roleID, permissions)
+	return as.twoFactorService.GetStatus(ctx, userID, constants.ENUM_ROLE_ADMIN)
+}
+func (as *AdminService) SetupTwoFactor(ctx context.Context) (dto.TwoFactorSetupResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := as.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGenerateAccessToken
+		return dto.TwoFactorSetupResponse{}, dto.ErrGetUserIDFromToken
 	}
 
-	return dto.RefreshTokenResponse{AccessToken: accessToken}, nil
+	user, err := as.adminRepo.GetUserByID(ctx, nil, userID)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, dto.ErrUserNotFound
+	}
+
+	return as.twoFactorService.Setup(ctx, userID, constants.ENUM_ROLE_ADMIN, user.Email)
+}
+func (as *AdminService) EnableTwoFactor(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := as.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	return as.twoFactorService.Enable(ctx, userID, constants.ENUM_ROLE_ADMIN, req)
+}
+func (as *AdminService) DisableTwoFactor(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := as.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	return as.twoFactorService.Disable(ctx, userID, constants.ENUM_ROLE_ADMIN, req)
+}
+func (as *AdminService) RegenerateRecoveryCodes(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := as.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	return as.twoFactorService.RegenerateRecoveryCodes(ctx, userID, constants.ENUM_ROLE_ADMIN, req)
+}
+
+// UpdateTwoFactorRequirement mewajibkan atau melepas kewajiban 2FA untuk akun admin atau psycholog
+func (as *AdminService) UpdateTwoFactorRequirement(ctx context.Context, req dto.UpdateTwoFactorRequirementRequest) (dto.TwoFactorStatusResponse, error) {
+	switch req.RoleName {
+	case constants.ENUM_ROLE_ADMIN:
+		if _, err := as.adminRepo.GetUserByID(ctx, nil, req.SubjectID); err != nil {
+			return dto.TwoFactorStatusResponse{}, dto.ErrUserNotFound
+		}
+	case constants.ENUM_ROLE_PSYCHOLOG:
+		if _, flag, err := as.masterRepo.GetPsychologByID(ctx, nil, req.SubjectID); err != nil || !flag {
+			return dto.TwoFactorStatusResponse{}, dto.ErrPsychologNotFound
+		}
+	default:
+		return dto.TwoFactorStatusResponse{}, dto.ErrInvalidTwoFactorRoleName
+	}
+
+	return as.twoFactorService.SetRequired(ctx, req.SubjectID, req.RoleName, *req.IsRequired)
 }
 
 // Role
@@ -177,6 +354,199 @@ func (as *AdminService) GetAllRole(ctx context.Context) (dto.RolePaginationRespo
 		Data: datas,
 	}, nil
 }
+func (as *AdminService) CreateRole(ctx context.Context, req dto.CreateRoleRequest) (dto.RoleResponse, error) {
+	req.Name = strings.ToLower(strings.TrimSpace(req.Name))
+	if req.Name == "" {
+		return dto.RoleResponse{}, dto.ErrInvalidRoleName
+	}
+
+	if _, flag, _ := as.adminRepo.GetRoleByName(ctx, nil, req.Name); flag {
+		return dto.RoleResponse{}, dto.ErrRoleNameAlreadyExists
+	}
+
+	role := entity.Role{
+		ID:   uuid.New(),
+		Name: req.Name,
+	}
+
+	if err := as.adminRepo.CreateRole(ctx, nil, role); err != nil {
+		return dto.RoleResponse{}, dto.ErrCreateRole
+	}
+
+	return dto.RoleResponse{
+		ID:   &role.ID,
+		Name: role.Name,
+	}, nil
+}
+func (as *AdminService) UpdateRole(ctx context.Context, req dto.UpdateRoleRequest) (dto.RoleResponse, error) {
+	role, err := as.adminRepo.GetRoleByID(ctx, nil, req.ID)
+	if err != nil {
+		return dto.RoleResponse{}, dto.ErrGetRoleFromID
+	}
+
+	// nama role bawaan dipakai di kode (constants.ENUM_ROLE_*), jadi tidak boleh diganti
+	if isBuiltInRole(role.Name) {
+		return dto.RoleResponse{}, dto.ErrBuiltInRoleProtected
+	}
+
+	req.Name = strings.ToLower(strings.TrimSpace(req.Name))
+	if req.Name != "" && req.Name != role.Name {
+		if _, flag, _ := as.adminRepo.GetRoleByName(ctx, nil, req.Name); flag {
+			return dto.RoleResponse{}, dto.ErrRoleNameAlreadyExists
+		}
+		role.Name = req.Name
+	}
+
+	if err := as.adminRepo.UpdateRole(ctx, nil, role); err != nil {
+		return dto.RoleResponse{}, dto.ErrUpdateRole
+	}
+
+	return dto.RoleResponse{
+		ID:   &role.ID,
+		Name: role.Name,
+	}, nil
+}
+func (as *AdminService) DeleteRole(ctx context.Context, req dto.DeleteRoleRequest) (dto.RoleResponse, error) {
+	role, err := as.adminRepo.GetRoleByID(ctx, nil, req.RoleID)
+	if err != nil {
+		return dto.RoleResponse{}, dto.ErrGetRoleFromID
+	}
+
+	if isBuiltInRole(role.Name) {
+		return dto.RoleResponse{}, dto.ErrBuiltInRoleProtected
+	}
+
+	if err := as.adminRepo.DeletePermissionByRoleID(ctx, nil, req.RoleID); err != nil {
+		return dto.RoleResponse{}, dto.ErrDeleteRole
+	}
+
+	if err := as.adminRepo.DeleteRoleByID(ctx, nil, req.RoleID); err != nil {
+		return dto.RoleResponse{}, dto.ErrDeleteRole
+	}
+
+	as.rbacService.InvalidateRole(req.RoleID)
+
+	return dto.RoleResponse{
+		ID:   &role.ID,
+		Name: role.Name,
+	}, nil
+}
+
+func isBuiltInRole(name string) bool {
+	return name == constants.ENUM_ROLE_ADMIN || name == constants.ENUM_ROLE_USER || name == constants.ENUM_ROLE_PSYCHOLOG
+}
+
+// Permission
+func (as *AdminService) CreatePermission(ctx context.Context, req dto.CreatePermissionRequest) (dto.PermissionResponse, error) {
+	method, endpoint, err := normalizePermission(req.Method, req.Endpoint)
+	if err != nil {
+		return dto.PermissionResponse{}, err
+	}
+
+	role, err := as.adminRepo.GetRoleByID(ctx, nil, req.RoleID)
+	if err != nil {
+		return dto.PermissionResponse{}, dto.ErrGetRoleFromID
+	}
+
+	permission := entity.Permission{
+		ID:       uuid.New(),
+		Method:   method,
+		Endpoint: endpoint,
+		RoleID:   &role.ID,
+	}
+
+	if err := as.adminRepo.CreatePermission(ctx, nil, permission); err != nil {
+		return dto.PermissionResponse{}, dto.ErrCreatePermission
+	}
+
+	as.rbacService.InvalidateRole(role.ID.String())
+
+	return toPermissionResponse(permission), nil
+}
+func (as *AdminService) GetAllPermission(ctx context.Context, req dto.GetAllPermissionRequest) (dto.AllPermissionResponse, error) {
+	permissions, err := as.adminRepo.GetAllPermission(ctx, nil, req.RoleID)
+	if err != nil {
+		return dto.AllPermissionResponse{}, dto.ErrGetAllPermission
+	}
+
+	var datas []dto.PermissionResponse
+	for _, permission := range permissions {
+		datas = append(datas, toPermissionResponse(permission))
+	}
+
+	return dto.AllPermissionResponse{
+		Data: datas,
+	}, nil
+}
+func (as *AdminService) UpdatePermission(ctx context.Context, req dto.UpdatePermissionRequest) (dto.PermissionResponse, error) {
+	permission, _, err := as.adminRepo.GetPermissionByID(ctx, nil, req.ID)
+	if err != nil {
+		return dto.PermissionResponse{}, dto.ErrGetPermissionFromID
+	}
+
+	if req.Method == "" {
+		req.Method = permission.Method
+	}
+	if req.Endpoint == "" {
+		req.Endpoint = permission.Endpoint
+	}
+
+	permission.Method, permission.Endpoint, err = normalizePermission(req.Method, req.Endpoint)
+	if err != nil {
+		return dto.PermissionResponse{}, err
+	}
+
+	if err := as.adminRepo.UpdatePermission(ctx, nil, permission); err != nil {
+		return dto.PermissionResponse{}, dto.ErrUpdatePermission
+	}
+
+	if permission.RoleID != nil {
+		as.rbacService.InvalidateRole(permission.RoleID.String())
+	}
+
+	return toPermissionResponse(permission), nil
+}
+func (as *AdminService) DeletePermission(ctx context.Context, req dto.DeletePermissionRequest) (dto.PermissionResponse, error) {
+	permission, _, err := as.adminRepo.GetPermissionByID(ctx, nil, req.PermissionID)
+	if err != nil {
+		return dto.PermissionResponse{}, dto.ErrGetPermissionFromID
+	}
+
+	if err := as.adminRepo.DeletePermissionByID(ctx, nil, req.PermissionID); err != nil {
+		return dto.PermissionResponse{}, dto.ErrDeletePermission
+	}
+
+	if permission.RoleID != nil {
+		as.rbacService.InvalidateRole(permission.RoleID.String())
+	}
+
+	return toPermissionResponse(permission), nil
+}
+
+func normalizePermission(method, endpoint string) (string, string, error) {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "" {
+		method = "*"
+	}
+	if !helpers.IsValidPermissionMethod(method) {
+		return "", "", dto.ErrInvalidPermissionMethod
+	}
+
+	endpoint = strings.TrimSpace(endpoint)
+	if !helpers.IsValidPermissionEndpoint(endpoint) {
+		return "", "", dto.ErrInvalidPermissionEndpoint
+	}
+
+	return method, endpoint, nil
+}
+func toPermissionResponse(permission entity.Permission) dto.PermissionResponse {
+	return dto.PermissionResponse{
+		ID:       &permission.ID,
+		Method:   permission.Method,
+		Endpoint: permission.Endpoint,
+		RoleID:   permission.RoleID,
+	}
+}
 
 // User
 func (as *AdminService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (dto.AllUserResponse, error) {
@@ -248,7 +618,7 @@ func (as *AdminService) CreateUser(ctx context.Context, req dto.CreateUserReques
 		ID:          uuid.New(),
 		Name:        req.Name,
 		Email:       req.Email,
-		Password:    req.Password,
+		Password:    helpers.PasswordHash(req.Password),
 		Image:       req.Image,
 		Gender:      req.Gender,
 		Birthdate:   birthdateFormatted,
@@ -266,7 +636,6 @@ func (as *AdminService) CreateUser(ctx context.Context, req dto.CreateUserReques
 		ID:          user.ID,
 		Name:        user.Name,
 		Email:       user.Email,
-		Password:    user.Password,
 		Birthdate:   user.Birthdate,
 		Image:       user.Image,
 		Gender:      user.Gender,
@@ -304,7 +673,6 @@ func (as *AdminService) GetAllUserWithPagination(ctx context.Context, req dto.Pa
 			ID:          user.ID,
 			Name:        user.Name,
 			Email:       user.Email,
-			Password:    user.Password,
 			Image:       user.Image,
 			Gender:      user.Gender,
 			Birthdate:   user.Birthdate,
@@ -351,7 +719,6 @@ func (as *AdminService) GetDetailUser(ctx context.Context, userID string) (dto.A
 		ID:          user.ID,
 		Name:        user.Name,
 		Email:       user.Email,
-		Password:    user.Password,
 		Image:       user.Image,
 		Gender:      user.Gender,
 		Birthdate:   user.Birthdate,
@@ -477,7 +844,6 @@ func (as *AdminService) UpdateUser(ctx context.Context, req dto.UpdateUserReques
 		ID:          user.ID,
 		Name:        user.Name,
 		Email:       user.Email,
-		Password:    user.Password,
 		Image:       user.Image,
 		Gender:      user.Gender,
 		Birthdate:   user.Birthdate,
@@ -518,7 +884,6 @@ func (as *AdminService) DeleteUser(ctx context.Context, req dto.DeleteUserReques
 		ID:          deletedUser.ID,
 		Name:        deletedUser.Name,
 		Email:       deletedUser.Email,
-		Password:    deletedUser.Password,
 		Birthdate:   deletedUser.Birthdate,
 		PhoneNumber: deletedUser.PhoneNumber,
 		Data01:      deletedUser.Data01,
@@ -1058,8 +1423,9 @@ func (as *AdminService) CreatePsycholog(ctx context.Context, req dto.CreatePsych
 		Name:        req.Name,
 		STRNumber:   req.STRNumber,
 		Email:       req.Email,
-		Password:    req.Password,
+		Password:    helpers.PasswordHash(req.Password),
 		WorkYear:    req.WorkYear,
+		Gender:      req.Gender,
 		Description: req.Description,
 		PhoneNumber: phoneNumberFormatted,
 		Image:       req.Image,
@@ -1077,8 +1443,8 @@ func (as *AdminService) CreatePsycholog(ctx context.Context, req dto.CreatePsych
 		Name:        psycholog.Name,
 		STRNumber:   psycholog.STRNumber,
 		Email:       psycholog.Email,
-		Password:    psycholog.Password,
 		WorkYear:    psycholog.WorkYear,
+		Gender:      psycholog.Gender,
 		Description: psycholog.Description,
 		PhoneNumber: psycholog.PhoneNumber,
 		Image:       psycholog.Image,
@@ -1113,8 +1479,8 @@ func (as *AdminService) GetAllPsychologWithPagination(ctx context.Context, req d
 			Name:        psycholog.Name,
 			STRNumber:   psycholog.STRNumber,
 			Email:       psycholog.Email,
-			Password:    psycholog.Password,
 			WorkYear:    psycholog.WorkYear,
+			Gender:      psycholog.Gender,
 			Description: psycholog.Description,
 			PhoneNumber: psycholog.PhoneNumber,
 			Image:       psycholog.Image,
@@ -1131,6 +1497,8 @@ func (as *AdminService) GetAllPsychologWithPagination(ctx context.Context, req d
 				ID:   psycholog.RoleID,
 				Name: psycholog.Role.Name,
 			},
+			RatingAverage: psycholog.RatingAverage,
+			RatingCount:   psycholog.RatingCount,
 		}
 
 		// LanguageMasters
@@ -1217,6 +1585,10 @@ func (as *AdminService) UpdatePsycholog(ctx context.Context, req dto.UpdatePsych
 		psycholog.WorkYear = req.WorkYear
 	}
 
+	if req.Gender != nil {
+		psycholog.Gender = req.Gender
+	}
+
 	if req.Description != "" {
 		psycholog.Description = req.Description
 	}
@@ -1397,8 +1769,8 @@ func (as *AdminService) UpdatePsycholog(ctx context.Context, req dto.UpdatePsych
 		Name:        psycholog.Name,
 		STRNumber:   psycholog.STRNumber,
 		Email:       psycholog.Email,
-		Password:    psycholog.Password,
 		WorkYear:    psycholog.WorkYear,
+		Gender:      psycholog.Gender,
 		Description: psycholog.Description,
 		PhoneNumber: psycholog.PhoneNumber,
 		Image:       psycholog.Image,
@@ -1438,8 +1810,8 @@ func (as *AdminService) DeletePsycholog(ctx context.Context, req dto.DeletePsych
 		Name:        deletedPsycholog.Name,
 		STRNumber:   deletedPsycholog.STRNumber,
 		Email:       deletedPsycholog.Email,
-		Password:    deletedPsycholog.Password,
 		WorkYear:    deletedPsycholog.WorkYear,
+		Gender:      deletedPsycholog.Gender,
 		Description: deletedPsycholog.Description,
 		PhoneNumber: deletedPsycholog.PhoneNumber,
 		Image:       deletedPsycholog.Image,
@@ -1477,7 +1849,6 @@ func (as *AdminService) GetAllUserMotivationWithPagination(ctx context.Context,
 				ID:          userMotivation.User.ID,
 				Name:        userMotivation.User.Name,
 				Email:       userMotivation.User.Email,
-				Password:    userMotivation.User.Password,
 				Image:       userMotivation.User.Image,
 				Gender:      userMotivation.User.Gender,
 				Birthdate:   userMotivation.User.Birthdate,
@@ -1542,7 +1913,6 @@ func (as *AdminService) GetAllUserNewsWithPagination(ctx context.Context, req dt
 				ID:          userNews.User.ID,
 				Name:        userNews.User.Name,
 				Email:       userNews.User.Email,
-				Password:    userNews.User.Password,
 				Image:       userNews.User.Image,
 				Gender:      userNews.User.Gender,
 				Birthdate:   userNews.User.Birthdate,
@@ -1628,3 +1998,353 @@ func (as *AdminService) GetAllSpecialization(ctx context.Context) (dto.AllSpecia
 		Specializations: datas,
 	}, nil
 }
+
+// Flagged Conversation
+func (as *AdminService) GetAllFlaggedConversationWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.FlaggedConversationPaginationResponse, error) {
+	dataWithPaginate, err := as.adminRepo.GetAllFlaggedConversationWithPagination(ctx, nil, req)
+	if err != nil {
+		return dto.FlaggedConversationPaginationResponse{}, dto.ErrGetFlaggedConversation
+	}
+
+	var datas []dto.FlaggedConversationResponse
+	for _, convo := range dataWithPaginate.Conversations {
+		data := dto.FlaggedConversationResponse{
+			ID:         convo.ID,
+			FlagReason: convo.FlagReason,
+			FlaggedAt:  convo.FlaggedAt,
+			User: dto.AllUserResponse{
+				ID:          convo.User.ID,
+				Name:        convo.User.Name,
+				Email:       convo.User.Email,
+				PhoneNumber: convo.User.PhoneNumber,
+				City: dto.CityResponse{
+					ID:   convo.User.CityID,
+					Name: convo.User.City.Name,
+					Type: convo.User.City.Type,
+					Province: dto.ProvinceResponse{
+						ID:   convo.User.City.ProvinceID,
+						Name: convo.User.City.Province.Name,
+					},
+				},
+				Role: dto.RoleResponse{
+					ID:   convo.User.RoleID,
+					Name: convo.User.Role.Name,
+				},
+			},
+		}
+
+		for _, msg := range convo.Messages {
+			data.Messages = append(data.Messages, dto.MessageResponse{
+				ID:        msg.ID,
+				Sender:    msg.Sender,
+				Content:   msg.Content,
+				CreatedAt: msg.CreatedAt,
+			})
+		}
+
+		datas = append(datas, data)
+	}
+
+	return dto.FlaggedConversationPaginationResponse{
+		Data: datas,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    dataWithPaginate.Page,
+			PerPage: dataWithPaginate.PerPage,
+			MaxPage: dataWithPaginate.MaxPage,
+			Count:   dataWithPaginate.Count,
+		},
+	}, nil
+}
+
+// Login Attempt
+func (as *AdminService) GetAllLoginAttemptWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.LoginAttemptPaginationResponse, error) {
+	return as.loginGuard.GetAllLoginAttemptWithPagination(ctx, req)
+}
+func (as *AdminService) ClearLoginAttempt(ctx context.Context, attemptID string) (dto.LoginAttemptResponse, error) {
+	return as.loginGuard.ClearLoginAttempt(ctx, attemptID)
+}
+
+// Psycholog Application
+func (as *AdminService) GetAllPsychologApplicationWithPagination(ctx context.Context, req dto.PsychologApplicationPaginationRequest) (dto.PsychologApplicationPaginationResponse, error) {
+	if req.Status != "" &&
+		req.Status != constants.ENUM_PSYCHOLOG_STATUS_PENDING &&
+		req.Status != constants.ENUM_PSYCHOLOG_STATUS_APPROVED &&
+		req.Status != constants.ENUM_PSYCHOLOG_STATUS_REJECTED {
+		return dto.PsychologApplicationPaginationResponse{}, dto.ErrInvalidPsychologStatus
+	}
+
+	dataWithPaginate, err := as.adminRepo.GetAllPsychologApplicationWithPagination(ctx, nil, req)
+	if err != nil {
+		return dto.PsychologApplicationPaginationResponse{}, dto.ErrGetAllPsychologApplication
+	}
+
+	datas := []dto.PsychologApplicationResponse{}
+	for _, psycholog := range dataWithPaginate.Psychologs {
+		datas = append(datas, toPsychologApplicationResponse(psycholog))
+	}
+
+	return dto.PsychologApplicationPaginationResponse{
+		Data: datas,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    dataWithPaginate.Page,
+			PerPage: dataWithPaginate.PerPage,
+			MaxPage: dataWithPaginate.MaxPage,
+			Count:   dataWithPaginate.Count,
+		},
+	}, nil
+}
+func (as *AdminService) GetDetailPsychologApplication(ctx context.Context, psychologID string) (dto.PsychologApplicationResponse, error) {
+	psycholog, found, err := as.adminRepo.GetPsychologApplicationByID(ctx, nil, psychologID)
+	if err != nil || !found {
+		return dto.PsychologApplicationResponse{}, dto.ErrPsychologApplicationNotFound
+	}
+
+	return toPsychologApplicationResponse(psycholog), nil
+}
+func (as *AdminService) GetPsychologDocument(ctx context.Context, documentID string) (dto.PsychologDocumentFileResponse, error) {
+	document, found, err := as.adminRepo.GetPsychologDocumentByID(ctx, nil, documentID)
+	if err != nil || !found {
+		return dto.PsychologDocumentFileResponse{}, dto.ErrPsychologDocumentNotFound
+	}
+
+	path := filepath.Join(psychologDocumentDir, filepath.Base(document.FileName))
+	if _, err := os.Stat(path); err != nil {
+		return dto.PsychologDocumentFileResponse{}, dto.ErrPsychologDocumentNotFound
+	}
+
+	return dto.PsychologDocumentFileResponse{
+		Path:         path,
+		OriginalName: document.OriginalName,
+		ContentType:  document.ContentType,
+	}, nil
+}
+func (as *AdminService) ApprovePsychologApplication(ctx context.Context, req dto.ReviewPsychologApplicationRequest) (dto.PsychologApplicationResponse, error) {
+	return as.reviewPsychologApplication(ctx, req, constants.ENUM_PSYCHOLOG_STATUS_APPROVED)
+}
+func (as *AdminService) RejectPsychologApplication(ctx context.Context, req dto.ReviewPsychologApplicationRequest) (dto.PsychologApplicationResponse, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return dto.PsychologApplicationResponse{}, dto.ErrRejectReasonRequired
+	}
+
+	return as.reviewPsychologApplication(ctx, req, constants.ENUM_PSYCHOLOG_STATUS_REJECTED)
+}
+func (as *AdminService) reviewPsychologApplication(ctx context.Context, req dto.ReviewPsychologApplicationRequest, toStatus string) (dto.PsychologApplicationResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	adminID, err := as.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	psycholog, found, err := as.adminRepo.GetPsychologApplicationByID(ctx, nil, req.ID)
+	if err != nil || !found {
+		return dto.PsychologApplicationResponse{}, dto.ErrPsychologApplicationNotFound
+	}
+
+	if psycholog.Status != constants.ENUM_PSYCHOLOG_STATUS_PENDING {
+		return dto.PsychologApplicationResponse{}, dto.ErrPsychologApplicationAlreadyReview
+	}
+
+	updated, err := as.adminRepo.UpdatePsychologReview(ctx, nil, psycholog.ID, constants.ENUM_PSYCHOLOG_STATUS_PENDING, toStatus, strings.TrimSpace(req.Reason), adminUUID)
+	if err != nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrReviewPsychologApplication
+	}
+
+	if !updated {
+		return dto.PsychologApplicationResponse{}, dto.ErrPsychologApplicationAlreadyReview
+	}
+
+	psycholog, _, err = as.adminRepo.GetPsychologApplicationByID(ctx, nil, req.ID)
+	if err != nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrPsychologApplicationNotFound
+	}
+
+	sendPsychologApplicationEmail(psycholog)
+
+	return toPsychologApplicationResponse(psycholog), nil
+}
+
+// Psycholog Change Request
+func (as *AdminService) GetAllPsychologChangeRequestWithPagination(ctx context.Context, req dto.PsychologChangeRequestPaginationRequest) (dto.PsychologChangeRequestPaginationResponse, error) {
+	if req.Status != "" &&
+		req.Status != constants.ENUM_CHANGE_REQUEST_STATUS_PENDING &&
+		req.Status != constants.ENUM_CHANGE_REQUEST_STATUS_APPROVED &&
+		req.Status != constants.ENUM_CHANGE_REQUEST_STATUS_REJECTED {
+		return dto.PsychologChangeRequestPaginationResponse{}, dto.ErrInvalidChangeRequestStatus
+	}
+
+	dataWithPaginate, err := as.adminRepo.GetAllPsychologChangeRequestWithPagination(ctx, nil, req)
+	if err != nil {
+		return dto.PsychologChangeRequestPaginationResponse{}, dto.ErrGetAllPsychologChangeRequest
+	}
+
+	return dto.PsychologChangeRequestPaginationResponse{
+		Data: toPsychologChangeRequestResponses(dataWithPaginate.ChangeRequests),
+		PaginationResponse: dto.PaginationResponse{
+			Page:    dataWithPaginate.Page,
+			PerPage: dataWithPaginate.PerPage,
+			MaxPage: dataWithPaginate.MaxPage,
+			Count:   dataWithPaginate.Count,
+		},
+	}, nil
+}
+func (as *AdminService) ApprovePsychologChangeRequest(ctx context.Context, req dto.ReviewPsychologChangeRequestRequest) (dto.PsychologChangeRequestResponse, error) {
+	return as.reviewPsychologChangeRequest(ctx, req, constants.ENUM_CHANGE_REQUEST_STATUS_APPROVED)
+}
+func (as *AdminService) RejectPsychologChangeRequest(ctx context.Context, req dto.ReviewPsychologChangeRequestRequest) (dto.PsychologChangeRequestResponse, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return dto.PsychologChangeRequestResponse{}, dto.ErrRejectReasonRequired
+	}
+
+	return as.reviewPsychologChangeRequest(ctx, req, constants.ENUM_CHANGE_REQUEST_STATUS_REJECTED)
+}
+func (as *AdminService) reviewPsychologChangeRequest(ctx context.Context, req dto.ReviewPsychologChangeRequestRequest, toStatus string) (dto.PsychologChangeRequestResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	adminID, err := as.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.PsychologChangeRequestResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return dto.PsychologChangeRequestResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	changeRequest, found, err := as.adminRepo.GetPsychologChangeRequestByID(ctx, nil, req.ID)
+	if err != nil || !found {
+		return dto.PsychologChangeRequestResponse{}, dto.ErrPsychologChangeRequestNotFound
+	}
+
+	if changeRequest.Status != constants.ENUM_CHANGE_REQUEST_STATUS_PENDING {
+		return dto.PsychologChangeRequestResponse{}, dto.ErrPsychologChangeRequestAlreadyReview
+	}
+
+	if toStatus == constants.ENUM_CHANGE_REQUEST_STATUS_APPROVED && changeRequest.Field == constants.ENUM_CHANGE_REQUEST_FIELD_EMAIL {
+		// email bisa saja sudah dipakai akun lain sejak request diajukan
+		if _, flag, err := as.masterRepo.GetPsychologByEmail(ctx, nil, changeRequest.NewValue); flag || err == nil {
+			return dto.PsychologChangeRequestResponse{}, dto.ErrEmailAlreadyExists
+		}
+	}
+
+	err = as.adminRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		updated, err := as.adminRepo.UpdatePsychologChangeRequestReview(ctx, tx, changeRequest.ID, toStatus, strings.TrimSpace(req.Reason), adminUUID)
+		if err != nil {
+			return dto.ErrReviewPsychologChangeRequest
+		}
+
+		if !updated {
+			return dto.ErrPsychologChangeRequestAlreadyReview
+		}
+
+		if toStatus != constants.ENUM_CHANGE_REQUEST_STATUS_APPROVED {
+			return nil
+		}
+
+		if err := as.adminRepo.UpdatePsychologSensitiveField(ctx, tx, *changeRequest.PsychologID, changeRequest.Field, changeRequest.NewValue); err != nil {
+			return dto.ErrUpdatePsycholog
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.PsychologChangeRequestResponse{}, err
+	}
+
+	changeRequest, _, err = as.adminRepo.GetPsychologChangeRequestByID(ctx, nil, req.ID)
+	if err != nil {
+		return dto.PsychologChangeRequestResponse{}, dto.ErrPsychologChangeRequestNotFound
+	}
+
+	return toPsychologChangeRequestResponses([]entity.PsychologChangeRequest{changeRequest})[0], nil
+}
+
+// Review
+func (as *AdminService) GetAllReviewWithPagination(ctx context.Context, req dto.ReviewModerationPaginationRequest) (dto.ReviewModerationPaginationResponse, error) {
+	if req.Status != "" &&
+		req.Status != constants.ENUM_REVIEW_STATUS_UNMODERATED &&
+		req.Status != constants.ENUM_REVIEW_STATUS_VISIBLE &&
+		req.Status != constants.ENUM_REVIEW_STATUS_HIDDEN {
+		return dto.ReviewModerationPaginationResponse{}, dto.ErrInvalidReviewStatus
+	}
+
+	dataWithPaginate, err := as.adminRepo.GetAllReviewWithPagination(ctx, nil, req)
+	if err != nil {
+		return dto.ReviewModerationPaginationResponse{}, dto.ErrGetAllReview
+	}
+
+	var datas []dto.ReviewModerationResponse
+	for _, consul := range dataWithPaginate.Consultations {
+		datas = append(datas, toReviewModerationResponse(consul))
+	}
+
+	return dto.ReviewModerationPaginationResponse{
+		Data: datas,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    dataWithPaginate.Page,
+			PerPage: dataWithPaginate.PerPage,
+			MaxPage: dataWithPaginate.MaxPage,
+			Count:   dataWithPaginate.Count,
+		},
+	}, nil
+}
+func (as *AdminService) ApproveReview(ctx context.Context, req dto.ModerateReviewRequest) (dto.ReviewModerationResponse, error) {
+	return as.moderateReview(ctx, req, false)
+}
+func (as *AdminService) HideReview(ctx context.Context, req dto.ModerateReviewRequest) (dto.ReviewModerationResponse, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return dto.ReviewModerationResponse{}, dto.ErrHideReasonRequired
+	}
+
+	return as.moderateReview(ctx, req, true)
+}
+func (as *AdminService) moderateReview(ctx context.Context, req dto.ModerateReviewRequest, hidden bool) (dto.ReviewModerationResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	adminID, err := as.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.ReviewModerationResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return dto.ReviewModerationResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	consul, found, err := as.adminRepo.GetReviewByID(ctx, nil, req.ID)
+	if err != nil || !found {
+		return dto.ReviewModerationResponse{}, dto.ErrReviewNotFound
+	}
+
+	// rating tetap masuk agregat, yang disembunyikan hanya comment
+	if err := as.adminRepo.UpdateReviewModeration(ctx, nil, consul.ID, hidden, strings.TrimSpace(req.Reason), adminUUID); err != nil {
+		return dto.ReviewModerationResponse{}, dto.ErrModerateReview
+	}
+
+	consul, _, err = as.adminRepo.GetReviewByID(ctx, nil, req.ID)
+	if err != nil {
+		return dto.ReviewModerationResponse{}, dto.ErrReviewNotFound
+	}
+
+	return toReviewModerationResponse(consul), nil
+}
+func toReviewModerationResponse(consul entity.Consultation) dto.ReviewModerationResponse {
+	return dto.ReviewModerationResponse{
+		ConsultationID: consul.ID,
+		Rate:           consul.Rate,
+		Comment:        consul.Comment,
+		Hidden:         consul.ReviewHidden,
+		ModerationNote: consul.ReviewModerationNote,
+		ModeratedAt:    consul.ReviewModeratedAt,
+		RatedAt:        consul.RatedAt,
+		UserID:         consul.UserID,
+		UserName:       consul.User.Name,
+		PsychologID:    consul.AvailableSlot.PsychologID,
+		PsychologName:  consul.AvailableSlot.Psycholog.Name,
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"math"
+	"os"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// ICancellationService dipakai bersama oleh pembatalan dari user dan psycholog
+	ICancellationService interface {
+		// Apply mengevaluasi kebijakan pembatalan dan mencatat refund, dipanggil di dalam
+		// transaksi yang sama dengan perubahan status consultation
+		Apply(ctx context.Context, tx *gorm.DB, consul entity.Consultation, toStatus int) (CancellationOutcome, error)
+		// Settle dipanggil setelah transaksi commit: mengirim refund ke gateway dan
+		// memberi tahu user serta psycholog
+		Settle(ctx context.Context, consul entity.Consultation, outcome CancellationOutcome) dto.CancellationResponse
+		RetryRefunds(ctx context.Context) (int, error)
+	}
+
+	// CancellationOutcome adalah hasil Apply. Refund nil jika consultation gratis,
+	// belum dibayar, atau kebijakan tidak memberikan refund.
+	CancellationOutcome struct {
+		Cancellation dto.CancellationResponse
+		Refund       *entity.Refund
+	}
+
+	CancellationService struct {
+		paymentRepo repository.IPaymentRepository
+		gateway     utils.PaymentGateway
+		cfg         *config.CancellationConfig
+	}
+)
+
+func NewCancellationService(paymentRepo repository.IPaymentRepository, gateway utils.PaymentGateway, cfg *config.CancellationConfig) *CancellationService {
+	return &CancellationService{
+		paymentRepo: paymentRepo,
+		gateway:     gateway,
+		cfg:         cfg,
+	}
+}
+
+func (cs *CancellationService) Apply(ctx context.Context, tx *gorm.DB, consul entity.Consultation, toStatus int) (CancellationOutcome, error) {
+	cancellation, err := cs.evaluate(consul, toStatus, time.Now())
+	if err != nil {
+		return CancellationOutcome{}, err
+	}
+
+	outcome := CancellationOutcome{
+		Cancellation: cancellation,
+	}
+
+	payment, _, err := cs.paymentRepo.GetPaidPaymentByConsultationID(ctx, tx, consul.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return outcome, nil
+		}
+
+		return CancellationOutcome{}, dto.ErrCreateRefund
+	}
+
+	amount := payment.Amount * int64(cancellation.RefundPercent) / 100
+	outcome.Cancellation.RefundAmount = amount
+	if amount == 0 {
+		return outcome, nil
+	}
+
+	refund := entity.Refund{
+		ID:             uuid.New(),
+		Amount:         amount,
+		Percent:        cancellation.RefundPercent,
+		Rule:           cancellation.Rule,
+		HoursBefore:    cancellation.HoursBefore,
+		Status:         constants.ENUM_REFUND_STATUS_PENDING,
+		Gateway:        payment.Gateway,
+		PaymentID:      &payment.ID,
+		ConsultationID: &consul.ID,
+	}
+	if err := cs.paymentRepo.CreateRefund(ctx, tx, refund); err != nil {
+		return CancellationOutcome{}, dto.ErrCreateRefund
+	}
+
+	refund.Payment = payment
+	outcome.Refund = &refund
+	outcome.Cancellation.RefundStatus = refund.Status
+
+	return outcome, nil
+}
+func (cs *CancellationService) Settle(ctx context.Context, consul entity.Consultation, outcome CancellationOutcome) dto.CancellationResponse {
+	res := outcome.Cancellation
+	if outcome.Refund != nil {
+		refund := processRefund(ctx, cs.gateway, cs.paymentRepo, *outcome.Refund)
+		res.RefundStatus = refund.Status
+	}
+
+	// email dikirim di background agar response pembatalan tidak menunggu SMTP
+	go sendConsultationCancellationEmail(consul, res)
+
+	return res
+}
+
+// RetryRefunds memproses ulang refund yang gagal atau tertahan, dijalankan berkala (cron)
+func (cs *CancellationService) RetryRefunds(ctx context.Context) (int, error) {
+	pendingBefore := time.Now().Add(-time.Duration(constants.ENUM_REFUND_RETRY_AFTER_MINUTES) * time.Minute)
+
+	refunds, err := cs.paymentRepo.GetRetryableRefunds(ctx, nil, pendingBefore, constants.ENUM_PAYMENT_EXPIRE_BATCH)
+	if err != nil {
+		return 0, dto.ErrRetryRefund
+	}
+
+	total := 0
+	for _, refund := range refunds {
+		if processRefund(ctx, cs.gateway, cs.paymentRepo, refund).Status == constants.ENUM_REFUND_STATUS_SUCCEEDED {
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+// evaluate menentukan aturan dan persentase refund. Pembatalan oleh user mengikuti
+// tier jam sebelum sesi dimulai, setelah sesi dimulai tidak ada refund. No-show
+// hanya bisa ditandai setelah sesi dimulai.
+func (cs *CancellationService) evaluate(consul entity.Consultation, toStatus int, now time.Time) (dto.CancellationResponse, error) {
+	start, err := helpers.ParseSessionStart(normalizeSlotDate(consul.Date), consul.AvailableSlot.Start, time.Local)
+	if err != nil {
+		return dto.CancellationResponse{}, dto.ErrParseConsultationDate
+	}
+
+	if toStatus == constants.ENUM_CONSULTATION_STATUS_NO_SHOW && now.Before(start) {
+		return dto.CancellationResponse{}, dto.ErrNoShowBeforeSession
+	}
+
+	res := dto.CancellationResponse{
+		HoursBefore: math.Floor(start.Sub(now).Hours()*100) / 100,
+	}
+
+	switch toStatus {
+	case constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG:
+		res.Rule = constants.ENUM_CANCELLATION_RULE_PSYCHOLOG
+		res.RefundPercent = cs.cfg.PsychologRefundPercent
+	case constants.ENUM_CONSULTATION_STATUS_NO_SHOW:
+		res.Rule = constants.ENUM_CANCELLATION_RULE_NO_SHOW
+		res.RefundPercent = cs.cfg.NoShowRefundPercent
+	default:
+		res.Rule = constants.ENUM_CANCELLATION_RULE_USER
+		for _, tier := range cs.cfg.Tiers {
+			if res.HoursBefore >= float64(tier.MinHoursBefore) {
+				res.RefundPercent = tier.RefundPercent
+				break
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// processRefund mengirim refund ke gateway dan menyimpan hasilnya. Refund yang gagal
+// tetap tercatat dengan status failed untuk diproses ulang oleh RetryRefunds.
+func processRefund(ctx context.Context, gateway utils.PaymentGateway, paymentRepo repository.IPaymentRepository, refund entity.Refund) entity.Refund {
+	result, err := gateway.Refund(ctx, utils.PaymentRefundRequest{
+		RefundID:         refund.ID.String(),
+		PaymentReference: refund.Payment.Reference,
+		Amount:           refund.Amount,
+		Reason:           refund.Rule,
+	})
+
+	now := time.Now()
+	refund.ProcessedAt = &now
+	if err != nil {
+		refund.Status = constants.ENUM_REFUND_STATUS_FAILED
+		refund.FailureReason = err.Error()
+	} else {
+		refund.Status = constants.ENUM_REFUND_STATUS_SUCCEEDED
+		refund.Reference = result.Reference
+		refund.FailureReason = ""
+	}
+
+	if err := paymentRepo.UpdateRefund(ctx, nil, refund); err != nil {
+		log.Printf("failed to update refund %s: %v", refund.ID, err)
+	}
+
+	return refund
+}
+
+// sendConsultationCancellationEmail memberi tahu user dan psycholog.
+// Kegagalan kirim email hanya dicatat agar tidak membatalkan proses pembatalan.
+func sendConsultationCancellationEmail(consul entity.Consultation, cancellation dto.CancellationResponse) {
+	title := "Consultation Canceled"
+	reason := "has been canceled by the client"
+	switch cancellation.Rule {
+	case constants.ENUM_CANCELLATION_RULE_PSYCHOLOG:
+		reason = "has been canceled by the psychologist"
+	case constants.ENUM_CANCELLATION_RULE_NO_SHOW:
+		title = "Consultation Marked as No-Show"
+		reason = "has been marked as no-show by the psychologist"
+	}
+
+	var refund string
+	if cancellation.RefundAmount > 0 {
+		refund = fmt.Sprintf("Rp%d (%d%% of the consultation fee), status: %s", cancellation.RefundAmount, cancellation.RefundPercent, cancellation.RefundStatus)
+	} else if consul.Fee > 0 {
+		refund = "No refund applies under the cancellation policy"
+	}
+
+	psycholog := consul.AvailableSlot.Psycholog
+	recipients := []struct {
+		name    string
+		email   string
+		message string
+	}{
+		{
+			name:    consul.User.Name,
+			email:   consul.User.Email,
+			message: fmt.Sprintf("Your consultation with %s on %s at %s %s.", psycholog.Name, consul.Date, consul.AvailableSlot.Start, reason),
+		},
+		{
+			name:    psycholog.Name,
+			email:   psycholog.Email,
+			message: fmt.Sprintf("The consultation with %s on %s at %s %s.", consul.User.Name, consul.Date, consul.AvailableSlot.Start, reason),
+		},
+	}
+
+	for _, recipient := range recipients {
+		if recipient.email == "" {
+			continue
+		}
+
+		draftEmail, err := makeConsultationCancellationEmail(recipient.name, title, recipient.message, refund)
+		if err != nil {
+			log.Printf("failed to make consultation cancellation email: %v", err)
+			return
+		}
+
+		if err := utils.SendEmail(recipient.email, draftEmail["subject"], draftEmail["body"]); err != nil {
+			log.Printf("failed to send consultation cancellation email: %v", err)
+		}
+	}
+}
+func makeConsultationCancellationEmail(name string, title string, message string, refund string) (map[string]string, error) {
+	readHTML, err := os.ReadFile("utils/email_template/consultation_cancellation_mail.html")
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		Name    string
+		Title   string
+		Message string
+		Refund  string
+	}{
+		Name:    name,
+		Title:   title,
+		Message: message,
+		Refund:  refund,
+	}
+
+	tmpl, err := htmltemplate.New("custom").Parse(string(readHTML))
+	if err != nil {
+		return nil, err
+	}
+
+	var strMail bytes.Buffer
+	if err := tmpl.Execute(&strMail, data); err != nil {
+		return nil, err
+	}
+
+	draftEmail := map[string]string{
+		"subject": "warasin",
+		"body":    strMail.String(),
+	}
+
+	return draftEmail, nil
+}
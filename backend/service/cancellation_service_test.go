@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+)
+
+func TestCancellationEvaluate(t *testing.T) {
+	cs := NewCancellationService(nil, nil, &config.CancellationConfig{
+		// urut dari jam terbesar seperti hasil parseCancellationTiers
+		Tiers: []config.CancellationTier{
+			{MinHoursBefore: 48, RefundPercent: 100},
+			{MinHoursBefore: 24, RefundPercent: 50},
+			{MinHoursBefore: 0, RefundPercent: 25},
+		},
+		NoShowRefundPercent:    10,
+		PsychologRefundPercent: 100,
+	})
+
+	start := time.Date(2026, 11, 2, 10, 0, 0, 0, time.Local)
+	consul := entity.Consultation{
+		Date:          "2026-11-02",
+		AvailableSlot: entity.AvailableSlot{Start: "10:00"},
+	}
+
+	tests := []struct {
+		name        string
+		status      int
+		before      time.Duration
+		wantRule    string
+		wantPercent int
+		wantHours   float64
+		wantErr     error
+	}{
+		{
+			name:        "user exactly on the highest tier",
+			status:      constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER,
+			before:      48 * time.Hour,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_USER,
+			wantPercent: 100,
+			wantHours:   48,
+		},
+		{
+			name:        "user one minute below the highest tier",
+			status:      constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER,
+			before:      48*time.Hour - time.Minute,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_USER,
+			wantPercent: 50,
+			wantHours:   47.98,
+		},
+		{
+			name:        "user exactly on the middle tier",
+			status:      constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER,
+			before:      24 * time.Hour,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_USER,
+			wantPercent: 50,
+			wantHours:   24,
+		},
+		{
+			name:        "user right before the session",
+			status:      constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER,
+			before:      time.Minute,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_USER,
+			wantPercent: 25,
+			wantHours:   0.01,
+		},
+		{
+			name:        "user after the session started",
+			status:      constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER,
+			before:      -time.Hour,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_USER,
+			wantPercent: 0,
+			wantHours:   -1,
+		},
+		{
+			name:        "psycholog cancel ignores tiers",
+			status:      constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG,
+			before:      time.Hour,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_PSYCHOLOG,
+			wantPercent: 100,
+			wantHours:   1,
+		},
+		{
+			name:        "psycholog cancel after the session started",
+			status:      constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG,
+			before:      -30 * time.Minute,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_PSYCHOLOG,
+			wantPercent: 100,
+			wantHours:   -0.5,
+		},
+		{
+			name:        "no-show after the session started",
+			status:      constants.ENUM_CONSULTATION_STATUS_NO_SHOW,
+			before:      -30 * time.Minute,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_NO_SHOW,
+			wantPercent: 10,
+			wantHours:   -0.5,
+		},
+		{
+			name:        "no-show at the session start",
+			status:      constants.ENUM_CONSULTATION_STATUS_NO_SHOW,
+			before:      0,
+			wantRule:    constants.ENUM_CANCELLATION_RULE_NO_SHOW,
+			wantPercent: 10,
+			wantHours:   0,
+		},
+		{
+			name:    "no-show before the session starts",
+			status:  constants.ENUM_CONSULTATION_STATUS_NO_SHOW,
+			before:  72 * time.Hour,
+			wantErr: dto.ErrNoShowBeforeSession,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := cs.evaluate(consul, tt.status, start.Add(-tt.before))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if res.Rule != tt.wantRule || res.RefundPercent != tt.wantPercent || res.HoursBefore != tt.wantHours {
+				t.Errorf("evaluate() = %s %d%% %.2fh, want %s %d%% %.2fh", res.Rule, res.RefundPercent, res.HoursBefore, tt.wantRule, tt.wantPercent, tt.wantHours)
+			}
+		})
+	}
+}
+
+func TestCancellationEvaluateInvalidDate(t *testing.T) {
+	cs := NewCancellationService(nil, nil, &config.CancellationConfig{})
+
+	consul := entity.Consultation{Date: "02-11-2026", AvailableSlot: entity.AvailableSlot{Start: "10:00"}}
+	if _, err := cs.evaluate(consul, constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER, time.Now()); !errors.Is(err, dto.ErrParseConsultationDate) {
+		t.Fatalf("expected %v, got %v", dto.ErrParseConsultationDate, err)
+	}
+}
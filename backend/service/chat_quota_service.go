@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	IChatQuotaService interface {
+		CheckQuota(ctx context.Context, userID string) (dto.ChatQuotaStatus, error)
+		RecordUsage(ctx context.Context, userID uuid.UUID, messages int, tokens int) error
+	}
+
+	ChatQuotaService struct {
+		userRepo     repository.IUserRepository
+		messageQuota int
+		tokenQuota   int
+	}
+)
+
+func NewChatQuotaService(userRepo repository.IUserRepository, cfg *config.ChatLimitConfig) *ChatQuotaService {
+	return &ChatQuotaService{
+		userRepo:     userRepo,
+		messageQuota: cfg.DailyMessageQuota,
+		tokenQuota:   cfg.DailyTokenQuota,
+	}
+}
+
+// CheckQuota mengembalikan pemakaian hari ini, beserta error jika salah satu kuota
+// sudah habis. Kuota bernilai 0 berarti tidak dibatasi.
+func (cs *ChatQuotaService) CheckQuota(ctx context.Context, userID string) (dto.ChatQuotaStatus, error) {
+	date := todayDate()
+
+	usage, _, err := cs.userRepo.GetChatUsage(ctx, nil, userID, date)
+	if err != nil {
+		return dto.ChatQuotaStatus{}, dto.ErrGetChatUsage
+	}
+
+	status := dto.ChatQuotaStatus{
+		Date:              date,
+		MessageCount:      usage.MessageCount,
+		TokenCount:        usage.TokenCount,
+		MessageQuota:      cs.messageQuota,
+		TokenQuota:        cs.tokenQuota,
+		MessagesRemaining: remainingQuota(cs.messageQuota, usage.MessageCount),
+		TokensRemaining:   remainingQuota(cs.tokenQuota, usage.TokenCount),
+	}
+
+	if cs.messageQuota > 0 && usage.MessageCount >= cs.messageQuota {
+		return status, dto.ErrChatMessageQuotaExceeded
+	}
+
+	if cs.tokenQuota > 0 && usage.TokenCount >= cs.tokenQuota {
+		return status, dto.ErrChatTokenQuotaExceeded
+	}
+
+	return status, nil
+}
+func (cs *ChatQuotaService) RecordUsage(ctx context.Context, userID uuid.UUID, messages int, tokens int) error {
+	return cs.userRepo.IncrementChatUsage(ctx, nil, userID, todayDate(), messages, tokens)
+}
+
+func remainingQuota(quota int, used int) int {
+	if quota <= 0 {
+		return -1
+	}
+
+	if used >= quota {
+		return 0
+	}
+
+	return quota - used
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+	"github.com/google/uuid"
+)
+
+func TestHandleChatStream(t *testing.T) {
+	t.Setenv("CHAT_SYSTEM_PROMPT_PATH", "../utils/chat_template/system_prompt.txt")
+	t.Setenv("CHAT_CRISIS_RESPONSE_PATH", "../utils/chat_template/crisis_response.txt")
+
+	crisisResponse, err := os.ReadFile("../utils/chat_template/crisis_response.txt")
+	if err != nil {
+		t.Fatalf("read crisis template: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		message    string
+		reply      string
+		wantCrisis bool
+	}{
+		{
+			name:    "safe reply is streamed completely",
+			message: "aku capek banget hari ini",
+			reply:   "Terima kasih sudah bercerita. Coba tarik napas pelan dan ceritakan apa yang paling berat hari ini.",
+		},
+		{
+			name:       "crisis reply is blocked before sending",
+			message:    "aku capek banget hari ini",
+			reply:      "Aku paham. Kadang orang berpikir untuk bunuh diri saat lelah, mari bicara pelan-pelan.",
+			wantCrisis: true,
+		},
+		{
+			name:       "crisis message is answered without streaming",
+			message:    "aku ingin bunuh diri",
+			reply:      "Terima kasih sudah bercerita.",
+			wantCrisis: true,
+		},
+	}
+
+	type event struct {
+		name string
+		text string
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			e.llm = utils.NewFakeLLMClient(tt.reply)
+
+			var (
+				events []event
+				deltas []string
+			)
+			res, err := e.userService().HandleChatStream(withUser(context.Background(), e.addUser()), dto.ChatRequest{Message: tt.message}, func(name string, text string) error {
+				events = append(events, event{name: name, text: text})
+				if name == constants.ENUM_CHAT_STREAM_EVENT_MESSAGE {
+					deltas = append(deltas, text)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if res.IsCrisis != tt.wantCrisis {
+				t.Errorf("IsCrisis = %v, want %v", res.IsCrisis, tt.wantCrisis)
+			}
+			if flagged := e.repo.conversations[res.ConversationID.String()].IsFlagged; flagged != tt.wantCrisis {
+				t.Errorf("conversation flagged = %v, want %v", flagged, tt.wantCrisis)
+			}
+
+			if !tt.wantCrisis {
+				if streamed := strings.Join(deltas, ""); streamed != tt.reply {
+					t.Errorf("expected streamed reply %q, got %q", tt.reply, streamed)
+				}
+				if len(deltas) != len(events) {
+					t.Errorf("safe reply must only send message events, got %v", events)
+				}
+				return
+			}
+
+			if res.Response != strings.TrimSpace(string(crisisResponse)) {
+				t.Errorf("expected approved crisis response, got %q", res.Response)
+			}
+			for _, delta := range deltas {
+				if isCrisis, _ := helpers.DetectCrisisLanguage(delta); isCrisis || strings.Contains(delta, "bunuh") {
+					t.Errorf("unsafe model output reached the client: %q", delta)
+				}
+			}
+			// client membuang delta yang sudah tampil saat menerima replace
+			last := events[len(events)-1]
+			if last.name != constants.ENUM_CHAT_STREAM_EVENT_REPLACE || last.text != res.Response {
+				t.Errorf("expected last event to replace the reply with the crisis response, got %v", last)
+			}
+			if len(deltas) != len(events)-1 {
+				t.Errorf("expected only the last event to be a replace, got %v", events)
+			}
+			for _, m := range e.repo.messages {
+				if m.Sender == "assistant" && strings.Contains(m.Content, "bunuh diri") {
+					t.Errorf("unsafe model output was saved: %q", m.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleChatSummarizesAfterCrisisCheck(t *testing.T) {
+	t.Setenv("CHAT_SYSTEM_PROMPT_PATH", "../utils/chat_template/system_prompt.txt")
+	t.Setenv("CHAT_SUMMARY_PROMPT_PATH", "../utils/chat_template/summary_prompt.txt")
+	t.Setenv("CHAT_CRISIS_RESPONSE_PATH", "../utils/chat_template/crisis_response.txt")
+
+	handlers := map[string]func(us *UserService, ctx context.Context, req dto.ChatRequest) (dto.ChatResponse, error){
+		"chat": func(us *UserService, ctx context.Context, req dto.ChatRequest) (dto.ChatResponse, error) {
+			return us.HandleChat(ctx, req)
+		},
+		"stream": func(us *UserService, ctx context.Context, req dto.ChatRequest) (dto.ChatResponse, error) {
+			return us.HandleChatStream(ctx, req, func(string, string) error { return nil })
+		},
+	}
+
+	tests := []struct {
+		name         string
+		message      string
+		wantLLMCalls int
+		wantSummary  bool
+	}{
+		{
+			name:         "long history is summarized and the summary is counted",
+			message:      "aku masih kepikiran soal kerjaan",
+			wantLLMCalls: 2,
+			wantSummary:  true,
+		},
+		{
+			name:    "crisis message skips the summary call",
+			message: "aku ingin bunuh diri",
+		},
+	}
+
+	for handlerName, handle := range handlers {
+		for _, tt := range tests {
+			t.Run(handlerName+"/"+tt.name, func(t *testing.T) {
+				e := newTestEnv(t)
+				llm := utils.NewFakeLLMClient("ringkasan singkat")
+				e.llm = llm
+
+				userID := e.addUser()
+				convo := entity.Conversation{ID: uuid.New(), Title: "curhat", UserID: &userID}
+				e.repo.conversations[convo.ID.String()] = convo
+				// riwayat panjang sehingga pesan lama harus diringkas agar muat di context
+				long := strings.Repeat("kata ", 200)
+				for i := 0; i < 8; i++ {
+					e.repo.messages = append(e.repo.messages, entity.Message{ID: uuid.New(), ConversationID: &convo.ID, Sender: "user", Content: long})
+				}
+
+				if _, err := handle(e.userService(), withUser(context.Background(), userID), dto.ChatRequest{
+					Message:        tt.message,
+					ConversationID: convo.ID,
+				}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if calls := len(llm.Calls()); calls != tt.wantLLMCalls {
+					t.Errorf("expected %d model calls, got %d", tt.wantLLMCalls, calls)
+				}
+				if summarized := e.repo.conversations[convo.ID.String()].Summary != ""; summarized != tt.wantSummary {
+					t.Errorf("conversation summarized = %v, want %v", summarized, tt.wantSummary)
+				}
+
+				if e.quota.messages != 1 {
+					t.Errorf("expected 1 message counted, got %d", e.quota.messages)
+				}
+				if !tt.wantSummary {
+					if e.quota.tokens != 0 {
+						t.Errorf("expected no tokens counted, got %d", e.quota.tokens)
+					}
+					return
+				}
+
+				// token prompt ringkasan sudah mencakup riwayat yang diringkas
+				if e.quota.records != 2 || e.quota.tokens < 5*200 {
+					t.Errorf("expected summary tokens to be counted, got %d tokens in %d records", e.quota.tokens, e.quota.records)
+				}
+			})
+		}
+	}
+}
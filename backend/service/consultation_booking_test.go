@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/google/uuid"
+)
+
+func TestCreateConsultationParallelBookingSameSlot(t *testing.T) {
+	const n = 20
+
+	e := newTestEnv(t)
+	slot := e.addSlot(e.psychologID, "2026-11-02", "09:00", false)
+	users := make([]uuid.UUID, n)
+	for i := range users {
+		users[i] = e.addUser()
+	}
+
+	// test ini hanya memastikan service memetakan request yang kalah ke ErrConsultationAlreadyBooked,
+	// atomisitas UPDATE kondisional di Postgres diuji di repository (make test-integration).
+	// semua request membaca slot yang belum dibooking sebelum ada yang lanjut,
+	// sehingga hanya booking kondisional yang bisa mencegah double booking
+	var reads sync.WaitGroup
+	reads.Add(n)
+	e.repo.afterSlotRead = func() {
+		reads.Done()
+		reads.Wait()
+	}
+
+	us := e.userService()
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, n)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_, errs[i] = us.CreateConsultation(withUser(context.Background(), users[i]), dto.CreateConsultationRequest{
+				AvailableSlotID: slot.ID.String(),
+				PracticeID:      e.practice.ID.String(),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	success, booked := 0, 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			success++
+		case errors.Is(err, dto.ErrConsultationAlreadyBooked):
+			booked++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if success != 1 {
+		t.Errorf("expected exactly 1 successful booking, got %d", success)
+	}
+	if booked != n-1 {
+		t.Errorf("expected %d ErrConsultationAlreadyBooked, got %d", n-1, booked)
+	}
+	if len(e.repo.consultations) != 1 {
+		t.Errorf("expected 1 consultation stored, got %d", len(e.repo.consultations))
+	}
+	if !e.repo.slots[slot.ID.String()].IsBooked {
+		t.Error("expected slot to be booked")
+	}
+}
+
+func TestUpdateConsultationReschedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  func(e *testEnv) uuid.UUID
+		wantErr error
+	}{
+		{
+			name:   "free slot of the same psycholog",
+			target: func(e *testEnv) uuid.UUID { return e.addSlot(e.psychologID, "2026-11-02", "09:00", false).ID },
+		},
+		{
+			name:    "slot already booked by someone else",
+			target:  func(e *testEnv) uuid.UUID { return e.addSlot(e.psychologID, "2026-11-02", "10:00", true).ID },
+			wantErr: dto.ErrConsultationAlreadyBooked,
+		},
+		{
+			name:    "slot of another psycholog",
+			target:  func(e *testEnv) uuid.UUID { return e.addSlot(uuid.New(), "2026-11-02", "09:00", false).ID },
+			wantErr: dto.ErrInvalidPsychologSchedule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			userID := e.addUser()
+			current := e.addSlot(e.psychologID, "2026-11-01", "09:00", true)
+			consul := e.addConsultation(userID, current, constants.ENUM_CONSULTATION_STATUS_REQUESTED)
+			target := tt.target(e)
+
+			_, err := e.userService().UpdateConsultation(withUser(context.Background(), userID), dto.UpdateConsultationRequestForUser{
+				AvailableSlotID: target.String(),
+			}, consul.ID.String())
+
+			stored := e.repo.consultations[consul.ID.String()]
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				if *stored.AvailableSlotID != current.ID {
+					t.Error("consultation slot must not change on failure")
+				}
+				if !e.repo.slots[current.ID.String()].IsBooked {
+					t.Error("current slot must stay booked on failure")
+				}
+				return
+			}
+
+			if *stored.AvailableSlotID != target {
+				t.Errorf("expected consultation on slot %s, got %s", target, stored.AvailableSlotID)
+			}
+			if stored.Date != e.repo.slots[target.String()].Date {
+				t.Errorf("expected consultation date %s, got %s", e.repo.slots[target.String()].Date, stored.Date)
+			}
+			if e.repo.slots[current.ID.String()].IsBooked {
+				t.Error("previous slot must be released")
+			}
+			if !e.repo.slots[target.String()].IsBooked {
+				t.Error("new slot must be booked")
+			}
+		})
+	}
+}
+
+func TestDeleteConsultationRejectsFinalStatus(t *testing.T) {
+	statuses := []int{
+		constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER,
+		constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_PSYCHOLOG,
+		constants.ENUM_CONSULTATION_STATUS_COMPLETED,
+		constants.ENUM_CONSULTATION_STATUS_NO_SHOW,
+		constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED,
+	}
+
+	for _, status := range statuses {
+		t.Run(helpers.ConsultationStatusName(status), func(t *testing.T) {
+			e := newTestEnv(t)
+			userID := e.addUser()
+			consul := e.addConsultation(userID, e.addSlot(e.psychologID, "2026-11-02", "09:00", false), status)
+
+			_, err := e.userService().DeleteConsultation(withUser(context.Background(), userID), consul.ID.String())
+			if !errors.Is(err, dto.ErrInvalidConsultationTransition) {
+				t.Fatalf("expected %v, got %v", dto.ErrInvalidConsultationTransition, err)
+			}
+
+			if e.repo.consultations[consul.ID.String()].Status != status {
+				t.Errorf("status must stay %d", status)
+			}
+			if len(e.repo.histories) != 0 {
+				t.Errorf("expected no status history, got %d", len(e.repo.histories))
+			}
+		})
+	}
+}
+
+func TestUpdateConsultationPracticeFee(t *testing.T) {
+	tests := []struct {
+		name       string
+		bookedFee  int64
+		practiceID func(e *testEnv) uuid.UUID
+		wantErr    error
+	}{
+		{
+			name:       "practice with the same fee",
+			bookedFee:  150000,
+			practiceID: func(e *testEnv) uuid.UUID { return e.addPractice(e.psychologID, 150000).ID },
+		},
+		{
+			name:       "free booking moved to a paid practice",
+			bookedFee:  0,
+			practiceID: func(e *testEnv) uuid.UUID { return e.addPractice(e.psychologID, 300000).ID },
+			wantErr:    dto.ErrPracticeFeeChanged,
+		},
+		{
+			name:       "paid booking moved to a more expensive practice",
+			bookedFee:  150000,
+			practiceID: func(e *testEnv) uuid.UUID { return e.addPractice(e.psychologID, 300000).ID },
+			wantErr:    dto.ErrPracticeFeeChanged,
+		},
+		{
+			name:       "paid booking moved to a cheaper practice",
+			bookedFee:  150000,
+			practiceID: func(e *testEnv) uuid.UUID { return e.addPractice(e.psychologID, 0).ID },
+			wantErr:    dto.ErrPracticeFeeChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			userID := e.addUser()
+			consul := e.addConsultation(userID, e.addSlot(e.psychologID, "2026-11-02", "09:00", true), constants.ENUM_CONSULTATION_STATUS_REQUESTED)
+			consul.Fee = tt.bookedFee
+			e.repo.consultations[consul.ID.String()] = consul
+			practiceID := tt.practiceID(e)
+
+			_, err := e.userService().UpdateConsultation(withUser(context.Background(), userID), dto.UpdateConsultationRequestForUser{
+				PracticeID: practiceID.String(),
+			}, consul.ID.String())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			stored := e.repo.consultations[consul.ID.String()]
+			if stored.Fee != tt.bookedFee {
+				t.Errorf("consultation fee must stay %d, got %d", tt.bookedFee, stored.Fee)
+			}
+
+			wantPractice := practiceID
+			if tt.wantErr != nil {
+				wantPractice = *consul.PracticeID
+			}
+			if *stored.PracticeID != wantPractice {
+				t.Errorf("expected practice %s, got %s", wantPractice, stored.PracticeID)
+			}
+		})
+	}
+}
+
+func TestCreateConsultationPaidBookingCharge(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(e *testEnv, slot entity.AvailableSlot)
+		wantErr     error
+		wantCharges int
+		wantVoided  bool
+	}{
+		{
+			name:        "won slot gets one charge",
+			wantCharges: 1,
+		},
+		{
+			name: "slot lost to another request creates no charge",
+			setup: func(e *testEnv, slot entity.AvailableSlot) {
+				// request lain membooking slot setelah slot dibaca
+				e.repo.afterSlotRead = func() {
+					e.repo.BookAvailableSlot(context.Background(), nil, slot.ID, nil)
+				}
+			},
+			wantErr: dto.ErrConsultationAlreadyBooked,
+		},
+		{
+			name: "rollback after the charge voids it",
+			setup: func(e *testEnv, slot entity.AvailableSlot) {
+				e.repo.createPaymentErr = errors.New("insert payment failed")
+			},
+			wantErr:     dto.ErrCreatePayment,
+			wantCharges: 1,
+			wantVoided:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			practice := e.addPractice(e.psychologID, 150000)
+			slot := e.addSlot(e.psychologID, "2026-11-02", "09:00", false)
+			if tt.setup != nil {
+				tt.setup(e, slot)
+			}
+
+			_, err := e.userService().CreateConsultation(withUser(context.Background(), e.addUser()), dto.CreateConsultationRequest{
+				AvailableSlotID: slot.ID.String(),
+				PracticeID:      practice.ID.String(),
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if charges := e.gateway.Charges(); len(charges) != tt.wantCharges {
+				t.Fatalf("expected %d charge at the gateway, got %d", tt.wantCharges, len(charges))
+			}
+
+			voided := e.gateway.Voided()
+			if tt.wantVoided != (len(voided) == 1) {
+				t.Errorf("expected voided=%v, got %v", tt.wantVoided, voided)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(e.repo.payments) != 1 {
+				t.Fatalf("expected 1 payment stored, got %d", len(e.repo.payments))
+			}
+			held := e.repo.slots[slot.ID.String()].HeldUntil
+			if held == nil || !held.Equal(e.repo.payments[0].ExpiresAt) {
+				t.Errorf("slot hold %v must match payment expiry %v", held, e.repo.payments[0].ExpiresAt)
+			}
+		})
+	}
+}
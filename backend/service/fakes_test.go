@@ -0,0 +1,599 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeJWTService menganggap token adalah user id
+type fakeJWTService struct {
+	IJWTService
+}
+
+func (f fakeJWTService) GetUserIDByToken(token string) (string, error) {
+	return token, nil
+}
+
+func withUser(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, "Authorization", userID.String())
+}
+
+// fakeUserRepo menyimpan data di memori dan juga dipakai sebagai IConsultationRepository.
+// BookAvailableSlot meniru UPDATE kondisional (is_booked = false) sehingga hanya satu
+// pemanggil yang menang.
+type fakeUserRepo struct {
+	repository.IUserRepository
+	repository.IConsultationRepository
+
+	mu            sync.Mutex
+	users         map[string]entity.User
+	practices     map[string]entity.Practice
+	slots         map[string]entity.AvailableSlot
+	consultations map[string]entity.Consultation
+	histories     []entity.ConsultationStatusHistory
+	payments      []entity.Payment
+	conversations map[string]entity.Conversation
+	messages      []entity.Message
+
+	// jika diisi, CreatePayment gagal dengan error ini
+	createPaymentErr error
+
+	// dipanggil setelah GetAvailableSlotByID membaca data, dipakai untuk menahan
+	// request paralel agar semuanya membaca slot sebelum ada yang membooking
+	afterSlotRead func()
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{
+		users:         make(map[string]entity.User),
+		practices:     make(map[string]entity.Practice),
+		slots:         make(map[string]entity.AvailableSlot),
+		consultations: make(map[string]entity.Consultation),
+		conversations: make(map[string]entity.Conversation),
+	}
+}
+
+func (f *fakeUserRepo) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, tx *gorm.DB, userID string) (entity.User, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, ok := f.users[userID]
+	if !ok {
+		return entity.User{}, false, gorm.ErrRecordNotFound
+	}
+
+	return u, true, nil
+}
+func (f *fakeUserRepo) GetPracticeByID(ctx context.Context, tx *gorm.DB, pracID string) (entity.Practice, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.practices[pracID]
+	if !ok {
+		return entity.Practice{}, false, gorm.ErrRecordNotFound
+	}
+
+	return p, true, nil
+}
+func (f *fakeUserRepo) GetAvailableSlotByID(ctx context.Context, tx *gorm.DB, slotID string) (entity.AvailableSlot, bool, error) {
+	f.mu.Lock()
+	slot, ok := f.slots[slotID]
+	f.mu.Unlock()
+
+	if f.afterSlotRead != nil {
+		f.afterSlotRead()
+	}
+
+	if !ok {
+		return entity.AvailableSlot{}, false, gorm.ErrRecordNotFound
+	}
+
+	return slot, true, nil
+}
+func (f *fakeUserRepo) GetConsultationByID(ctx context.Context, tx *gorm.DB, consulID string) (entity.Consultation, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	consul, ok := f.consultations[consulID]
+	if !ok {
+		return entity.Consultation{}, false, gorm.ErrRecordNotFound
+	}
+
+	consul.AvailableSlot = f.slots[consul.AvailableSlotID.String()]
+	consul.Practice = f.practices[consul.PracticeID.String()]
+
+	return consul, true, nil
+}
+func (f *fakeUserRepo) BookAvailableSlot(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, heldUntil *time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	slot, ok := f.slots[slotID.String()]
+	if !ok || slot.IsBooked {
+		return false, nil
+	}
+
+	slot.IsBooked = true
+	slot.HeldUntil = heldUntil
+	f.slots[slotID.String()] = slot
+	return true, nil
+}
+func (f *fakeUserRepo) CreateConsultation(ctx context.Context, tx *gorm.DB, consultation entity.Consultation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.consultations[consultation.ID.String()] = consultation
+	return nil
+}
+func (f *fakeUserRepo) CreatePayment(ctx context.Context, tx *gorm.DB, payment entity.Payment) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.createPaymentErr != nil {
+		return f.createPaymentErr
+	}
+
+	f.payments = append(f.payments, payment)
+	return nil
+}
+func (f *fakeUserRepo) CreateConsultationStatusHistory(ctx context.Context, tx *gorm.DB, history entity.ConsultationStatusHistory) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.histories = append(f.histories, history)
+	return nil
+}
+func (f *fakeUserRepo) GetConsultationStatusHistory(ctx context.Context, tx *gorm.DB, consulID string) ([]entity.ConsultationStatusHistory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var histories []entity.ConsultationStatusHistory
+	for _, h := range f.histories {
+		if h.ConsultationID != nil && h.ConsultationID.String() == consulID {
+			histories = append(histories, h)
+		}
+	}
+
+	return histories, nil
+}
+func (f *fakeUserRepo) UpdateConsultationStatus(ctx context.Context, tx *gorm.DB, consulID uuid.UUID, fromStatus int, toStatus int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	consul, ok := f.consultations[consulID.String()]
+	if !ok || consul.Status != fromStatus {
+		return false, nil
+	}
+
+	consul.Status = toStatus
+	f.consultations[consulID.String()] = consul
+	return true, nil
+}
+func (f *fakeUserRepo) UpdateConsultation(ctx context.Context, tx *gorm.DB, consultation entity.Consultation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored := f.consultations[consultation.ID.String()]
+	consultation.Status = stored.Status
+	f.consultations[consultation.ID.String()] = consultation
+	return nil
+}
+func (f *fakeUserRepo) UpdateStatusBookSlot(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, statusBook bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	slot := f.slots[slotID.String()]
+	slot.IsBooked = statusBook
+	slot.HeldUntil = nil
+	f.slots[slotID.String()] = slot
+	return nil
+}
+func (f *fakeUserRepo) CreateConversation(ctx context.Context, tx *gorm.DB, convo entity.Conversation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.conversations[convo.ID.String()] = convo
+	return nil
+}
+func (f *fakeUserRepo) GetConversationByID(ctx context.Context, tx *gorm.DB, convoID string) (entity.Conversation, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	convo, ok := f.conversations[convoID]
+	if !ok {
+		return entity.Conversation{}, false, gorm.ErrRecordNotFound
+	}
+
+	return convo, true, nil
+}
+func (f *fakeUserRepo) SaveMessage(ctx context.Context, tx *gorm.DB, msg entity.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages = append(f.messages, msg)
+	return nil
+}
+func (f *fakeUserRepo) GetMessagesByConversationID(ctx context.Context, convoID uuid.UUID) ([]entity.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var messages []entity.Message
+	for _, m := range f.messages {
+		if m.ConversationID != nil && *m.ConversationID == convoID {
+			messages = append(messages, m)
+		}
+	}
+
+	return messages, nil
+}
+func (f *fakeUserRepo) UpdateConversationSummary(ctx context.Context, tx *gorm.DB, convoID uuid.UUID, summary string, summarizedCount int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	convo := f.conversations[convoID.String()]
+	convo.Summary = summary
+	convo.SummarizedCount = summarizedCount
+	f.conversations[convoID.String()] = convo
+	return nil
+}
+func (f *fakeUserRepo) FlagConversation(ctx context.Context, tx *gorm.DB, convoID uuid.UUID, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	convo := f.conversations[convoID.String()]
+	convo.IsFlagged = true
+	convo.FlagReason = reason
+	f.conversations[convoID.String()] = convo
+	return nil
+}
+
+// fakeChatQuota tidak membatasi apa pun, cukup mencatat pemakaian
+type fakeChatQuota struct {
+	IChatQuotaService
+
+	mu       sync.Mutex
+	messages int
+	tokens   int
+	records  int
+}
+
+func (f *fakeChatQuota) RecordUsage(ctx context.Context, userID uuid.UUID, messages int, tokens int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages += messages
+	f.tokens += tokens
+	f.records++
+	return nil
+}
+
+// fakePaymentRepo menyimpan payment di memori. Transaksi dipulihkan jika fn gagal,
+// sama seperti rollback, dan perubahan consultation/slot diteruskan ke consultations.
+type fakePaymentRepo struct {
+	repository.IPaymentRepository
+
+	consultations *fakeUserRepo
+	payments      map[string]entity.Payment
+	events        map[string]entity.PaymentWebhookEvent
+	refunds       map[string]entity.Refund
+}
+
+func newFakePaymentRepo(consultations *fakeUserRepo) *fakePaymentRepo {
+	return &fakePaymentRepo{
+		consultations: consultations,
+		payments:      make(map[string]entity.Payment),
+		events:        make(map[string]entity.PaymentWebhookEvent),
+		refunds:       make(map[string]entity.Refund),
+	}
+}
+
+func (f *fakePaymentRepo) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	payments := maps.Clone(f.payments)
+	events := maps.Clone(f.events)
+	refunds := maps.Clone(f.refunds)
+
+	if err := fn(nil); err != nil {
+		f.payments, f.events, f.refunds = payments, events, refunds
+		return err
+	}
+
+	return nil
+}
+func (f *fakePaymentRepo) GetPaymentByReference(ctx context.Context, tx *gorm.DB, gateway string, reference string) (entity.Payment, bool, error) {
+	for _, payment := range f.payments {
+		if payment.Gateway == gateway && payment.Reference == reference {
+			consul, _, _ := f.consultations.GetConsultationByID(ctx, tx, payment.ConsultationID.String())
+			payment.Consultation = consul
+			return payment, true, nil
+		}
+	}
+
+	return entity.Payment{}, false, gorm.ErrRecordNotFound
+}
+func (f *fakePaymentRepo) GetPendingPaymentByConsultationID(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) (entity.Payment, bool, error) {
+	for _, payment := range f.payments {
+		if *payment.ConsultationID == consulID && payment.Status == constants.ENUM_PAYMENT_STATUS_PENDING {
+			return payment, true, nil
+		}
+	}
+
+	return entity.Payment{}, false, gorm.ErrRecordNotFound
+}
+func (f *fakePaymentRepo) GetOverduePendingPayments(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	for _, payment := range f.payments {
+		if payment.Status == constants.ENUM_PAYMENT_STATUS_PENDING && !payment.ExpiresAt.After(now) {
+			consul, _, _ := f.consultations.GetConsultationByID(ctx, tx, payment.ConsultationID.String())
+			payment.Consultation = consul
+			payments = append(payments, payment)
+		}
+	}
+
+	return payments, nil
+}
+func (f *fakePaymentRepo) CreatePaymentWebhookEvent(ctx context.Context, tx *gorm.DB, event entity.PaymentWebhookEvent) (bool, error) {
+	key := event.Gateway + "/" + event.EventID
+	if _, ok := f.events[key]; ok {
+		return false, nil
+	}
+
+	f.events[key] = event
+	return true, nil
+}
+func (f *fakePaymentRepo) CreateRefund(ctx context.Context, tx *gorm.DB, refund entity.Refund) error {
+	f.refunds[refund.ID.String()] = refund
+	return nil
+}
+func (f *fakePaymentRepo) UpdatePaymentStatus(ctx context.Context, tx *gorm.DB, paymentID uuid.UUID, fromStatus string, toStatus string, paidAt *time.Time) (bool, error) {
+	payment, ok := f.payments[paymentID.String()]
+	if !ok || payment.Status != fromStatus {
+		return false, nil
+	}
+
+	payment.Status = toStatus
+	if paidAt != nil {
+		payment.PaidAt = paidAt
+	}
+	f.payments[paymentID.String()] = payment
+	return true, nil
+}
+func (f *fakePaymentRepo) ReleaseSlotHold(ctx context.Context, tx *gorm.DB, slotID uuid.UUID, booked bool) error {
+	return f.consultations.UpdateStatusBookSlot(ctx, tx, slotID, booked)
+}
+func (f *fakePaymentRepo) UpdateRefund(ctx context.Context, tx *gorm.DB, refund entity.Refund) error {
+	stored := f.refunds[refund.ID.String()]
+	stored.Status = refund.Status
+	stored.Reference = refund.Reference
+	stored.FailureReason = refund.FailureReason
+	stored.ProcessedAt = refund.ProcessedAt
+	f.refunds[refund.ID.String()] = stored
+	return nil
+}
+
+// testEnv adalah builder bersama untuk test service: fakeUserRepo (sekaligus
+// IConsultationRepository), fakePaymentRepo dan gateway fake yang saling terhubung,
+// dengan satu psycholog dan satu practice gratis.
+type testEnv struct {
+	t        *testing.T
+	repo     *fakeUserRepo
+	payments *fakePaymentRepo
+	gateway  *utils.FakePaymentGateway
+	llm      utils.LLMClient
+	quota    *fakeChatQuota
+
+	psychologID uuid.UUID
+	practice    entity.Practice
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	repo := newFakeUserRepo()
+	e := &testEnv{
+		t:           t,
+		repo:        repo,
+		payments:    newFakePaymentRepo(repo),
+		gateway:     utils.NewFakePaymentGateway("test-secret", "http://localhost/checkout"),
+		quota:       &fakeChatQuota{},
+		psychologID: uuid.New(),
+	}
+	e.practice = e.addPractice(e.psychologID, 0)
+
+	return e
+}
+
+func (e *testEnv) paymentService() *PaymentService {
+	return NewPaymentService(e.payments, e.repo, e.gateway, &config.PaymentConfig{HoldMinutes: 15})
+}
+func (e *testEnv) userService() *UserService {
+	builder := utils.NewChatHistoryBuilder(utils.NewFakeTokenizer(), 1000, 4)
+	return NewUserService(e.repo, e.repo, nil, fakeJWTService{}, e.llm, builder, e.quota, nil, nil, nil, e.paymentService(), nil)
+}
+func (e *testEnv) addUser() uuid.UUID {
+	id := uuid.New()
+	e.repo.users[id.String()] = entity.User{ID: id, Name: "user " + id.String()[:8]}
+	return id
+}
+func (e *testEnv) addPractice(psychologID uuid.UUID, fee int64) entity.Practice {
+	practice := entity.Practice{ID: uuid.New(), Name: "Klinik", Fee: fee, PsychologID: &psychologID}
+	e.repo.practices[practice.ID.String()] = practice
+	return practice
+}
+func (e *testEnv) addSlot(psychologID uuid.UUID, date string, start string, booked bool) entity.AvailableSlot {
+	end, _ := time.Parse("15:04", start)
+	slot := entity.AvailableSlot{
+		ID:          uuid.New(),
+		Date:        date,
+		Start:       start,
+		End:         end.Add(time.Hour).Format("15:04"),
+		IsBooked:    booked,
+		PsychologID: &psychologID,
+	}
+	e.repo.slots[slot.ID.String()] = slot
+	return slot
+}
+
+// addConsultation menyimpan consultation milik userID di slot dengan practice default
+func (e *testEnv) addConsultation(userID uuid.UUID, slot entity.AvailableSlot, status int) entity.Consultation {
+	consul := entity.Consultation{
+		ID:              uuid.New(),
+		Date:            slot.Date,
+		Fee:             e.practice.Fee,
+		Status:          status,
+		UserID:          &userID,
+		PracticeID:      &e.practice.ID,
+		AvailableSlotID: &slot.ID,
+	}
+	e.repo.consultations[consul.ID.String()] = consul
+	return consul
+}
+
+// addPendingPayment menyiapkan consultation yang menunggu pembayaran dengan slot di-hold
+func (e *testEnv) addPendingPayment(fee int64) (entity.Consultation, entity.Payment) {
+	heldUntil := time.Now().Add(15 * time.Minute)
+	slot := e.addSlot(e.psychologID, "2026-11-02", "09:00", true)
+	slot.HeldUntil = &heldUntil
+	e.repo.slots[slot.ID.String()] = slot
+
+	consul := e.addConsultation(e.addUser(), slot, constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT)
+	consul.Fee = fee
+	e.repo.consultations[consul.ID.String()] = consul
+
+	payment := entity.Payment{
+		ID:             uuid.New(),
+		InvoiceNumber:  "INV-" + strings.ToUpper(uuid.NewString()[:8]),
+		Amount:         fee,
+		Currency:       constants.ENUM_PAYMENT_CURRENCY,
+		Status:         constants.ENUM_PAYMENT_STATUS_PENDING,
+		Gateway:        e.gateway.Name(),
+		Reference:      "fake_" + uuid.NewString(),
+		ExpiresAt:      heldUntil,
+		ConsultationID: &consul.ID,
+	}
+	e.payments.payments[payment.ID.String()] = payment
+
+	return consul, payment
+}
+
+// webhook mengirim event untuk payment, ditandatangani dengan secret gateway
+func (e *testEnv) webhook(payment entity.Payment, eventID string, status string, amount int64) error {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"event_id":  eventID,
+		"reference": payment.Reference,
+		"status":    status,
+		"amount":    amount,
+	})
+
+	header := http.Header{}
+	header.Set(utils.FakePaymentSignatureHeader, e.gateway.SignWebhook(payload))
+
+	return e.paymentService().HandleWebhook(context.Background(), payload, header)
+}
+
+// fakePsychologRepo menyimpan practice, template dan slot di memori. Transaksi
+// dipulihkan jika fn gagal dan CreateAvailableSlots meniru ON CONFLICT DO NOTHING
+// pada (date, start, psycholog_id).
+type fakePsychologRepo struct {
+	repository.IPsychologRepository
+
+	practices []entity.Practice
+	schedules []entity.PracticeSchedule
+	templates []entity.SlotTemplate
+	slots     []entity.AvailableSlot
+
+	// jika diisi, CreateSlotTemplate gagal setelah sejumlah template tersimpan
+	failTemplateAfter *int
+}
+
+func (f *fakePsychologRepo) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	practices, schedules := slices.Clone(f.practices), slices.Clone(f.schedules)
+	templates, slots := slices.Clone(f.templates), slices.Clone(f.slots)
+
+	if err := fn(nil); err != nil {
+		f.practices, f.schedules, f.templates, f.slots = practices, schedules, templates, slots
+		return err
+	}
+
+	return nil
+}
+func (f *fakePsychologRepo) GetAllSlotTemplate(ctx context.Context, tx *gorm.DB, psyID string) ([]entity.SlotTemplate, error) {
+	var templates []entity.SlotTemplate
+	for _, tmpl := range f.templates {
+		if tmpl.PsychologID.String() == psyID {
+			templates = append(templates, tmpl)
+		}
+	}
+
+	return templates, nil
+}
+func (f *fakePsychologRepo) GetAllPsychologIDWithSlotTemplate(ctx context.Context, tx *gorm.DB) ([]string, error) {
+	var ids []string
+	for _, tmpl := range f.templates {
+		if !slices.Contains(ids, tmpl.PsychologID.String()) {
+			ids = append(ids, tmpl.PsychologID.String())
+		}
+	}
+
+	return ids, nil
+}
+func (f *fakePsychologRepo) GetAvailableSlotByDateRange(ctx context.Context, tx *gorm.DB, psyID string, from string, to string) ([]entity.AvailableSlot, error) {
+	var slots []entity.AvailableSlot
+	for _, slot := range f.slots {
+		if slot.PsychologID.String() == psyID && slot.Date >= from && slot.Date <= to {
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots, nil
+}
+func (f *fakePsychologRepo) CreatePractice(ctx context.Context, tx *gorm.DB, practice entity.Practice) error {
+	f.practices = append(f.practices, practice)
+	return nil
+}
+func (f *fakePsychologRepo) CreatePracticeSchedule(ctx context.Context, tx *gorm.DB, schedules []entity.PracticeSchedule) error {
+	f.schedules = append(f.schedules, schedules...)
+	return nil
+}
+func (f *fakePsychologRepo) CreateSlotTemplate(ctx context.Context, tx *gorm.DB, template entity.SlotTemplate) error {
+	if f.failTemplateAfter != nil && len(f.templates) >= *f.failTemplateAfter {
+		return errors.New("insert slot template failed")
+	}
+
+	f.templates = append(f.templates, template)
+	return nil
+}
+func (f *fakePsychologRepo) CreateAvailableSlots(ctx context.Context, tx *gorm.DB, slots []entity.AvailableSlot) (int, error) {
+	created := 0
+	for _, slot := range slots {
+		exists := slices.ContainsFunc(f.slots, func(s entity.AvailableSlot) bool {
+			return s.Date == slot.Date && s.Start == slot.Start && *s.PsychologID == *slot.PsychologID
+		})
+		if exists {
+			continue
+		}
+
+		f.slots = append(f.slots, slot)
+		created++
+	}
+
+	return created, nil
+}
@@ -1,99 +1,186 @@
 package service
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"os"
+	"log"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/Reyysusanto/warasin-web/backend/config"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type (
 	IJWTService interface {
-		GenerateToken(userID string, role string, permissions []string) (string, string, error)
+		GenerateAccessToken(userID string, roleID string) (string, error)
 		ValidateToken(token string) (*jwt.Token, error)
 		GetUserIDByToken(tokenString string) (string, error)
 		GetRoleIDByToken(tokenString string) (string, error)
+		GetJWKS() dto.JWKSResponse
 	}
 
 	jwtCustomClaim struct {
-		UserID      string   `json:"user_id"`
-		RoleID      string   `json:"role_id"`
-		Permissions []string `json:"endpoints"`
+		UserID string `json:"user_id"`
+		RoleID string `json:"role_id"`
 		jwt.RegisteredClaims
 	}
 
+	jwtVerificationKey struct {
+		algorithm string
+		publicKey crypto.PublicKey
+	}
+
 	JWTService struct {
-		secretKey string
-		issuer    string
+		issuer         string
+		accessTokenTTL time.Duration
+
+		signingKeyID     string
+		signingAlgorithm string
+		signingKey       crypto.Signer
+
+		// semua key yang masih diterima saat verifikasi, termasuk key lama selama rotasi
+		verificationKeys map[string]jwtVerificationKey
 	}
 )
 
-func NewJWTService() *JWTService {
-	return &JWTService{
-		secretKey: getSecretKey(),
-		issuer:    "Template",
+// NewJWTService memuat signing key dari JWT_PRIVATE_KEY_PATH dan public key tambahan
+// dari JWT_PUBLIC_KEYS_DIR (nama file tanpa ekstensi menjadi kid). Tanpa private key,
+// di luar production dibuat key Ed25519 sementara yang hilang saat restart.
+func NewJWTService(cfg *config.JWTConfig) (*JWTService, error) {
+	js := &JWTService{
+		issuer:           cfg.Issuer,
+		accessTokenTTL:   time.Duration(cfg.AccessTokenTTLSeconds) * time.Second,
+		verificationKeys: map[string]jwtVerificationKey{},
 	}
-}
 
-func getSecretKey() string {
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		secretKey = "Template"
+	if cfg.PrivateKeyPath == "" {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Println("JWT_PRIVATE_KEY_PATH is not set, using an ephemeral Ed25519 key (tokens are invalidated on restart)")
+		js.signingKey = privateKey
+	} else {
+		privateKey, err := utils.LoadPrivateKey(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		js.signingKey = privateKey
 	}
 
-	return secretKey
-}
+	algorithm, err := utils.JWTAlgorithmForKey(js.signingKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	js.signingAlgorithm = algorithm
 
-func (j *JWTService) GenerateToken(userID string, roleID string, endpoints []string) (string, string, error) {
-	accessClaims := jwtCustomClaim{
-		userID,
-		roleID,
-		endpoints,
-		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Second * 300)),
-			Issuer:    j.issuer,
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	js.signingKeyID = cfg.KeyID
+	if js.signingKeyID == "" {
+		js.signingKeyID, err = utils.KeyThumbprint(js.signingKey.Public())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	js.verificationKeys[js.signingKeyID] = jwtVerificationKey{
+		algorithm: algorithm,
+		publicKey: js.signingKey.Public(),
+	}
+
+	if cfg.PublicKeysDir != "" {
+		if err := js.loadVerificationKeys(cfg.PublicKeysDir); err != nil {
+			return nil, err
+		}
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(j.secretKey))
+	return js, nil
+}
+
+func (j *JWTService) loadVerificationKeys(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
 	if err != nil {
-		return "", "", dto.ErrGenerateAccessToken
+		return err
 	}
 
-	refreshClaims := jwtCustomClaim{
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if kid == j.signingKeyID {
+			continue
+		}
+
+		publicKey, err := utils.LoadPublicKey(path)
+		if err != nil {
+			return err
+		}
+
+		algorithm, err := utils.JWTAlgorithmForKey(publicKey)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		j.verificationKeys[kid] = jwtVerificationKey{
+			algorithm: algorithm,
+			publicKey: publicKey,
+		}
+	}
+
+	return nil
+}
+
+// GenerateAccessToken membuat access token berumur pendek. Refresh token tidak lagi
+// berupa JWT, melainkan token acak yang disimpan lewat RefreshTokenService.
+func (j *JWTService) GenerateAccessToken(userID string, roleID string) (string, error) {
+	accessClaims := jwtCustomClaim{
 		userID,
 		roleID,
-		endpoints,
 		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Second * 3600 * 24 * 7)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessTokenTTL)),
 			Issuer:    j.issuer,
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(j.secretKey))
+	accessToken := jwt.NewWithClaims(jwt.GetSigningMethod(j.signingAlgorithm), accessClaims)
+	accessToken.Header["kid"] = j.signingKeyID
+	accessTokenString, err := accessToken.SignedString(j.signingKey)
 	if err != nil {
-		return "", "", dto.ErrGenerateRefreshToken
+		return "", dto.ErrGenerateAccessToken
 	}
 
-	return accessTokenString, refreshTokenString, nil
+	return accessTokenString, nil
 }
 
 func (j *JWTService) parseToken(t_ *jwt.Token) (any, error) {
-	if _, ok := t_.Method.(*jwt.SigningMethodHMAC); !ok {
+	kid, _ := t_.Header["kid"].(string)
+	key, ok := j.verificationKeys[kid]
+	if !ok {
+		return nil, dto.ErrUnknownSigningKey
+	}
+
+	if t_.Method.Alg() != key.algorithm {
 		return nil, dto.ErrUnexpectedSigningMethod
 	}
 
-	return []byte(j.secretKey), nil
+	return key.publicKey, nil
 }
 
 func (j *JWTService) ValidateToken(tokenString string) (*jwt.Token, error) {
-	token, err := jwt.Parse(tokenString, j.parseToken)
+	token, err := jwt.Parse(tokenString, j.parseToken,
+		jwt.WithValidMethods([]string{utils.JWTAlgorithmRS256, utils.JWTAlgorithmEdDSA}),
+		jwt.WithIssuer(j.issuer),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -132,3 +219,49 @@ func (j *JWTService) GetRoleIDByToken(tokenString string) (string, error) {
 
 	return roleID, nil
 }
+
+// GetJWKS mengembalikan seluruh public key verifikasi dalam format JWK Set
+func (j *JWTService) GetJWKS() dto.JWKSResponse {
+	kids := make([]string, 0, len(j.verificationKeys))
+	for kid := range j.verificationKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	res := dto.JWKSResponse{Keys: []dto.JWK{}}
+	for _, kid := range kids {
+		key := j.verificationKeys[kid]
+		jwk, err := toJWK(kid, key)
+		if err != nil {
+			continue
+		}
+		res.Keys = append(res.Keys, jwk)
+	}
+
+	return res
+}
+
+func toJWK(kid string, key jwtVerificationKey) (dto.JWK, error) {
+	switch pub := key.publicKey.(type) {
+	case *rsa.PublicKey:
+		return dto.JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: key.algorithm,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return dto.JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: key.algorithm,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, nil
+	default:
+		return dto.JWK{}, errors.New("unsupported key type")
+	}
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	loginAttemptScopeAccount = "account"
+	loginAttemptScopeIP      = "ip"
+)
+
+type (
+	// ILoginGuardService dipakai bersama oleh Login user, admin dan psycholog
+	ILoginGuardService interface {
+		Check(ctx context.Context, roleName string, email string, ip string) error
+		RecordFailure(ctx context.Context, roleName string, email string, ip string, accountExists bool) error
+		RecordSuccess(ctx context.Context, roleName string, email string) error
+		Unlock(ctx context.Context, rawToken string) error
+		GetAllLoginAttemptWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.LoginAttemptPaginationResponse, error)
+		ClearLoginAttempt(ctx context.Context, attemptID string) (dto.LoginAttemptResponse, error)
+	}
+
+	// LoginBlockedError dikembalikan saat login ditahan, RetryAfter dipakai handler
+	// untuk header Retry-After
+	LoginBlockedError struct {
+		Err        error
+		RetryAfter time.Duration
+	}
+
+	LoginGuardService struct {
+		loginAttemptRepo repository.ILoginAttemptRepository
+		cfg              *config.LoginGuardConfig
+	}
+)
+
+func (e *LoginBlockedError) Error() string {
+	return e.Err.Error()
+}
+func (e *LoginBlockedError) Unwrap() error {
+	return e.Err
+}
+
+func NewLoginGuardService(loginAttemptRepo repository.ILoginAttemptRepository, cfg *config.LoginGuardConfig) *LoginGuardService {
+	return &LoginGuardService{
+		loginAttemptRepo: loginAttemptRepo,
+		cfg:              cfg,
+	}
+}
+
+// Check menolak login jika akun atau IP sedang dikunci, atau akun masih dalam masa
+// tunda progresif setelah beberapa kali gagal
+func (lg *LoginGuardService) Check(ctx context.Context, roleName string, email string, ip string) error {
+	now := time.Now()
+
+	account, err := lg.getAttempt(ctx, loginAttemptScopeAccount, roleName, normalizeLoginEmail(email))
+	if err != nil {
+		return err
+	}
+	if blocked := lg.blockedFor(account, now, true); blocked != nil {
+		return blocked
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	// IP hanya dikunci, tanpa tunda progresif, agar pengguna di balik NAT yang sama tidak ikut tertahan
+	ipAttempt, err := lg.getAttempt(ctx, loginAttemptScopeIP, "", ip)
+	if err != nil {
+		return err
+	}
+	if blocked := lg.blockedFor(ipAttempt, now, false); blocked != nil {
+		return blocked
+	}
+
+	return nil
+}
+
+// RecordFailure mencatat login gagal untuk akun dan IP. Jika batas tercapai, key dikunci
+// dan pemilik akun (jika ada) dikirimi email berisi link unlock.
+func (lg *LoginGuardService) RecordFailure(ctx context.Context, roleName string, email string, ip string, accountExists bool) error {
+	now := time.Now()
+	windowStart := now.Add(-time.Duration(lg.cfg.AttemptWindowMinutes) * time.Minute)
+	lockedUntil := now.Add(time.Duration(lg.cfg.LockoutMinutes) * time.Minute)
+	email = normalizeLoginEmail(email)
+
+	account, err := lg.loginAttemptRepo.IncrementLoginAttempt(ctx, nil, loginAttemptScopeAccount, roleName, email, windowStart)
+	if err != nil {
+		return dto.ErrRecordLoginAttempt
+	}
+
+	var blocked error
+	if account.FailedCount >= lg.cfg.LockoutAttempts && account.LockedUntil == nil {
+		if err := lg.lockAccount(ctx, account, lockedUntil, accountExists); err != nil {
+			return err
+		}
+		blocked = &LoginBlockedError{Err: dto.ErrAccountLocked, RetryAfter: lockedUntil.Sub(now)}
+	}
+
+	if ip != "" {
+		ipAttempt, err := lg.loginAttemptRepo.IncrementLoginAttempt(ctx, nil, loginAttemptScopeIP, "", ip, windowStart)
+		if err != nil {
+			return dto.ErrRecordLoginAttempt
+		}
+
+		if ipAttempt.FailedCount >= lg.cfg.IPLockoutAttempts && ipAttempt.LockedUntil == nil {
+			if err := lg.loginAttemptRepo.LockLoginAttempt(ctx, nil, ipAttempt.ID, lockedUntil, ""); err != nil {
+				return dto.ErrRecordLoginAttempt
+			}
+			blocked = &LoginBlockedError{Err: dto.ErrTooManyLoginAttempts, RetryAfter: lockedUntil.Sub(now)}
+		}
+	}
+
+	return blocked
+}
+func (lg *LoginGuardService) RecordSuccess(ctx context.Context, roleName string, email string) error {
+	if err := lg.loginAttemptRepo.DeleteLoginAttempt(ctx, nil, loginAttemptScopeAccount, roleName, normalizeLoginEmail(email)); err != nil {
+		return dto.ErrRecordLoginAttempt
+	}
+
+	return nil
+}
+func (lg *LoginGuardService) Unlock(ctx context.Context, rawToken string) error {
+	attempt, flag, err := lg.loginAttemptRepo.GetLoginAttemptByUnlockTokenHash(ctx, nil, helpers.HashToken(rawToken))
+	if err != nil || !flag {
+		return dto.ErrTokenInvalid
+	}
+
+	if err := lg.loginAttemptRepo.DeleteLoginAttemptByID(ctx, nil, attempt.ID.String()); err != nil {
+		return dto.ErrClearLoginAttempt
+	}
+
+	return nil
+}
+func (lg *LoginGuardService) GetAllLoginAttemptWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.LoginAttemptPaginationResponse, error) {
+	dataWithPaginate, err := lg.loginAttemptRepo.GetAllLoginAttemptWithPagination(ctx, nil, req)
+	if err != nil {
+		return dto.LoginAttemptPaginationResponse{}, dto.ErrGetAllLoginAttempt
+	}
+
+	now := time.Now()
+	var datas []dto.LoginAttemptResponse
+	for _, attempt := range dataWithPaginate.LoginAttempts {
+		datas = append(datas, toLoginAttemptResponse(attempt, now))
+	}
+
+	return dto.LoginAttemptPaginationResponse{
+		Data:               datas,
+		PaginationResponse: dataWithPaginate.PaginationResponse,
+	}, nil
+}
+func (lg *LoginGuardService) ClearLoginAttempt(ctx context.Context, attemptID string) (dto.LoginAttemptResponse, error) {
+	attempt, flag, err := lg.loginAttemptRepo.GetLoginAttemptByID(ctx, nil, attemptID)
+	if err != nil || !flag {
+		return dto.LoginAttemptResponse{}, dto.ErrLoginAttemptNotFound
+	}
+
+	if err := lg.loginAttemptRepo.DeleteLoginAttemptByID(ctx, nil, attemptID); err != nil {
+		return dto.LoginAttemptResponse{}, dto.ErrClearLoginAttempt
+	}
+
+	return toLoginAttemptResponse(attempt, time.Now()), nil
+}
+
+func (lg *LoginGuardService) getAttempt(ctx context.Context, scope string, roleName string, identifier string) (entity.LoginAttempt, error) {
+	attempt, _, err := lg.loginAttemptRepo.GetLoginAttempt(ctx, nil, scope, roleName, identifier)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.LoginAttempt{}, dto.ErrRecordLoginAttempt
+	}
+
+	return attempt, nil
+}
+func (lg *LoginGuardService) blockedFor(attempt entity.LoginAttempt, now time.Time, progressive bool) error {
+	if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+		return &LoginBlockedError{Err: dto.ErrAccountLocked, RetryAfter: attempt.LockedUntil.Sub(now)}
+	}
+
+	if !progressive || attempt.FailedCount < lg.cfg.DelayAfterAttempts {
+		return nil
+	}
+
+	// tunda 2^n kali base sejak gagal terakhir, dibatasi DelayMaxSeconds
+	exp := attempt.FailedCount - lg.cfg.DelayAfterAttempts
+	if exp > 16 {
+		exp = 16
+	}
+	delay := time.Duration(lg.cfg.DelayBaseSeconds) * time.Second << exp
+	if maxDelay := time.Duration(lg.cfg.DelayMaxSeconds) * time.Second; delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if wait := attempt.LastFailedAt.Add(delay).Sub(now); wait > 0 {
+		return &LoginBlockedError{Err: dto.ErrTooManyLoginAttempts, RetryAfter: wait}
+	}
+
+	return nil
+}
+func (lg *LoginGuardService) lockAccount(ctx context.Context, attempt entity.LoginAttempt, lockedUntil time.Time, notify bool) error {
+	var rawToken, tokenHash string
+	if notify {
+		token, err := helpers.GenerateRandomToken(32)
+		if err != nil {
+			return dto.ErrRecordLoginAttempt
+		}
+		rawToken, tokenHash = token, helpers.HashToken(token)
+	}
+
+	if err := lg.loginAttemptRepo.LockLoginAttempt(ctx, nil, attempt.ID, lockedUntil, tokenHash); err != nil {
+		return dto.ErrRecordLoginAttempt
+	}
+
+	if !notify {
+		return nil
+	}
+
+	draftEmail, err := makeUnlockAccountEmail(attempt.Identifier, rawToken, attempt.FailedCount, lockedUntil)
+	if err != nil {
+		log.Printf("failed to make unlock account email: %v", err)
+		return nil
+	}
+
+	// akun tetap terkunci walau email gagal terkirim, admin masih bisa membuka lock
+	if err := utils.SendEmail(attempt.Identifier, draftEmail["subject"], draftEmail["body"]); err != nil {
+		log.Printf("failed to send unlock account email: %v", err)
+	}
+
+	return nil
+}
+
+func makeUnlockAccountEmail(receiverEmail string, token string, attempts int, lockedUntil time.Time) (map[string]string, error) {
+	baseURL := os.Getenv("API_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://127.0.0.1:8000/api/v1"
+	}
+
+	unlockLink := baseURL + "/unlock-account?token=" + token
+
+	readHTML, err := os.ReadFile("utils/email_template/unlock_account_mail.html")
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		Email       string
+		Attempts    int
+		LockedUntil string
+		Unlock      string
+	}{
+		Email:       receiverEmail,
+		Attempts:    attempts,
+		LockedUntil: lockedUntil.Format("2006-01-02 15:04 MST"),
+		Unlock:      unlockLink,
+	}
+
+	tmpl, err := template.New("custom").Parse(string(readHTML))
+	if err != nil {
+		return nil, err
+	}
+
+	var strMail bytes.Buffer
+	if err := tmpl.Execute(&strMail, data); err != nil {
+		return nil, err
+	}
+
+	draftEmail := map[string]string{
+		"subject": "warasin",
+		"body":    strMail.String(),
+	}
+
+	return draftEmail, nil
+}
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+func toLoginAttemptResponse(attempt entity.LoginAttempt, now time.Time) dto.LoginAttemptResponse {
+	return dto.LoginAttemptResponse{
+		ID:           attempt.ID,
+		Scope:        attempt.Scope,
+		RoleName:     attempt.RoleName,
+		Identifier:   attempt.Identifier,
+		FailedCount:  attempt.FailedCount,
+		LastFailedAt: attempt.LastFailedAt,
+		LockedUntil:  attempt.LockedUntil,
+		IsLocked:     attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil),
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/repository"
@@ -15,18 +16,31 @@ type (
 
 		// Psycholog
 		GetDetailPsycholog(ctx context.Context, psychologID string) (dto.PsychologResponse, error)
+
+		// JWKS
+		GetJWKS(ctx context.Context) dto.JWKSResponse
+
+		// Login Attempt
+		UnlockAccount(ctx context.Context, req dto.UnlockAccountRequest) error
+
+		// Payment
+		HandlePaymentWebhook(ctx context.Context, payload []byte, header http.Header) error
 	}
 
 	MasterService struct {
-		masterRepo repository.IMasterRepository
-		jwtService IJWTService
+		masterRepo     repository.IMasterRepository
+		jwtService     IJWTService
+		loginGuard     ILoginGuardService
+		paymentService IPaymentService
 	}
 )
 
-func NewMasterService(masterRepo repository.IMasterRepository, jwtService IJWTService) *MasterService {
+func NewMasterService(masterRepo repository.IMasterRepository, jwtService IJWTService, loginGuard ILoginGuardService, paymentService IPaymentService) *MasterService {
 	return &MasterService{
-		masterRepo: masterRepo,
-		jwtService: jwtService,
+		masterRepo:     masterRepo,
+		jwtService:     jwtService,
+		loginGuard:     loginGuard,
+		paymentService: paymentService,
 	}
 }
 
@@ -106,8 +120,8 @@ func (ms *MasterService) GetDetailPsycholog(ctx context.Context, psychologID str
 		Name:        psycholog.Name,
 		STRNumber:   psycholog.STRNumber,
 		Email:       psycholog.Email,
-		Password:    psycholog.Password,
 		WorkYear:    psycholog.WorkYear,
+		Gender:      psycholog.Gender,
 		Description: psycholog.Description,
 		PhoneNumber: psycholog.PhoneNumber,
 		Image:       psycholog.Image,
@@ -124,6 +138,8 @@ func (ms *MasterService) GetDetailPsycholog(ctx context.Context, psychologID str
 			ID:   psycholog.RoleID,
 			Name: psycholog.Role.Name,
 		},
+		RatingAverage: psycholog.RatingAverage,
+		RatingCount:   psycholog.RatingCount,
 	}
 
 	// LanguageMasters
@@ -156,3 +172,18 @@ func (ms *MasterService) GetDetailPsycholog(ctx context.Context, psychologID str
 
 	return data, nil
 }
+
+// JWKS
+func (ms *MasterService) GetJWKS(ctx context.Context) dto.JWKSResponse {
+	return ms.jwtService.GetJWKS()
+}
+
+// Login Attempt
+func (ms *MasterService) UnlockAccount(ctx context.Context, req dto.UnlockAccountRequest) error {
+	return ms.loginGuard.Unlock(ctx, req.Token)
+}
+
+// Payment
+func (ms *MasterService) HandlePaymentWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	return ms.paymentService.HandleWebhook(ctx, payload, header)
+}
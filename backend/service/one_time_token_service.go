@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	IOneTimeTokenService interface {
+		Issue(ctx context.Context, userID uuid.UUID, purpose string) (string, error)
+		Validate(ctx context.Context, rawToken string, purpose string) (entity.OneTimeToken, error)
+		Consume(ctx context.Context, tx *gorm.DB, rawToken string, purpose string) (entity.OneTimeToken, error)
+		InvalidateAll(ctx context.Context, tx *gorm.DB, userID uuid.UUID, purpose string) error
+	}
+
+	OneTimeTokenService struct {
+		oneTimeTokenRepo repository.IOneTimeTokenRepository
+	}
+)
+
+func NewOneTimeTokenService(oneTimeTokenRepo repository.IOneTimeTokenRepository) *OneTimeTokenService {
+	return &OneTimeTokenService{
+		oneTimeTokenRepo: oneTimeTokenRepo,
+	}
+}
+
+func oneTimeTokenTTL(purpose string) time.Duration {
+	if purpose == constants.ENUM_ONE_TIME_TOKEN_RESET_PASSWORD {
+		return time.Duration(constants.ENUM_RESET_PASSWORD_TOKEN_TTL_MINUTES) * time.Minute
+	}
+
+	return time.Duration(constants.ENUM_VERIFY_EMAIL_TOKEN_TTL_HOURS) * time.Hour
+}
+
+// Issue membuat token baru dan membatalkan token lain dengan purpose yang sama,
+// sehingga hanya link email terakhir yang berlaku
+func (ots *OneTimeTokenService) Issue(ctx context.Context, userID uuid.UUID, purpose string) (string, error) {
+	rawToken, err := helpers.GenerateRandomToken(32)
+	if err != nil {
+		return "", dto.ErrGenerateOneTimeToken
+	}
+
+	token := entity.OneTimeToken{
+		ID:        uuid.New(),
+		TokenHash: helpers.HashToken(rawToken),
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(oneTimeTokenTTL(purpose)),
+		UserID:    userID,
+	}
+
+	if err := ots.oneTimeTokenRepo.ConsumeOneTimeTokenByUserID(ctx, nil, userID, purpose); err != nil {
+		return "", dto.ErrGenerateOneTimeToken
+	}
+
+	if err := ots.oneTimeTokenRepo.CreateOneTimeToken(ctx, nil, token); err != nil {
+		return "", dto.ErrGenerateOneTimeToken
+	}
+
+	return rawToken, nil
+}
+
+// Validate mengecek token tanpa memakainya, dipakai untuk halaman konfirmasi
+func (ots *OneTimeTokenService) Validate(ctx context.Context, rawToken string, purpose string) (entity.OneTimeToken, error) {
+	token, flag, err := ots.oneTimeTokenRepo.GetOneTimeTokenByHash(ctx, nil, helpers.HashToken(rawToken), purpose)
+	if err != nil || !flag {
+		return entity.OneTimeToken{}, dto.ErrTokenInvalid
+	}
+
+	if token.ConsumedAt != nil {
+		return entity.OneTimeToken{}, dto.ErrTokenAlreadyUsed
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return entity.OneTimeToken{}, dto.ErrTokenExpired
+	}
+
+	return token, nil
+}
+
+// Consume menukar token secara atomik; jalankan di dalam transaksi yang sama dengan
+// perubahan data agar token tidak terbuang jika perubahan gagal
+func (ots *OneTimeTokenService) Consume(ctx context.Context, tx *gorm.DB, rawToken string, purpose string) (entity.OneTimeToken, error) {
+	token, flag, err := ots.oneTimeTokenRepo.GetOneTimeTokenByHash(ctx, tx, helpers.HashToken(rawToken), purpose)
+	if err != nil || !flag {
+		return entity.OneTimeToken{}, dto.ErrTokenInvalid
+	}
+
+	if token.ConsumedAt != nil {
+		return entity.OneTimeToken{}, dto.ErrTokenAlreadyUsed
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return entity.OneTimeToken{}, dto.ErrTokenExpired
+	}
+
+	consumed, err := ots.oneTimeTokenRepo.ConsumeOneTimeToken(ctx, tx, token.ID)
+	if err != nil {
+		return entity.OneTimeToken{}, dto.ErrConsumeOneTimeToken
+	}
+
+	if !consumed {
+		return entity.OneTimeToken{}, dto.ErrTokenAlreadyUsed
+	}
+
+	return token, nil
+}
+func (ots *OneTimeTokenService) InvalidateAll(ctx context.Context, tx *gorm.DB, userID uuid.UUID, purpose string) error {
+	if err := ots.oneTimeTokenRepo.ConsumeOneTimeTokenByUserID(ctx, tx, userID, purpose); err != nil {
+		return dto.ErrConsumeOneTimeToken
+	}
+
+	return nil
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	IPaymentService interface {
+		HoldUntil(now time.Time) time.Time
+		NewInvoice(ctx context.Context, consultation entity.Consultation, user entity.User, expiresAt time.Time) (entity.Payment, error)
+		VoidInvoice(ctx context.Context, payment entity.Payment)
+		HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+		CancelPendingPayment(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) error
+		ExpireOverduePayments(ctx context.Context) (int, error)
+	}
+
+	PaymentService struct {
+		paymentRepo      repository.IPaymentRepository
+		consultationRepo repository.IConsultationRepository
+		gateway          utils.PaymentGateway
+		cfg              *config.PaymentConfig
+	}
+)
+
+func NewPaymentService(paymentRepo repository.IPaymentRepository, consultationRepo repository.IConsultationRepository, gateway utils.PaymentGateway, cfg *config.PaymentConfig) *PaymentService {
+	return &PaymentService{
+		paymentRepo:      paymentRepo,
+		consultationRepo: consultationRepo,
+		gateway:          gateway,
+		cfg:              cfg,
+	}
+}
+
+// HoldUntil adalah batas hold slot sekaligus batas bayar tagihan yang dibuat sekarang
+func (ps *PaymentService) HoldUntil(now time.Time) time.Time {
+	return now.Add(time.Duration(ps.cfg.HoldMinutes) * time.Minute)
+}
+
+// NewInvoice membuat tagihan di gateway untuk consultation berbayar. Payment yang
+// dikembalikan belum disimpan, caller menyimpannya bersama consultation dalam satu
+// transaksi dan memanggil VoidInvoice jika transaksi gagal.
+func (ps *PaymentService) NewInvoice(ctx context.Context, consultation entity.Consultation, user entity.User, expiresAt time.Time) (entity.Payment, error) {
+	now := time.Now()
+
+	payment := entity.Payment{
+		ID:             uuid.New(),
+		Amount:         consultation.Fee,
+		Currency:       constants.ENUM_PAYMENT_CURRENCY,
+		Status:         constants.ENUM_PAYMENT_STATUS_PENDING,
+		Gateway:        ps.gateway.Name(),
+		ExpiresAt:      expiresAt,
+		ConsultationID: &consultation.ID,
+	}
+	payment.InvoiceNumber = fmt.Sprintf("INV-%s-%s", now.Format("20060102"), strings.ToUpper(payment.ID.String()[:8]))
+
+	charge, err := ps.gateway.CreateCharge(ctx, utils.PaymentChargeRequest{
+		InvoiceNumber: payment.InvoiceNumber,
+		Amount:        payment.Amount,
+		Currency:      payment.Currency,
+		Description:   "Konsultasi " + consultation.Date,
+		CustomerName:  user.Name,
+		CustomerEmail: user.Email,
+		ExpiresAt:     payment.ExpiresAt,
+	})
+	if err != nil {
+		return entity.Payment{}, dto.ErrCreatePaymentCharge
+	}
+
+	payment.Reference = charge.Reference
+	payment.CheckoutURL = charge.CheckoutURL
+
+	return payment, nil
+}
+
+// VoidInvoice membatalkan charge yang payment-nya tidak jadi tersimpan, gagal void
+// hanya dicatat karena webhook untuk charge tersebut tidak akan cocok dengan payment mana pun
+func (ps *PaymentService) VoidInvoice(ctx context.Context, payment entity.Payment) {
+	if err := ps.gateway.VoidCharge(context.WithoutCancel(ctx), payment.Reference); err != nil {
+		log.Printf("failed to void charge %s for invoice %s: %v", payment.Reference, payment.InvoiceNumber, err)
+	}
+}
+
+// HandleWebhook memproses notifikasi dari gateway. Event yang sama bisa dikirim
+// berulang kali, hanya kiriman pertama yang mengubah payment dan consultation.
+func (ps *PaymentService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	event, err := ps.gateway.ParseWebhook(payload, header)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidWebhookSignature) {
+			return dto.ErrInvalidPaymentSignature
+		}
+
+		return dto.ErrInvalidPaymentWebhook
+	}
+
+	payment, flag, err := ps.paymentRepo.GetPaymentByReference(ctx, nil, ps.gateway.Name(), event.Reference)
+	if err != nil || !flag {
+		return dto.ErrPaymentNotFound
+	}
+
+	var lateRefund *entity.Refund
+	err = ps.paymentRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		recorded, err := ps.paymentRepo.CreatePaymentWebhookEvent(ctx, tx, entity.PaymentWebhookEvent{
+			ID:        uuid.New(),
+			Gateway:   ps.gateway.Name(),
+			EventID:   event.EventID,
+			Reference: event.Reference,
+			Status:    event.Status,
+			Payload:   string(event.Payload),
+			PaymentID: &payment.ID,
+		})
+		if err != nil {
+			return dto.ErrRecordPaymentWebhookEvent
+		}
+
+		if !recorded {
+			return nil
+		}
+
+		switch event.Status {
+		case constants.ENUM_PAYMENT_STATUS_PAID:
+			if event.Amount != payment.Amount {
+				return dto.ErrPaymentAmountMismatch
+			}
+
+			now := time.Now()
+			changed, err := ps.paymentRepo.UpdatePaymentStatus(ctx, tx, payment.ID, constants.ENUM_PAYMENT_STATUS_PENDING, constants.ENUM_PAYMENT_STATUS_PAID, &now)
+			if err != nil {
+				return dto.ErrUpdatePayment
+			}
+
+			if !changed {
+				lateRefund, err = ps.reconcileLatePayment(ctx, tx, payment, now)
+				return err
+			}
+
+			return ps.transitionConsultation(ctx, tx, payment, constants.ENUM_CONSULTATION_STATUS_REQUESTED, "pembayaran "+payment.InvoiceNumber+" diterima")
+		case constants.ENUM_PAYMENT_STATUS_FAILED, constants.ENUM_PAYMENT_STATUS_EXPIRED:
+			changed, err := ps.paymentRepo.UpdatePaymentStatus(ctx, tx, payment.ID, constants.ENUM_PAYMENT_STATUS_PENDING, event.Status, nil)
+			if err != nil {
+				return dto.ErrUpdatePayment
+			}
+
+			if !changed {
+				return nil
+			}
+
+			return ps.transitionConsultation(ctx, tx, payment, constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED, "pembayaran "+payment.InvoiceNumber+" "+event.Status)
+		}
+
+		// status lain (misalnya pending) tidak mengubah apa pun
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if lateRefund != nil {
+		log.Printf("payment %s paid after it was %s, refunding %d", lateRefund.Payment.InvoiceNumber, lateRefund.Payment.Status, lateRefund.Amount)
+
+		// refund yang gagal diulang oleh RetryRefunds
+		if refund := processRefund(ctx, ps.gateway, ps.paymentRepo, *lateRefund); refund.Status != constants.ENUM_REFUND_STATUS_SUCCEEDED {
+			log.Printf("failed to refund late payment %s: %s", lateRefund.Payment.InvoiceNumber, refund.FailureReason)
+		}
+	}
+
+	return nil
+}
+
+// CancelPendingPayment dipanggil saat user membatalkan consultation yang belum dibayar
+func (ps *PaymentService) CancelPendingPayment(ctx context.Context, tx *gorm.DB, consulID uuid.UUID) error {
+	payment, _, err := ps.paymentRepo.GetPendingPaymentByConsultationID(ctx, tx, consulID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		return dto.ErrUpdatePayment
+	}
+
+	if _, err := ps.paymentRepo.UpdatePaymentStatus(ctx, tx, payment.ID, constants.ENUM_PAYMENT_STATUS_PENDING, constants.ENUM_PAYMENT_STATUS_CANCELED, nil); err != nil {
+		return dto.ErrUpdatePayment
+	}
+
+	return nil
+}
+
+// ExpireOverduePayments melepas slot dari consultation yang tidak dibayar sampai batas hold
+func (ps *PaymentService) ExpireOverduePayments(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		payments, err := ps.paymentRepo.GetOverduePendingPayments(ctx, nil, time.Now(), constants.ENUM_PAYMENT_EXPIRE_BATCH)
+		if err != nil {
+			return total, dto.ErrExpirePayment
+		}
+
+		for _, payment := range payments {
+			expired := false
+			err := ps.paymentRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+				changed, err := ps.paymentRepo.UpdatePaymentStatus(ctx, tx, payment.ID, constants.ENUM_PAYMENT_STATUS_PENDING, constants.ENUM_PAYMENT_STATUS_EXPIRED, nil)
+				if err != nil {
+					return dto.ErrUpdatePayment
+				}
+
+				if !changed {
+					return nil
+				}
+
+				expired = true
+				return ps.transitionConsultation(ctx, tx, payment, constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED, "batas waktu pembayaran "+payment.InvoiceNumber+" habis")
+			})
+			if err != nil {
+				return total, err
+			}
+
+			if expired {
+				total++
+			}
+		}
+
+		if len(payments) < constants.ENUM_PAYMENT_EXPIRE_BATCH {
+			return total, nil
+		}
+	}
+}
+
+// reconcileLatePayment menangani pembayaran yang diterima gateway setelah tagihan ditutup
+// (kedaluwarsa, gagal, atau dibatalkan). Slot mungkin sudah dipakai orang lain, jadi
+// consultation tidak dihidupkan lagi: payment ditandai late_paid dan dana dikembalikan penuh.
+func (ps *PaymentService) reconcileLatePayment(ctx context.Context, tx *gorm.DB, payment entity.Payment, paidAt time.Time) (*entity.Refund, error) {
+	current, _, err := ps.paymentRepo.GetPaymentByReference(ctx, tx, payment.Gateway, payment.Reference)
+	if err != nil {
+		return nil, dto.ErrUpdatePayment
+	}
+
+	switch current.Status {
+	case constants.ENUM_PAYMENT_STATUS_FAILED, constants.ENUM_PAYMENT_STATUS_EXPIRED, constants.ENUM_PAYMENT_STATUS_CANCELED:
+	default:
+		// sudah paid atau sudah direkonsiliasi oleh event lain
+		return nil, nil
+	}
+
+	changed, err := ps.paymentRepo.UpdatePaymentStatus(ctx, tx, current.ID, current.Status, constants.ENUM_PAYMENT_STATUS_LATE_PAID, &paidAt)
+	if err != nil {
+		return nil, dto.ErrUpdatePayment
+	}
+
+	if !changed {
+		return nil, nil
+	}
+
+	refund := entity.Refund{
+		ID:             uuid.New(),
+		Amount:         current.Amount,
+		Percent:        100,
+		Rule:           constants.ENUM_CANCELLATION_RULE_LATE_PAYMENT,
+		Status:         constants.ENUM_REFUND_STATUS_PENDING,
+		Gateway:        current.Gateway,
+		PaymentID:      &current.ID,
+		ConsultationID: current.ConsultationID,
+	}
+	if err := ps.paymentRepo.CreateRefund(ctx, tx, refund); err != nil {
+		return nil, dto.ErrCreateRefund
+	}
+
+	refund.Payment = current
+	return &refund, nil
+}
+
+// transitionConsultation memindahkan consultation dari menunggu pembayaran dan
+// melepas hold slot, slot tetap booked hanya jika pembayaran diterima
+func (ps *PaymentService) transitionConsultation(ctx context.Context, tx *gorm.DB, payment entity.Payment, toStatus int, note string) error {
+	if payment.ConsultationID == nil {
+		return nil
+	}
+
+	fromStatus := constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT
+	if !helpers.CanTransitionConsultationStatus(constants.ENUM_CONSULTATION_CHANGED_BY_SYSTEM, fromStatus, toStatus) {
+		return dto.ErrInvalidConsultationTransition
+	}
+
+	changed, err := ps.consultationRepo.UpdateConsultationStatus(ctx, tx, *payment.ConsultationID, fromStatus, toStatus)
+	if err != nil {
+		return dto.ErrUpdateConsultation
+	}
+
+	// consultation sudah dibatalkan user lebih dulu
+	if !changed {
+		return nil
+	}
+
+	if payment.Consultation.AvailableSlotID != nil {
+		booked := toStatus == constants.ENUM_CONSULTATION_STATUS_REQUESTED
+		if err := ps.paymentRepo.ReleaseSlotHold(ctx, tx, *payment.Consultation.AvailableSlotID, booked); err != nil {
+			return dto.ErrUpdateStatusBookSlot
+		}
+	}
+
+	history := entity.ConsultationStatusHistory{
+		ID:             uuid.New(),
+		FromStatus:     &fromStatus,
+		ToStatus:       toStatus,
+		ChangedByRole:  constants.ENUM_CONSULTATION_CHANGED_BY_SYSTEM,
+		Note:           note,
+		ConsultationID: payment.ConsultationID,
+	}
+	if err := ps.consultationRepo.CreateConsultationStatusHistory(ctx, tx, history); err != nil {
+		return dto.ErrCreateConsultationStatusHistory
+	}
+
+	return nil
+}
+
+func toPaymentResponse(payment entity.Payment) dto.PaymentResponse {
+	res := dto.PaymentResponse{
+		ID:            payment.ID,
+		InvoiceNumber: payment.InvoiceNumber,
+		Amount:        payment.Amount,
+		Currency:      payment.Currency,
+		Status:        payment.Status,
+		Gateway:       payment.Gateway,
+		ExpiresAt:     payment.ExpiresAt,
+		PaidAt:        payment.PaidAt,
+	}
+
+	for _, refund := range payment.Refunds {
+		res.Refunds = append(res.Refunds, dto.RefundResponse{
+			ID:          refund.ID,
+			Amount:      refund.Amount,
+			Percent:     refund.Percent,
+			Rule:        refund.Rule,
+			Status:      refund.Status,
+			ProcessedAt: refund.ProcessedAt,
+		})
+	}
+
+	// link checkout hanya relevan selama tagihan masih bisa dibayar
+	if payment.Status == constants.ENUM_PAYMENT_STATUS_PENDING {
+		res.CheckoutURL = payment.CheckoutURL
+	}
+
+	return res
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
+)
+
+func TestHandleWebhookRejectsInvalidSignature(t *testing.T) {
+	e := newTestEnv(t)
+	_, payment := e.addPendingPayment(150000)
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"event_id":  "evt_1",
+		"reference": payment.Reference,
+		"status":    constants.ENUM_PAYMENT_STATUS_PAID,
+		"amount":    payment.Amount,
+	})
+
+	header := http.Header{}
+	header.Set(utils.FakePaymentSignatureHeader, utils.NewFakePaymentGateway("other-secret", "").SignWebhook(payload))
+
+	if err := e.paymentService().HandleWebhook(context.Background(), payload, header); !errors.Is(err, dto.ErrInvalidPaymentSignature) {
+		t.Fatalf("expected %v, got %v", dto.ErrInvalidPaymentSignature, err)
+	}
+
+	if status := e.payments.payments[payment.ID.String()].Status; status != constants.ENUM_PAYMENT_STATUS_PENDING {
+		t.Errorf("payment must stay pending, got %s", status)
+	}
+	if len(e.payments.events) != 0 {
+		t.Errorf("unsigned event must not be recorded, got %d", len(e.payments.events))
+	}
+}
+
+func TestHandleWebhook(t *testing.T) {
+	type event struct {
+		id     string
+		status string
+		// selisih dari nominal tagihan
+		amountDiff int64
+	}
+
+	expire := func(t *testing.T, e *testEnv, consul entity.Consultation, payment entity.Payment) {
+		payment.ExpiresAt = time.Now().Add(-time.Minute)
+		e.payments.payments[payment.ID.String()] = payment
+
+		if total, err := e.paymentService().ExpireOverduePayments(context.Background()); err != nil || total != 1 {
+			t.Fatalf("ExpireOverduePayments() = %d, %v", total, err)
+		}
+	}
+	cancelByUser := func(t *testing.T, e *testEnv, consul entity.Consultation, payment entity.Payment) {
+		// seperti DeleteConsultation: status diubah dan slot dilepas di user service
+		consul.Status = constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER
+		e.repo.consultations[consul.ID.String()] = consul
+		e.repo.UpdateStatusBookSlot(context.Background(), nil, *consul.AvailableSlotID, false)
+
+		if err := e.paymentService().CancelPendingPayment(context.Background(), nil, consul.ID); err != nil {
+			t.Fatalf("CancelPendingPayment() = %v", err)
+		}
+	}
+	gatewayDown := func(t *testing.T, e *testEnv, consul entity.Consultation, payment entity.Payment) {
+		if err := e.webhook(payment, "evt_expired", constants.ENUM_PAYMENT_STATUS_EXPIRED, payment.Amount); err != nil {
+			t.Fatalf("expired event: %v", err)
+		}
+		e.gateway.Err = errors.New("gateway down")
+	}
+
+	paidTwice := []event{
+		{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID},
+		{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID},
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, e *testEnv, consul entity.Consultation, payment entity.Payment)
+		events []event
+
+		wantErr          error
+		wantPayment      string
+		wantConsul       int
+		wantSlotBooked   bool
+		wantHistories    int
+		wantRefundStatus string
+	}{
+		{
+			name: "repeated event id is processed once",
+			events: []event{
+				{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID},
+				{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID},
+				{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID},
+				// event lain untuk pembayaran yang sama bukan pembayaran terlambat
+				{id: "evt_paid_again", status: constants.ENUM_PAYMENT_STATUS_PAID},
+			},
+			wantPayment:    constants.ENUM_PAYMENT_STATUS_PAID,
+			wantConsul:     constants.ENUM_CONSULTATION_STATUS_REQUESTED,
+			wantSlotBooked: true,
+			wantHistories:  1,
+		},
+		{
+			name:           "amount mismatch is rolled back for redelivery",
+			events:         []event{{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID, amountDiff: -1}},
+			wantErr:        dto.ErrPaymentAmountMismatch,
+			wantPayment:    constants.ENUM_PAYMENT_STATUS_PENDING,
+			wantConsul:     constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT,
+			wantSlotBooked: true,
+		},
+		{
+			name:             "late paid event after payment expired",
+			setup:            expire,
+			events:           paidTwice,
+			wantPayment:      constants.ENUM_PAYMENT_STATUS_LATE_PAID,
+			wantConsul:       constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED,
+			wantHistories:    1,
+			wantRefundStatus: constants.ENUM_REFUND_STATUS_SUCCEEDED,
+		},
+		{
+			name:             "late paid event after user canceled",
+			setup:            cancelByUser,
+			events:           paidTwice,
+			wantPayment:      constants.ENUM_PAYMENT_STATUS_LATE_PAID,
+			wantConsul:       constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER,
+			wantRefundStatus: constants.ENUM_REFUND_STATUS_SUCCEEDED,
+		},
+		{
+			name: "late paid event after failed event",
+			events: []event{
+				{id: "evt_failed", status: constants.ENUM_PAYMENT_STATUS_FAILED},
+				{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID},
+				{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID},
+			},
+			wantPayment:      constants.ENUM_PAYMENT_STATUS_LATE_PAID,
+			wantConsul:       constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED,
+			wantHistories:    1,
+			wantRefundStatus: constants.ENUM_REFUND_STATUS_SUCCEEDED,
+		},
+		{
+			name:             "late paid refund failure is kept for retry",
+			setup:            gatewayDown,
+			events:           []event{{id: "evt_paid", status: constants.ENUM_PAYMENT_STATUS_PAID}},
+			wantPayment:      constants.ENUM_PAYMENT_STATUS_LATE_PAID,
+			wantConsul:       constants.ENUM_CONSULTATION_STATUS_PAYMENT_EXPIRED,
+			wantHistories:    1,
+			wantRefundStatus: constants.ENUM_REFUND_STATUS_FAILED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			consul, payment := e.addPendingPayment(150000)
+			if tt.setup != nil {
+				tt.setup(t, e, consul, payment)
+			}
+
+			var err error
+			for _, ev := range tt.events {
+				if err = e.webhook(payment, ev.id, ev.status, payment.Amount+ev.amountDiff); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if status := e.payments.payments[payment.ID.String()].Status; status != tt.wantPayment {
+				t.Errorf("expected payment %s, got %s", tt.wantPayment, status)
+			}
+			if status := e.repo.consultations[consul.ID.String()].Status; status != tt.wantConsul {
+				t.Errorf("expected consultation %d, got %d", tt.wantConsul, status)
+			}
+			if len(e.repo.histories) != tt.wantHistories {
+				t.Errorf("expected %d status history, got %d", tt.wantHistories, len(e.repo.histories))
+			}
+
+			slot := e.repo.slots[consul.AvailableSlotID.String()]
+			if slot.IsBooked != tt.wantSlotBooked {
+				t.Errorf("expected slot booked=%v, got %v", tt.wantSlotBooked, slot.IsBooked)
+			}
+			if tt.wantErr == nil && slot.HeldUntil != nil {
+				t.Error("processed payment must release the slot hold")
+			}
+
+			if tt.wantErr != nil && len(e.payments.events) != 0 {
+				t.Error("rejected event must be rolled back so the gateway can redeliver it")
+			}
+
+			if tt.wantRefundStatus == "" {
+				if len(e.payments.refunds) != 0 {
+					t.Errorf("expected no refund, got %d", len(e.payments.refunds))
+				}
+				return
+			}
+
+			if len(e.payments.refunds) != 1 {
+				t.Fatalf("expected 1 refund, got %d", len(e.payments.refunds))
+			}
+			for _, refund := range e.payments.refunds {
+				if refund.Amount != payment.Amount || refund.Percent != 100 || refund.Rule != constants.ENUM_CANCELLATION_RULE_LATE_PAYMENT {
+					t.Errorf("expected full late payment refund of %d, got %d (%d%%, %s)", payment.Amount, refund.Amount, refund.Percent, refund.Rule)
+				}
+				if refund.Status != tt.wantRefundStatus {
+					t.Errorf("expected refund %s, got %s (%s)", tt.wantRefundStatus, refund.Status, refund.FailureReason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/google/uuid"
+)
+
+func TestCreatePractice(t *testing.T) {
+	one := 1
+
+	tests := []struct {
+		name              string
+		practiceType      string
+		templates         []dto.SlotTemplateRequest
+		failTemplateAfter *int
+		wantErr           error
+		wantTemplates     int
+	}{
+		{
+			name:         "request templates are saved with the practice",
+			practiceType: constants.ENUM_PRACTICE_TYPE_CLINIC,
+			templates: []dto.SlotTemplateRequest{
+				{Day: "Monday", Start: "08:00", End: "10:00", SessionDuration: 60},
+				{Day: "Tuesday", Start: "09:00", End: "10:00", SessionDuration: 60},
+			},
+			wantTemplates: 2,
+		},
+		{
+			name:          "default templates without request templates",
+			practiceType:  constants.ENUM_PRACTICE_TYPE_CLINIC,
+			wantTemplates: 6,
+		},
+		{
+			name:         "template outside the practice schedule",
+			practiceType: constants.ENUM_PRACTICE_TYPE_CLINIC,
+			templates: []dto.SlotTemplateRequest{
+				{Day: "Monday", Start: "08:00", End: "10:00", SessionDuration: 60},
+				{Day: "Thursday", Start: "08:00", End: "10:00", SessionDuration: 60},
+			},
+			wantErr: dto.ErrSlotTemplateOutsideSchedule,
+		},
+		{
+			name:         "templates in the same request overlap",
+			practiceType: constants.ENUM_PRACTICE_TYPE_CLINIC,
+			templates: []dto.SlotTemplateRequest{
+				{Day: "Monday", Start: "08:00", End: "10:00", SessionDuration: 60},
+				{Day: "Monday", Start: "09:00", End: "11:00", SessionDuration: 60},
+			},
+			wantErr: dto.ErrSlotTemplateOverlap,
+		},
+		{
+			name:         "insert failure rolls back the practice",
+			practiceType: constants.ENUM_PRACTICE_TYPE_CLINIC,
+			templates: []dto.SlotTemplateRequest{
+				{Day: "Monday", Start: "08:00", End: "10:00", SessionDuration: 60},
+				{Day: "Tuesday", Start: "09:00", End: "10:00", SessionDuration: 60},
+			},
+			failTemplateAfter: &one,
+			wantErr:           dto.ErrCreateSlotTemplate,
+		},
+		{
+			name:         "unknown practice type",
+			practiceType: "Kunjungan Rumah",
+			wantErr:      dto.ErrAddPracticeSchedule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePsychologRepo{failTemplateAfter: tt.failTemplateAfter}
+			ps := NewPsychologService(repo, nil, nil, fakeJWTService{}, NewSlotGenerator(repo), nil, nil, nil, nil)
+
+			res, err := ps.CreatePractice(withUser(context.Background(), uuid.New()), dto.CreatePracticeRequest{
+				Type:          tt.practiceType,
+				Name:          "Klinik Sehat",
+				Address:       "Jl. Merdeka 1",
+				PhoneNumber:   "0211234567",
+				SlotTemplates: tt.templates,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				if len(repo.practices) != 0 || len(repo.schedules) != 0 || len(repo.templates) != 0 || len(repo.slots) != 0 {
+					t.Errorf("failed request left %d practice, %d schedule, %d template, %d slot", len(repo.practices), len(repo.schedules), len(repo.templates), len(repo.slots))
+				}
+				return
+			}
+
+			if len(repo.practices) != 1 || len(repo.schedules) != 3 {
+				t.Errorf("expected 1 practice with 3 schedules, got %d and %d", len(repo.practices), len(repo.schedules))
+			}
+			if len(repo.templates) != tt.wantTemplates || len(res.SlotTemplates) != tt.wantTemplates {
+				t.Errorf("expected %d templates, stored %d and returned %d", tt.wantTemplates, len(repo.templates), len(res.SlotTemplates))
+			}
+			if len(repo.practices) == 1 && len(repo.practices[0].PracticeSchedules) != 0 {
+				t.Error("schedules must not be attached to the saved practice")
+			}
+		})
+	}
+}
@@ -1,13 +1,26 @@
 package service
 
 import (
+	"bytes"
 	"context"
-
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/entity"
 	"github.com/Reyysusanto/warasin-web/backend/helpers"
 	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/Reyysusanto/warasin-web/backend/utils"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type (
@@ -15,6 +28,28 @@ type (
 		// Authentication
 		Login(ctx context.Context, req dto.PsychologLoginRequest) (dto.PsychologLoginResponse, error)
 		RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.RefreshTokenResponse, error)
+		Logout(ctx context.Context, req dto.LogoutRequest) error
+		LogoutAll(ctx context.Context) error
+
+		// Two Factor
+		VerifyTwoFactorLogin(ctx context.Context, req dto.TwoFactorLoginRequest) (dto.PsychologLoginResponse, error)
+		SetupTwoFactorLogin(ctx context.Context, req dto.TwoFactorChallengeSetupRequest) (dto.TwoFactorSetupResponse, error)
+		GetTwoFactorStatus(ctx context.Context) (dto.TwoFactorStatusResponse, error)
+		SetupTwoFactor(ctx context.Context) (dto.TwoFactorSetupResponse, error)
+		EnableTwoFactor(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+		DisableTwoFactor(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+		RegenerateRecoveryCodes(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+
+		// Application
+		Apply(ctx context.Context, req dto.ApplyPsychologRequest) (dto.PsychologApplicationResponse, error)
+
+		// Profile
+		UpdateProfile(ctx context.Context, req dto.UpdatePsychologProfileRequest) (dto.PsychologProfileResponse, error)
+		GetAllProfileChangeRequest(ctx context.Context) ([]dto.PsychologChangeRequestResponse, error)
+		ChangePassword(ctx context.Context, req dto.ChangePsychologPasswordRequest) error
+		CreateEducation(ctx context.Context, req dto.CreateEducationRequest) (dto.EducationResponse, error)
+		UpdateEducation(ctx context.Context, req dto.UpdateEducationRequest) (dto.EducationResponse, error)
+		DeleteEducation(ctx context.Context, educationID string) (dto.EducationResponse, error)
 
 		// Practice
 		CreatePractice(ctx context.Context, req dto.CreatePracticeRequest) (dto.PracticeResponse, error)
@@ -24,24 +59,46 @@ type (
 
 		// Available Slot
 		GetAllAvailableSlot(ctx context.Context) (dto.AllAvailableSlotResponse, error)
+		GenerateAvailableSlot(ctx context.Context) (dto.GenerateAvailableSlotResponse, error)
+
+		// Slot Template
+		CreateSlotTemplate(ctx context.Context, req dto.CreateSlotTemplateRequest) (dto.SlotTemplateResponse, error)
+		GetAllSlotTemplate(ctx context.Context) ([]dto.SlotTemplateResponse, error)
+		UpdateSlotTemplate(ctx context.Context, req dto.UpdateSlotTemplateRequest) (dto.SlotTemplateResponse, error)
+		DeleteSlotTemplate(ctx context.Context, templateID string) (dto.SlotTemplateResponse, error)
 
 		// Consultation
 		GetAllConsultationWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.ConsultationPaginationResponse, error)
 		UpdateConsultation(ctx context.Context, req dto.UpdateConsultationRequest, consulID string) (dto.ConsultationResponse, error)
+		GetConsultationStatusHistory(ctx context.Context, consulID string) ([]dto.ConsultationStatusHistoryResponse, error)
 	}
 
 	PsychologService struct {
-		psychologRepo repository.IPsychologRepository
-		masterRepo    repository.IMasterRepository
-		jwtService    IJWTService
+		psychologRepo    repository.IPsychologRepository
+		consultationRepo repository.IConsultationRepository
+		masterRepo       repository.IMasterRepository
+		jwtService       IJWTService
+		slotGenerator    ISlotGenerator
+
+		refreshTokenService IRefreshTokenService
+		loginGuard          ILoginGuardService
+		twoFactorService    ITwoFactorService
+		cancellationService ICancellationService
 	}
 )
 
-func NewPsychologService(psychologRepo repository.IPsychologRepository, masterRepo repository.IMasterRepository, jwtService IJWTService) *PsychologService {
+func NewPsychologService(psychologRepo repository.IPsychologRepository, consultationRepo repository.IConsultationRepository, masterRepo repository.IMasterRepository, jwtService IJWTService, slotGenerator ISlotGenerator, refreshTokenService IRefreshTokenService, loginGuard ILoginGuardService, twoFactorService ITwoFactorService, cancellationService ICancellationService) *PsychologService {
 	return &PsychologService{
-		psychologRepo: psychologRepo,
-		masterRepo:    masterRepo,
-		jwtService:    jwtService,
+		psychologRepo:    psychologRepo,
+		consultationRepo: consultationRepo,
+		masterRepo:       masterRepo,
+		jwtService:       jwtService,
+		slotGenerator:    slotGenerator,
+
+		refreshTokenService: refreshTokenService,
+		loginGuard:          loginGuard,
+		twoFactorService:    twoFactorService,
+		cancellationService: cancellationService,
 	}
 }
 
@@ -55,8 +112,15 @@ func (ps *PsychologService) Login(ctx context.Context, req dto.PsychologLoginReq
 		return dto.PsychologLoginResponse{}, dto.ErrInvalidPassword
 	}
 
+	if err := ps.loginGuard.Check(ctx, constants.ENUM_ROLE_PSYCHOLOG, req.Email, req.IPAddress); err != nil {
+		return dto.PsychologLoginResponse{}, err
+	}
+
 	psycholog, flag, err := ps.masterRepo.GetPsychologByEmail(ctx, nil, req.Email)
 	if !flag || err != nil {
+		if err := ps.loginGuard.RecordFailure(ctx, constants.ENUM_ROLE_PSYCHOLOG, req.Email, req.IPAddress, false); err != nil {
+			return dto.PsychologLoginResponse{}, err
+		}
 		return dto.PsychologLoginResponse{}, dto.ErrEmailNotFound
 	}
 
@@ -66,61 +130,734 @@ func (ps *PsychologService) Login(ctx context.Context, req dto.PsychologLoginReq
 
 	checkPassword, err := helpers.CheckPassword(psycholog.Password, []byte(req.Password))
 	if err != nil || !checkPassword {
+		if err := ps.loginGuard.RecordFailure(ctx, constants.ENUM_ROLE_PSYCHOLOG, req.Email, req.IPAddress, true); err != nil {
+			return dto.PsychologLoginResponse{}, err
+		}
 		return dto.PsychologLoginResponse{}, dto.ErrPasswordNotMatch
 	}
 
-	permissions, _, err := ps.psychologRepo.GetPermissionsByRoleID(ctx, nil, psycholog.RoleID.String())
-	if err != nil {
-		return dto.PsychologLoginResponse{}, dto.ErrGetPermissionsByRoleID
+	if err := ps.loginGuard.RecordSuccess(ctx, constants.ENUM_ROLE_PSYCHOLOG, req.Email); err != nil {
+		return dto.PsychologLoginResponse{}, err
+	}
+
+	switch psycholog.Status {
+	case constants.ENUM_PSYCHOLOG_STATUS_PENDING:
+		return dto.PsychologLoginResponse{}, dto.ErrPsychologPendingApproval
+	case constants.ENUM_PSYCHOLOG_STATUS_REJECTED:
+		return dto.PsychologLoginResponse{}, dto.ErrPsychologApplicationRejected
+	}
+
+	challenge, required, err := ps.twoFactorService.StartChallenge(ctx, psycholog.ID.String(), psycholog.RoleID.String(), constants.ENUM_ROLE_PSYCHOLOG, psycholog.Email)
+	if err != nil {
+		return dto.PsychologLoginResponse{}, err
+	}
+
+	if required {
+		return dto.PsychologLoginResponse{
+			TwoFactorRequired:      true,
+			TwoFactorSetupRequired: challenge.SetupRequired,
+			ChallengeToken:         challenge.Token,
+			ChallengeExpiresIn:     challenge.ExpiresIn,
+		}, nil
+	}
+
+	accessToken, err := ps.jwtService.GenerateAccessToken(psycholog.ID.String(), psycholog.RoleID.String())
+	if err != nil {
+		return dto.PsychologLoginResponse{}, err
+	}
+
+	refreshToken, err := ps.refreshTokenService.Issue(ctx, psycholog.ID.String(), psycholog.RoleID.String(), constants.ENUM_ROLE_PSYCHOLOG)
+	if err != nil {
+		return dto.PsychologLoginResponse{}, err
+	}
+
+	return dto.PsychologLoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+func (ps *PsychologService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.RefreshTokenResponse, error) {
+	token, refreshToken, err := ps.refreshTokenService.Rotate(ctx, req.RefreshToken, constants.ENUM_ROLE_PSYCHOLOG)
+	if err != nil {
+		return dto.RefreshTokenResponse{}, err
+	}
+
+	accessToken, err := ps.jwtService.GenerateAccessToken(token.SubjectID.String(), token.RoleID.String())
+	if err != nil {
+		return dto.RefreshTokenResponse{}, dto.ErrGenerateAccessToken
+	}
+
+	return dto.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+func (ps *PsychologService) Logout(ctx context.Context, req dto.LogoutRequest) error {
+	return ps.refreshTokenService.Revoke(ctx, req.RefreshToken, constants.ENUM_ROLE_PSYCHOLOG)
+}
+func (ps *PsychologService) LogoutAll(ctx context.Context) error {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.ErrGetUserIDFromToken
+	}
+
+	return ps.refreshTokenService.RevokeAll(ctx, userID, constants.ENUM_ROLE_PSYCHOLOG)
+}
+
+// Two Factor
+func (ps *PsychologService) VerifyTwoFactorLogin(ctx context.Context, req dto.TwoFactorLoginRequest) (dto.PsychologLoginResponse, error) {
+	challenge, recoveryCodes, err := ps.twoFactorService.VerifyChallenge(ctx, constants.ENUM_ROLE_PSYCHOLOG, req)
+	if err != nil {
+		return dto.PsychologLoginResponse{}, err
+	}
+
+	accessToken, err := ps.jwtService.GenerateAccessToken(challenge.SubjectID.String(), challenge.RoleID.String())
+	if err != nil {
+		return dto.PsychologLoginResponse{}, err
+	}
+
+	refreshToken, err := ps.refreshTokenService.Issue(ctx, challenge.SubjectID.String(), challenge.RoleID.String(), constants.ENUM_ROLE_PSYCHOLOG)
+	if err != nil {
+		return dto.PsychologLoginResponse{}, err
+	}
+
+	return dto.PsychologLoginResponse{
+		AccessToken:   accessToken,
+		RefreshToken:  refreshToken,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+func (ps *PsychologService) SetupTwoFactorLogin(ctx context.Context, req dto.TwoFactorChallengeSetupRequest) (dto.TwoFactorSetupResponse, error) {
+	return ps.twoFactorService.SetupFromChallenge(ctx, constants.ENUM_ROLE_PSYCHOLOG, req)
+}
+func (ps *PsychologService) GetTwoFactorStatus(ctx context.Context) (dto.TwoFactorStatusResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	return ps.twoFactorService.GetStatus(ctx, psyID, constants.ENUM_ROLE_PSYCHOLOG)
+}
+func (ps *PsychologService) SetupTwoFactor(ctx context.Context) (dto.TwoFactorSetupResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	psycholog, flag, err := ps.psychologRepo.GetPsychologByID(ctx, nil, psyID)
+	if err != nil || !flag {
+		return dto.TwoFactorSetupResponse{}, dto.ErrPsychologNotFound
+	}
+
+	return ps.twoFactorService.Setup(ctx, psyID, constants.ENUM_ROLE_PSYCHOLOG, psycholog.Email)
+}
+func (ps *PsychologService) EnableTwoFactor(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	return ps.twoFactorService.Enable(ctx, psyID, constants.ENUM_ROLE_PSYCHOLOG, req)
+}
+func (ps *PsychologService) DisableTwoFactor(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	return ps.twoFactorService.Disable(ctx, psyID, constants.ENUM_ROLE_PSYCHOLOG, req)
+}
+func (ps *PsychologService) RegenerateRecoveryCodes(ctx context.Context, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	return ps.twoFactorService.RegenerateRecoveryCodes(ctx, psyID, constants.ENUM_ROLE_PSYCHOLOG, req)
+}
+
+// Application
+func (ps *PsychologService) Apply(ctx context.Context, req dto.ApplyPsychologRequest) (dto.PsychologApplicationResponse, error) {
+	if len(req.Name) < 5 {
+		return dto.PsychologApplicationResponse{}, dto.ErrInvalidName
+	}
+
+	if !helpers.IsValidSTRNumber(req.STRNumber) {
+		return dto.PsychologApplicationResponse{}, dto.ErrInvalidSTRNumber
+	}
+
+	if len(req.WorkYear) < 4 {
+		return dto.PsychologApplicationResponse{}, dto.ErrInvalidWorkYear
+	}
+
+	if !helpers.IsValidEmail(req.Email) {
+		return dto.PsychologApplicationResponse{}, dto.ErrInvalidEmail
+	}
+
+	_, flag, err := ps.masterRepo.GetPsychologByEmail(ctx, nil, req.Email)
+	if flag || err == nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrEmailAlreadyExists
+	}
+
+	if len(req.Password) < 8 {
+		return dto.PsychologApplicationResponse{}, dto.ErrInvalidPassword
+	}
+
+	phoneNumberFormatted, err := helpers.StandardizePhoneNumber(req.PhoneNumber, true)
+	if err != nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrFormatPhoneNumber
+	}
+
+	if req.CityID == nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrGetCityByID
+	}
+
+	city, err := ps.masterRepo.GetCityByID(ctx, nil, req.CityID.String())
+	if err != nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrGetCityByID
+	}
+
+	role, _, err := ps.psychologRepo.GetRoleByName(ctx, nil, constants.ENUM_ROLE_PSYCHOLOG)
+	if err != nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrGetRoleFromName
+	}
+
+	// dokumen divalidasi di awal agar tidak ada file yang tersimpan jika request tidak valid
+	hasLicense := false
+	for _, document := range req.Documents {
+		if document.Type != constants.ENUM_PSYCHOLOG_DOCUMENT_LICENSE &&
+			document.Type != constants.ENUM_PSYCHOLOG_DOCUMENT_STR &&
+			document.Type != constants.ENUM_PSYCHOLOG_DOCUMENT_CERTIFICATE {
+			return dto.PsychologApplicationResponse{}, dto.ErrInvalidDocumentType
+		}
+
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(document.FileHeader.Filename), "."))
+		if ext != "pdf" && ext != "jpg" && ext != "jpeg" && ext != "png" {
+			return dto.PsychologApplicationResponse{}, dto.ErrInvalidExtensionDocument
+		}
+
+		if document.FileHeader.Size > constants.ENUM_PSYCHOLOG_DOCUMENT_MAX_SIZE {
+			return dto.PsychologApplicationResponse{}, dto.ErrDocumentTooLarge
+		}
+
+		if document.Type == constants.ENUM_PSYCHOLOG_DOCUMENT_LICENSE {
+			hasLicense = true
+		}
+	}
+
+	if !hasLicense {
+		return dto.PsychologApplicationResponse{}, dto.ErrLicenseDocumentRequired
+	}
+
+	psycholog := entity.Psycholog{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		STRNumber:   req.STRNumber,
+		Email:       req.Email,
+		Password:    helpers.PasswordHash(req.Password),
+		WorkYear:    req.WorkYear,
+		Gender:      req.Gender,
+		Description: req.Description,
+		PhoneNumber: phoneNumberFormatted,
+		Status:      constants.ENUM_PSYCHOLOG_STATUS_PENDING,
+		CityID:      &city.ID,
+		RoleID:      &role.ID,
+	}
+
+	var savedFiles []string
+	if req.FileHeader != nil || req.FileReader != nil {
+		ext := strings.TrimPrefix(filepath.Ext(req.FileHeader.Filename), ".")
+		ext = strings.ToLower(ext)
+		if ext != "jpg" && ext != "jpeg" && ext != "png" {
+			return dto.PsychologApplicationResponse{}, dto.ErrInvalidExtensionPhoto
+		}
+
+		// nama file memakai id psycholog agar pendaftar dengan nama sama tidak saling menimpa
+		fileName := fmt.Sprintf("%s_warasin.%s", psycholog.ID.String(), ext)
+
+		_ = os.MkdirAll("assets/psycholog", os.ModePerm)
+		savePath := fmt.Sprintf("assets/psycholog/%s", fileName)
+		if err := saveUploadedFile(savePath, req.FileReader); err != nil {
+			return dto.PsychologApplicationResponse{}, err
+		}
+
+		savedFiles = append(savedFiles, savePath)
+		psycholog.Image = fileName
+	}
+
+	var documents []entity.PsychologDocument
+	_ = os.MkdirAll(psychologDocumentDir, os.ModePerm)
+	for _, document := range req.Documents {
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(document.FileHeader.Filename), "."))
+		documentID := uuid.New()
+		fileName := fmt.Sprintf("%s.%s", documentID.String(), ext)
+		savePath := filepath.Join(psychologDocumentDir, fileName)
+		if err := saveUploadedFile(savePath, document.FileReader); err != nil {
+			removeFiles(savedFiles)
+			return dto.PsychologApplicationResponse{}, err
+		}
+		savedFiles = append(savedFiles, savePath)
+
+		documents = append(documents, entity.PsychologDocument{
+			ID:           documentID,
+			Type:         document.Type,
+			FileName:     fileName,
+			OriginalName: filepath.Base(document.FileHeader.Filename),
+			ContentType:  document.FileHeader.Header.Get("Content-Type"),
+			Size:         document.FileHeader.Size,
+			PsychologID:  &psycholog.ID,
+		})
+	}
+
+	err = ps.psychologRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := ps.psychologRepo.CreatePsycholog(ctx, tx, psycholog); err != nil {
+			return dto.ErrApplyPsycholog
+		}
+
+		var newLangs []entity.PsychologLanguage
+		for _, langID := range req.LanguageMasterIDs {
+			languageMaster, found, err := ps.psychologRepo.GetLanguageMasterByID(ctx, tx, langID)
+			if err != nil || !found {
+				return dto.ErrLanguageMasterNotFound
+			}
+
+			newLangs = append(newLangs, entity.PsychologLanguage{
+				ID:               uuid.New(),
+				PsychologID:      &psycholog.ID,
+				LanguageMasterID: &languageMaster.ID,
+			})
+		}
+
+		if len(newLangs) > 0 {
+			if err := ps.psychologRepo.CreatePsychologLanguages(ctx, tx, newLangs); err != nil {
+				return dto.ErrCreatePsychologLanguages
+			}
+		}
+
+		var newSpecializations []entity.PsychologSpecialization
+		for _, speID := range req.SpecializationIDs {
+			specialization, found, err := ps.psychologRepo.GetSpecializationByID(ctx, tx, speID)
+			if err != nil || !found {
+				return dto.ErrSpecializationNotFound
+			}
+
+			newSpecializations = append(newSpecializations, entity.PsychologSpecialization{
+				ID:               uuid.New(),
+				PsychologID:      &psycholog.ID,
+				SpecializationID: &specialization.ID,
+			})
+		}
+
+		if len(newSpecializations) > 0 {
+			if err := ps.psychologRepo.CreatePsychologSpecializations(ctx, tx, newSpecializations); err != nil {
+				return dto.ErrCreatePsychologSpecializations
+			}
+		}
+
+		var newEducations []entity.Education
+		for _, education := range req.Educations {
+			newEducations = append(newEducations, entity.Education{
+				ID:             uuid.New(),
+				Degree:         education.Degree,
+				Major:          education.Major,
+				Institution:    education.Institution,
+				GraduationYear: education.GraduationYear,
+				PsychologID:    &psycholog.ID,
+			})
+		}
+
+		if len(newEducations) > 0 {
+			if err := ps.psychologRepo.CreateEducations(ctx, tx, newEducations); err != nil {
+				return dto.ErrCreateEducations
+			}
+		}
+
+		if err := ps.psychologRepo.CreatePsychologDocuments(ctx, tx, documents); err != nil {
+			return dto.ErrCreatePsychologDocuments
+		}
+
+		return nil
+	})
+	if err != nil {
+		removeFiles(savedFiles)
+		return dto.PsychologApplicationResponse{}, err
+	}
+
+	newPsycholog, _, err := ps.psychologRepo.GetPsychologByID(ctx, nil, psycholog.ID.String())
+	if err != nil {
+		return dto.PsychologApplicationResponse{}, dto.ErrGetPsychologFromID
+	}
+	newPsycholog.Documents = documents
+
+	sendPsychologApplicationEmail(newPsycholog)
+
+	return toPsychologApplicationResponse(newPsycholog), nil
+}
+
+// Profile
+func (ps *PsychologService) UpdateProfile(ctx context.Context, req dto.UpdatePsychologProfileRequest) (dto.PsychologProfileResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.PsychologProfileResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	psycholog, flag, err := ps.psychologRepo.GetPsychologByID(ctx, nil, psyID)
+	if err != nil || !flag {
+		return dto.PsychologProfileResponse{}, dto.ErrPsychologNotFound
+	}
+
+	if req.Name != "" {
+		if len(req.Name) < 5 {
+			return dto.PsychologProfileResponse{}, dto.ErrInvalidName
+		}
+
+		psycholog.Name = req.Name
+	}
+
+	if req.WorkYear != "" {
+		if len(req.WorkYear) < 4 {
+			return dto.PsychologProfileResponse{}, dto.ErrInvalidWorkYear
+		}
+
+		psycholog.WorkYear = req.WorkYear
+	}
+
+	if req.Gender != nil {
+		psycholog.Gender = req.Gender
+	}
+
+	if req.Description != "" {
+		psycholog.Description = req.Description
+	}
+
+	if req.PhoneNumber != "" {
+		phoneNumberFormatted, err := helpers.StandardizePhoneNumber(req.PhoneNumber, true)
+		if err != nil {
+			return dto.PsychologProfileResponse{}, dto.ErrFormatPhoneNumber
+		}
+
+		psycholog.PhoneNumber = phoneNumberFormatted
+	}
+
+	if req.CityID != nil {
+		city, err := ps.masterRepo.GetCityByID(ctx, nil, req.CityID.String())
+		if err != nil {
+			return dto.PsychologProfileResponse{}, dto.ErrGetCityByID
+		}
+
+		psycholog.CityID = &city.ID
+	}
+
+	// STR dan email tidak langsung diubah, melainkan diajukan ke admin
+	var changeRequests []entity.PsychologChangeRequest
+	if req.STRNumber != "" && req.STRNumber != psycholog.STRNumber {
+		if !helpers.IsValidSTRNumber(req.STRNumber) {
+			return dto.PsychologProfileResponse{}, dto.ErrInvalidSTRNumber
+		}
+
+		changeRequests = append(changeRequests, entity.PsychologChangeRequest{
+			ID:          uuid.New(),
+			Field:       constants.ENUM_CHANGE_REQUEST_FIELD_STR,
+			OldValue:    psycholog.STRNumber,
+			NewValue:    req.STRNumber,
+			Status:      constants.ENUM_CHANGE_REQUEST_STATUS_PENDING,
+			PsychologID: &psycholog.ID,
+		})
+	}
+
+	if req.Email != "" && req.Email != psycholog.Email {
+		if !helpers.IsValidEmail(req.Email) {
+			return dto.PsychologProfileResponse{}, dto.ErrInvalidEmail
+		}
+
+		_, flag, err := ps.masterRepo.GetPsychologByEmail(ctx, nil, req.Email)
+		if flag || err == nil {
+			return dto.PsychologProfileResponse{}, dto.ErrEmailAlreadyExists
+		}
+
+		changeRequests = append(changeRequests, entity.PsychologChangeRequest{
+			ID:          uuid.New(),
+			Field:       constants.ENUM_CHANGE_REQUEST_FIELD_EMAIL,
+			OldValue:    psycholog.Email,
+			NewValue:    req.Email,
+			Status:      constants.ENUM_CHANGE_REQUEST_STATUS_PENDING,
+			PsychologID: &psycholog.ID,
+		})
+	}
+
+	for _, changeRequest := range changeRequests {
+		if _, found, _ := ps.psychologRepo.GetPendingChangeRequestByField(ctx, nil, psyID, changeRequest.Field); found {
+			return dto.PsychologProfileResponse{}, dto.ErrChangeRequestAlreadyPending
+		}
+	}
+
+	var savedFiles []string
+	if req.FileHeader != nil || req.FileReader != nil {
+		ext := strings.TrimPrefix(filepath.Ext(req.FileHeader.Filename), ".")
+		ext = strings.ToLower(ext)
+		if ext != "jpg" && ext != "jpeg" && ext != "png" {
+			return dto.PsychologProfileResponse{}, dto.ErrInvalidExtensionPhoto
+		}
+
+		fileName := fmt.Sprintf("%s_warasin.%s", psycholog.ID.String(), ext)
+
+		_ = os.MkdirAll("assets/psycholog", os.ModePerm)
+		savePath := fmt.Sprintf("assets/psycholog/%s", fileName)
+		if err := saveUploadedFile(savePath, req.FileReader); err != nil {
+			return dto.PsychologProfileResponse{}, err
+		}
+
+		if psycholog.Image != fileName {
+			savedFiles = append(savedFiles, savePath)
+		}
+		psycholog.Image = fileName
+	}
+
+	err = ps.psychologRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := ps.psychologRepo.UpdatePsycholog(ctx, tx, psycholog); err != nil {
+			return dto.ErrUpdatePsycholog
+		}
+
+		if len(req.LanguageMasterIDs) > 0 {
+			if err := ps.psychologRepo.DeletePsychologLanguageByPsychologID(ctx, tx, psyID); err != nil {
+				return dto.ErrDeletePsychologLanguageByPsychologID
+			}
+
+			var newLangs []entity.PsychologLanguage
+			for _, langID := range req.LanguageMasterIDs {
+				languageMaster, found, err := ps.psychologRepo.GetLanguageMasterByID(ctx, tx, langID)
+				if err != nil || !found {
+					return dto.ErrLanguageMasterNotFound
+				}
+
+				newLangs = append(newLangs, entity.PsychologLanguage{
+					ID:               uuid.New(),
+					PsychologID:      &psycholog.ID,
+					LanguageMasterID: &languageMaster.ID,
+				})
+			}
+
+			if err := ps.psychologRepo.CreatePsychologLanguages(ctx, tx, newLangs); err != nil {
+				return dto.ErrCreatePsychologLanguages
+			}
+		}
+
+		if len(req.SpecializationIDs) > 0 {
+			if err := ps.psychologRepo.DeletePsychologSpecializationByPsychologID(ctx, tx, psyID); err != nil {
+				return dto.ErrDeletePsychologSpecializationByPsychologID
+			}
+
+			var newSpecializations []entity.PsychologSpecialization
+			for _, speID := range req.SpecializationIDs {
+				specialization, found, err := ps.psychologRepo.GetSpecializationByID(ctx, tx, speID)
+				if err != nil || !found {
+					return dto.ErrSpecializationNotFound
+				}
+
+				newSpecializations = append(newSpecializations, entity.PsychologSpecialization{
+					ID:               uuid.New(),
+					PsychologID:      &psycholog.ID,
+					SpecializationID: &specialization.ID,
+				})
+			}
+
+			if err := ps.psychologRepo.CreatePsychologSpecializations(ctx, tx, newSpecializations); err != nil {
+				return dto.ErrCreatePsychologSpecializations
+			}
+		}
+
+		for _, changeRequest := range changeRequests {
+			if err := ps.psychologRepo.CreatePsychologChangeRequest(ctx, tx, changeRequest); err != nil {
+				return dto.ErrCreatePsychologChangeRequest
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		removeFiles(savedFiles)
+		return dto.PsychologProfileResponse{}, err
+	}
+
+	updatedPsycholog, _, err := ps.psychologRepo.GetPsychologByID(ctx, nil, psyID)
+	if err != nil {
+		return dto.PsychologProfileResponse{}, dto.ErrGetPsychologFromID
+	}
+
+	pendingChangeRequests, err := ps.psychologRepo.GetAllPsychologChangeRequest(ctx, nil, psyID, constants.ENUM_CHANGE_REQUEST_STATUS_PENDING)
+	if err != nil {
+		return dto.PsychologProfileResponse{}, dto.ErrGetAllPsychologChangeRequest
+	}
+
+	return dto.PsychologProfileResponse{
+		PsychologResponse:     toPsychologApplicationResponse(updatedPsycholog).PsychologResponse,
+		PendingChangeRequests: toPsychologChangeRequestResponses(pendingChangeRequests),
+	}, nil
+}
+func (ps *PsychologService) GetAllProfileChangeRequest(ctx context.Context) ([]dto.PsychologChangeRequestResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return []dto.PsychologChangeRequestResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	changeRequests, err := ps.psychologRepo.GetAllPsychologChangeRequest(ctx, nil, psyID, "")
+	if err != nil {
+		return []dto.PsychologChangeRequestResponse{}, dto.ErrGetAllPsychologChangeRequest
+	}
+
+	return toPsychologChangeRequestResponses(changeRequests), nil
+}
+func (ps *PsychologService) ChangePassword(ctx context.Context, req dto.ChangePsychologPasswordRequest) error {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.ErrGetPsychologIDFromToken
+	}
+
+	psycholog, flag, err := ps.psychologRepo.GetPsychologByID(ctx, nil, psyID)
+	if err != nil || !flag {
+		return dto.ErrPsychologNotFound
+	}
+
+	checkPassword, err := helpers.CheckPassword(psycholog.Password, []byte(req.OldPassword))
+	if err != nil || !checkPassword {
+		return dto.ErrOldPasswordNotMatch
+	}
+
+	if len(req.NewPassword) < 8 {
+		return dto.ErrInvalidPassword
+	}
+
+	if req.NewPassword == req.OldPassword {
+		return dto.ErrSamePassword
+	}
+
+	hashedPassword, err := helpers.HashPassword(req.NewPassword)
+	if err != nil {
+		return dto.ErrHashPassword
+	}
+
+	if err := ps.psychologRepo.UpdatePsychologPassword(ctx, nil, psyID, hashedPassword); err != nil {
+		return dto.ErrUpdatePassword
+	}
+
+	// sesi lain dipaksa login ulang dengan password baru
+	return ps.refreshTokenService.RevokeAll(ctx, psyID, constants.ENUM_ROLE_PSYCHOLOG)
+}
+func (ps *PsychologService) CreateEducation(ctx context.Context, req dto.CreateEducationRequest) (dto.EducationResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.EducationResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	psyUUID, err := uuid.Parse(psyID)
+	if err != nil {
+		return dto.EducationResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	if !isValidGraduationYear(req.GraduationYear) {
+		return dto.EducationResponse{}, dto.ErrInvalidGraduationYear
+	}
+
+	education := entity.Education{
+		ID:             uuid.New(),
+		Degree:         req.Degree,
+		Major:          req.Major,
+		Institution:    req.Institution,
+		GraduationYear: req.GraduationYear,
+		PsychologID:    &psyUUID,
+	}
+
+	if err := ps.psychologRepo.CreateEducations(ctx, nil, []entity.Education{education}); err != nil {
+		return dto.EducationResponse{}, dto.ErrCreateEducations
+	}
+
+	return toEducationResponse(education), nil
+}
+func (ps *PsychologService) UpdateEducation(ctx context.Context, req dto.UpdateEducationRequest) (dto.EducationResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.EducationResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	education, flag, err := ps.psychologRepo.GetEducationByID(ctx, nil, req.ID)
+	if err != nil || !flag {
+		return dto.EducationResponse{}, dto.ErrEducationNotFound
+	}
+
+	if education.PsychologID == nil || education.PsychologID.String() != psyID {
+		return dto.EducationResponse{}, dto.ErrDeniedAccess
 	}
 
-	accessToken, refreshToken, err := ps.jwtService.GenerateToken(psycholog.ID.String(), psycholog.RoleID.String(), permissions)
-	if err != nil {
-		return dto.PsychologLoginResponse{}, err
+	if req.Degree != "" {
+		education.Degree = req.Degree
 	}
 
-	return dto.PsychologLoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
-}
-func (ps *PsychologService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.RefreshTokenResponse, error) {
-	_, err := ps.jwtService.ValidateToken(req.RefreshToken)
+	if req.Major != "" {
+		education.Major = req.Major
+	}
 
-	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrValidateToken
+	if req.Institution != "" {
+		education.Institution = req.Institution
 	}
 
-	userID, err := ps.jwtService.GetUserIDByToken(req.RefreshToken)
-	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetUserIDFromToken
+	if req.GraduationYear != "" {
+		if !isValidGraduationYear(req.GraduationYear) {
+			return dto.EducationResponse{}, dto.ErrInvalidGraduationYear
+		}
+
+		education.GraduationYear = req.GraduationYear
 	}
 
-	roleID, err := ps.jwtService.GetRoleIDByToken(req.RefreshToken)
-	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetRoleFromToken
+	if err := ps.psychologRepo.UpdateEducation(ctx, nil, education); err != nil {
+		return dto.EducationResponse{}, dto.ErrUpdateEducation
 	}
 
-	role, _, err := ps.psychologRepo.GetRoleByID(ctx, nil, roleID)
+	return toEducationResponse(education), nil
+}
+func (ps *PsychologService) DeleteEducation(ctx context.Context, educationID string) (dto.EducationResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetRoleFromID
+		return dto.EducationResponse{}, dto.ErrGetPsychologIDFromToken
 	}
 
-	if role.Name != "psycholog" {
-		return dto.RefreshTokenResponse{}, dto.ErrDeniedAccess
+	education, flag, err := ps.psychologRepo.GetEducationByID(ctx, nil, educationID)
+	if err != nil || !flag {
+		return dto.EducationResponse{}, dto.ErrEducationNotFound
 	}
 
-	endpoints, _, err := ps.psychologRepo.GetPermissionsByRoleID(ctx, nil, roleID)
-	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetPermissionsByRoleID
+	if education.PsychologID == nil || education.PsychologID.String() != psyID {
+		return dto.EducationResponse{}, dto.ErrDeniedAccess
 	}
 
-	accessToken, _, err := ps.jwtService.GenerateToken(userID, roleID, endpoints)
-	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGenerateAccessToken
+	if err := ps.psychologRepo.DeleteEducationByID(ctx, nil, educationID); err != nil {
+		return dto.EducationResponse{}, dto.ErrDeleteEducation
 	}
 
-	return dto.RefreshTokenResponse{AccessToken: accessToken}, nil
+	return toEducationResponse(education), nil
 }
 
 // Practice
@@ -146,23 +883,23 @@ func (ps *PsychologService) CreatePractice(ctx context.Context, req dto.CreatePr
 		return dto.PracticeResponse{}, dto.ErrFormatPhoneNumber
 	}
 
+	if req.Fee < 0 {
+		return dto.PracticeResponse{}, dto.ErrInvalidPracticeFee
+	}
+
 	practice := entity.Practice{
 		ID:          uuid.New(),
 		Type:        req.Type,
 		Name:        req.Name,
 		Address:     req.Address,
 		PhoneNumber: phoneNumberFormatted,
+		Fee:         req.Fee,
 		PsychologID: &psychologID,
 	}
 
-	err = ps.psychologRepo.CreatePractice(ctx, nil, practice)
-	if err != nil {
-		return dto.PracticeResponse{}, dto.ErrCreatePractice
-	}
-
 	var schedules []entity.PracticeSchedule
 	switch req.Type {
-	case "Konsultasi Online":
+	case constants.ENUM_PRACTICE_TYPE_ONLINE:
 		days := []string{"Thursday", "Friday", "Saturday"}
 		for _, day := range days {
 			schedules = append(schedules, entity.PracticeSchedule{
@@ -173,7 +910,7 @@ func (ps *PsychologService) CreatePractice(ctx context.Context, req dto.CreatePr
 				PracticeID: &practice.ID,
 			})
 		}
-	case "Praktek Klinik":
+	case constants.ENUM_PRACTICE_TYPE_CLINIC:
 		days := []string{"Monday", "Tuesday", "Wednesday"}
 		for _, day := range days {
 			schedules = append(schedules, entity.PracticeSchedule{
@@ -198,35 +935,76 @@ func (ps *PsychologService) CreatePractice(ctx context.Context, req dto.CreatePr
 		})
 	}
 
-	err = ps.psychologRepo.CreatePracticeSchedule(ctx, nil, schedules)
+	// jadwal tidak ditempel ke practice yang disimpan agar GORM tidak ikut membuatnya
+	scheduledPractice := practice
+	scheduledPractice.PracticeSchedules = schedules
+
+	// tanpa template dari request, pakai jam default 08:00-11:00 dan 13:00-15:00
+	reqTemplates := req.SlotTemplates
+	useDefault := len(reqTemplates) == 0
+	if useDefault {
+		reqTemplates = defaultSlotTemplates(schedules)
+	}
+
+	// semua template divalidasi sebelum ada yang disimpan, termasuk bentrok antar
+	// template di request yang sama
+	var templates []entity.SlotTemplate
+	for _, t := range reqTemplates {
+		template := entity.SlotTemplate{
+			ID:              uuid.New(),
+			Day:             t.Day,
+			Start:           t.Start,
+			End:             t.End,
+			SessionDuration: t.SessionDuration,
+			BufferDuration:  t.BufferDuration,
+			PracticeID:      &practice.ID,
+			PsychologID:     &psychologID,
+		}
+
+		err := ps.validateSlotTemplate(ctx, psyID, template, scheduledPractice)
+		if err == nil && isSlotTemplateOverlap(template, templates) {
+			err = dto.ErrSlotTemplateOverlap
+		}
+		if err != nil {
+			if useDefault && errors.Is(err, dto.ErrSlotTemplateOverlap) {
+				continue
+			}
+			return dto.PracticeResponse{}, err
+		}
+
+		templates = append(templates, template)
+	}
+
+	// practice, jadwal dan template disimpan bersama, gagal di tengah tidak meninggalkan practice setengah jadi
+	err = ps.psychologRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := ps.psychologRepo.CreatePractice(ctx, tx, practice); err != nil {
+			return dto.ErrCreatePractice
+		}
+
+		if err := ps.psychologRepo.CreatePracticeSchedule(ctx, tx, schedules); err != nil {
+			return dto.ErrCreatePracticeSchedule
+		}
+
+		for _, template := range templates {
+			if err := ps.psychologRepo.CreateSlotTemplate(ctx, tx, template); err != nil {
+				return dto.ErrCreateSlotTemplate
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return dto.PracticeResponse{}, dto.ErrCreatePracticeSchedule
+		return dto.PracticeResponse{}, err
 	}
 
-	var availableSlots []entity.AvailableSlot
-	timeSlots := []struct {
-		start string
-		end   string
-	}{
-		{"08:00", "09:00"},
-		{"09:00", "10:00"},
-		{"10:00", "11:00"},
-		{"13:00", "14:00"},
-		{"14:00", "15:00"},
-	}
-	for _, slot := range timeSlots {
-		availableSlots = append(availableSlots, entity.AvailableSlot{
-			ID:          uuid.New(),
-			Start:       slot.start,
-			End:         slot.end,
-			IsBooked:    false,
-			PsychologID: &psychologID,
-		})
+	var slotTemplates []dto.SlotTemplateResponse
+	for _, template := range templates {
+		slotTemplates = append(slotTemplates, toSlotTemplateResponse(template))
 	}
 
-	err = ps.psychologRepo.CreateAvailableSlots(ctx, nil, availableSlots)
+	_, err = ps.slotGenerator.GenerateForPsycholog(ctx, psyID)
 	if err != nil {
-		return dto.PracticeResponse{}, dto.ErrCreateAvailableSlots
+		return dto.PracticeResponse{}, dto.ErrGenerateAvailableSlots
 	}
 
 	return dto.PracticeResponse{
@@ -235,7 +1013,9 @@ func (ps *PsychologService) CreatePractice(ctx context.Context, req dto.CreatePr
 		Name:              practice.Name,
 		Address:           practice.Address,
 		PhoneNumber:       phoneNumberFormatted,
+		Fee:               practice.Fee,
 		PracticeSchedules: practiceSchedules,
+		SlotTemplates:     slotTemplates,
 	}, nil
 }
 func (ps *PsychologService) GetAllPractice(ctx context.Context) (dto.AllPracticeResponse, error) {
@@ -256,7 +1036,6 @@ func (ps *PsychologService) GetAllPractice(ctx context.Context) (dto.AllPractice
 		Name:        datas.Practices[0].Psycholog.Name,
 		STRNumber:   datas.Practices[0].Psycholog.STRNumber,
 		Email:       datas.Practices[0].Psycholog.Email,
-		Password:    datas.Practices[0].Psycholog.Password,
 		WorkYear:    datas.Practices[0].Psycholog.WorkYear,
 		Description: datas.Practices[0].Psycholog.Description,
 		PhoneNumber: datas.Practices[0].Psycholog.PhoneNumber,
@@ -284,6 +1063,7 @@ func (ps *PsychologService) GetAllPractice(ctx context.Context) (dto.AllPractice
 			Name:        practice.Name,
 			Address:     practice.Address,
 			PhoneNumber: practice.PhoneNumber,
+			Fee:         practice.Fee,
 		}
 
 		// PracticeSchedule
@@ -318,7 +1098,7 @@ func (ps *PsychologService) UpdatePractice(ctx context.Context, req dto.UpdatePr
 
 		var schedules []entity.PracticeSchedule
 		switch req.Type {
-		case "Konsultasi Online":
+		case constants.ENUM_PRACTICE_TYPE_ONLINE:
 			days := []string{"Thursday", "Friday", "Saturday"}
 			for _, day := range days {
 				schedules = append(schedules, entity.PracticeSchedule{
@@ -330,7 +1110,7 @@ func (ps *PsychologService) UpdatePractice(ctx context.Context, req dto.UpdatePr
 				})
 			}
 			prac.Type = req.Type
-		case "Praktek Klinik":
+		case constants.ENUM_PRACTICE_TYPE_CLINIC:
 			days := []string{"Monday", "Tuesday", "Wednesday"}
 			for _, day := range days {
 				schedules = append(schedules, entity.PracticeSchedule{
@@ -375,6 +1155,14 @@ func (ps *PsychologService) UpdatePractice(ctx context.Context, req dto.UpdatePr
 		prac.PhoneNumber = phoneNumberFormatted
 	}
 
+	if req.Fee != nil {
+		if *req.Fee < 0 {
+			return dto.PracticeResponse{}, dto.ErrInvalidPracticeFee
+		}
+
+		prac.Fee = *req.Fee
+	}
+
 	err = ps.psychologRepo.UpdatePractice(ctx, nil, prac)
 	if err != nil {
 		return dto.PracticeResponse{}, dto.ErrUpdatePractice
@@ -396,6 +1184,7 @@ func (ps *PsychologService) UpdatePractice(ctx context.Context, req dto.UpdatePr
 		Name:              prac.Name,
 		Address:           prac.Address,
 		PhoneNumber:       prac.PhoneNumber,
+		Fee:               prac.Fee,
 		PracticeSchedules: practiceSchedules,
 	}, nil
 }
@@ -405,6 +1194,16 @@ func (ps *PsychologService) DeletePractice(ctx context.Context, practiceID strin
 		return dto.PracticeResponse{}, dto.ErrPracticeNotFound
 	}
 
+	err = ps.psychologRepo.DeleteUnbookedSlotByPracticeID(ctx, nil, practiceID, todayDate())
+	if err != nil {
+		return dto.PracticeResponse{}, dto.ErrDeleteAvailableSlots
+	}
+
+	err = ps.psychologRepo.DeleteSlotTemplateByPracticeID(ctx, nil, practiceID)
+	if err != nil {
+		return dto.PracticeResponse{}, dto.ErrDeleteSlotTemplate
+	}
+
 	err = ps.psychologRepo.DeletePracticeSchedule(ctx, nil, practiceID)
 	if err != nil {
 		return dto.PracticeResponse{}, dto.ErrDeletePracticeSchedules
@@ -431,132 +1230,357 @@ func (ps *PsychologService) DeletePractice(ctx context.Context, practiceID strin
 		Name:              deletedPractice.Name,
 		Address:           deletedPractice.Address,
 		PhoneNumber:       deletedPractice.PhoneNumber,
+		Fee:               deletedPractice.Fee,
 		PracticeSchedules: practiceSchedules,
 	}
 
-	return res, nil
+	return res, nil
+}
+
+// Available Slot
+func (ps *PsychologService) GetAllAvailableSlot(ctx context.Context) (dto.AllAvailableSlotResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.AllAvailableSlotResponse{}, dto.ErrGetPsychologFromID
+	}
+
+	datas, err := ps.psychologRepo.GetAllAvailableSlot(ctx, nil, psyID)
+	if err != nil {
+		return dto.AllAvailableSlotResponse{}, dto.ErrGetAllAvailableSlot
+	}
+
+	if len(datas.AvailableSlots) == 0 {
+		psy, _, err := ps.psychologRepo.GetPsychologByID(ctx, nil, psyID)
+		if err != nil {
+			return dto.AllAvailableSlotResponse{}, dto.ErrPsychologNotFound
+		}
+
+		psycholog := dto.PsychologResponse{
+			ID:          psy.ID,
+			Name:        psy.Name,
+			STRNumber:   psy.STRNumber,
+			Email:       psy.Email,
+			WorkYear:    psy.WorkYear,
+			Gender:      psy.Gender,
+			Description: psy.Description,
+			PhoneNumber: psy.PhoneNumber,
+			Image:       psy.Image,
+			City: dto.CityResponse{
+				ID:   psy.CityID,
+				Name: psy.City.Name,
+				Type: psy.City.Type,
+				Province: dto.ProvinceResponse{
+					ID:   psy.City.ProvinceID,
+					Name: psy.City.Province.Name,
+				},
+			},
+			Role: dto.RoleResponse{
+				ID:   psy.RoleID,
+				Name: psy.Role.Name,
+			},
+		}
+
+		// LanguageMasters
+		for _, lang := range psy.PsychologLanguages {
+			psycholog.LanguageMasters = append(psycholog.LanguageMasters, dto.LanguageMasterResponse{
+				ID:   &lang.LanguageMaster.ID,
+				Name: lang.LanguageMaster.Name,
+			})
+		}
+
+		// Specializations
+		for _, spec := range psy.PsychologSpecializations {
+			psycholog.Specializations = append(psycholog.Specializations, dto.SpecializationResponse{
+				ID:          &spec.Specialization.ID,
+				Name:        spec.Specialization.Name,
+				Description: spec.Specialization.Description,
+			})
+		}
+
+		// Educations
+		for _, edu := range psy.Educations {
+			psycholog.Educations = append(psycholog.Educations, dto.EducationResponse{
+				ID:             &edu.ID,
+				Degree:         edu.Degree,
+				Major:          edu.Major,
+				Institution:    edu.Institution,
+				GraduationYear: edu.GraduationYear,
+			})
+		}
+
+		return dto.AllAvailableSlotResponse{
+			Psycholog:      psycholog,
+			AvailableSlots: []dto.AvailableSlotResponse{},
+		}, nil
+	}
+
+	psycholog := dto.PsychologResponse{
+		ID:          datas.AvailableSlots[0].Psycholog.ID,
+		Name:        datas.AvailableSlots[0].Psycholog.Name,
+		STRNumber:   datas.AvailableSlots[0].Psycholog.STRNumber,
+		Email:       datas.AvailableSlots[0].Psycholog.Email,
+		WorkYear:    datas.AvailableSlots[0].Psycholog.WorkYear,
+		Description: datas.AvailableSlots[0].Psycholog.Description,
+		PhoneNumber: datas.AvailableSlots[0].Psycholog.PhoneNumber,
+		Image:       datas.AvailableSlots[0].Psycholog.Image,
+		City: dto.CityResponse{
+			ID:   datas.AvailableSlots[0].Psycholog.CityID,
+			Name: datas.AvailableSlots[0].Psycholog.City.Name,
+			Type: datas.AvailableSlots[0].Psycholog.City.Type,
+			Province: dto.ProvinceResponse{
+				ID:   datas.AvailableSlots[0].Psycholog.City.ProvinceID,
+				Name: datas.AvailableSlots[0].Psycholog.City.Province.Name,
+			},
+		},
+		Role: dto.RoleResponse{
+			ID:   datas.AvailableSlots[0].Psycholog.RoleID,
+			Name: datas.AvailableSlots[0].Psycholog.Role.Name,
+		},
+	}
+
+	var availableSlots []dto.AvailableSlotResponse
+	for _, availableSlot := range datas.AvailableSlots {
+		data := dto.AvailableSlotResponse{
+			ID:       availableSlot.ID,
+			Date:     availableSlot.Date,
+			Start:    availableSlot.Start,
+			End:      availableSlot.End,
+			IsBooked: availableSlot.IsBooked,
+		}
+
+		availableSlots = append(availableSlots, data)
+	}
+
+	return dto.AllAvailableSlotResponse{
+		Psycholog:      psycholog,
+		AvailableSlots: availableSlots,
+	}, nil
+}
+func (ps *PsychologService) GenerateAvailableSlot(ctx context.Context) (dto.GenerateAvailableSlotResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.GenerateAvailableSlotResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	return ps.slotGenerator.GenerateForPsycholog(ctx, psyID)
+}
+
+// Slot Template
+func (ps *PsychologService) CreateSlotTemplate(ctx context.Context, req dto.CreateSlotTemplateRequest) (dto.SlotTemplateResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	psychologID, err := uuid.Parse(psyID)
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrParseUUID
+	}
+
+	practice, flag, err := ps.psychologRepo.GetPracticeByID(ctx, nil, req.PracticeID)
+	if err != nil || !flag {
+		return dto.SlotTemplateResponse{}, dto.ErrPracticeNotFound
+	}
+
+	if practice.PsychologID == nil || *practice.PsychologID != psychologID {
+		return dto.SlotTemplateResponse{}, dto.ErrDeniedAccess
+	}
+
+	template := entity.SlotTemplate{
+		ID:              uuid.New(),
+		Day:             req.Day,
+		Start:           req.Start,
+		End:             req.End,
+		SessionDuration: req.SessionDuration,
+		BufferDuration:  req.BufferDuration,
+		PracticeID:      &practice.ID,
+		PsychologID:     &psychologID,
+	}
+
+	if err := ps.validateSlotTemplate(ctx, psyID, template, practice); err != nil {
+		return dto.SlotTemplateResponse{}, err
+	}
+
+	err = ps.psychologRepo.CreateSlotTemplate(ctx, nil, template)
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrCreateSlotTemplate
+	}
+
+	_, err = ps.slotGenerator.GenerateForPsycholog(ctx, psyID)
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrGenerateAvailableSlots
+	}
+
+	return toSlotTemplateResponse(template), nil
+}
+func (ps *PsychologService) GetAllSlotTemplate(ctx context.Context) ([]dto.SlotTemplateResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return nil, dto.ErrGetPsychologIDFromToken
+	}
+
+	templates, err := ps.psychologRepo.GetAllSlotTemplate(ctx, nil, psyID)
+	if err != nil {
+		return nil, dto.ErrGetAllSlotTemplate
+	}
+
+	var datas []dto.SlotTemplateResponse
+	for _, template := range templates {
+		datas = append(datas, toSlotTemplateResponse(template))
+	}
+
+	return datas, nil
+}
+func (ps *PsychologService) UpdateSlotTemplate(ctx context.Context, req dto.UpdateSlotTemplateRequest) (dto.SlotTemplateResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	template, flag, err := ps.psychologRepo.GetSlotTemplateByID(ctx, nil, req.ID)
+	if err != nil || !flag {
+		return dto.SlotTemplateResponse{}, dto.ErrSlotTemplateNotFound
+	}
+
+	if template.PsychologID == nil || template.PsychologID.String() != psyID {
+		return dto.SlotTemplateResponse{}, dto.ErrDeniedAccess
+	}
+
+	if req.Day != "" {
+		template.Day = req.Day
+	}
+
+	if req.Start != "" {
+		template.Start = req.Start
+	}
+
+	if req.End != "" {
+		template.End = req.End
+	}
+
+	if req.SessionDuration != nil {
+		template.SessionDuration = *req.SessionDuration
+	}
+
+	if req.BufferDuration != nil {
+		template.BufferDuration = *req.BufferDuration
+	}
+
+	if err := ps.validateSlotTemplate(ctx, psyID, template, template.Practice); err != nil {
+		return dto.SlotTemplateResponse{}, err
+	}
+
+	// slot lama yang belum dibooking dibuang lalu dibuat ulang dari template baru
+	err = ps.psychologRepo.DeleteUnbookedSlotByTemplateID(ctx, nil, req.ID, todayDate())
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrDeleteAvailableSlots
+	}
+
+	err = ps.psychologRepo.UpdateSlotTemplate(ctx, nil, template)
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrUpdateSlotTemplate
+	}
+
+	_, err = ps.slotGenerator.GenerateForPsycholog(ctx, psyID)
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrGenerateAvailableSlots
+	}
+
+	return toSlotTemplateResponse(template), nil
 }
-
-// Available Slot
-func (ps *PsychologService) GetAllAvailableSlot(ctx context.Context) (dto.AllAvailableSlotResponse, error) {
+func (ps *PsychologService) DeleteSlotTemplate(ctx context.Context, templateID string) (dto.SlotTemplateResponse, error) {
 	token := ctx.Value("Authorization").(string)
 
 	psyID, err := ps.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.AllAvailableSlotResponse{}, dto.ErrGetPsychologFromID
+		return dto.SlotTemplateResponse{}, dto.ErrGetPsychologIDFromToken
 	}
 
-	datas, err := ps.psychologRepo.GetAllAvailableSlot(ctx, nil, psyID)
+	template, flag, err := ps.psychologRepo.GetSlotTemplateByID(ctx, nil, templateID)
+	if err != nil || !flag {
+		return dto.SlotTemplateResponse{}, dto.ErrSlotTemplateNotFound
+	}
+
+	if template.PsychologID == nil || template.PsychologID.String() != psyID {
+		return dto.SlotTemplateResponse{}, dto.ErrDeniedAccess
+	}
+
+	err = ps.psychologRepo.DeleteUnbookedSlotByTemplateID(ctx, nil, templateID, todayDate())
 	if err != nil {
-		return dto.AllAvailableSlotResponse{}, dto.ErrGetAllAvailableSlot
+		return dto.SlotTemplateResponse{}, dto.ErrDeleteAvailableSlots
 	}
 
-	if len(datas.AvailableSlots) == 0 {
-		psy, _, err := ps.psychologRepo.GetPsychologByID(ctx, nil, psyID)
-		if err != nil {
-			return dto.AllAvailableSlotResponse{}, dto.ErrPsychologNotFound
-		}
+	err = ps.psychologRepo.DeleteSlotTemplateByID(ctx, nil, templateID)
+	if err != nil {
+		return dto.SlotTemplateResponse{}, dto.ErrDeleteSlotTemplate
+	}
 
-		psycholog := dto.PsychologResponse{
-			ID:          psy.ID,
-			Name:        psy.Name,
-			STRNumber:   psy.STRNumber,
-			Email:       psy.Email,
-			Password:    psy.Password,
-			WorkYear:    psy.WorkYear,
-			Description: psy.Description,
-			PhoneNumber: psy.PhoneNumber,
-			Image:       psy.Image,
-			City: dto.CityResponse{
-				ID:   psy.CityID,
-				Name: psy.City.Name,
-				Type: psy.City.Type,
-				Province: dto.ProvinceResponse{
-					ID:   psy.City.ProvinceID,
-					Name: psy.City.Province.Name,
-				},
-			},
-			Role: dto.RoleResponse{
-				ID:   psy.RoleID,
-				Name: psy.Role.Name,
-			},
-		}
+	return toSlotTemplateResponse(template), nil
+}
+func (ps *PsychologService) validateSlotTemplate(ctx context.Context, psyID string, template entity.SlotTemplate, practice entity.Practice) error {
+	if !helpers.IsValidDayName(template.Day) {
+		return dto.ErrInvalidSlotTemplateDay
+	}
 
-		// LanguageMasters
-		for _, lang := range psy.PsychologLanguages {
-			psycholog.LanguageMasters = append(psycholog.LanguageMasters, dto.LanguageMasterResponse{
-				ID:   &lang.LanguageMaster.ID,
-				Name: lang.LanguageMaster.Name,
-			})
-		}
+	if template.SessionDuration <= 0 || template.BufferDuration < 0 {
+		return dto.ErrInvalidSlotTemplateDuration
+	}
 
-		// Specializations
-		for _, spec := range psy.PsychologSpecializations {
-			psycholog.Specializations = append(psycholog.Specializations, dto.SpecializationResponse{
-				ID:          &spec.Specialization.ID,
-				Name:        spec.Specialization.Name,
-				Description: spec.Specialization.Description,
-			})
-		}
+	sessions, err := helpers.SplitSessions(template.Start, template.End, template.SessionDuration, template.BufferDuration)
+	if err != nil || len(sessions) == 0 {
+		return dto.ErrInvalidSlotTemplateTime
+	}
 
-		// Educations
-		for _, edu := range psy.Educations {
-			psycholog.Educations = append(psycholog.Educations, dto.EducationResponse{
-				ID:             &edu.ID,
-				Degree:         edu.Degree,
-				Major:          edu.Major,
-				Institution:    edu.Institution,
-				GraduationYear: edu.GraduationYear,
-			})
-		}
+	window := helpers.TimeRange{Start: template.Start, End: template.End}
 
-		return dto.AllAvailableSlotResponse{
-			Psycholog:      psycholog,
-			AvailableSlots: []dto.AvailableSlotResponse{},
-		}, nil
+	// template harus berada di dalam jam buka practice pada hari yang sama
+	inSchedule := false
+	for _, sch := range practice.PracticeSchedules {
+		if sch.Day == template.Day && helpers.IsTimeRangeWithin(window, helpers.TimeRange{Start: sch.Open, End: sch.Close}) {
+			inSchedule = true
+			break
+		}
+	}
+	if !inSchedule {
+		return dto.ErrSlotTemplateOutsideSchedule
 	}
 
-	psycholog := dto.PsychologResponse{
-		ID:          datas.AvailableSlots[0].Psycholog.ID,
-		Name:        datas.AvailableSlots[0].Psycholog.Name,
-		STRNumber:   datas.AvailableSlots[0].Psycholog.STRNumber,
-		Email:       datas.AvailableSlots[0].Psycholog.Email,
-		Password:    datas.AvailableSlots[0].Psycholog.Password,
-		WorkYear:    datas.AvailableSlots[0].Psycholog.WorkYear,
-		Description: datas.AvailableSlots[0].Psycholog.Description,
-		PhoneNumber: datas.AvailableSlots[0].Psycholog.PhoneNumber,
-		Image:       datas.AvailableSlots[0].Psycholog.Image,
-		City: dto.CityResponse{
-			ID:   datas.AvailableSlots[0].Psycholog.CityID,
-			Name: datas.AvailableSlots[0].Psycholog.City.Name,
-			Type: datas.AvailableSlots[0].Psycholog.City.Type,
-			Province: dto.ProvinceResponse{
-				ID:   datas.AvailableSlots[0].Psycholog.City.ProvinceID,
-				Name: datas.AvailableSlots[0].Psycholog.City.Province.Name,
-			},
-		},
-		Role: dto.RoleResponse{
-			ID:   datas.AvailableSlots[0].Psycholog.RoleID,
-			Name: datas.AvailableSlots[0].Psycholog.Role.Name,
-		},
+	// template tidak boleh bertabrakan dengan template lain milik psikolog yang sama
+	templates, err := ps.psychologRepo.GetAllSlotTemplate(ctx, nil, psyID)
+	if err != nil {
+		return dto.ErrGetAllSlotTemplate
 	}
 
-	var availableSlots []dto.AvailableSlotResponse
-	for _, availableSlot := range datas.AvailableSlots {
-		data := dto.AvailableSlotResponse{
-			ID:       availableSlot.ID,
-			Start:    availableSlot.Start,
-			End:      availableSlot.End,
-			IsBooked: availableSlot.IsBooked,
+	for _, other := range templates {
+		if other.ID == template.ID || other.Day != template.Day {
+			continue
 		}
 
-		availableSlots = append(availableSlots, data)
+		if helpers.IsTimeRangeOverlap(window, helpers.TimeRange{Start: other.Start, End: other.End}) {
+			return dto.ErrSlotTemplateOverlap
+		}
 	}
 
-	return dto.AllAvailableSlotResponse{
-		Psycholog:      psycholog,
-		AvailableSlots: availableSlots,
-	}, nil
+	return nil
+}
+func isSlotTemplateOverlap(template entity.SlotTemplate, others []entity.SlotTemplate) bool {
+	for _, other := range others {
+		if other.Day == template.Day && helpers.IsTimeRangeOverlap(helpers.TimeRange{Start: template.Start, End: template.End}, helpers.TimeRange{Start: other.Start, End: other.End}) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Consultation
@@ -589,8 +1613,8 @@ func (ps *PsychologService) GetAllConsultationWithPagination(ctx context.Context
 			Name:        psy.Name,
 			STRNumber:   psy.STRNumber,
 			Email:       psy.Email,
-			Password:    psy.Password,
 			WorkYear:    psy.WorkYear,
+			Gender:      psy.Gender,
 			Description: psy.Description,
 			PhoneNumber: psy.PhoneNumber,
 			Image:       psy.Image,
@@ -656,7 +1680,6 @@ func (ps *PsychologService) GetAllConsultationWithPagination(ctx context.Context
 		Name:        dataWithPaginate.Consultations[0].AvailableSlot.Psycholog.Name,
 		STRNumber:   dataWithPaginate.Consultations[0].AvailableSlot.Psycholog.STRNumber,
 		Email:       dataWithPaginate.Consultations[0].AvailableSlot.Psycholog.Email,
-		Password:    dataWithPaginate.Consultations[0].AvailableSlot.Psycholog.Password,
 		WorkYear:    dataWithPaginate.Consultations[0].AvailableSlot.Psycholog.WorkYear,
 		Description: dataWithPaginate.Consultations[0].AvailableSlot.Psycholog.Description,
 		PhoneNumber: dataWithPaginate.Consultations[0].AvailableSlot.Psycholog.PhoneNumber,
@@ -728,11 +1751,11 @@ func (ps *PsychologService) GetAllConsultationWithPagination(ctx context.Context
 			Rate:    consultation.Rate,
 			Comment: consultation.Comment,
 			Status:  consultation.Status,
+			Fee:     consultation.Fee,
 			User: dto.AllUserResponse{
 				ID:          consultation.User.ID,
 				Name:        consultation.User.Name,
 				Email:       consultation.User.Email,
-				Password:    consultation.User.Password,
 				Birthdate:   consultation.User.Birthdate,
 				PhoneNumber: consultation.User.PhoneNumber,
 				Data01:      consultation.User.Data01,
@@ -755,6 +1778,7 @@ func (ps *PsychologService) GetAllConsultationWithPagination(ctx context.Context
 			},
 			AvailableSlot: dto.AvailableSlotResponse{
 				ID:       consultation.AvailableSlot.ID,
+				Date:     consultation.AvailableSlot.Date,
 				Start:    consultation.AvailableSlot.Start,
 				End:      consultation.AvailableSlot.End,
 				IsBooked: consultation.AvailableSlot.IsBooked,
@@ -765,6 +1789,7 @@ func (ps *PsychologService) GetAllConsultationWithPagination(ctx context.Context
 				Name:              consultation.Practice.Name,
 				Address:           consultation.Practice.Address,
 				PhoneNumber:       consultation.Practice.PhoneNumber,
+				Fee:               consultation.Practice.Fee,
 				PracticeSchedules: practiceSchedules,
 			},
 		}
@@ -788,23 +1813,92 @@ func (ps *PsychologService) GetAllConsultationWithPagination(ctx context.Context
 	}, nil
 }
 func (ps *PsychologService) UpdateConsultation(ctx context.Context, req dto.UpdateConsultationRequest, consulID string) (dto.ConsultationResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.ConsultationResponse{}, dto.ErrGetPsychologIDFromToken
+	}
+
+	psychologID, err := uuid.Parse(psyID)
+	if err != nil {
+		return dto.ConsultationResponse{}, dto.ErrParseUUID
+	}
+
 	consul, flag, err := ps.psychologRepo.GetConsultationByID(ctx, nil, consulID)
 	if err != nil || !flag {
 		return dto.ConsultationResponse{}, dto.ErrConsultationNotFound
 	}
 
+	if consul.AvailableSlot.PsychologID == nil || *consul.AvailableSlot.PsychologID != psychologID {
+		return dto.ConsultationResponse{}, dto.ErrDeniedAccess
+	}
+
+	var cancellation *dto.CancellationResponse
 	if req.Status != nil {
-		valid := false
-		switch *req.Status {
-		case 1, 2:
-			valid = true
+		if !helpers.IsValidConsultationStatus(*req.Status) {
+			return dto.ConsultationResponse{}, dto.ErrInvalidStatusInput
+		}
+
+		if !helpers.CanTransitionConsultationStatus(constants.ENUM_ROLE_PSYCHOLOG, consul.Status, *req.Status) {
+			return dto.ConsultationResponse{}, dto.ErrInvalidConsultationTransition
+		}
+
+		fromStatus := consul.Status
+		var outcome *CancellationOutcome
+		err = ps.psychologRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+			changed, err := ps.consultationRepo.UpdateConsultationStatus(ctx, tx, consul.ID, fromStatus, *req.Status)
+			if err != nil {
+				return dto.ErrUpdateConsultation
+			}
+
+			if !changed {
+				return dto.ErrConsultationStatusChanged
+			}
+
+			if helpers.IsConsultationStatusReleasingSlot(*req.Status) && consul.AvailableSlotID != nil {
+				if err := ps.psychologRepo.UpdateStatusBookSlot(ctx, tx, *consul.AvailableSlotID, false); err != nil {
+					return dto.ErrUpdateStatusBookSlot
+				}
+			}
+
+			history := entity.ConsultationStatusHistory{
+				ID:             uuid.New(),
+				FromStatus:     &fromStatus,
+				ToStatus:       *req.Status,
+				ChangedByRole:  constants.ENUM_ROLE_PSYCHOLOG,
+				ChangedByID:    &psychologID,
+				Note:           req.Note,
+				ConsultationID: &consul.ID,
+			}
+			if err := ps.consultationRepo.CreateConsultationStatusHistory(ctx, tx, history); err != nil {
+				return dto.ErrCreateConsultationStatusHistory
+			}
 
-			if !valid {
-				return dto.ConsultationResponse{}, dto.ErrInvalidStatusInput
+			if helpers.IsConsultationStatusCancellation(*req.Status) {
+				result, err := ps.cancellationService.Apply(ctx, tx, consul, *req.Status)
+				if err != nil {
+					return err
+				}
+
+				outcome = &result
 			}
+
+			return nil
+		})
+		if err != nil {
+			return dto.ConsultationResponse{}, err
 		}
 
 		consul.Status = *req.Status
+		if helpers.IsConsultationStatusReleasingSlot(consul.Status) {
+			consul.AvailableSlot.IsBooked = false
+		}
+
+		if outcome != nil {
+			res := ps.cancellationService.Settle(ctx, consul, *outcome)
+			cancellation = &res
+		}
 	}
 
 	dayName, err := helpers.GetDayName(consul.Date)
@@ -830,11 +1924,11 @@ func (ps *PsychologService) UpdateConsultation(ctx context.Context, req dto.Upda
 		Rate:    consul.Rate,
 		Comment: consul.Comment,
 		Status:  consul.Status,
+		Fee:     consul.Fee,
 		User: dto.AllUserResponse{
 			ID:          consul.User.ID,
 			Name:        consul.User.Name,
 			Email:       consul.User.Email,
-			Password:    consul.User.Password,
 			Birthdate:   consul.User.Birthdate,
 			PhoneNumber: consul.User.PhoneNumber,
 			Data01:      consul.User.Data01,
@@ -857,6 +1951,7 @@ func (ps *PsychologService) UpdateConsultation(ctx context.Context, req dto.Upda
 		},
 		AvailableSlot: dto.AvailableSlotResponse{
 			ID:       consul.AvailableSlot.ID,
+			Date:     consul.AvailableSlot.Date,
 			Start:    consul.AvailableSlot.Start,
 			End:      consul.AvailableSlot.End,
 			IsBooked: consul.AvailableSlot.IsBooked,
@@ -867,14 +1962,288 @@ func (ps *PsychologService) UpdateConsultation(ctx context.Context, req dto.Upda
 			Name:              consul.Practice.Name,
 			Address:           consul.Practice.Address,
 			PhoneNumber:       consul.Practice.PhoneNumber,
+			Fee:               consul.Practice.Fee,
 			PracticeSchedules: practiceSchedules,
 		},
+		Cancellation: cancellation,
 	}
 
-	err = ps.psychologRepo.UpdateConsultation(ctx, nil, consul)
+	return data, nil
+}
+func (ps *PsychologService) GetConsultationStatusHistory(ctx context.Context, consulID string) ([]dto.ConsultationStatusHistoryResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	psyID, err := ps.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.ConsultationResponse{}, dto.ErrUpdateConsultation
+		return nil, dto.ErrGetPsychologIDFromToken
 	}
 
-	return data, nil
+	consul, flag, err := ps.psychologRepo.GetConsultationByID(ctx, nil, consulID)
+	if err != nil || !flag {
+		return nil, dto.ErrConsultationNotFound
+	}
+
+	if consul.AvailableSlot.PsychologID == nil || consul.AvailableSlot.PsychologID.String() != psyID {
+		return nil, dto.ErrDeniedAccess
+	}
+
+	histories, err := ps.consultationRepo.GetConsultationStatusHistory(ctx, nil, consulID)
+	if err != nil {
+		return nil, dto.ErrGetConsultationStatusHistory
+	}
+
+	return toConsultationStatusHistoryResponses(histories), nil
+}
+
+func defaultSlotTemplates(schedules []entity.PracticeSchedule) []dto.SlotTemplateRequest {
+	windows := []helpers.TimeRange{
+		{Start: "08:00", End: "11:00"},
+		{Start: "13:00", End: "15:00"},
+	}
+
+	var templates []dto.SlotTemplateRequest
+	for _, sch := range schedules {
+		for _, w := range windows {
+			templates = append(templates, dto.SlotTemplateRequest{
+				Day:             sch.Day,
+				Start:           w.Start,
+				End:             w.End,
+				SessionDuration: 60,
+				BufferDuration:  0,
+			})
+		}
+	}
+
+	return templates
+}
+
+func toSlotTemplateResponse(template entity.SlotTemplate) dto.SlotTemplateResponse {
+	return dto.SlotTemplateResponse{
+		ID:              template.ID,
+		Day:             template.Day,
+		Start:           template.Start,
+		End:             template.End,
+		SessionDuration: template.SessionDuration,
+		BufferDuration:  template.BufferDuration,
+		PracticeID:      template.PracticeID,
+	}
+}
+
+func toConsultationStatusHistoryResponses(histories []entity.ConsultationStatusHistory) []dto.ConsultationStatusHistoryResponse {
+	var datas []dto.ConsultationStatusHistoryResponse
+	for _, history := range histories {
+		data := dto.ConsultationStatusHistoryResponse{
+			ID:            history.ID,
+			FromStatus:    history.FromStatus,
+			ToStatus:      history.ToStatus,
+			ToStatusName:  helpers.ConsultationStatusName(history.ToStatus),
+			ChangedByRole: history.ChangedByRole,
+			ChangedByID:   history.ChangedByID,
+			Note:          history.Note,
+			CreatedAt:     history.CreatedAt,
+		}
+
+		if history.FromStatus != nil {
+			data.FromStatusName = helpers.ConsultationStatusName(*history.FromStatus)
+		}
+
+		datas = append(datas, data)
+	}
+
+	return datas
+}
+
+// dokumen pendaftaran bersifat privat, jadi tidak disimpan di assets yang disajikan secara publik
+const psychologDocumentDir = "storage/psycholog_document"
+
+func saveUploadedFile(savePath string, reader io.Reader) error {
+	out, err := os.Create(savePath)
+	if err != nil {
+		return dto.ErrCreateFile
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, reader); err != nil {
+		return dto.ErrSaveFile
+	}
+
+	return nil
+}
+func removeFiles(paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("failed to remove file %s: %v", path, err)
+		}
+	}
+}
+func toPsychologApplicationResponse(psycholog entity.Psycholog) dto.PsychologApplicationResponse {
+	data := dto.PsychologApplicationResponse{
+		PsychologResponse: dto.PsychologResponse{
+			ID:          psycholog.ID,
+			Name:        psycholog.Name,
+			STRNumber:   psycholog.STRNumber,
+			Email:       psycholog.Email,
+			WorkYear:    psycholog.WorkYear,
+			Gender:      psycholog.Gender,
+			Description: psycholog.Description,
+			PhoneNumber: psycholog.PhoneNumber,
+			Image:       psycholog.Image,
+			City: dto.CityResponse{
+				ID:   psycholog.CityID,
+				Name: psycholog.City.Name,
+				Type: psycholog.City.Type,
+				Province: dto.ProvinceResponse{
+					ID:   psycholog.City.ProvinceID,
+					Name: psycholog.City.Province.Name,
+				},
+			},
+			Role: dto.RoleResponse{
+				ID:   psycholog.RoleID,
+				Name: psycholog.Role.Name,
+			},
+		},
+		Status:     psycholog.Status,
+		ReviewNote: psycholog.ReviewNote,
+		ReviewedAt: psycholog.ReviewedAt,
+		AppliedAt:  psycholog.CreatedAt,
+		Documents:  []dto.PsychologDocumentResponse{},
+	}
+
+	for _, lang := range psycholog.PsychologLanguages {
+		data.LanguageMasters = append(data.LanguageMasters, dto.LanguageMasterResponse{
+			ID:   &lang.LanguageMaster.ID,
+			Name: lang.LanguageMaster.Name,
+		})
+	}
+
+	for _, spec := range psycholog.PsychologSpecializations {
+		data.Specializations = append(data.Specializations, dto.SpecializationResponse{
+			ID:          &spec.Specialization.ID,
+			Name:        spec.Specialization.Name,
+			Description: spec.Specialization.Description,
+		})
+	}
+
+	for _, edu := range psycholog.Educations {
+		data.Educations = append(data.Educations, dto.EducationResponse{
+			ID:             &edu.ID,
+			Degree:         edu.Degree,
+			Major:          edu.Major,
+			Institution:    edu.Institution,
+			GraduationYear: edu.GraduationYear,
+		})
+	}
+
+	for _, document := range psycholog.Documents {
+		data.Documents = append(data.Documents, dto.PsychologDocumentResponse{
+			ID:           document.ID,
+			Type:         document.Type,
+			OriginalName: document.OriginalName,
+			ContentType:  document.ContentType,
+			Size:         document.Size,
+			CreatedAt:    document.CreatedAt,
+		})
+	}
+
+	return data
+}
+
+// sendPsychologApplicationEmail mengirim email sesuai status aplikasi psycholog.
+// Kegagalan kirim email hanya dicatat agar tidak membatalkan proses pendaftaran atau review.
+func sendPsychologApplicationEmail(psycholog entity.Psycholog) {
+	var title, message string
+	switch psycholog.Status {
+	case constants.ENUM_PSYCHOLOG_STATUS_PENDING:
+		title = "Application Received"
+		message = "Thank you for applying as a psychologist on Warasin APP. We have received your profile, STR number and documents. Our team will verify your application and notify you by email once the review is complete."
+	case constants.ENUM_PSYCHOLOG_STATUS_APPROVED:
+		title = "Application Approved"
+		message = "Congratulations, your STR and documents have been verified and your psychologist application on Warasin APP has been approved. You can now sign in with the email and password you registered."
+	case constants.ENUM_PSYCHOLOG_STATUS_REJECTED:
+		title = "Application Rejected"
+		message = "Unfortunately, your psychologist application on Warasin APP could not be approved after reviewing your STR and documents."
+	default:
+		return
+	}
+
+	draftEmail, err := makePsychologApplicationEmail(psycholog.Name, title, message, psycholog.ReviewNote)
+	if err != nil {
+		log.Printf("failed to make psycholog application email: %v", err)
+		return
+	}
+
+	if err := utils.SendEmail(psycholog.Email, draftEmail["subject"], draftEmail["body"]); err != nil {
+		log.Printf("failed to send psycholog application email: %v", err)
+	}
+}
+func makePsychologApplicationEmail(name string, title string, message string, reason string) (map[string]string, error) {
+	readHTML, err := os.ReadFile("utils/email_template/psycholog_application_mail.html")
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		Name    string
+		Title   string
+		Message string
+		Reason  string
+	}{
+		Name:    name,
+		Title:   title,
+		Message: message,
+		Reason:  reason,
+	}
+
+	tmpl, err := htmltemplate.New("custom").Parse(string(readHTML))
+	if err != nil {
+		return nil, err
+	}
+
+	var strMail bytes.Buffer
+	if err := tmpl.Execute(&strMail, data); err != nil {
+		return nil, err
+	}
+
+	draftEmail := map[string]string{
+		"subject": "warasin",
+		"body":    strMail.String(),
+	}
+
+	return draftEmail, nil
+}
+func isValidGraduationYear(year string) bool {
+	if len(year) != 4 {
+		return false
+	}
+
+	_, err := strconv.Atoi(year)
+	return err == nil
+}
+func toEducationResponse(education entity.Education) dto.EducationResponse {
+	return dto.EducationResponse{
+		ID:             &education.ID,
+		Degree:         education.Degree,
+		Major:          education.Major,
+		Institution:    education.Institution,
+		GraduationYear: education.GraduationYear,
+	}
+}
+func toPsychologChangeRequestResponses(changeRequests []entity.PsychologChangeRequest) []dto.PsychologChangeRequestResponse {
+	datas := []dto.PsychologChangeRequestResponse{}
+	for _, changeRequest := range changeRequests {
+		datas = append(datas, dto.PsychologChangeRequestResponse{
+			ID:          changeRequest.ID,
+			PsychologID: changeRequest.PsychologID,
+			Name:        changeRequest.Psycholog.Name,
+			Field:       changeRequest.Field,
+			OldValue:    changeRequest.OldValue,
+			NewValue:    changeRequest.NewValue,
+			Status:      changeRequest.Status,
+			ReviewNote:  changeRequest.ReviewNote,
+			ReviewedAt:  changeRequest.ReviewedAt,
+			CreatedAt:   changeRequest.CreatedAt,
+		})
+	}
+
+	return datas
 }
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+)
+
+type (
+	IRBACService interface {
+		HasAccess(ctx context.Context, roleID string, method string, path string) (bool, error)
+		InvalidateRole(roleID string)
+		InvalidateAll()
+	}
+
+	rbacCacheEntry struct {
+		permissions []entity.Permission
+		expiresAt   time.Time
+	}
+
+	// RBACService me-resolve permission role saat request, dengan cache per role.
+	// Cache dibuang saat admin mengubah role/permission; TTL membatasi data basi
+	// jika ada beberapa instance server.
+	RBACService struct {
+		masterRepo repository.IMasterRepository
+		ttl        time.Duration
+
+		mu    sync.RWMutex
+		cache map[string]rbacCacheEntry
+	}
+)
+
+func NewRBACService(masterRepo repository.IMasterRepository) *RBACService {
+	return &RBACService{
+		masterRepo: masterRepo,
+		ttl:        time.Duration(getRBACCacheTTLSeconds()) * time.Second,
+		cache:      map[string]rbacCacheEntry{},
+	}
+}
+
+func getRBACCacheTTLSeconds() int {
+	seconds, err := strconv.Atoi(os.Getenv("RBAC_CACHE_TTL_SECONDS"))
+	if err != nil || seconds < 0 {
+		seconds = constants.ENUM_RBAC_CACHE_TTL_SECONDS
+	}
+
+	return seconds
+}
+
+func (rs *RBACService) HasAccess(ctx context.Context, roleID string, method string, path string) (bool, error) {
+	permissions, err := rs.getPermissions(ctx, roleID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		if helpers.MatchPermission(permission.Method, permission.Endpoint, method, path) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+func (rs *RBACService) InvalidateRole(roleID string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	delete(rs.cache, roleID)
+}
+func (rs *RBACService) InvalidateAll() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.cache = map[string]rbacCacheEntry{}
+}
+
+func (rs *RBACService) getPermissions(ctx context.Context, roleID string) ([]entity.Permission, error) {
+	rs.mu.RLock()
+	entry, ok := rs.cache[roleID]
+	rs.mu.RUnlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.permissions, nil
+	}
+
+	permissions, err := rs.masterRepo.GetPermissionsByRoleID(ctx, nil, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.mu.Lock()
+	rs.cache[roleID] = rbacCacheEntry{
+		permissions: permissions,
+		expiresAt:   time.Now().Add(rs.ttl),
+	}
+	rs.mu.Unlock()
+
+	return permissions, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	IRefreshTokenService interface {
+		Issue(ctx context.Context, subjectID string, roleID string, roleName string) (string, error)
+		Rotate(ctx context.Context, rawToken string, roleName string) (entity.RefreshToken, string, error)
+		Revoke(ctx context.Context, rawToken string, roleName string) error
+		RevokeAll(ctx context.Context, subjectID string, roleName string) error
+	}
+
+	RefreshTokenService struct {
+		refreshTokenRepo repository.IRefreshTokenRepository
+		ttl              time.Duration
+	}
+)
+
+func NewRefreshTokenService(refreshTokenRepo repository.IRefreshTokenRepository) *RefreshTokenService {
+	return &RefreshTokenService{
+		refreshTokenRepo: refreshTokenRepo,
+		ttl:              time.Duration(getRefreshTokenTTLHours()) * time.Hour,
+	}
+}
+
+func getRefreshTokenTTLHours() int {
+	hours, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = constants.ENUM_REFRESH_TOKEN_TTL_HOURS
+	}
+
+	return hours
+}
+
+// Issue membuat refresh token pertama dari family baru, dipanggil saat login
+func (rs *RefreshTokenService) Issue(ctx context.Context, subjectID string, roleID string, roleName string) (string, error) {
+	sID, err := uuid.Parse(subjectID)
+	if err != nil {
+		return "", dto.ErrParseUUID
+	}
+
+	rID, err := uuid.Parse(roleID)
+	if err != nil {
+		return "", dto.ErrParseUUID
+	}
+
+	rawToken, token, err := rs.newRefreshToken(uuid.New(), sID, rID, roleName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := rs.refreshTokenRepo.CreateRefreshToken(ctx, nil, token); err != nil {
+		return "", dto.ErrGenerateRefreshToken
+	}
+
+	return rawToken, nil
+}
+
+// Rotate menukar refresh token dengan token baru di family yang sama. Token yang sudah
+// pernah dirotasi lalu dipakai lagi dianggap bocor, seluruh family-nya dicabut.
+func (rs *RefreshTokenService) Rotate(ctx context.Context, rawToken string, roleName string) (entity.RefreshToken, string, error) {
+	current, flag, err := rs.refreshTokenRepo.GetRefreshTokenByHash(ctx, nil, helpers.HashToken(rawToken))
+	if err != nil || !flag {
+		return entity.RefreshToken{}, "", dto.ErrRefreshTokenInvalid
+	}
+
+	if current.RoleName != roleName {
+		return entity.RefreshToken{}, "", dto.ErrDeniedAccess
+	}
+
+	if current.RevokedAt != nil {
+		if current.ReplacedByID == nil {
+			return entity.RefreshToken{}, "", dto.ErrRefreshTokenRevoked
+		}
+
+		if err := rs.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, nil, current.FamilyID); err != nil {
+			return entity.RefreshToken{}, "", dto.ErrRevokeRefreshToken
+		}
+		return entity.RefreshToken{}, "", dto.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return entity.RefreshToken{}, "", dto.ErrRefreshTokenExpired
+	}
+
+	rawNext, next, err := rs.newRefreshToken(current.FamilyID, current.SubjectID, current.RoleID, current.RoleName)
+	if err != nil {
+		return entity.RefreshToken{}, "", err
+	}
+
+	reused := false
+	err = rs.refreshTokenRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := rs.refreshTokenRepo.CreateRefreshToken(ctx, tx, next); err != nil {
+			return dto.ErrGenerateRefreshToken
+		}
+
+		rotated, err := rs.refreshTokenRepo.RotateRefreshToken(ctx, tx, current.ID, next.ID)
+		if err != nil {
+			return dto.ErrGenerateRefreshToken
+		}
+
+		// request lain sudah lebih dulu merotasi token ini
+		if !rotated {
+			reused = true
+			return dto.ErrRefreshTokenReused
+		}
+
+		return nil
+	})
+	if reused {
+		if err := rs.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, nil, current.FamilyID); err != nil {
+			return entity.RefreshToken{}, "", dto.ErrRevokeRefreshToken
+		}
+	}
+	if err != nil {
+		return entity.RefreshToken{}, "", err
+	}
+
+	return next, rawNext, nil
+}
+
+// Revoke mencabut family dari refresh token, dipakai untuk logout satu perangkat
+func (rs *RefreshTokenService) Revoke(ctx context.Context, rawToken string, roleName string) error {
+	token, flag, err := rs.refreshTokenRepo.GetRefreshTokenByHash(ctx, nil, helpers.HashToken(rawToken))
+	if err != nil || !flag {
+		return dto.ErrRefreshTokenInvalid
+	}
+
+	if token.RoleName != roleName {
+		return dto.ErrDeniedAccess
+	}
+
+	if err := rs.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, nil, token.FamilyID); err != nil {
+		return dto.ErrRevokeRefreshToken
+	}
+
+	return nil
+}
+
+// RevokeAll mencabut semua refresh token milik subject, untuk logout semua perangkat
+func (rs *RefreshTokenService) RevokeAll(ctx context.Context, subjectID string, roleName string) error {
+	sID, err := uuid.Parse(subjectID)
+	if err != nil {
+		return dto.ErrParseUUID
+	}
+
+	if err := rs.refreshTokenRepo.RevokeRefreshTokenBySubject(ctx, nil, sID, roleName); err != nil {
+		return dto.ErrRevokeRefreshToken
+	}
+
+	return nil
+}
+
+func (rs *RefreshTokenService) newRefreshToken(familyID uuid.UUID, subjectID uuid.UUID, roleID uuid.UUID, roleName string) (string, entity.RefreshToken, error) {
+	rawToken, err := helpers.GenerateRandomToken(32)
+	if err != nil {
+		return "", entity.RefreshToken{}, dto.ErrGenerateRefreshToken
+	}
+
+	return rawToken, entity.RefreshToken{
+		ID:        uuid.New(),
+		TokenHash: helpers.HashToken(rawToken),
+		FamilyID:  familyID,
+		SubjectID: subjectID,
+		RoleID:    roleID,
+		RoleName:  roleName,
+		ExpiresAt: time.Now().Add(rs.ttl),
+	}, nil
+}
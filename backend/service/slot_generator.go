@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	ISlotGenerator interface {
+		GenerateForPsycholog(ctx context.Context, psyID string) (dto.GenerateAvailableSlotResponse, error)
+		GenerateAll(ctx context.Context) (int, error)
+	}
+
+	SlotGenerator struct {
+		psychologRepo repository.IPsychologRepository
+		horizonDays   int
+	}
+)
+
+func NewSlotGenerator(psychologRepo repository.IPsychologRepository) *SlotGenerator {
+	return &SlotGenerator{
+		psychologRepo: psychologRepo,
+		horizonDays:   getSlotHorizonDays(),
+	}
+}
+
+func getSlotHorizonDays() int {
+	days, err := strconv.Atoi(os.Getenv("SLOT_GENERATION_HORIZON_DAYS"))
+	if err != nil || days <= 0 {
+		days = constants.ENUM_SLOT_GENERATION_HORIZON_DAYS
+	}
+
+	return days
+}
+
+// GenerateForPsycholog membuat AvailableSlot bertanggal dari seluruh SlotTemplate
+// psikolog untuk horizon ke depan. Slot yang sudah ada (booked maupun belum) tidak
+// pernah ditimpa; sesi yang bertabrakan dengan slot lama dilewati.
+func (sg *SlotGenerator) GenerateForPsycholog(ctx context.Context, psyID string) (dto.GenerateAvailableSlotResponse, error) {
+	psychologID, err := uuid.Parse(psyID)
+	if err != nil {
+		return dto.GenerateAvailableSlotResponse{}, dto.ErrParseUUID
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.Format("2006-01-02")
+	to := today.AddDate(0, 0, sg.horizonDays-1).Format("2006-01-02")
+
+	templates, err := sg.psychologRepo.GetAllSlotTemplate(ctx, nil, psyID)
+	if err != nil {
+		return dto.GenerateAvailableSlotResponse{}, dto.ErrGetAllSlotTemplate
+	}
+
+	existing, err := sg.psychologRepo.GetAvailableSlotByDateRange(ctx, nil, psyID, from, to)
+	if err != nil {
+		return dto.GenerateAvailableSlotResponse{}, dto.ErrGetAllAvailableSlot
+	}
+
+	taken := make(map[string][]helpers.TimeRange)
+	for _, slot := range existing {
+		date := normalizeSlotDate(slot.Date)
+		taken[date] = append(taken[date], helpers.TimeRange{Start: slot.Start, End: slot.End})
+	}
+
+	var slots []entity.AvailableSlot
+	for i := 0; i < sg.horizonDays; i++ {
+		day := today.AddDate(0, 0, i)
+		date := day.Format("2006-01-02")
+
+		for _, tmpl := range templates {
+			if tmpl.Day != day.Weekday().String() {
+				continue
+			}
+
+			sessions, err := helpers.SplitSessions(tmpl.Start, tmpl.End, tmpl.SessionDuration, tmpl.BufferDuration)
+			if err != nil {
+				return dto.GenerateAvailableSlotResponse{}, dto.ErrInvalidSlotTemplateTime
+			}
+
+			for _, session := range sessions {
+				if isSessionTaken(taken[date], session) {
+					continue
+				}
+
+				// sesi hari ini yang jamnya sudah lewat tidak perlu dibuat
+				start, err := helpers.ParseSessionStart(date, session.Start, now.Location())
+				if err != nil {
+					return dto.GenerateAvailableSlotResponse{}, dto.ErrInvalidSlotTemplateTime
+				}
+				if start.Before(now) {
+					continue
+				}
+
+				templateID := tmpl.ID
+				slots = append(slots, entity.AvailableSlot{
+					ID:             uuid.New(),
+					Date:           date,
+					Start:          session.Start,
+					End:            session.End,
+					IsBooked:       false,
+					PsychologID:    &psychologID,
+					PracticeID:     tmpl.PracticeID,
+					SlotTemplateID: &templateID,
+				})
+				taken[date] = append(taken[date], session)
+			}
+		}
+	}
+
+	created := 0
+	if len(slots) > 0 {
+		created, err = sg.psychologRepo.CreateAvailableSlots(ctx, nil, slots)
+		if err != nil {
+			return dto.GenerateAvailableSlotResponse{}, dto.ErrCreateAvailableSlots
+		}
+	}
+
+	return dto.GenerateAvailableSlotResponse{
+		From:         from,
+		To:           to,
+		CreatedCount: created,
+	}, nil
+}
+func (sg *SlotGenerator) GenerateAll(ctx context.Context) (int, error) {
+	psyIDs, err := sg.psychologRepo.GetAllPsychologIDWithSlotTemplate(ctx, nil)
+	if err != nil {
+		return 0, dto.ErrGetAllSlotTemplate
+	}
+
+	total := 0
+	for _, psyID := range psyIDs {
+		res, err := sg.GenerateForPsycholog(ctx, psyID)
+		if err != nil {
+			return total, err
+		}
+
+		total += res.CreatedCount
+	}
+
+	return total, nil
+}
+
+func isSessionTaken(taken []helpers.TimeRange, session helpers.TimeRange) bool {
+	for _, t := range taken {
+		if helpers.IsTimeRangeOverlap(t, session) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kolom date bisa terbaca sebagai "2006-01-02T00:00:00Z" tergantung driver
+func normalizeSlotDate(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+
+	return date
+}
+
+func todayDate() string {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/google/uuid"
+)
+
+func addSlotTemplate(repo *fakePsychologRepo, psychologID uuid.UUID, day string, start string, end string, session int, buffer int) entity.SlotTemplate {
+	practiceID := uuid.New()
+	tmpl := entity.SlotTemplate{
+		ID:              uuid.New(),
+		Day:             day,
+		Start:           start,
+		End:             end,
+		SessionDuration: session,
+		BufferDuration:  buffer,
+		PracticeID:      &practiceID,
+		PsychologID:     &psychologID,
+	}
+	repo.templates = append(repo.templates, tmpl)
+	return tmpl
+}
+
+func TestGenerateForPsycholog(t *testing.T) {
+	now := time.Now()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	date := tomorrow.Format("2006-01-02")
+	day := tomorrow.Weekday().String()
+
+	tests := []struct {
+		name    string
+		horizon int
+		setup   func(repo *fakePsychologRepo, psychologID uuid.UUID)
+		want    []helpers.TimeRange
+	}{
+		{
+			name:    "sessions on the template weekday",
+			horizon: 7,
+			setup: func(repo *fakePsychologRepo, psychologID uuid.UUID) {
+				addSlotTemplate(repo, psychologID, day, "09:00", "11:00", 50, 10)
+			},
+			want: []helpers.TimeRange{{Start: "09:00", End: "09:50"}, {Start: "10:00", End: "10:50"}},
+		},
+		{
+			name:    "existing slot is kept and its session skipped",
+			horizon: 7,
+			setup: func(repo *fakePsychologRepo, psychologID uuid.UUID) {
+				addSlotTemplate(repo, psychologID, day, "09:00", "11:00", 50, 10)
+				repo.slots = append(repo.slots, entity.AvailableSlot{ID: uuid.New(), Date: date, Start: "09:15", End: "09:45", IsBooked: true, PsychologID: &psychologID})
+			},
+			want: []helpers.TimeRange{{Start: "09:15", End: "09:45"}, {Start: "10:00", End: "10:50"}},
+		},
+		{
+			name:    "overlapping templates do not create overlapping slots",
+			horizon: 7,
+			setup: func(repo *fakePsychologRepo, psychologID uuid.UUID) {
+				addSlotTemplate(repo, psychologID, day, "09:00", "11:00", 60, 0)
+				addSlotTemplate(repo, psychologID, day, "10:00", "12:00", 60, 0)
+			},
+			want: []helpers.TimeRange{{Start: "09:00", End: "10:00"}, {Start: "10:00", End: "11:00"}, {Start: "11:00", End: "12:00"}},
+		},
+		{
+			name:    "weekday outside the horizon",
+			horizon: 1,
+			setup: func(repo *fakePsychologRepo, psychologID uuid.UUID) {
+				addSlotTemplate(repo, psychologID, day, "09:00", "11:00", 60, 0)
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePsychologRepo{}
+			psychologID := uuid.New()
+			tt.setup(repo, psychologID)
+			existing := len(repo.slots)
+			existingIDs := make(map[uuid.UUID]bool)
+			for _, slot := range repo.slots {
+				existingIDs[slot.ID] = true
+			}
+
+			sg := &SlotGenerator{psychologRepo: repo, horizonDays: tt.horizon}
+
+			res, err := sg.GenerateForPsycholog(context.Background(), psychologID.String())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.CreatedCount != len(repo.slots)-existing {
+				t.Errorf("CreatedCount = %d, stored %d new slot", res.CreatedCount, len(repo.slots)-existing)
+			}
+
+			var got []helpers.TimeRange
+			for _, slot := range repo.slots {
+				if slot.Date != date {
+					continue
+				}
+				got = append(got, helpers.TimeRange{Start: slot.Start, End: slot.End})
+
+				if existingIDs[slot.ID] {
+					continue
+				}
+				if slot.IsBooked || slot.SlotTemplateID == nil || slot.PracticeID == nil || *slot.PsychologID != psychologID {
+					t.Errorf("generated slot %s-%s has unexpected fields: %+v", slot.Start, slot.End, slot)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("slots on %s = %v, want %v", date, got, tt.want)
+			}
+			for _, want := range tt.want {
+				if !containsTimeRange(got, want) {
+					t.Errorf("slots on %s = %v, missing %v", date, got, want)
+				}
+			}
+
+			// generate ulang tidak membuat slot baru
+			res, err = sg.GenerateForPsycholog(context.Background(), psychologID.String())
+			if err != nil || res.CreatedCount != 0 {
+				t.Errorf("second run = %d, %v; want 0, nil", res.CreatedCount, err)
+			}
+		})
+	}
+}
+
+func TestGenerateForPsychologSkipsPastSessionsToday(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	repo := &fakePsychologRepo{}
+	psychologID := uuid.New()
+	addSlotTemplate(repo, psychologID, today.Weekday().String(), "00:00", "23:00", 60, 0)
+
+	want := 0
+	for h := 0; h < 23; h++ {
+		if !today.Add(time.Duration(h) * time.Hour).Before(now) {
+			want++
+		}
+	}
+
+	sg := &SlotGenerator{psychologRepo: repo, horizonDays: 1}
+	res, err := sg.GenerateForPsycholog(context.Background(), psychologID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.CreatedCount != want {
+		t.Errorf("CreatedCount = %d, want %d", res.CreatedCount, want)
+	}
+	for _, slot := range repo.slots {
+		start, _ := helpers.ParseSessionStart(slot.Date, slot.Start, now.Location())
+		if start.Before(now.Add(-time.Second)) {
+			t.Errorf("slot %s %s already started", slot.Date, slot.Start)
+		}
+	}
+}
+
+func TestGenerateAll(t *testing.T) {
+	now := time.Now()
+	day := now.AddDate(0, 0, 1).Weekday().String()
+
+	repo := &fakePsychologRepo{}
+	addSlotTemplate(repo, uuid.New(), day, "09:00", "11:00", 60, 0)
+	addSlotTemplate(repo, uuid.New(), day, "13:00", "16:00", 60, 0)
+
+	sg := &SlotGenerator{psychologRepo: repo, horizonDays: 7}
+	total, err := sg.GenerateAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if total != 5 || len(repo.slots) != 5 {
+		t.Errorf("GenerateAll() = %d with %d stored, want 5", total, len(repo.slots))
+	}
+}
+
+func containsTimeRange(ranges []helpers.TimeRange, want helpers.TimeRange) bool {
+	for _, r := range ranges {
+		if r == want {
+			return true
+		}
+	}
+
+	return false
+}
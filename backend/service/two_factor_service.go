@@ -0,0 +1,491 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/Reyysusanto/warasin-web/backend/dto"
+	"github.com/Reyysusanto/warasin-web/backend/entity"
+	"github.com/Reyysusanto/warasin-web/backend/helpers"
+	"github.com/Reyysusanto/warasin-web/backend/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	ITwoFactorService interface {
+		// Login
+		StartChallenge(ctx context.Context, subjectID string, roleID string, roleName string, accountName string) (dto.TwoFactorChallengeResponse, bool, error)
+		SetupFromChallenge(ctx context.Context, roleName string, req dto.TwoFactorChallengeSetupRequest) (dto.TwoFactorSetupResponse, error)
+		VerifyChallenge(ctx context.Context, roleName string, req dto.TwoFactorLoginRequest) (entity.TwoFactorChallenge, []string, error)
+
+		// Account
+		GetStatus(ctx context.Context, subjectID string, roleName string) (dto.TwoFactorStatusResponse, error)
+		Setup(ctx context.Context, subjectID string, roleName string, accountName string) (dto.TwoFactorSetupResponse, error)
+		Enable(ctx context.Context, subjectID string, roleName string, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+		Disable(ctx context.Context, subjectID string, roleName string, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+		RegenerateRecoveryCodes(ctx context.Context, subjectID string, roleName string, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error)
+
+		// Admin
+		SetRequired(ctx context.Context, subjectID string, roleName string, isRequired bool) (dto.TwoFactorStatusResponse, error)
+	}
+
+	TwoFactorService struct {
+		twoFactorRepo repository.ITwoFactorRepository
+	}
+)
+
+func NewTwoFactorService(twoFactorRepo repository.ITwoFactorRepository) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+// getTwoFactor mengembalikan konfigurasi kosong (belum disimpan) jika subject belum pernah setup
+func (ts *TwoFactorService) getTwoFactor(ctx context.Context, tx *gorm.DB, subjectID uuid.UUID, roleName string) (entity.TwoFactor, error) {
+	twoFactor, flag, err := ts.twoFactorRepo.GetTwoFactorBySubject(ctx, tx, subjectID, roleName)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.TwoFactor{}, dto.ErrGetTwoFactor
+	}
+
+	if !flag {
+		return entity.TwoFactor{
+			ID:        uuid.New(),
+			SubjectID: subjectID,
+			RoleName:  roleName,
+		}, nil
+	}
+
+	return twoFactor, nil
+}
+func (ts *TwoFactorService) parseSubject(subjectID string, roleName string) (uuid.UUID, error) {
+	if roleName != constants.ENUM_ROLE_ADMIN && roleName != constants.ENUM_ROLE_PSYCHOLOG {
+		return uuid.Nil, dto.ErrInvalidTwoFactorRoleName
+	}
+
+	sID, err := uuid.Parse(subjectID)
+	if err != nil {
+		return uuid.Nil, dto.ErrParseUUID
+	}
+
+	return sID, nil
+}
+func (ts *TwoFactorService) newSecret(ctx context.Context, twoFactor entity.TwoFactor, accountName string) (dto.TwoFactorSetupResponse, error) {
+	if twoFactor.IsEnabled {
+		return dto.TwoFactorSetupResponse{}, dto.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := helpers.GenerateTOTPSecret()
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, dto.ErrGenerateTwoFactorSecret
+	}
+
+	twoFactor.Secret = secret
+	twoFactor.LastUsedStep = 0
+	if err := ts.twoFactorRepo.SaveTwoFactor(ctx, nil, twoFactor); err != nil {
+		return dto.TwoFactorSetupResponse{}, dto.ErrSaveTwoFactor
+	}
+
+	return dto.TwoFactorSetupResponse{
+		Secret:     secret,
+		OtpauthURI: helpers.TOTPURI(constants.ENUM_TWO_FACTOR_ISSUER, accountName, secret),
+	}, nil
+}
+
+// verifyCode menerima code TOTP atau (jika allowRecovery) recovery code. Step TOTP
+// yang dipakai disimpan agar code yang sama tidak bisa dipakai ulang.
+func (ts *TwoFactorService) verifyCode(ctx context.Context, tx *gorm.DB, twoFactor *entity.TwoFactor, code string, recoveryCode string, allowRecovery bool) error {
+	if twoFactor.Secret == "" {
+		return dto.ErrTwoFactorNotSetup
+	}
+
+	if code != "" {
+		step, ok := helpers.VerifyTOTP(twoFactor.Secret, code, time.Now(), 1)
+		if !ok {
+			return dto.ErrTwoFactorCodeInvalid
+		}
+
+		updated, err := ts.twoFactorRepo.UpdateTwoFactorLastUsedStep(ctx, tx, twoFactor.ID, step)
+		if err != nil {
+			return dto.ErrVerifyTwoFactor
+		}
+
+		if !updated {
+			return dto.ErrTwoFactorCodeInvalid
+		}
+
+		twoFactor.LastUsedStep = step
+		return nil
+	}
+
+	if recoveryCode == "" || !allowRecovery {
+		return dto.ErrTwoFactorCodeRequired
+	}
+
+	codeHash := helpers.HashToken(helpers.NormalizeRecoveryCode(recoveryCode))
+	recovery, flag, err := ts.twoFactorRepo.GetUnusedRecoveryCode(ctx, tx, twoFactor.ID, codeHash)
+	if err != nil || !flag {
+		return dto.ErrTwoFactorCodeInvalid
+	}
+
+	used, err := ts.twoFactorRepo.UseRecoveryCode(ctx, tx, recovery.ID)
+	if err != nil {
+		return dto.ErrVerifyTwoFactor
+	}
+
+	if !used {
+		return dto.ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes membuang recovery code lama dan mengembalikan code baru dalam
+// bentuk asli, hanya hash yang disimpan sehingga code tidak bisa ditampilkan ulang
+func (ts *TwoFactorService) replaceRecoveryCodes(ctx context.Context, tx *gorm.DB, twoFactorID uuid.UUID) ([]string, error) {
+	if err := ts.twoFactorRepo.DeleteTwoFactorRecoveryCodes(ctx, tx, twoFactorID); err != nil {
+		return nil, dto.ErrGenerateRecoveryCodes
+	}
+
+	var rawCodes []string
+	var codes []entity.TwoFactorRecoveryCode
+	for i := 0; i < constants.ENUM_TWO_FACTOR_RECOVERY_CODE_COUNT; i++ {
+		rawCode, err := helpers.GenerateRecoveryCode()
+		if err != nil {
+			return nil, dto.ErrGenerateRecoveryCodes
+		}
+
+		rawCodes = append(rawCodes, rawCode)
+		codes = append(codes, entity.TwoFactorRecoveryCode{
+			ID:          uuid.New(),
+			CodeHash:    helpers.HashToken(rawCode),
+			TwoFactorID: twoFactorID,
+		})
+	}
+
+	if err := ts.twoFactorRepo.CreateTwoFactorRecoveryCodes(ctx, tx, codes); err != nil {
+		return nil, dto.ErrGenerateRecoveryCodes
+	}
+
+	return rawCodes, nil
+}
+func (ts *TwoFactorService) enable(ctx context.Context, tx *gorm.DB, twoFactor *entity.TwoFactor) ([]string, error) {
+	now := time.Now()
+	twoFactor.IsEnabled = true
+	twoFactor.EnabledAt = &now
+	if err := ts.twoFactorRepo.SaveTwoFactor(ctx, tx, *twoFactor); err != nil {
+		return nil, dto.ErrSaveTwoFactor
+	}
+
+	return ts.replaceRecoveryCodes(ctx, tx, twoFactor.ID)
+}
+func (ts *TwoFactorService) toStatusResponse(ctx context.Context, twoFactor entity.TwoFactor, recoveryCodes []string) (dto.TwoFactorStatusResponse, error) {
+	var remaining int64
+	if twoFactor.IsEnabled {
+		count, err := ts.twoFactorRepo.CountUnusedRecoveryCode(ctx, nil, twoFactor.ID)
+		if err != nil {
+			return dto.TwoFactorStatusResponse{}, dto.ErrGetTwoFactor
+		}
+		remaining = count
+	}
+
+	return dto.TwoFactorStatusResponse{
+		IsEnabled:              twoFactor.IsEnabled,
+		IsRequired:             twoFactor.IsRequired,
+		EnabledAt:              twoFactor.EnabledAt,
+		RemainingRecoveryCodes: remaining,
+		RecoveryCodes:          recoveryCodes,
+	}, nil
+}
+func (ts *TwoFactorService) getChallenge(ctx context.Context, roleName string, rawToken string) (entity.TwoFactorChallenge, error) {
+	challenge, flag, err := ts.twoFactorRepo.GetTwoFactorChallengeByHash(ctx, nil, helpers.HashToken(rawToken))
+	if err != nil || !flag {
+		return entity.TwoFactorChallenge{}, dto.ErrTwoFactorChallengeInvalid
+	}
+
+	if challenge.RoleName != roleName || challenge.ConsumedAt != nil {
+		return entity.TwoFactorChallenge{}, dto.ErrTwoFactorChallengeInvalid
+	}
+
+	if time.Now().After(challenge.ExpiresAt) {
+		return entity.TwoFactorChallenge{}, dto.ErrTwoFactorChallengeExpired
+	}
+
+	if challenge.Attempts >= constants.ENUM_TWO_FACTOR_CHALLENGE_MAX_ATTEMPTS {
+		return entity.TwoFactorChallenge{}, dto.ErrTwoFactorTooManyAttempts
+	}
+
+	return challenge, nil
+}
+
+// Login
+
+// StartChallenge dipanggil setelah password benar. Jika 2FA aktif atau diwajibkan admin,
+// login ditahan dan challenge token dikembalikan sebagai pengganti access token.
+func (ts *TwoFactorService) StartChallenge(ctx context.Context, subjectID string, roleID string, roleName string, accountName string) (dto.TwoFactorChallengeResponse, bool, error) {
+	sID, err := ts.parseSubject(subjectID, roleName)
+	if err != nil {
+		return dto.TwoFactorChallengeResponse{}, false, err
+	}
+
+	rID, err := uuid.Parse(roleID)
+	if err != nil {
+		return dto.TwoFactorChallengeResponse{}, false, dto.ErrParseUUID
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, sID, roleName)
+	if err != nil {
+		return dto.TwoFactorChallengeResponse{}, false, err
+	}
+
+	if !twoFactor.IsEnabled && !twoFactor.IsRequired {
+		return dto.TwoFactorChallengeResponse{}, false, nil
+	}
+
+	rawToken, err := helpers.GenerateRandomToken(32)
+	if err != nil {
+		return dto.TwoFactorChallengeResponse{}, false, dto.ErrCreateTwoFactorChallenge
+	}
+
+	ttl := time.Duration(constants.ENUM_TWO_FACTOR_CHALLENGE_TTL_MINUTES) * time.Minute
+	challenge := entity.TwoFactorChallenge{
+		ID:          uuid.New(),
+		TokenHash:   helpers.HashToken(rawToken),
+		SubjectID:   sID,
+		RoleID:      rID,
+		RoleName:    roleName,
+		AccountName: accountName,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+
+	if err := ts.twoFactorRepo.CreateTwoFactorChallenge(ctx, nil, challenge); err != nil {
+		return dto.TwoFactorChallengeResponse{}, false, dto.ErrCreateTwoFactorChallenge
+	}
+
+	return dto.TwoFactorChallengeResponse{
+		Token:         rawToken,
+		ExpiresIn:     int(ttl.Seconds()),
+		SetupRequired: !twoFactor.IsEnabled,
+	}, true, nil
+}
+
+// SetupFromChallenge dipakai akun yang diwajibkan 2FA tetapi belum enroll, sehingga
+// enrollment bisa dilakukan di tengah login tanpa access token
+func (ts *TwoFactorService) SetupFromChallenge(ctx context.Context, roleName string, req dto.TwoFactorChallengeSetupRequest) (dto.TwoFactorSetupResponse, error) {
+	challenge, err := ts.getChallenge(ctx, roleName, req.ChallengeToken)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, challenge.SubjectID, roleName)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	return ts.newSecret(ctx, twoFactor, challenge.AccountName)
+}
+
+// VerifyChallenge menukar challenge token dengan code 2FA. Untuk akun yang baru enroll,
+// 2FA langsung diaktifkan dan recovery code ikut dikembalikan.
+func (ts *TwoFactorService) VerifyChallenge(ctx context.Context, roleName string, req dto.TwoFactorLoginRequest) (entity.TwoFactorChallenge, []string, error) {
+	challenge, err := ts.getChallenge(ctx, roleName, req.ChallengeToken)
+	if err != nil {
+		return entity.TwoFactorChallenge{}, nil, err
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, challenge.SubjectID, roleName)
+	if err != nil {
+		return entity.TwoFactorChallenge{}, nil, err
+	}
+
+	var recoveryCodes []string
+	err = ts.twoFactorRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		// recovery code hanya berlaku untuk akun yang 2FA-nya sudah aktif
+		if err := ts.verifyCode(ctx, tx, &twoFactor, req.Code, req.RecoveryCode, twoFactor.IsEnabled); err != nil {
+			return err
+		}
+
+		consumed, err := ts.twoFactorRepo.ConsumeTwoFactorChallenge(ctx, tx, challenge.ID)
+		if err != nil {
+			return dto.ErrVerifyTwoFactor
+		}
+
+		if !consumed {
+			return dto.ErrTwoFactorChallengeInvalid
+		}
+
+		if !twoFactor.IsEnabled {
+			recoveryCodes, err = ts.enable(ctx, tx, &twoFactor)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, dto.ErrTwoFactorCodeInvalid) {
+			if err := ts.twoFactorRepo.IncrementTwoFactorChallengeAttempts(ctx, nil, challenge.ID); err != nil {
+				return entity.TwoFactorChallenge{}, nil, dto.ErrVerifyTwoFactor
+			}
+		}
+
+		return entity.TwoFactorChallenge{}, nil, err
+	}
+
+	return challenge, recoveryCodes, nil
+}
+
+// Account
+func (ts *TwoFactorService) GetStatus(ctx context.Context, subjectID string, roleName string) (dto.TwoFactorStatusResponse, error) {
+	sID, err := ts.parseSubject(subjectID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, sID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	return ts.toStatusResponse(ctx, twoFactor, nil)
+}
+func (ts *TwoFactorService) Setup(ctx context.Context, subjectID string, roleName string, accountName string) (dto.TwoFactorSetupResponse, error) {
+	sID, err := ts.parseSubject(subjectID, roleName)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, sID, roleName)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	return ts.newSecret(ctx, twoFactor, accountName)
+}
+func (ts *TwoFactorService) Enable(ctx context.Context, subjectID string, roleName string, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	sID, err := ts.parseSubject(subjectID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, sID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	if twoFactor.IsEnabled {
+		return dto.TwoFactorStatusResponse{}, dto.ErrTwoFactorAlreadyEnabled
+	}
+
+	var recoveryCodes []string
+	err = ts.twoFactorRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := ts.verifyCode(ctx, tx, &twoFactor, req.Code, "", false); err != nil {
+			return err
+		}
+
+		recoveryCodes, err = ts.enable(ctx, tx, &twoFactor)
+		return err
+	})
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	return ts.toStatusResponse(ctx, twoFactor, recoveryCodes)
+}
+func (ts *TwoFactorService) Disable(ctx context.Context, subjectID string, roleName string, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	sID, err := ts.parseSubject(subjectID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, sID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	if !twoFactor.IsEnabled {
+		return dto.TwoFactorStatusResponse{}, dto.ErrTwoFactorNotEnabled
+	}
+
+	if twoFactor.IsRequired {
+		return dto.TwoFactorStatusResponse{}, dto.ErrTwoFactorRequiredByAdmin
+	}
+
+	err = ts.twoFactorRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := ts.verifyCode(ctx, tx, &twoFactor, req.Code, req.RecoveryCode, true); err != nil {
+			return err
+		}
+
+		twoFactor.IsEnabled = false
+		twoFactor.EnabledAt = nil
+		twoFactor.Secret = ""
+		twoFactor.LastUsedStep = 0
+		if err := ts.twoFactorRepo.SaveTwoFactor(ctx, tx, twoFactor); err != nil {
+			return dto.ErrSaveTwoFactor
+		}
+
+		if err := ts.twoFactorRepo.DeleteTwoFactorRecoveryCodes(ctx, tx, twoFactor.ID); err != nil {
+			return dto.ErrSaveTwoFactor
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	return ts.toStatusResponse(ctx, twoFactor, nil)
+}
+func (ts *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, subjectID string, roleName string, req dto.TwoFactorCodeRequest) (dto.TwoFactorStatusResponse, error) {
+	sID, err := ts.parseSubject(subjectID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, sID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	if !twoFactor.IsEnabled {
+		return dto.TwoFactorStatusResponse{}, dto.ErrTwoFactorNotEnabled
+	}
+
+	var recoveryCodes []string
+	err = ts.twoFactorRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := ts.verifyCode(ctx, tx, &twoFactor, req.Code, "", false); err != nil {
+			return err
+		}
+
+		recoveryCodes, err = ts.replaceRecoveryCodes(ctx, tx, twoFactor.ID)
+		return err
+	})
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	return ts.toStatusResponse(ctx, twoFactor, recoveryCodes)
+}
+
+// Admin
+func (ts *TwoFactorService) SetRequired(ctx context.Context, subjectID string, roleName string, isRequired bool) (dto.TwoFactorStatusResponse, error) {
+	sID, err := ts.parseSubject(subjectID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	twoFactor, err := ts.getTwoFactor(ctx, nil, sID, roleName)
+	if err != nil {
+		return dto.TwoFactorStatusResponse{}, err
+	}
+
+	twoFactor.IsRequired = isRequired
+	if err := ts.twoFactorRepo.SaveTwoFactor(ctx, nil, twoFactor); err != nil {
+		return dto.TwoFactorStatusResponse{}, dto.ErrSaveTwoFactor
+	}
+
+	return ts.toStatusResponse(ctx, twoFactor, nil)
+}
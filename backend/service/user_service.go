@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -11,20 +12,36 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Reyysusanto/warasin-web/backend/constants"
 	"github.com/Reyysusanto/warasin-web/backend/dto"
 	"github.com/Reyysusanto/warasin-web/backend/entity"
 	"github.com/Reyysusanto/warasin-web/backend/helpers"
 	"github.com/Reyysusanto/warasin-web/backend/repository"
 	"github.com/Reyysusanto/warasin-web/backend/utils"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// jumlah maksimal rekomendasi per jenis yang disertakan di respons chat
+const chatSuggestionLimit = 3
+
+// dipakai untuk menghentikan stream model saat balasannya terdeteksi krisis
+var errChatStreamCrisis = errors.New("crisis language detected in model reply")
+
+// kode filter tipe praktik di pencarian psycholog -> nilai practices.type
+var practiceTypeFilters = map[string]string{
+	constants.ENUM_PRACTICE_TYPE_FILTER_ONLINE: constants.ENUM_PRACTICE_TYPE_ONLINE,
+	constants.ENUM_PRACTICE_TYPE_FILTER_CLINIC: constants.ENUM_PRACTICE_TYPE_CLINIC,
+}
+
 type (
 	IUserService interface {
 		// Authentication
 		Register(ctx context.Context, req dto.UserRegisterRequest) (dto.AllUserResponse, error)
 		Login(ctx context.Context, req dto.UserLoginRequest) (dto.UserLoginResponse, error)
 		RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.RefreshTokenResponse, error)
+		Logout(ctx context.Context, req dto.LogoutRequest) error
+		LogoutAll(ctx context.Context) error
 
 		// Forgot Password
 		SendForgotPasswordEmail(ctx context.Context, req dto.SendForgotPasswordEmailRequest) error
@@ -53,10 +70,15 @@ type (
 		GetDetailConsultation(ctx context.Context, consulID string) (dto.ConsultationResponseForUser, error)
 		UpdateConsultation(ctx context.Context, req dto.UpdateConsultationRequestForUser, consulID string) (dto.ConsultationResponseForUser, error)
 		DeleteConsultation(ctx context.Context, consulID string) (dto.ConsultationResponseForUser, error)
+		GetConsultationStatusHistory(ctx context.Context, consulID string) ([]dto.ConsultationStatusHistoryResponse, error)
+		GetConsultationPayment(ctx context.Context, consulID string) (dto.PaymentResponse, error)
 
 		// Psycholog
 		GetAllPsycholog(ctx context.Context, filter dto.PsychologFilter) ([]dto.PsychologResponse, error)
 		GetDetailPsycholog(ctx context.Context, psyID string) (dto.PsychologResponse, error)
+		GetAllPsychologReviewWithPagination(ctx context.Context, req dto.PaginationRequest, psyID string) (dto.ReviewPaginationResponse, error)
+		SearchPsychologWithPagination(ctx context.Context, req dto.PsychologSearchRequest) (dto.PsychologSearchPaginationResponse, error)
+		SearchWithPagination(ctx context.Context, req dto.SearchRequest) (dto.SearchPaginationResponse, error)
 
 		// Practice
 		GetAllPractice(ctx context.Context, psyID string) ([]dto.PracticeResponse, error)
@@ -74,20 +96,47 @@ type (
 
 		// Chat
 		HandleChat(ctx context.Context, req dto.ChatRequest) (dto.ChatResponse, error)
+		HandleChatStream(ctx context.Context, req dto.ChatRequest, onEvent func(event string, text string) error) (dto.ChatResponse, error)
+
+		// Conversation
+		GetAllConversationWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.ConversationPaginationResponse, error)
+		GetDetailConversation(ctx context.Context, convoID string) (dto.ConversationResponse, error)
+		UpdateConversation(ctx context.Context, req dto.UpdateConversationRequest) (dto.ConversationResponse, error)
+		DeleteConversation(ctx context.Context, convoID string) (dto.ConversationResponse, error)
 	}
 
 	UserService struct {
-		userRepo   repository.IUserRepository
-		masterRepo repository.IMasterRepository
-		jwtService IJWTService
+		userRepo         repository.IUserRepository
+		consultationRepo repository.IConsultationRepository
+		masterRepo       repository.IMasterRepository
+		jwtService       IJWTService
+		llmClient        utils.LLMClient
+		chatBuilder      *utils.ChatHistoryBuilder
+		chatQuota        IChatQuotaService
+
+		refreshTokenService IRefreshTokenService
+		loginGuard          ILoginGuardService
+		oneTimeTokenService IOneTimeTokenService
+		paymentService      IPaymentService
+		cancellationService ICancellationService
 	}
 )
 
-func NewUserService(userRepo repository.IUserRepository, masterRepo repository.IMasterRepository, jwtService IJWTService) *UserService {
+func NewUserService(userRepo repository.IUserRepository, consultationRepo repository.IConsultationRepository, masterRepo repository.IMasterRepository, jwtService IJWTService, llmClient utils.LLMClient, chatBuilder *utils.ChatHistoryBuilder, chatQuota IChatQuotaService, refreshTokenService IRefreshTokenService, loginGuard ILoginGuardService, oneTimeTokenService IOneTimeTokenService, paymentService IPaymentService, cancellationService ICancellationService) *UserService {
 	return &UserService{
-		userRepo:   userRepo,
-		masterRepo: masterRepo,
-		jwtService: jwtService,
+		userRepo:         userRepo,
+		consultationRepo: consultationRepo,
+		masterRepo:       masterRepo,
+		jwtService:       jwtService,
+		llmClient:        llmClient,
+		chatBuilder:      chatBuilder,
+		chatQuota:        chatQuota,
+
+		refreshTokenService: refreshTokenService,
+		loginGuard:          loginGuard,
+		oneTimeTokenService: oneTimeTokenService,
+		paymentService:      paymentService,
+		cancellationService: cancellationService,
 	}
 }
 
@@ -118,7 +167,7 @@ func (us *UserService) Register(ctx context.Context, req dto.UserRegisterRequest
 	user := entity.User{
 		Name:     req.Name,
 		Email:    req.Email,
-		Password: req.Password,
+		Password: helpers.PasswordHash(req.Password),
 		Role:     role,
 	}
 
@@ -128,10 +177,9 @@ func (us *UserService) Register(ctx context.Context, req dto.UserRegisterRequest
 	}
 
 	return dto.AllUserResponse{
-		ID:       userReg.ID,
-		Name:     userReg.Name,
-		Email:    userReg.Email,
-		Password: userReg.Password,
+		ID:    userReg.ID,
+		Name:  userReg.Name,
+		Email: userReg.Email,
 		Role: dto.RoleResponse{
 			ID:   userReg.RoleID,
 			Name: user.Role.Name,
@@ -147,8 +195,15 @@ func (us *UserService) Login(ctx context.Context, req dto.UserLoginRequest) (dto
 		return dto.UserLoginResponse{}, dto.ErrInvalidPassword
 	}
 
+	if err := us.loginGuard.Check(ctx, constants.ENUM_ROLE_USER, req.Email, req.IPAddress); err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
 	user, flag, err := us.userRepo.GetUserByEmail(ctx, nil, req.Email)
 	if !flag || err != nil {
+		if err := us.loginGuard.RecordFailure(ctx, constants.ENUM_ROLE_USER, req.Email, req.IPAddress, false); err != nil {
+			return dto.UserLoginResponse{}, err
+		}
 		return dto.UserLoginResponse{}, dto.ErrEmailNotFound
 	}
 
@@ -158,15 +213,22 @@ func (us *UserService) Login(ctx context.Context, req dto.UserLoginRequest) (dto
 
 	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
 	if err != nil || !checkPassword {
+		if err := us.loginGuard.RecordFailure(ctx, constants.ENUM_ROLE_USER, req.Email, req.IPAddress, true); err != nil {
+			return dto.UserLoginResponse{}, err
+		}
 		return dto.UserLoginResponse{}, dto.ErrPasswordNotMatch
 	}
 
-	permissions, _, err := us.userRepo.GetPermissionsByRoleID(ctx, nil, user.RoleID.String())
+	if err := us.loginGuard.RecordSuccess(ctx, constants.ENUM_ROLE_USER, req.Email); err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	accessToken, err := us.jwtService.GenerateAccessToken(user.ID.String(), user.RoleID.String())
 	if err != nil {
-		return dto.UserLoginResponse{}, dto.ErrGetPermissionsByRoleID
+		return dto.UserLoginResponse{}, err
 	}
 
-	accessToken, refreshToken, err := us.jwtService.GenerateToken(user.ID.String(), user.RoleID.String(), permissions)
+	refreshToken, err := us.refreshTokenService.Issue(ctx, user.ID.String(), user.RoleID.String(), constants.ENUM_ROLE_USER)
 	if err != nil {
 		return dto.UserLoginResponse{}, err
 	}
@@ -177,53 +239,37 @@ func (us *UserService) Login(ctx context.Context, req dto.UserLoginRequest) (dto
 	}, nil
 }
 func (us *UserService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.RefreshTokenResponse, error) {
-	_, err := us.jwtService.ValidateToken(req.RefreshToken)
-
-	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrValidateToken
-	}
-
-	userID, err := us.jwtService.GetUserIDByToken(req.RefreshToken)
-	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetUserIDFromToken
-	}
-
-	roleID, err := us.jwtService.GetRoleIDByToken(req.RefreshToken)
+	token, refreshToken, err := us.refreshTokenService.Rotate(ctx, req.RefreshToken, constants.ENUM_ROLE_USER)
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetRoleFromToken
+		return dto.RefreshTokenResponse{}, err
 	}
 
-	role, _, err := us.userRepo.GetRoleByID(ctx, nil, roleID)
+	accessToken, err := us.jwtService.GenerateAccessToken(token.SubjectID.String(), token.RoleID.String())
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetRoleFromID
-	}
-
-	if role.Name != "user" {
-		return dto.RefreshTokenResponse{}, dto.ErrDeniedAccess
+		return dto.RefreshTokenResponse{}, dto.ErrGenerateAccessToken
 	}
 
-	endpoints, _, err := us.userRepo.GetPermissionsByRoleID(ctx, nil, roleID)
-	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGetPermissionsByRoleID
-	}
+	return dto.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+func (us *UserService) Logout(ctx context.Context, req dto.LogoutRequest) error {
+	return us.refreshTokenService.Revoke(ctx, req.RefreshToken, constants.ENUM_ROLE_USER)
+}
+func (us *UserService) LogoutAll(ctx context.Context) error {
+	token := ctx.Value("Authorization").(string)
 
-	accessToken, _, err := us.jwtService.GenerateToken(userID, roleID, endpoints)
+	userID, err := us.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.RefreshTokenResponse{}, dto.ErrGenerateAccessToken
+		return dto.ErrGetUserIDFromToken
 	}
 
-	return dto.RefreshTokenResponse{AccessToken: accessToken}, nil
+	return us.refreshTokenService.RevokeAll(ctx, userID, constants.ENUM_ROLE_USER)
 }
 
 // Forgot Password
-func makeForgotPasswordEmail(receiverEmail string) (map[string]string, error) {
-	expired := time.Now().Add(time.Hour * 24).Format("2006-01-02 15:04:05")
-	plainText := fmt.Sprintf("%s_%s", receiverEmail, expired)
-	token, err := utils.AESEncrypt(plainText)
-	if err != nil {
-		return nil, err
-	}
-
+func makeForgotPasswordEmail(receiverEmail string, token string) (map[string]string, error) {
 	baseURL := os.Getenv("BASE_URL")
 	forgotPasswordEmailRoute := "forgot-password"
 	if baseURL == "" {
@@ -268,9 +314,14 @@ func (us *UserService) SendForgotPasswordEmail(ctx context.Context, req dto.Send
 		return dto.ErrEmailNotFound
 	}
 
-	draftEmail, err := makeForgotPasswordEmail(user.Email)
+	token, err := us.oneTimeTokenService.Issue(ctx, user.ID, constants.ENUM_ONE_TIME_TOKEN_RESET_PASSWORD)
 	if err != nil {
-		return dto.ErrMakeVerificationEmail
+		return err
+	}
+
+	draftEmail, err := makeForgotPasswordEmail(user.Email, token)
+	if err != nil {
+		return dto.ErrMakeForgotPasswordEmail
 	}
 
 	if err := utils.SendEmail(user.Email, draftEmail["subject"], draftEmail["body"]); err != nil {
@@ -280,76 +331,66 @@ func (us *UserService) SendForgotPasswordEmail(ctx context.Context, req dto.Send
 	return nil
 }
 func (us *UserService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) (dto.ForgotPasswordResponse, error) {
-	decryptedToken, err := utils.AESDecrypt(req.Token)
-	if err != nil {
-		return dto.ForgotPasswordResponse{}, dto.ErrDecryptToken
-	}
-
-	if !strings.Contains(decryptedToken, "_") {
-		return dto.ForgotPasswordResponse{}, dto.ErrTokenInvalid
-	}
-
-	decryptedTokenSplit := strings.Split(decryptedToken, "_")
-	if len(decryptedTokenSplit) != 2 {
-		return dto.ForgotPasswordResponse{}, dto.ErrTokenInvalid
-	}
-
-	email := decryptedTokenSplit[0]
-	expired := decryptedTokenSplit[1]
-
-	now := time.Now()
-	expiredTime, err := time.Parse("2006-01-02 15:04:05", expired)
+	token, err := us.oneTimeTokenService.Validate(ctx, req.Token, constants.ENUM_ONE_TIME_TOKEN_RESET_PASSWORD)
 	if err != nil {
-		return dto.ForgotPasswordResponse{}, dto.ErrParsingExpiredTime
+		return dto.ForgotPasswordResponse{}, err
 	}
 
-	if expiredTime.Sub(now) < 0 {
-		return dto.ForgotPasswordResponse{}, dto.ErrTokenExpired
+	user, flag, err := us.userRepo.GetUserByID(ctx, nil, token.UserID.String())
+	if err != nil || !flag {
+		return dto.ForgotPasswordResponse{}, dto.ErrUserNotFound
 	}
 
 	return dto.ForgotPasswordResponse{
-		Email: email,
+		Email: user.Email,
 	}, nil
 }
 func (us *UserService) UpdatePassword(ctx context.Context, req dto.UpdatePasswordRequest) (dto.UpdatePasswordResponse, error) {
-	user, flag, err := us.userRepo.GetUserByEmail(ctx, nil, req.Email)
-	if err != nil || !flag {
-		return dto.UpdatePasswordResponse{}, dto.ErrEmailNotFound
-	}
-
 	if len(req.Password) < 8 {
 		return dto.UpdatePasswordResponse{}, dto.ErrInvalidPassword
 	}
 
-	oldPassword := user.Password
-
 	newPassword, err := helpers.HashPassword(req.Password)
 	if err != nil {
 		return dto.UpdatePasswordResponse{}, dto.ErrHashPassword
 	}
 
-	user.Password = newPassword
+	var user entity.User
+	err = us.userRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		token, err := us.oneTimeTokenService.Consume(ctx, tx, req.Token, constants.ENUM_ONE_TIME_TOKEN_RESET_PASSWORD)
+		if err != nil {
+			return err
+		}
+
+		var flag bool
+		user, flag, err = us.userRepo.GetUserByID(ctx, tx, token.UserID.String())
+		if err != nil || !flag {
+			return dto.ErrUserNotFound
+		}
+
+		user.Password = newPassword
+		if _, err := us.userRepo.UpdateUser(ctx, tx, user); err != nil {
+			return dto.ErrUpdateUser
+		}
 
-	_, err = us.userRepo.UpdateUser(ctx, nil, user)
+		// link reset lain yang masih beredar ikut hangus setelah password berubah
+		return us.oneTimeTokenService.InvalidateAll(ctx, tx, user.ID, constants.ENUM_ONE_TIME_TOKEN_RESET_PASSWORD)
+	})
 	if err != nil {
-		return dto.UpdatePasswordResponse{}, dto.ErrUpdateUser
+		return dto.UpdatePasswordResponse{}, err
+	}
+
+	if err := us.refreshTokenService.RevokeAll(ctx, user.ID.String(), constants.ENUM_ROLE_USER); err != nil {
+		return dto.UpdatePasswordResponse{}, err
 	}
 
 	return dto.UpdatePasswordResponse{
-		OldPassword: oldPassword,
-		NewPassword: newPassword,
+		Email: user.Email,
 	}, nil
 }
 
 // Verification Email
-func makeVerificationEmail(receiverEmail string) (map[string]string, error) {
-	expired := time.Now().Add(time.Hour * 24).Format("2006-01-02 15:04:05")
-	plainText := fmt.Sprintf("%s_%s", receiverEmail, expired)
-	token, err := utils.AESEncrypt(plainText)
-	if err != nil {
-		return nil, err
-	}
-
+func makeVerificationEmail(receiverEmail string, token string) (map[string]string, error) {
 	baseURL := os.Getenv("BASE_URL")
 	verifyEmailRoute := "verify-email"
 	if baseURL == "" {
@@ -394,7 +435,16 @@ func (us *UserService) SendVerificationEmail(ctx context.Context, req dto.SendVe
 		return dto.ErrEmailNotFound
 	}
 
-	draftEmail, err := makeVerificationEmail(user.Email)
+	if user.IsVerified != nil && *user.IsVerified {
+		return dto.ErrEmailAlreadyVerified
+	}
+
+	token, err := us.oneTimeTokenService.Issue(ctx, user.ID, constants.ENUM_ONE_TIME_TOKEN_VERIFY_EMAIL)
+	if err != nil {
+		return err
+	}
+
+	draftEmail, err := makeVerificationEmail(user.Email, token)
 	if err != nil {
 		return dto.ErrMakeVerificationEmail
 	}
@@ -406,57 +456,40 @@ func (us *UserService) SendVerificationEmail(ctx context.Context, req dto.SendVe
 	return nil
 }
 func (us *UserService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (dto.VerifyEmailResponse, error) {
-	decryptedToken, err := utils.AESDecrypt(req.Token)
-	if err != nil {
-		return dto.VerifyEmailResponse{}, dto.ErrDecryptToken
-	}
-
-	if !strings.Contains(decryptedToken, "_") {
-		return dto.VerifyEmailResponse{}, dto.ErrTokenInvalid
-	}
-
-	decryptedTokenSplit := strings.Split(decryptedToken, "_")
-	if len(decryptedTokenSplit) != 2 {
-		return dto.VerifyEmailResponse{}, dto.ErrTokenInvalid
-	}
-
-	email := decryptedTokenSplit[0]
-	expired := decryptedTokenSplit[1]
-
-	now := time.Now()
-	expiredTime, err := time.Parse("2006-01-02 15:04:05", expired)
-	if err != nil {
-		return dto.VerifyEmailResponse{}, dto.ErrParsingExpiredTime
-	}
+	var user entity.User
+	err := us.userRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		token, err := us.oneTimeTokenService.Consume(ctx, tx, req.Token, constants.ENUM_ONE_TIME_TOKEN_VERIFY_EMAIL)
+		if err != nil {
+			return err
+		}
 
-	if expiredTime.Sub(now) < 0 {
-		return dto.VerifyEmailResponse{
-			Email:      email,
-			IsVerified: false,
-		}, dto.ErrTokenExpired
-	}
+		var flag bool
+		user, flag, err = us.userRepo.GetUserByID(ctx, tx, token.UserID.String())
+		if err != nil || !flag {
+			return dto.ErrUserNotFound
+		}
 
-	user, flag, err := us.userRepo.GetUserByEmail(ctx, nil, email)
-	if !flag || err != nil {
-		return dto.VerifyEmailResponse{}, dto.ErrUserNotFound
-	}
+		if user.IsVerified != nil && *user.IsVerified {
+			return dto.ErrEmailAlreadyVerified
+		}
 
-	if *user.IsVerified {
-		return dto.VerifyEmailResponse{}, dto.ErrEmailAlreadyVerified
-	}
+		trueValue := true
+		if _, err := us.userRepo.UpdateUser(ctx, tx, entity.User{
+			ID:         user.ID,
+			IsVerified: &trueValue,
+		}); err != nil {
+			return dto.ErrUpdateUser
+		}
 
-	trueValue := true
-	updatedUser, err := us.userRepo.UpdateUser(ctx, nil, entity.User{
-		ID:         user.ID,
-		IsVerified: &trueValue,
+		return nil
 	})
 	if err != nil {
-		return dto.VerifyEmailResponse{}, dto.ErrUpdateUser
+		return dto.VerifyEmailResponse{}, err
 	}
 
 	return dto.VerifyEmailResponse{
-		Email:      email,
-		IsVerified: *updatedUser.IsVerified,
+		Email:      user.Email,
+		IsVerified: true,
 	}, nil
 }
 
@@ -478,7 +511,6 @@ func (us *UserService) GetDetailUser(ctx context.Context) (dto.AllUserResponse,
 		ID:          user.ID,
 		Name:        user.Name,
 		Email:       user.Email,
-		Password:    user.Password,
 		Image:       user.Image,
 		Gender:      user.Gender,
 		Birthdate:   user.Birthdate,
@@ -605,11 +637,17 @@ func (us *UserService) UpdateUser(ctx context.Context, req dto.UpdateUserRequest
 		return dto.AllUserResponse{}, dto.ErrUpdateUser
 	}
 
+	// link verifikasi lama milik email sebelumnya tidak boleh memverifikasi email baru
+	if req.Email != "" {
+		if err := us.oneTimeTokenService.InvalidateAll(ctx, nil, user.ID, constants.ENUM_ONE_TIME_TOKEN_VERIFY_EMAIL); err != nil {
+			return dto.AllUserResponse{}, err
+		}
+	}
+
 	res := dto.AllUserResponse{
 		ID:          updatedUser.ID,
 		Name:        updatedUser.Name,
 		Email:       updatedUser.Email,
-		Password:    updatedUser.Password,
 		Image:       updatedUser.Image,
 		Gender:      updatedUser.Gender,
 		Birthdate:   updatedUser.Birthdate,
@@ -749,6 +787,18 @@ func (us *UserService) CreateConsultation(ctx context.Context, req dto.CreateCon
 		return dto.ConsultationResponse{}, dto.ErrAvailableSlotNotFound
 	}
 
+	// hold yang sudah lewat dilepas dulu agar slot bisa dibooking ulang
+	if a.IsBooked && helpers.IsSlotHoldExpired(a.HeldUntil, time.Now()) {
+		if _, err := us.paymentService.ExpireOverduePayments(ctx); err != nil {
+			return dto.ConsultationResponse{}, err
+		}
+
+		a, flag, err = us.userRepo.GetAvailableSlotByID(ctx, nil, req.AvailableSlotID)
+		if err != nil || !flag {
+			return dto.ConsultationResponse{}, dto.ErrAvailableSlotNotFound
+		}
+	}
+
 	p, flag, err := us.userRepo.GetPracticeByID(ctx, nil, req.PracticeID)
 	if err != nil || !flag {
 		return dto.ConsultationResponse{}, dto.ErrPracticeNotFound
@@ -762,33 +812,94 @@ func (us *UserService) CreateConsultation(ctx context.Context, req dto.CreateCon
 		return dto.ConsultationResponse{}, dto.ErrInvalidPsychologSchedule
 	}
 
+	// slot hasil template sudah bertanggal, tanggal dari request hanya untuk slot lama
+	date := req.Date
+	if a.Date != "" {
+		date = normalizeSlotDate(a.Date)
+	}
+
 	consultation := entity.Consultation{
 		ID:              uuid.New(),
-		Date:            req.Date,
+		Date:            date,
 		Rate:            0,
 		Comment:         "",
-		Status:          0,
+		Status:          constants.ENUM_CONSULTATION_STATUS_REQUESTED,
+		Fee:             p.Fee,
 		UserID:          &u.ID,
 		PracticeID:      &p.ID,
 		AvailableSlotID: &a.ID,
 	}
 
-	err = us.userRepo.CreateConsultation(ctx, nil, consultation)
-	if err != nil {
-		return dto.ConsultationResponse{}, dto.ErrCreateConsultation
+	// consultation berbayar menahan slot sampai pembayaran diterima atau batas hold lewat
+	var (
+		payment   *entity.Payment
+		heldUntil *time.Time
+	)
+	if consultation.Fee > 0 {
+		hold := us.paymentService.HoldUntil(time.Now())
+		consultation.Status = constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT
+		heldUntil = &hold
 	}
 
-	a.IsBooked = true
-	err = us.userRepo.UpdateStatusBookSlot(ctx, nil, a.ID, a.IsBooked)
+	// booking slot dan insert consultation harus atomik, hanya satu request yang menang
+	err = us.userRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		booked, err := us.userRepo.BookAvailableSlot(ctx, tx, a.ID, heldUntil)
+		if err != nil {
+			return dto.ErrUpdateStatusBookSlot
+		}
+
+		if !booked {
+			return dto.ErrConsultationAlreadyBooked
+		}
+
+		// charge baru dibuat setelah slot didapat, request yang kalah tidak meninggalkan charge di gateway
+		if heldUntil != nil {
+			invoice, err := us.paymentService.NewInvoice(ctx, consultation, u, *heldUntil)
+			if err != nil {
+				return err
+			}
+
+			payment = &invoice
+		}
+
+		if err := us.userRepo.CreateConsultation(ctx, tx, consultation); err != nil {
+			return dto.ErrCreateConsultation
+		}
+
+		if payment != nil {
+			if err := us.userRepo.CreatePayment(ctx, tx, *payment); err != nil {
+				return dto.ErrCreatePayment
+			}
+		}
+
+		history := entity.ConsultationStatusHistory{
+			ID:             uuid.New(),
+			ToStatus:       consultation.Status,
+			ChangedByRole:  constants.ENUM_ROLE_USER,
+			ChangedByID:    &u.ID,
+			ConsultationID: &consultation.ID,
+		}
+		if err := us.consultationRepo.CreateConsultationStatusHistory(ctx, tx, history); err != nil {
+			return dto.ErrCreateConsultationStatusHistory
+		}
+
+		return nil
+	})
 	if err != nil {
-		return dto.ConsultationResponse{}, dto.ErrUpdateStatusBookSlot
+		// transaksi batal setelah charge dibuat, charge tidak boleh tetap bisa dibayar
+		if payment != nil {
+			us.paymentService.VoidInvoice(ctx, *payment)
+		}
+
+		return dto.ConsultationResponse{}, err
 	}
 
+	a.IsBooked = true
+
 	user := dto.AllUserResponse{
 		ID:          u.ID,
 		Name:        u.Name,
 		Email:       u.Email,
-		Password:    u.Password,
 		Birthdate:   u.Birthdate,
 		PhoneNumber: u.PhoneNumber,
 		Data01:      u.Data01,
@@ -812,6 +923,7 @@ func (us *UserService) CreateConsultation(ctx context.Context, req dto.CreateCon
 
 	availableSlot := dto.AvailableSlotResponse{
 		ID:       a.ID,
+		Date:     a.Date,
 		Start:    a.Start,
 		End:      a.End,
 		IsBooked: a.IsBooked,
@@ -823,6 +935,7 @@ func (us *UserService) CreateConsultation(ctx context.Context, req dto.CreateCon
 		Name:        p.Name,
 		Address:     p.Address,
 		PhoneNumber: p.PhoneNumber,
+		Fee:         p.Fee,
 	}
 
 	dayName, err := helpers.GetDayName(consultation.Date)
@@ -843,16 +956,24 @@ func (us *UserService) CreateConsultation(ctx context.Context, req dto.CreateCon
 	}
 	practice.PracticeSchedules = matchedSchedules
 
-	return dto.ConsultationResponse{
+	res := dto.ConsultationResponse{
 		ID:            consultation.ID,
 		Date:          consultation.Date,
 		Rate:          consultation.Rate,
 		Comment:       consultation.Comment,
 		Status:        consultation.Status,
+		Fee:           consultation.Fee,
 		User:          user,
 		AvailableSlot: availableSlot,
 		Practice:      practice,
-	}, nil
+	}
+
+	if payment != nil {
+		paymentResponse := toPaymentResponse(*payment)
+		res.Payment = &paymentResponse
+	}
+
+	return res, nil
 }
 func (us *UserService) GetAllConsultationWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.ConsultationPaginationResponseForUser, error) {
 	token := ctx.Value("Authorization").(string)
@@ -884,7 +1005,6 @@ func (us *UserService) GetAllConsultationWithPagination(ctx context.Context, req
 					ID:          user.ID,
 					Name:        user.Name,
 					Email:       user.Email,
-					Password:    user.Password,
 					Birthdate:   user.Birthdate,
 					PhoneNumber: user.PhoneNumber,
 					Data01:      user.Data01,
@@ -920,7 +1040,6 @@ func (us *UserService) GetAllConsultationWithPagination(ctx context.Context, req
 		ID:          dataWithPaginate.Consultations[0].User.ID,
 		Name:        dataWithPaginate.Consultations[0].User.Name,
 		Email:       dataWithPaginate.Consultations[0].User.Email,
-		Password:    dataWithPaginate.Consultations[0].User.Password,
 		Birthdate:   dataWithPaginate.Consultations[0].User.Birthdate,
 		PhoneNumber: dataWithPaginate.Consultations[0].User.PhoneNumber,
 		Data01:      dataWithPaginate.Consultations[0].User.Data01,
@@ -966,12 +1085,12 @@ func (us *UserService) GetAllConsultationWithPagination(ctx context.Context, req
 			Rate:    consultation.Rate,
 			Comment: consultation.Comment,
 			Status:  consultation.Status,
+			Fee:     consultation.Fee,
 			Psycholog: dto.PsychologResponse{
 				ID:          consultation.AvailableSlot.Psycholog.ID,
 				Name:        consultation.AvailableSlot.Psycholog.Name,
 				STRNumber:   consultation.AvailableSlot.Psycholog.STRNumber,
 				Email:       consultation.AvailableSlot.Psycholog.Email,
-				Password:    consultation.AvailableSlot.Psycholog.Password,
 				WorkYear:    consultation.AvailableSlot.Psycholog.WorkYear,
 				Description: consultation.AvailableSlot.Psycholog.Description,
 				PhoneNumber: consultation.AvailableSlot.Psycholog.PhoneNumber,
@@ -992,6 +1111,7 @@ func (us *UserService) GetAllConsultationWithPagination(ctx context.Context, req
 			},
 			AvailableSlot: dto.AvailableSlotResponse{
 				ID:       consultation.AvailableSlot.ID,
+				Date:     consultation.AvailableSlot.Date,
 				Start:    consultation.AvailableSlot.Start,
 				End:      consultation.AvailableSlot.End,
 				IsBooked: consultation.AvailableSlot.IsBooked,
@@ -1002,6 +1122,7 @@ func (us *UserService) GetAllConsultationWithPagination(ctx context.Context, req
 				Name:              consultation.Practice.Name,
 				Address:           consultation.Practice.Address,
 				PhoneNumber:       consultation.Practice.PhoneNumber,
+				Fee:               consultation.Practice.Fee,
 				PracticeSchedules: practiceSchedules,
 			},
 		}
@@ -1081,12 +1202,12 @@ func (us *UserService) GetDetailConsultation(ctx context.Context, consulID strin
 		Rate:    consultation.Rate,
 		Comment: consultation.Comment,
 		Status:  consultation.Status,
+		Fee:     consultation.Fee,
 		Psycholog: dto.PsychologResponse{
 			ID:          consultation.AvailableSlot.Psycholog.ID,
 			Name:        consultation.AvailableSlot.Psycholog.Name,
 			STRNumber:   consultation.AvailableSlot.Psycholog.STRNumber,
 			Email:       consultation.AvailableSlot.Psycholog.Email,
-			Password:    consultation.AvailableSlot.Psycholog.Password,
 			WorkYear:    consultation.AvailableSlot.Psycholog.WorkYear,
 			Description: consultation.AvailableSlot.Psycholog.Description,
 			PhoneNumber: consultation.AvailableSlot.Psycholog.PhoneNumber,
@@ -1107,6 +1228,7 @@ func (us *UserService) GetDetailConsultation(ctx context.Context, consulID strin
 		},
 		AvailableSlot: dto.AvailableSlotResponse{
 			ID:       consultation.AvailableSlot.ID,
+			Date:     consultation.AvailableSlot.Date,
 			Start:    consultation.AvailableSlot.Start,
 			End:      consultation.AvailableSlot.End,
 			IsBooked: consultation.AvailableSlot.IsBooked,
@@ -1117,6 +1239,7 @@ func (us *UserService) GetDetailConsultation(ctx context.Context, consulID strin
 			Name:              consultation.Practice.Name,
 			Address:           consultation.Practice.Address,
 			PhoneNumber:       consultation.Practice.PhoneNumber,
+			Fee:               consultation.Practice.Fee,
 			PracticeSchedules: practiceSchedules,
 		},
 	}
@@ -1152,17 +1275,36 @@ func (us *UserService) GetDetailConsultation(ctx context.Context, consulID strin
 	return data, nil
 }
 func (us *UserService) UpdateConsultation(ctx context.Context, req dto.UpdateConsultationRequestForUser, consulID string) (dto.ConsultationResponseForUser, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := us.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.ConsultationResponseForUser{}, dto.ErrGetUserIDFromToken
+	}
+
 	consul, flag, err := us.userRepo.GetConsultationByID(ctx, nil, consulID)
 	if err != nil || !flag {
 		return dto.ConsultationResponseForUser{}, dto.ErrConsultationNotFound
 	}
 
-	if req.Status != nil {
-		if *req.Status != 1 {
+	if consul.UserID == nil || consul.UserID.String() != userID {
+		return dto.ConsultationResponseForUser{}, dto.ErrDeniedAccess
+	}
+
+	fromStatus := consul.Status
+	statusChanged := false
+	// status yang sama dianggap tidak berubah, kecuali pembatalan ulang yang harus ditolak
+	if req.Status != nil && (*req.Status != consul.Status || helpers.IsConsultationStatusCancellation(*req.Status)) {
+		if !helpers.IsValidConsultationStatus(*req.Status) {
 			return dto.ConsultationResponseForUser{}, dto.ErrInvalidStatusInput
 		}
 
+		if !helpers.CanTransitionConsultationStatus(constants.ENUM_ROLE_USER, consul.Status, *req.Status) {
+			return dto.ConsultationResponseForUser{}, dto.ErrInvalidConsultationTransition
+		}
+
 		consul.Status = *req.Status
+		statusChanged = true
 	}
 
 	if req.Date != "" {
@@ -1174,6 +1316,12 @@ func (us *UserService) UpdateConsultation(ctx context.Context, req dto.UpdateCon
 		consul.Date = date
 	}
 
+	// rate dan comment hanya boleh diberikan untuk consultation yang sudah selesai
+	reviewed := req.Rate != nil || req.Comment != ""
+	if reviewed && consul.Status != constants.ENUM_CONSULTATION_STATUS_COMPLETED {
+		return dto.ConsultationResponseForUser{}, dto.ErrReviewNotAllowed
+	}
+
 	if req.Rate != nil {
 		valid := false
 		switch *req.Rate {
@@ -1192,33 +1340,56 @@ func (us *UserService) UpdateConsultation(ctx context.Context, req dto.UpdateCon
 			return dto.ConsultationResponseForUser{}, dto.ErrConsultationCommentToShort
 		}
 
-		consul.Comment = req.Comment
+		if consul.Rate == 0 {
+			return dto.ConsultationResponseForUser{}, dto.ErrRateRequired
+		}
+	}
+
+	commentChanged := req.Comment != "" && req.Comment != consul.Comment
+	if reviewed {
+		now := time.Now()
+		consul.RatedAt = &now
+		if commentChanged {
+			consul.Comment = req.Comment
+		}
+	}
+
+	// jadwal dan practice menentukan tagihan, tidak bisa diubah selama menunggu pembayaran
+	if (req.AvailableSlotID != "" || req.PracticeID != "") && fromStatus == constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT {
+		return dto.ConsultationResponseForUser{}, dto.ErrConsultationAwaitingPayment
 	}
 
-	if req.AvailableSlotID != "" {
+	// slot lama dan slot baru baru dipindah di dalam transaksi, slot baru dibooking
+	// secara kondisional seperti CreateConsultation
+	var oldSlotID *uuid.UUID
+	rescheduled := false
+	if req.AvailableSlotID != "" && (consul.AvailableSlotID == nil || consul.AvailableSlotID.String() != req.AvailableSlotID) {
+		if helpers.IsConsultationStatusReleasingSlot(consul.Status) || consul.Status == constants.ENUM_CONSULTATION_STATUS_COMPLETED {
+			return dto.ConsultationResponseForUser{}, dto.ErrRescheduleNotAllowed
+		}
+
 		slot, flag, err := us.userRepo.GetAvailableSlotByID(ctx, nil, req.AvailableSlotID)
 		if err != nil || !flag {
 			return dto.ConsultationResponseForUser{}, dto.ErrAvailableSlotNotFound
 		}
 
-		err = us.userRepo.UpdateStatusBookSlot(ctx, nil, *consul.AvailableSlotID, false)
-		if err != nil {
-			return dto.ConsultationResponseForUser{}, dto.ErrUpdateStatusBookSlot
-		}
-
-		slotID, err := uuid.Parse(req.AvailableSlotID)
-		if err != nil {
-			return dto.ConsultationResponseForUser{}, dto.ErrParseUUID
+		if slot.PsychologID == nil || consul.AvailableSlot.PsychologID == nil || *slot.PsychologID != *consul.AvailableSlot.PsychologID {
+			return dto.ConsultationResponseForUser{}, dto.ErrInvalidPsychologSchedule
 		}
 
-		err = us.userRepo.UpdateStatusBookSlot(ctx, nil, slotID, true)
-		if err != nil {
-			return dto.ConsultationResponseForUser{}, dto.ErrUpdateStatusBookSlot
+		if slot.IsBooked {
+			return dto.ConsultationResponseForUser{}, dto.ErrConsultationAlreadyBooked
 		}
 
+		oldSlotID = consul.AvailableSlotID
+		rescheduled = true
 		slot.IsBooked = true
+		slot.Psycholog = consul.AvailableSlot.Psycholog
 		consul.AvailableSlot = slot
-		consul.AvailableSlotID = &slotID
+		consul.AvailableSlotID = &slot.ID
+		if slot.Date != "" {
+			consul.Date = normalizeSlotDate(slot.Date)
+		}
 	}
 
 	if req.PracticeID != "" {
@@ -1227,13 +1398,17 @@ func (us *UserService) UpdateConsultation(ctx context.Context, req dto.UpdateCon
 			return dto.ConsultationResponseForUser{}, dto.ErrPracticeNotFound
 		}
 
-		pracID, err := uuid.Parse(req.PracticeID)
-		if err != nil {
-			return dto.ConsultationResponseForUser{}, dto.ErrParseUUID
+		if prac.PsychologID == nil || consul.AvailableSlot.PsychologID == nil || *prac.PsychologID != *consul.AvailableSlot.PsychologID {
+			return dto.ConsultationResponseForUser{}, dto.ErrInvalidPsychologSchedule
+		}
+
+		// tarif dikunci saat booking, pindah ke practice dengan tarif lain harus booking ulang
+		if prac.Fee != consul.Fee {
+			return dto.ConsultationResponseForUser{}, dto.ErrPracticeFeeChanged
 		}
 
 		consul.Practice = prac
-		consul.PracticeID = &pracID
+		consul.PracticeID = &prac.ID
 	}
 
 	dayName, err := helpers.GetDayName(consul.Date)
@@ -1259,12 +1434,12 @@ func (us *UserService) UpdateConsultation(ctx context.Context, req dto.UpdateCon
 		Rate:    consul.Rate,
 		Comment: consul.Comment,
 		Status:  consul.Status,
+		Fee:     consul.Fee,
 		Psycholog: dto.PsychologResponse{
 			ID:          consul.AvailableSlot.Psycholog.ID,
 			Name:        consul.AvailableSlot.Psycholog.Name,
 			STRNumber:   consul.AvailableSlot.Psycholog.STRNumber,
 			Email:       consul.AvailableSlot.Psycholog.Email,
-			Password:    consul.AvailableSlot.Psycholog.Password,
 			WorkYear:    consul.AvailableSlot.Psycholog.WorkYear,
 			Description: consul.AvailableSlot.Psycholog.Description,
 			PhoneNumber: consul.AvailableSlot.Psycholog.PhoneNumber,
@@ -1285,6 +1460,7 @@ func (us *UserService) UpdateConsultation(ctx context.Context, req dto.UpdateCon
 		},
 		AvailableSlot: dto.AvailableSlotResponse{
 			ID:       consul.AvailableSlot.ID,
+			Date:     consul.AvailableSlot.Date,
 			Start:    consul.AvailableSlot.Start,
 			End:      consul.AvailableSlot.End,
 			IsBooked: consul.AvailableSlot.IsBooked,
@@ -1295,6 +1471,7 @@ func (us *UserService) UpdateConsultation(ctx context.Context, req dto.UpdateCon
 			Name:              consul.Practice.Name,
 			Address:           consul.Practice.Address,
 			PhoneNumber:       consul.Practice.PhoneNumber,
+			Fee:               consul.Practice.Fee,
 			PracticeSchedules: practiceSchedules,
 		},
 	}
@@ -1327,121 +1504,155 @@ func (us *UserService) UpdateConsultation(ctx context.Context, req dto.UpdateCon
 		})
 	}
 
-	err = us.userRepo.UpdateConsultation(ctx, nil, consul)
+	var cancellation *CancellationOutcome
+	err = us.userRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if rescheduled {
+			booked, err := us.userRepo.BookAvailableSlot(ctx, tx, *consul.AvailableSlotID, nil)
+			if err != nil {
+				return dto.ErrUpdateStatusBookSlot
+			}
+
+			if !booked {
+				return dto.ErrConsultationAlreadyBooked
+			}
+
+			if oldSlotID != nil {
+				if err := us.userRepo.UpdateStatusBookSlot(ctx, tx, *oldSlotID, false); err != nil {
+					return dto.ErrUpdateStatusBookSlot
+				}
+			}
+		}
+
+		if err := us.userRepo.UpdateConsultation(ctx, tx, consul); err != nil {
+			return dto.ErrUpdateConsultation
+		}
+
+		if commentChanged {
+			if err := us.userRepo.ResetReviewModeration(ctx, tx, consul.ID); err != nil {
+				return dto.ErrUpdateConsultation
+			}
+		}
+
+		if reviewed && consul.AvailableSlot.PsychologID != nil {
+			if err := us.userRepo.RecalculatePsychologRating(ctx, tx, *consul.AvailableSlot.PsychologID); err != nil {
+				return dto.ErrRecalculatePsychologRating
+			}
+		}
+
+		if !statusChanged {
+			return nil
+		}
+
+		changed, err := us.consultationRepo.UpdateConsultationStatus(ctx, tx, consul.ID, fromStatus, consul.Status)
+		if err != nil {
+			return dto.ErrUpdateConsultation
+		}
+
+		if !changed {
+			return dto.ErrConsultationStatusChanged
+		}
+
+		if fromStatus == constants.ENUM_CONSULTATION_STATUS_AWAITING_PAYMENT {
+			if err := us.paymentService.CancelPendingPayment(ctx, tx, consul.ID); err != nil {
+				return err
+			}
+		}
+
+		if helpers.IsConsultationStatusReleasingSlot(consul.Status) && consul.AvailableSlotID != nil {
+			if err := us.userRepo.UpdateStatusBookSlot(ctx, tx, *consul.AvailableSlotID, false); err != nil {
+				return dto.ErrUpdateStatusBookSlot
+			}
+		}
+
+		history := entity.ConsultationStatusHistory{
+			ID:             uuid.New(),
+			FromStatus:     &fromStatus,
+			ToStatus:       consul.Status,
+			ChangedByRole:  constants.ENUM_ROLE_USER,
+			ChangedByID:    consul.UserID,
+			Note:           req.StatusNote,
+			ConsultationID: &consul.ID,
+		}
+		if err := us.consultationRepo.CreateConsultationStatusHistory(ctx, tx, history); err != nil {
+			return dto.ErrCreateConsultationStatusHistory
+		}
+
+		if helpers.IsConsultationStatusCancellation(consul.Status) {
+			outcome, err := us.cancellationService.Apply(ctx, tx, consul, consul.Status)
+			if err != nil {
+				return err
+			}
+
+			cancellation = &outcome
+		}
+
+		return nil
+	})
 	if err != nil {
-		return dto.ConsultationResponseForUser{}, dto.ErrUpdateConsultation
+		return dto.ConsultationResponseForUser{}, err
+	}
+
+	if cancellation != nil {
+		res := us.cancellationService.Settle(ctx, consul, *cancellation)
+		data.Cancellation = &res
 	}
 
 	return data, nil
 }
-func (us *UserService) DeleteConsultation(ctx context.Context, consulID string) (dto.ConsultationResponseForUser, error) {
-	deletedConsul, flag, err := us.userRepo.GetConsultationByID(ctx, nil, consulID)
+func (us *UserService) GetConsultationStatusHistory(ctx context.Context, consulID string) ([]dto.ConsultationStatusHistoryResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := us.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return nil, dto.ErrGetUserIDFromToken
+	}
+
+	consul, flag, err := us.userRepo.GetConsultationByID(ctx, nil, consulID)
 	if err != nil || !flag {
-		return dto.ConsultationResponseForUser{}, dto.ErrConsultationNotFound
+		return nil, dto.ErrConsultationNotFound
 	}
 
-	err = us.userRepo.UpdateStatusBookSlot(ctx, nil, *deletedConsul.AvailableSlotID, false)
-	if err != nil {
-		return dto.ConsultationResponseForUser{}, dto.ErrUpdateStatusBookSlot
+	if consul.UserID == nil || consul.UserID.String() != userID {
+		return nil, dto.ErrDeniedAccess
 	}
 
-	err = us.userRepo.DeleteConsultation(ctx, nil, consulID)
+	histories, err := us.consultationRepo.GetConsultationStatusHistory(ctx, nil, consulID)
 	if err != nil {
-		return dto.ConsultationResponseForUser{}, dto.ErrDeleteConsultation
+		return nil, dto.ErrGetConsultationStatusHistory
 	}
 
-	dayName, err := helpers.GetDayName(deletedConsul.Date)
+	return toConsultationStatusHistoryResponses(histories), nil
+}
+func (us *UserService) GetConsultationPayment(ctx context.Context, consulID string) (dto.PaymentResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := us.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.ConsultationResponseForUser{}, dto.ErrParseConsultationDate
+		return dto.PaymentResponse{}, dto.ErrGetUserIDFromToken
 	}
 
-	var practiceSchedules []dto.PracticeScheduleResponse
-	for _, pracSch := range deletedConsul.Practice.PracticeSchedules {
-		if dayName == pracSch.Day {
-			practiceSchedules = append(practiceSchedules, dto.PracticeScheduleResponse{
-				ID:    pracSch.ID,
-				Day:   pracSch.Day,
-				Open:  pracSch.Open,
-				Close: pracSch.Close,
-			})
-		}
+	consul, flag, err := us.userRepo.GetConsultationByID(ctx, nil, consulID)
+	if err != nil || !flag {
+		return dto.PaymentResponse{}, dto.ErrConsultationNotFound
 	}
 
-	res := dto.ConsultationResponseForUser{
-		ID:      deletedConsul.ID,
-		Date:    deletedConsul.Date,
-		Rate:    deletedConsul.Rate,
-		Comment: deletedConsul.Comment,
-		Status:  deletedConsul.Status,
-		Psycholog: dto.PsychologResponse{
-			ID:          deletedConsul.AvailableSlot.Psycholog.ID,
-			Name:        deletedConsul.AvailableSlot.Psycholog.Name,
-			STRNumber:   deletedConsul.AvailableSlot.Psycholog.STRNumber,
-			Email:       deletedConsul.AvailableSlot.Psycholog.Email,
-			Password:    deletedConsul.AvailableSlot.Psycholog.Password,
-			WorkYear:    deletedConsul.AvailableSlot.Psycholog.WorkYear,
-			Description: deletedConsul.AvailableSlot.Psycholog.Description,
-			PhoneNumber: deletedConsul.AvailableSlot.Psycholog.PhoneNumber,
-			Image:       deletedConsul.AvailableSlot.Psycholog.Image,
-			City: dto.CityResponse{
-				ID:   deletedConsul.AvailableSlot.Psycholog.CityID,
-				Name: deletedConsul.AvailableSlot.Psycholog.City.Name,
-				Type: deletedConsul.AvailableSlot.Psycholog.City.Type,
-				Province: dto.ProvinceResponse{
-					ID:   deletedConsul.AvailableSlot.Psycholog.City.ProvinceID,
-					Name: deletedConsul.AvailableSlot.Psycholog.City.Province.Name,
-				},
-			},
-			Role: dto.RoleResponse{
-				ID:   deletedConsul.AvailableSlot.Psycholog.RoleID,
-				Name: deletedConsul.AvailableSlot.Psycholog.Role.Name,
-			},
-		},
-		AvailableSlot: dto.AvailableSlotResponse{
-			ID:       deletedConsul.AvailableSlot.ID,
-			Start:    deletedConsul.AvailableSlot.Start,
-			End:      deletedConsul.AvailableSlot.End,
-			IsBooked: deletedConsul.AvailableSlot.IsBooked,
-		},
-		Practice: dto.PracticeResponse{
-			ID:                deletedConsul.Practice.ID,
-			Type:              deletedConsul.Practice.Type,
-			Name:              deletedConsul.Practice.Name,
-			Address:           deletedConsul.Practice.Address,
-			PhoneNumber:       deletedConsul.Practice.PhoneNumber,
-			PracticeSchedules: practiceSchedules,
-		},
+	if consul.UserID == nil || consul.UserID.String() != userID {
+		return dto.PaymentResponse{}, dto.ErrDeniedAccess
 	}
 
-	// LanguageMasters
-	for _, lang := range deletedConsul.AvailableSlot.Psycholog.PsychologLanguages {
-		res.Psycholog.LanguageMasters = append(res.Psycholog.LanguageMasters, dto.LanguageMasterResponse{
-			ID:   &lang.LanguageMaster.ID,
-			Name: lang.LanguageMaster.Name,
-		})
+	payment, flag, err := us.userRepo.GetLatestPaymentByConsultationID(ctx, nil, consulID)
+	if err != nil || !flag {
+		return dto.PaymentResponse{}, dto.ErrPaymentNotFound
 	}
 
-	// Specializations
-	for _, spec := range deletedConsul.AvailableSlot.Psycholog.PsychologSpecializations {
-		res.Psycholog.Specializations = append(res.Psycholog.Specializations, dto.SpecializationResponse{
-			ID:          &spec.Specialization.ID,
-			Name:        spec.Specialization.Name,
-			Description: spec.Specialization.Description,
-		})
-	}
-
-	// Educations
-	for _, edu := range deletedConsul.AvailableSlot.Psycholog.Educations {
-		res.Psycholog.Educations = append(res.Psycholog.Educations, dto.EducationResponse{
-			ID:             &edu.ID,
-			Degree:         edu.Degree,
-			Major:          edu.Major,
-			Institution:    edu.Institution,
-			GraduationYear: edu.GraduationYear,
-		})
-	}
+	return toPaymentResponse(payment), nil
+}
 
-	return res, nil
+// DeleteConsultation tidak menghapus data, consultation dibatalkan oleh user agar
+// kebijakan pembatalan dan refund tetap berlaku
+func (us *UserService) DeleteConsultation(ctx context.Context, consulID string) (dto.ConsultationResponseForUser, error) {
+	status := constants.ENUM_CONSULTATION_STATUS_CANCELED_BY_USER
+	return us.UpdateConsultation(ctx, dto.UpdateConsultationRequestForUser{Status: &status}, consulID)
 }
 
 // Psycholog
@@ -1458,8 +1669,8 @@ func (us *UserService) GetAllPsycholog(ctx context.Context, filter dto.Psycholog
 			Name:        psycholog.Name,
 			STRNumber:   psycholog.STRNumber,
 			Email:       psycholog.Email,
-			Password:    psycholog.Password,
 			WorkYear:    psycholog.WorkYear,
+			Gender:      psycholog.Gender,
 			Description: psycholog.Description,
 			PhoneNumber: psycholog.PhoneNumber,
 			Image:       psycholog.Image,
@@ -1476,6 +1687,8 @@ func (us *UserService) GetAllPsycholog(ctx context.Context, filter dto.Psycholog
 				ID:   psycholog.RoleID,
 				Name: psycholog.Role.Name,
 			},
+			RatingAverage: psycholog.RatingAverage,
+			RatingCount:   psycholog.RatingCount,
 		}
 
 		// LanguageMasters
@@ -1522,8 +1735,8 @@ func (us *UserService) GetDetailPsycholog(ctx context.Context, psyID string) (dt
 		Name:        psy.Name,
 		STRNumber:   psy.STRNumber,
 		Email:       psy.Email,
-		Password:    psy.Password,
 		WorkYear:    psy.WorkYear,
+		Gender:      psy.Gender,
 		Description: psy.Description,
 		PhoneNumber: psy.PhoneNumber,
 		Image:       psy.Image,
@@ -1540,6 +1753,8 @@ func (us *UserService) GetDetailPsycholog(ctx context.Context, psyID string) (dt
 			ID:   psy.RoleID,
 			Name: psy.Role.Name,
 		},
+		RatingAverage: psy.RatingAverage,
+		RatingCount:   psy.RatingCount,
 	}
 
 	// LanguageMasters
@@ -1570,8 +1785,260 @@ func (us *UserService) GetDetailPsycholog(ctx context.Context, psyID string) (dt
 		})
 	}
 
+	// RecentReviews
+	reviews, err := us.userRepo.GetAllReviewByPsychologIDWithPagination(ctx, nil, dto.PaginationRequest{
+		Page:    1,
+		PerPage: constants.ENUM_RECENT_REVIEW_LIMIT,
+	}, psyID)
+	if err != nil {
+		return dto.PsychologResponse{}, dto.ErrGetAllReview
+	}
+
+	psycholog.RecentReviews = toReviewResponses(reviews.Consultations)
+
 	return psycholog, nil
 }
+func (us *UserService) GetAllPsychologReviewWithPagination(ctx context.Context, req dto.PaginationRequest, psyID string) (dto.ReviewPaginationResponse, error) {
+	if _, flag, err := us.userRepo.GetPsychologByID(ctx, nil, psyID); err != nil || !flag {
+		return dto.ReviewPaginationResponse{}, dto.ErrPsychologNotFound
+	}
+
+	dataWithPaginate, err := us.userRepo.GetAllReviewByPsychologIDWithPagination(ctx, nil, req, psyID)
+	if err != nil {
+		return dto.ReviewPaginationResponse{}, dto.ErrGetAllReview
+	}
+
+	return dto.ReviewPaginationResponse{
+		Data: toReviewResponses(dataWithPaginate.Consultations),
+		PaginationResponse: dto.PaginationResponse{
+			Page:    dataWithPaginate.Page,
+			PerPage: dataWithPaginate.PerPage,
+			MaxPage: dataWithPaginate.MaxPage,
+			Count:   dataWithPaginate.Count,
+		},
+	}, nil
+}
+func (us *UserService) SearchPsychologWithPagination(ctx context.Context, req dto.PsychologSearchRequest) (dto.PsychologSearchPaginationResponse, error) {
+	switch req.Sort {
+	case "":
+		req.Sort = constants.ENUM_PSYCHOLOG_SORT_NEWEST
+	case constants.ENUM_PSYCHOLOG_SORT_NEWEST,
+		constants.ENUM_PSYCHOLOG_SORT_RATING,
+		constants.ENUM_PSYCHOLOG_SORT_EXPERIENCE,
+		constants.ENUM_PSYCHOLOG_SORT_SOONEST_SLOT:
+	default:
+		return dto.PsychologSearchPaginationResponse{}, dto.ErrInvalidPsychologSort
+	}
+
+	// filter tipe praktik memakai kode singkat, di database tersimpan nama lengkapnya
+	practiceTypes := make([]string, 0, len(req.PracticeTypes))
+	for _, practiceType := range req.PracticeTypes {
+		value, ok := practiceTypeFilters[practiceType]
+		if !ok {
+			return dto.PsychologSearchPaginationResponse{}, dto.ErrInvalidPracticeTypeFilter
+		}
+
+		practiceTypes = append(practiceTypes, value)
+	}
+	req.PracticeTypes = practiceTypes
+
+	if (req.MinFee != nil && *req.MinFee < 0) ||
+		(req.MaxFee != nil && *req.MaxFee < 0) ||
+		(req.MinFee != nil && req.MaxFee != nil && *req.MinFee > *req.MaxFee) {
+		return dto.PsychologSearchPaginationResponse{}, dto.ErrInvalidFeeRange
+	}
+
+	if req.AvailableWithin < 0 || req.AvailableWithin > constants.ENUM_SEARCH_MAX_AVAILABLE_WITHIN {
+		return dto.PsychologSearchPaginationResponse{}, dto.ErrInvalidAvailableWithin
+	}
+
+	ids := append(append([]string{}, req.LanguageIDs...), req.SpecializationIDs...)
+	if req.CityID != "" {
+		ids = append(ids, req.CityID)
+	}
+	if req.ProvinceID != "" {
+		ids = append(ids, req.ProvinceID)
+	}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return dto.PsychologSearchPaginationResponse{}, dto.ErrParseUUID
+		}
+	}
+
+	dataWithPaginate, err := us.userRepo.SearchPsychologWithPagination(ctx, nil, req)
+	if err != nil {
+		return dto.PsychologSearchPaginationResponse{}, dto.ErrSearchPsycholog
+	}
+
+	nextSlots := make(map[uuid.UUID]entity.AvailableSlot)
+	for _, slot := range dataWithPaginate.NextSlots {
+		if slot.PsychologID != nil {
+			nextSlots[*slot.PsychologID] = slot
+		}
+	}
+
+	datas := []dto.PsychologSearchResponse{}
+	for _, psycholog := range dataWithPaginate.Psychologs {
+		data := dto.PsychologSearchResponse{
+			PsychologResponse: dto.PsychologResponse{
+				ID:          psycholog.ID,
+				Name:        psycholog.Name,
+				STRNumber:   psycholog.STRNumber,
+				Email:       psycholog.Email,
+				WorkYear:    psycholog.WorkYear,
+				Gender:      psycholog.Gender,
+				Description: psycholog.Description,
+				PhoneNumber: psycholog.PhoneNumber,
+				Image:       psycholog.Image,
+				City: dto.CityResponse{
+					ID:   psycholog.CityID,
+					Name: psycholog.City.Name,
+					Type: psycholog.City.Type,
+					Province: dto.ProvinceResponse{
+						ID:   psycholog.City.ProvinceID,
+						Name: psycholog.City.Province.Name,
+					},
+				},
+				Role: dto.RoleResponse{
+					ID:   psycholog.RoleID,
+					Name: psycholog.Role.Name,
+				},
+				RatingAverage: psycholog.RatingAverage,
+				RatingCount:   psycholog.RatingCount,
+			},
+		}
+
+		// LanguageMasters
+		for _, lang := range psycholog.PsychologLanguages {
+			data.LanguageMasters = append(data.LanguageMasters, dto.LanguageMasterResponse{
+				ID:   &lang.LanguageMaster.ID,
+				Name: lang.LanguageMaster.Name,
+			})
+		}
+
+		// Specializations
+		for _, spec := range psycholog.PsychologSpecializations {
+			data.Specializations = append(data.Specializations, dto.SpecializationResponse{
+				ID:          &spec.Specialization.ID,
+				Name:        spec.Specialization.Name,
+				Description: spec.Specialization.Description,
+			})
+		}
+
+		// Educations
+		for _, edu := range psycholog.Educations {
+			data.Educations = append(data.Educations, dto.EducationResponse{
+				ID:             &edu.ID,
+				Degree:         edu.Degree,
+				Major:          edu.Major,
+				Institution:    edu.Institution,
+				GraduationYear: edu.GraduationYear,
+			})
+		}
+
+		// MinFee
+		for _, prac := range psycholog.Practices {
+			if data.MinFee == nil || prac.Fee < *data.MinFee {
+				fee := prac.Fee
+				data.MinFee = &fee
+			}
+		}
+
+		// NextSlot
+		if slot, ok := nextSlots[psycholog.ID]; ok {
+			data.NextSlot = &dto.AvailableSlotResponse{
+				ID:       slot.ID,
+				Date:     slot.Date,
+				Start:    slot.Start,
+				End:      slot.End,
+				IsBooked: slot.IsBooked,
+			}
+		}
+
+		datas = append(datas, data)
+	}
+
+	facets := dataWithPaginate.Facets
+	for i, facet := range facets.PracticeTypes {
+		for code, value := range practiceTypeFilters {
+			if facet.Label == value {
+				facets.PracticeTypes[i].Value = code
+			}
+		}
+	}
+	for i, facet := range facets.Genders {
+		facets.Genders[i].Label = "Laki-laki"
+		if facet.Value == "true" {
+			facets.Genders[i].Label = "Perempuan"
+		}
+	}
+
+	return dto.PsychologSearchPaginationResponse{
+		Data: datas,
+		PsychologSearchMeta: dto.PsychologSearchMeta{
+			Facets: facets,
+			PaginationResponse: dto.PaginationResponse{
+				Page:    dataWithPaginate.Page,
+				PerPage: dataWithPaginate.PerPage,
+				MaxPage: dataWithPaginate.MaxPage,
+				Count:   dataWithPaginate.Count,
+			},
+		},
+	}, nil
+}
+func (us *UserService) SearchWithPagination(ctx context.Context, req dto.SearchRequest) (dto.SearchPaginationResponse, error) {
+	req.Search = strings.TrimSpace(req.Search)
+	if req.Search == "" {
+		return dto.SearchPaginationResponse{}, dto.ErrSearchQueryRequired
+	}
+
+	if len(req.Types) == 0 {
+		req.Types = []string{
+			constants.ENUM_SEARCH_TYPE_NEWS,
+			constants.ENUM_SEARCH_TYPE_MOTIVATION,
+			constants.ENUM_SEARCH_TYPE_PSYCHOLOG,
+		}
+	}
+
+	for _, searchType := range req.Types {
+		switch searchType {
+		case constants.ENUM_SEARCH_TYPE_NEWS,
+			constants.ENUM_SEARCH_TYPE_MOTIVATION,
+			constants.ENUM_SEARCH_TYPE_PSYCHOLOG:
+		default:
+			return dto.SearchPaginationResponse{}, dto.ErrInvalidSearchType
+		}
+	}
+
+	dataWithPaginate, err := us.userRepo.SearchWithPagination(ctx, nil, req)
+	if err != nil {
+		return dto.SearchPaginationResponse{}, dto.ErrSearch
+	}
+
+	datas := []dto.SearchResultResponse{}
+	for _, result := range dataWithPaginate.Results {
+		result.Snippet = helpers.HighlightSnippet(result.Snippet)
+		datas = append(datas, result)
+	}
+
+	counts := []dto.SearchCountResponse{}
+	if dataWithPaginate.Counts != nil {
+		counts = dataWithPaginate.Counts
+	}
+
+	return dto.SearchPaginationResponse{
+		Data: datas,
+		SearchMeta: dto.SearchMeta{
+			Counts: counts,
+			PaginationResponse: dto.PaginationResponse{
+				Page:    dataWithPaginate.Page,
+				PerPage: dataWithPaginate.PerPage,
+				MaxPage: dataWithPaginate.MaxPage,
+				Count:   dataWithPaginate.Count,
+			},
+		},
+	}, nil
+}
 
 // Practice
 func (us *UserService) GetAllPractice(ctx context.Context, psyID string) ([]dto.PracticeResponse, error) {
@@ -1592,6 +2059,7 @@ func (us *UserService) GetAllPractice(ctx context.Context, psyID string) ([]dto.
 			Name:        practice.Name,
 			Address:     practice.Address,
 			PhoneNumber: practice.PhoneNumber,
+			Fee:         practice.Fee,
 		}
 
 		// PracticeSchedule
@@ -1617,13 +2085,17 @@ func (us *UserService) GetAllAvailableSlot(ctx context.Context, psyID string) ([
 		return []dto.AvailableSlotResponse{}, dto.ErrGetAllAvailableSlot
 	}
 
+	now := time.Now()
+
 	var availableSlots []dto.AvailableSlotResponse
 	for _, availableSlot := range datas.AvailableSlots {
+		// slot dengan hold pembayaran yang sudah lewat bisa dibooking lagi
 		data := dto.AvailableSlotResponse{
 			ID:       availableSlot.ID,
+			Date:     availableSlot.Date,
 			Start:    availableSlot.Start,
 			End:      availableSlot.End,
-			IsBooked: availableSlot.IsBooked,
+			IsBooked: availableSlot.IsBooked && !helpers.IsSlotHoldExpired(availableSlot.HeldUntil, now),
 		}
 
 		availableSlots = append(availableSlots, data)
@@ -1685,7 +2157,6 @@ func (us *UserService) CreateNewsDetail(ctx context.Context, req dto.CreateNewsD
 			ID:          newsDetail.User.ID,
 			Name:        newsDetail.User.Name,
 			Email:       newsDetail.User.Email,
-			Password:    newsDetail.User.Password,
 			Image:       newsDetail.User.Image,
 			Gender:      newsDetail.User.Gender,
 			Birthdate:   newsDetail.User.Birthdate,
@@ -1850,31 +2321,175 @@ func (us *UserService) GetAllUserMotivation(ctx context.Context) ([]dto.UserMoti
 
 // Chat
 func (us *UserService) HandleChat(ctx context.Context, req dto.ChatRequest) (dto.ChatResponse, error) {
+	convo, err := us.startChat(ctx, req)
+	if err != nil {
+		return dto.ChatResponse{}, err
+	}
+	convoID := convo.ID
+
+	// pesan krisis tidak diteruskan ke model, termasuk untuk meringkas riwayat,
+	// dan langsung dijawab dengan respons baku
+	if isCrisis, keyword := helpers.DetectCrisisLanguage(req.Message); isCrisis {
+		us.recordChatUsage(ctx, 1, nil, "")
+		return us.respondToCrisis(ctx, convoID, "user_message: "+keyword)
+	}
+
+	chatHistory, err := us.buildChatHistory(ctx, convo)
+	if err != nil {
+		return dto.ChatResponse{}, err
+	}
+
+	suggestions := us.retrieveChatSuggestions(ctx, req.Message)
+	chatHistory = withChatSuggestions(chatHistory, suggestions)
+
+	replyRaw, err := us.llmClient.Chat(ctx, chatHistory)
+	if err != nil {
+		return dto.ChatResponse{}, dto.ErrGetChatGPTResponse
+	}
+	us.recordChatUsage(ctx, 1, chatHistory, replyRaw)
+
+	if isCrisis, keyword := helpers.DetectCrisisLanguage(replyRaw); isCrisis {
+		return us.respondToCrisis(ctx, convoID, "assistant_reply: "+keyword)
+	}
+
+	reply := helpers.StripMarkdown(replyRaw)
+	if err := us.saveAssistantMessage(ctx, convoID, reply); err != nil {
+		return dto.ChatResponse{}, err
+	}
+
+	return dto.ChatResponse{
+		Response:       reply,
+		ConversationID: convoID,
+		Suggestions:    suggestions,
+	}, nil
+}
+
+// HandleChatStream mengirim balasan model per delta lewat event message. Respons krisis
+// dikirim utuh lewat event replace agar client membuang teks yang sudah tampil.
+func (us *UserService) HandleChatStream(ctx context.Context, req dto.ChatRequest, onEvent func(event string, text string) error) (dto.ChatResponse, error) {
+	convo, err := us.startChat(ctx, req)
+	if err != nil {
+		return dto.ChatResponse{}, err
+	}
+	convoID := convo.ID
+
+	if isCrisis, keyword := helpers.DetectCrisisLanguage(req.Message); isCrisis {
+		us.recordChatUsage(ctx, 1, nil, "")
+		res, err := us.respondToCrisis(ctx, convoID, "user_message: "+keyword)
+		if err != nil {
+			return res, err
+		}
+
+		return res, onEvent(constants.ENUM_CHAT_STREAM_EVENT_REPLACE, res.Response)
+	}
+
+	chatHistory, err := us.buildChatHistory(ctx, convo)
+	if err != nil {
+		return dto.ChatResponse{ConversationID: convoID}, err
+	}
+
+	suggestions := us.retrieveChatSuggestions(ctx, req.Message)
+	chatHistory = withChatSuggestions(chatHistory, suggestions)
+
+	// balasan model diperiksa sebelum setiap delta dikirim, frasa krisis tidak pernah
+	// sampai ke client dan stream langsung dihentikan
+	var (
+		guard         helpers.CrisisStreamGuard
+		crisisKeyword string
+	)
+	replyRaw, streamErr := us.llmClient.ChatStream(ctx, chatHistory, func(delta string) error {
+		safe, isCrisis, keyword := guard.Write(delta)
+		if isCrisis {
+			crisisKeyword = keyword
+			return errChatStreamCrisis
+		}
+
+		if safe == "" {
+			return nil
+		}
+
+		return onEvent(constants.ENUM_CHAT_STREAM_EVENT_MESSAGE, safe)
+	})
+	us.recordChatUsage(context.WithoutCancel(ctx), 1, chatHistory, replyRaw)
+
+	if crisisKeyword != "" {
+		res, err := us.respondToCrisis(context.WithoutCancel(ctx), convoID, "assistant_reply: "+crisisKeyword)
+		if err != nil {
+			return res, err
+		}
+
+		return res, onEvent(constants.ENUM_CHAT_STREAM_EVENT_REPLACE, res.Response)
+	}
+
+	if streamErr != nil && replyRaw == "" {
+		return dto.ChatResponse{ConversationID: convoID}, dto.ErrGetChatGPTResponse
+	}
+
+	// kata terakhir yang ditahan guard dilepas setelah stream selesai
+	if tail := guard.Flush(); tail != "" && streamErr == nil {
+		streamErr = onEvent(constants.ENUM_CHAT_STREAM_EVENT_MESSAGE, tail)
+	}
+
+	// client bisa disconnect di tengah stream, balasan parsial tetap disimpan
+	// memakai context yang tidak ikut dibatalkan
+	reply := helpers.StripMarkdown(replyRaw)
+	if err := us.saveAssistantMessage(context.WithoutCancel(ctx), convoID, reply); err != nil {
+		return dto.ChatResponse{ConversationID: convoID}, err
+	}
+
+	res := dto.ChatResponse{
+		Response:       reply,
+		ConversationID: convoID,
+		Suggestions:    suggestions,
+	}
+
+	if streamErr != nil {
+		return res, dto.ErrChatStreamInterrupted
+	}
+
+	return res, nil
+}
+
+// startChat memastikan conversation milik user dan menyimpan pesan user, tanpa
+// memanggil model. Riwayat untuk model disusun terpisah di buildChatHistory.
+func (us *UserService) startChat(ctx context.Context, req dto.ChatRequest) (entity.Conversation, error) {
 	tokenRaw := ctx.Value("Authorization")
 	token, ok := tokenRaw.(string)
 	if !ok {
-		return dto.ChatResponse{}, dto.ErrInvalidToken
+		return entity.Conversation{}, dto.ErrInvalidToken
 	}
 
 	userID, err := us.jwtService.GetUserIDByToken(token)
 	if err != nil {
-		return dto.ChatResponse{}, dto.ErrGetUserIDFromToken
+		return entity.Conversation{}, dto.ErrGetUserIDFromToken
 	}
 	uID, err := uuid.Parse(userID)
 	if err != nil {
-		return dto.ChatResponse{}, dto.ErrParseUUID
+		return entity.Conversation{}, dto.ErrParseUUID
 	}
 
+	var convo entity.Conversation
 	convoID := req.ConversationID
 	if convoID == uuid.Nil {
-		convo := entity.Conversation{
+		convo = entity.Conversation{
 			ID:     uuid.New(),
+			Title:  helpers.GenerateConversationTitle(req.Message),
 			UserID: &uID,
 		}
 		if err := us.userRepo.CreateConversation(ctx, nil, convo); err != nil {
-			return dto.ChatResponse{}, dto.ErrCreateConversation
+			return entity.Conversation{}, dto.ErrCreateConversation
 		}
 		convoID = convo.ID
+	} else {
+		var flag bool
+		convo, flag, err = us.userRepo.GetConversationByID(ctx, nil, convoID.String())
+		if err != nil || !flag {
+			return entity.Conversation{}, dto.ErrConversationNotFound
+		}
+
+		if convo.UserID == nil || *convo.UserID != uID {
+			return entity.Conversation{}, dto.ErrDeniedAccess
+		}
 	}
 
 	userMsg := entity.Message{
@@ -1884,29 +2499,55 @@ func (us *UserService) HandleChat(ctx context.Context, req dto.ChatRequest) (dto
 		Content:        req.Message,
 	}
 	if err := us.userRepo.SaveMessage(ctx, nil, userMsg); err != nil {
-		return dto.ChatResponse{}, dto.ErrSaveMessage
+		return entity.Conversation{}, dto.ErrSaveMessage
 	}
 
+	return convo, nil
+}
+func (us *UserService) buildChatHistory(ctx context.Context, convo entity.Conversation) ([]utils.ChatMessage, error) {
+	convoID := convo.ID
+
 	messages, err := us.userRepo.GetMessagesByConversationID(ctx, convoID)
 	if err != nil {
-		return dto.ChatResponse{}, dto.ErrGetMessages
+		return nil, dto.ErrGetMessages
 	}
 
-	var chatHistory []map[string]string
-	for _, m := range messages {
-		chatHistory = append(chatHistory, map[string]string{
-			"role":    m.Sender,
-			"content": m.Content,
-		})
+	systemPrompt, err := readChatTemplate("CHAT_SYSTEM_PROMPT_PATH", "utils/chat_template/system_prompt.txt")
+	if err != nil {
+		return nil, dto.ErrLoadChatTemplate
 	}
 
-	replyRaw, err := utils.GetChatGPTResponse(ctx, chatHistory)
-	if err != nil {
-		return dto.ChatResponse{}, dto.ErrGetChatGPTResponse
+	// pesan yang sudah tercakup di summary tidak dikirim ulang
+	summarizedCount := convo.SummarizedCount
+	if summarizedCount > len(messages) {
+		summarizedCount = len(messages)
 	}
 
-	reply := helpers.StripMarkdown(replyRaw)
+	var recent []utils.ChatMessage
+	for _, m := range messages[summarizedCount:] {
+		recent = append(recent, utils.ChatMessage{
+			Role:    m.Sender,
+			Content: m.Content,
+		})
+	}
+
+	summary := convo.Summary
+	if overflow := us.chatBuilder.Split(systemPrompt, summary, recent); overflow > 0 {
+		newSummary, err := us.summarizeConversation(ctx, summary, recent[:overflow])
+		if err == nil {
+			if err := us.userRepo.UpdateConversationSummary(ctx, nil, convoID, newSummary, summarizedCount+overflow); err == nil {
+				summary = newSummary
+			}
+		}
+
+		// jika ringkasan gagal, pesan lama tetap dibuang dari prompt agar tidak melebihi
+		// context model, dan akan dicoba diringkas lagi di pesan berikutnya
+		recent = recent[overflow:]
+	}
 
+	return us.chatBuilder.Build(systemPrompt, summary, recent), nil
+}
+func (us *UserService) saveAssistantMessage(ctx context.Context, convoID uuid.UUID, reply string) error {
 	aiMsg := entity.Message{
 		ID:             uuid.New(),
 		ConversationID: &convoID,
@@ -1914,11 +2555,352 @@ func (us *UserService) HandleChat(ctx context.Context, req dto.ChatRequest) (dto
 		Content:        reply,
 	}
 	if err := us.userRepo.SaveMessage(ctx, nil, aiMsg); err != nil {
-		return dto.ChatResponse{}, dto.ErrSaveMessage
+		return dto.ErrSaveMessage
+	}
+
+	return nil
+}
+
+// Conversation
+func (us *UserService) GetAllConversationWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.ConversationPaginationResponse, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := us.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return dto.ConversationPaginationResponse{}, dto.ErrGetUserIDFromToken
+	}
+
+	dataWithPaginate, err := us.userRepo.GetAllConversationWithPagination(ctx, nil, req, userID)
+	if err != nil {
+		return dto.ConversationPaginationResponse{}, dto.ErrGetAllConversation
+	}
+
+	var datas []dto.ConversationResponse
+	for _, convo := range dataWithPaginate.Conversations {
+		datas = append(datas, dto.ConversationResponse{
+			ID:        convo.ID,
+			Title:     convo.Title,
+			CreatedAt: convo.CreatedAt,
+			UpdatedAt: convo.UpdatedAt,
+		})
+	}
+
+	return dto.ConversationPaginationResponse{
+		Data: datas,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    dataWithPaginate.Page,
+			PerPage: dataWithPaginate.PerPage,
+			MaxPage: dataWithPaginate.MaxPage,
+			Count:   dataWithPaginate.Count,
+		},
+	}, nil
+}
+func (us *UserService) GetDetailConversation(ctx context.Context, convoID string) (dto.ConversationResponse, error) {
+	convo, err := us.getOwnedConversation(ctx, convoID)
+	if err != nil {
+		return dto.ConversationResponse{}, err
+	}
+
+	messages, err := us.userRepo.GetMessagesByConversationID(ctx, convo.ID)
+	if err != nil {
+		return dto.ConversationResponse{}, dto.ErrGetMessages
+	}
+
+	res := dto.ConversationResponse{
+		ID:        convo.ID,
+		Title:     convo.Title,
+		CreatedAt: convo.CreatedAt,
+		UpdatedAt: convo.UpdatedAt,
+	}
+
+	for _, msg := range messages {
+		res.Messages = append(res.Messages, dto.MessageResponse{
+			ID:        msg.ID,
+			Sender:    msg.Sender,
+			Content:   msg.Content,
+			CreatedAt: msg.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+func (us *UserService) UpdateConversation(ctx context.Context, req dto.UpdateConversationRequest) (dto.ConversationResponse, error) {
+	convo, err := us.getOwnedConversation(ctx, req.ID)
+	if err != nil {
+		return dto.ConversationResponse{}, err
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len([]rune(title)) > 100 {
+		return dto.ConversationResponse{}, dto.ErrInvalidConversationTitle
+	}
+
+	if err := us.userRepo.UpdateConversationTitle(ctx, nil, convo.ID, title); err != nil {
+		return dto.ConversationResponse{}, dto.ErrUpdateConversation
+	}
+
+	return dto.ConversationResponse{
+		ID:        convo.ID,
+		Title:     title,
+		CreatedAt: convo.CreatedAt,
+		UpdatedAt: convo.UpdatedAt,
+	}, nil
+}
+func (us *UserService) DeleteConversation(ctx context.Context, convoID string) (dto.ConversationResponse, error) {
+	convo, err := us.getOwnedConversation(ctx, convoID)
+	if err != nil {
+		return dto.ConversationResponse{}, err
+	}
+
+	err = us.userRepo.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		return us.userRepo.DeleteConversationByID(ctx, tx, convo.ID)
+	})
+	if err != nil {
+		return dto.ConversationResponse{}, dto.ErrDeleteConversation
+	}
+
+	return dto.ConversationResponse{
+		ID:        convo.ID,
+		Title:     convo.Title,
+		CreatedAt: convo.CreatedAt,
+		UpdatedAt: convo.UpdatedAt,
+	}, nil
+}
+func (us *UserService) getOwnedConversation(ctx context.Context, convoID string) (entity.Conversation, error) {
+	token := ctx.Value("Authorization").(string)
+
+	userID, err := us.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return entity.Conversation{}, dto.ErrGetUserIDFromToken
+	}
+
+	convo, flag, err := us.userRepo.GetConversationByID(ctx, nil, convoID)
+	if err != nil || !flag {
+		return entity.Conversation{}, dto.ErrConversationNotFound
+	}
+
+	if convo.UserID == nil || convo.UserID.String() != userID {
+		return entity.Conversation{}, dto.ErrDeniedAccess
+	}
+
+	return convo, nil
+}
+func (us *UserService) respondToCrisis(ctx context.Context, convoID uuid.UUID, reason string) (dto.ChatResponse, error) {
+	reply, err := readChatTemplate("CHAT_CRISIS_RESPONSE_PATH", "utils/chat_template/crisis_response.txt")
+	if err != nil {
+		return dto.ChatResponse{ConversationID: convoID}, dto.ErrLoadChatTemplate
+	}
+
+	if err := us.userRepo.FlagConversation(ctx, nil, convoID, reason); err != nil {
+		return dto.ChatResponse{ConversationID: convoID}, dto.ErrFlagConversation
+	}
+
+	if err := us.saveAssistantMessage(ctx, convoID, reply); err != nil {
+		return dto.ChatResponse{ConversationID: convoID}, err
 	}
 
 	return dto.ChatResponse{
 		Response:       reply,
 		ConversationID: convoID,
+		IsCrisis:       true,
 	}, nil
 }
+
+// retrieveChatSuggestions mengambil data psikolog dan konten Warasin yang relevan
+// dengan pesan user. Kegagalan query tidak menggagalkan chat, suggestion cukup dikosongkan.
+func (us *UserService) retrieveChatSuggestions(ctx context.Context, message string) *dto.ChatSuggestions {
+	wantsPsycholog, wantsContent := helpers.DetectChatIntent(message)
+	if !wantsPsycholog && !wantsContent {
+		return nil
+	}
+
+	var suggestions dto.ChatSuggestions
+	if wantsPsycholog {
+		psychologs, err := us.userRepo.GetPsychologSuggestions(ctx, nil, message, chatSuggestionLimit)
+		if err == nil {
+			for _, psy := range psychologs {
+				item := dto.ChatPsychologSuggestion{
+					ID:    psy.ID,
+					Name:  psy.Name,
+					Image: psy.Image,
+					City:  psy.City.Name,
+				}
+				for _, ps := range psy.PsychologSpecializations {
+					item.Specializations = append(item.Specializations, ps.Specialization.Name)
+				}
+				for _, pl := range psy.PsychologLanguages {
+					item.Languages = append(item.Languages, pl.LanguageMaster.Name)
+				}
+
+				suggestions.Psychologs = append(suggestions.Psychologs, item)
+			}
+		}
+	}
+
+	if wantsContent {
+		keywords := helpers.ExtractKeywords(message)
+
+		news, err := us.userRepo.GetNewsSuggestions(ctx, nil, keywords, chatSuggestionLimit)
+		if err == nil {
+			for _, n := range news {
+				suggestions.News = append(suggestions.News, dto.ChatNewsSuggestion{
+					ID:    n.ID,
+					Title: n.Title,
+					Image: n.Image,
+					Date:  n.Date,
+				})
+			}
+		}
+
+		motivations, err := us.userRepo.GetMotivationSuggestions(ctx, nil, keywords, chatSuggestionLimit)
+		if err == nil {
+			for _, m := range motivations {
+				suggestions.Motivations = append(suggestions.Motivations, dto.ChatMotivationSuggestion{
+					ID:       m.ID,
+					Author:   m.Author,
+					Content:  m.Content,
+					Category: m.MotivationCategory.Name,
+				})
+			}
+		}
+	}
+
+	if len(suggestions.Psychologs) == 0 && len(suggestions.News) == 0 && len(suggestions.Motivations) == 0 {
+		return nil
+	}
+
+	return &suggestions
+}
+
+// withChatSuggestions menyisipkan data suggestion sebagai pesan system tepat sebelum
+// pesan terakhir user, agar model hanya merekomendasikan data yang benar-benar ada.
+func withChatSuggestions(history []utils.ChatMessage, suggestions *dto.ChatSuggestions) []utils.ChatMessage {
+	if suggestions == nil || len(history) == 0 {
+		return history
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Data dari platform Warasin yang relevan dengan pesan user. Jika merekomendasikan psikolog, artikel, atau motivasi, gunakan hanya data berikut dan jangan mengarang nama lain. Data ini juga ditampilkan ke user sebagai kartu rekomendasi.\n")
+	for _, psy := range suggestions.Psychologs {
+		sb.WriteString(fmt.Sprintf("- Psikolog: %s (kota: %s; spesialisasi: %s; bahasa: %s)\n",
+			psy.Name, psy.City, strings.Join(psy.Specializations, ", "), strings.Join(psy.Languages, ", ")))
+	}
+	for _, n := range suggestions.News {
+		sb.WriteString(fmt.Sprintf("- Artikel: %s\n", n.Title))
+	}
+	for _, m := range suggestions.Motivations {
+		sb.WriteString(fmt.Sprintf("- Motivasi: \"%s\" - %s\n", m.Content, m.Author))
+	}
+
+	last := len(history) - 1
+	grounded := append([]utils.ChatMessage{}, history[:last]...)
+	grounded = append(grounded, utils.ChatMessage{
+		Role:    "system",
+		Content: strings.TrimSpace(sb.String()),
+	})
+
+	return append(grounded, history[last])
+}
+
+// recordChatUsage menambah pemakaian kuota harian user. Token dihitung dari prompt
+// yang dikirim dan balasan model, gagal mencatat tidak menggagalkan chat.
+// messages 0 dipakai untuk panggilan model tambahan seperti ringkasan.
+func (us *UserService) recordChatUsage(ctx context.Context, messages int, prompt []utils.ChatMessage, reply string) {
+	token, ok := ctx.Value("Authorization").(string)
+	if !ok {
+		return
+	}
+
+	userID, err := us.jwtService.GetUserIDByToken(token)
+	if err != nil {
+		return
+	}
+	uID, err := uuid.Parse(userID)
+	if err != nil {
+		return
+	}
+
+	tokens := 0
+	for _, msg := range prompt {
+		tokens += us.chatBuilder.CountMessageTokens(msg)
+	}
+	if reply != "" {
+		tokens += us.chatBuilder.CountMessageTokens(utils.ChatMessage{Role: "assistant", Content: reply})
+	}
+
+	_ = us.chatQuota.RecordUsage(ctx, uID, messages, tokens)
+}
+func (us *UserService) summarizeConversation(ctx context.Context, summary string, messages []utils.ChatMessage) (string, error) {
+	summaryPrompt, err := readChatTemplate("CHAT_SUMMARY_PROMPT_PATH", "utils/chat_template/summary_prompt.txt")
+	if err != nil {
+		return "", err
+	}
+
+	var transcript strings.Builder
+	if summary != "" {
+		transcript.WriteString("Ringkasan sebelumnya:\n" + summary + "\n\n")
+	}
+	transcript.WriteString("Pesan baru:\n")
+	for _, m := range messages {
+		transcript.WriteString(m.Role + ": " + m.Content + "\n")
+	}
+
+	prompt := []utils.ChatMessage{
+		{
+			Role:    "system",
+			Content: summaryPrompt,
+		},
+		{
+			Role:    "user",
+			Content: transcript.String(),
+		},
+	}
+	reply, err := us.llmClient.Chat(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	us.recordChatUsage(ctx, 0, prompt, reply)
+
+	return strings.TrimSpace(helpers.StripMarkdown(reply)), nil
+}
+
+// template chat dibaca dari file agar bisa diubah tanpa rebuild, path bisa dioverride lewat env
+func readChatTemplate(envKey string, defaultPath string) (string, error) {
+	path := os.Getenv(envKey)
+	if path == "" {
+		path = defaultPath
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// comment yang disembunyikan admin tidak ditampilkan, rating tetap dihitung
+func toReviewResponses(consultations []entity.Consultation) []dto.ReviewResponse {
+	reviews := []dto.ReviewResponse{}
+	for _, consul := range consultations {
+		comment := consul.Comment
+		if consul.ReviewHidden {
+			comment = ""
+		}
+
+		ratedAt := consul.RatedAt
+		if ratedAt == nil {
+			ratedAt = &consul.UpdatedAt
+		}
+
+		reviews = append(reviews, dto.ReviewResponse{
+			ConsultationID: consul.ID,
+			Rate:           consul.Rate,
+			Comment:        comment,
+			ReviewerName:   helpers.MaskReviewerName(consul.User.Name),
+			RatedAt:        ratedAt,
+		})
+	}
+
+	return reviews
+}
@@ -0,0 +1,81 @@
+package utils
+
+// tambahan token per pesan untuk role dan pemisah dari format chat
+const chatMessageOverhead = 4
+
+// ChatHistoryBuilder menyusun riwayat chat yang muat dalam budget token.
+// Pesan terbaru dipertahankan apa adanya, pesan lama yang tidak muat
+// dikembalikan sebagai overflow untuk digulung ke ringkasan percakapan.
+type ChatHistoryBuilder struct {
+	tokenizer      Tokenizer
+	maxTokens      int
+	minRecentTurns int
+}
+
+func NewChatHistoryBuilder(tokenizer Tokenizer, maxTokens int, minRecentTurns int) *ChatHistoryBuilder {
+	if minRecentTurns < 1 {
+		minRecentTurns = 1
+	}
+
+	return &ChatHistoryBuilder{
+		tokenizer:      tokenizer,
+		maxTokens:      maxTokens,
+		minRecentTurns: minRecentTurns,
+	}
+}
+
+func (b *ChatHistoryBuilder) CountMessageTokens(msg ChatMessage) int {
+	return b.tokenizer.CountTokens(msg.Content) + chatMessageOverhead
+}
+
+// Split mengembalikan jumlah pesan terlama di messages yang harus diringkas agar
+// sisanya, bersama system prompt dan summary, muat di maxTokens. minRecentTurns
+// pesan terakhir selalu dipertahankan walaupun melebihi budget.
+func (b *ChatHistoryBuilder) Split(systemPrompt string, summary string, messages []ChatMessage) int {
+	if b.maxTokens <= 0 {
+		return 0
+	}
+
+	used := b.tokenizer.CountTokens(systemPrompt) + chatMessageOverhead
+	if summary != "" {
+		used += b.CountMessageTokens(summaryMessage(summary))
+	}
+
+	keep := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		cost := b.CountMessageTokens(messages[i])
+		if keep >= b.minRecentTurns && used+cost > b.maxTokens {
+			break
+		}
+
+		used += cost
+		keep++
+	}
+
+	return len(messages) - keep
+}
+
+// Build menyusun pesan final untuk model: system prompt, ringkasan (jika ada),
+// lalu pesan terbaru yang tersisa.
+func (b *ChatHistoryBuilder) Build(systemPrompt string, summary string, recent []ChatMessage) []ChatMessage {
+	history := []ChatMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+	}
+
+	if summary != "" {
+		history = append(history, summaryMessage(summary))
+	}
+
+	return append(history, recent...)
+}
+
+// summaryMessage dipakai Split dan Build agar token prefix ringkasan ikut terhitung
+func summaryMessage(summary string) ChatMessage {
+	return ChatMessage{
+		Role:    "system",
+		Content: "Ringkasan percakapan sebelumnya:\n" + summary,
+	}
+}
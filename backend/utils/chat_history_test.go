@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// dengan FakeTokenizer setiap kata = 1 token, ditambah chatMessageOverhead per pesan
+func words(n int) string {
+	return strings.TrimSpace(strings.Repeat("kata ", n))
+}
+
+func userMessages(contents ...string) []ChatMessage {
+	messages := make([]ChatMessage, len(contents))
+	for i, content := range contents {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		messages[i] = ChatMessage{Role: role, Content: content}
+	}
+
+	return messages
+}
+
+func TestChatHistoryBuilderSplit(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxTokens      int
+		minRecentTurns int
+		summary        string
+		messages       []ChatMessage
+		wantOverflow   int
+	}{
+		{
+			name:           "everything fits in budget",
+			maxTokens:      100,
+			minRecentTurns: 1,
+			messages:       userMessages("halo", "hai juga", "apa kabar"),
+			wantOverflow:   0,
+		},
+		{
+			// system 5, tiap pesan 5: tiga pesan terakhir pas 20
+			name:           "oldest messages overflow the budget",
+			maxTokens:      20,
+			minRecentTurns: 1,
+			messages:       userMessages("satu", "dua", "tiga", "empat", "lima"),
+			wantOverflow:   2,
+		},
+		{
+			// summary beserta prefixnya (4 kata + overhead = 8) ikut memakan budget
+			name:           "summary takes part of the budget",
+			maxTokens:      23,
+			minRecentTurns: 1,
+			summary:        "ringkasan",
+			messages:       userMessages("satu", "dua", "tiga", "empat", "lima"),
+			wantOverflow:   3,
+		},
+		{
+			// budget hanya cukup untuk satu pesan, tapi tiga pesan terakhir tetap dipertahankan
+			name:           "minRecentTurns floor keeps recent messages over budget",
+			maxTokens:      10,
+			minRecentTurns: 3,
+			messages:       userMessages("satu", "dua", "tiga", "empat", "lima"),
+			wantOverflow:   2,
+		},
+		{
+			name:           "single latest message larger than the whole budget is kept",
+			maxTokens:      10,
+			minRecentTurns: 1,
+			messages:       userMessages("halo", words(50)),
+			wantOverflow:   1,
+		},
+		{
+			name:           "single old message larger than the whole budget overflows",
+			maxTokens:      20,
+			minRecentTurns: 1,
+			messages:       userMessages(words(50), "halo", "hai"),
+			wantOverflow:   1,
+		},
+		{
+			name:           "non positive budget disables splitting",
+			maxTokens:      0,
+			minRecentTurns: 1,
+			messages:       userMessages(words(50), words(50)),
+			wantOverflow:   0,
+		},
+		{
+			name:           "no messages",
+			maxTokens:      10,
+			minRecentTurns: 2,
+			wantOverflow:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewChatHistoryBuilder(NewFakeTokenizer(), tt.maxTokens, tt.minRecentTurns)
+
+			got := b.Split("sistem", tt.summary, tt.messages)
+			if got != tt.wantOverflow {
+				t.Errorf("Split() = %d, want %d", got, tt.wantOverflow)
+			}
+		})
+	}
+}
+
+func TestChatHistoryBuilderSplitRespectsBudget(t *testing.T) {
+	b := NewChatHistoryBuilder(NewFakeTokenizer(), 60, 2)
+
+	var contents []string
+	for i := 1; i <= 12; i++ {
+		contents = append(contents, words(i))
+	}
+	messages := userMessages(contents...)
+
+	overflow := b.Split("kamu adalah asisten", "user sedang cemas", messages)
+	history := b.Build("kamu adalah asisten", "user sedang cemas", messages[overflow:])
+
+	total := 0
+	for _, msg := range history {
+		total += b.CountMessageTokens(msg)
+	}
+	if total > 60 {
+		t.Errorf("history uses %d tokens, budget is 60", total)
+	}
+
+	if len(messages[overflow:]) < 2 {
+		t.Errorf("expected at least minRecentTurns messages kept, got %d", len(messages[overflow:]))
+	}
+}
+
+func TestChatHistoryBuilderMinRecentTurnsDefault(t *testing.T) {
+	// minRecentTurns < 1 dinaikkan ke 1 agar pesan terbaru tidak pernah dibuang
+	b := NewChatHistoryBuilder(NewFakeTokenizer(), 1, 0)
+
+	if got := b.Split("sistem", "", userMessages("halo", "apa kabar")); got != 1 {
+		t.Errorf("Split() = %d, want 1", got)
+	}
+}
+
+func TestChatHistoryBuilderBuild(t *testing.T) {
+	recent := userMessages("halo", "hai, ada yang bisa dibantu?")
+
+	tests := []struct {
+		name      string
+		summary   string
+		wantRoles []string
+	}{
+		{
+			name:      "without summary",
+			wantRoles: []string{"system", "user", "assistant"},
+		},
+		{
+			name:      "summary inserted after system prompt",
+			summary:   "user bercerita soal ujian",
+			wantRoles: []string{"system", "system", "user", "assistant"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewChatHistoryBuilder(NewFakeTokenizer(), 100, 1)
+			history := b.Build("kamu adalah asisten", tt.summary, recent)
+
+			if len(history) != len(tt.wantRoles) {
+				t.Fatalf("got %d messages, want %d", len(history), len(tt.wantRoles))
+			}
+			for i, role := range tt.wantRoles {
+				if history[i].Role != role {
+					t.Errorf("message %d role = %q, want %q", i, history[i].Role, role)
+				}
+			}
+
+			if history[0].Content != "kamu adalah asisten" {
+				t.Errorf("system prompt = %q", history[0].Content)
+			}
+			if tt.summary != "" && !strings.HasSuffix(history[1].Content, tt.summary) {
+				t.Errorf("summary message = %q, want it to contain %q", history[1].Content, tt.summary)
+			}
+			if history[len(history)-1] != recent[len(recent)-1] {
+				t.Errorf("latest message must stay last, got %+v", history[len(history)-1])
+			}
+		})
+	}
+}
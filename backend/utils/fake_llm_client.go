@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// FakeLLMClient adalah LLMClient deterministik tanpa network, untuk testing dan
+// development lokal. Jika Reply kosong, balasannya meng-echo pesan terakhir.
+type FakeLLMClient struct {
+	Reply string
+	Err   error
+
+	mu    sync.Mutex
+	calls [][]ChatMessage
+}
+
+func NewFakeLLMClient(reply string) *FakeLLMClient {
+	return &FakeLLMClient{
+		Reply: reply,
+	}
+}
+
+func (f *FakeLLMClient) Chat(ctx context.Context, messages []ChatMessage) (string, error) {
+	f.record(messages)
+
+	if f.Err != nil {
+		return "", f.Err
+	}
+
+	return f.reply(messages), nil
+}
+
+func (f *FakeLLMClient) ChatStream(ctx context.Context, messages []ChatMessage, onDelta func(delta string) error) (string, error) {
+	f.record(messages)
+
+	if f.Err != nil {
+		return "", f.Err
+	}
+
+	var sent strings.Builder
+	for _, word := range strings.SplitAfter(f.reply(messages), " ") {
+		if err := ctx.Err(); err != nil {
+			return sent.String(), err
+		}
+
+		sent.WriteString(word)
+		if err := onDelta(word); err != nil {
+			return sent.String(), err
+		}
+	}
+
+	return sent.String(), nil
+}
+
+func (f *FakeLLMClient) Calls() [][]ChatMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([][]ChatMessage(nil), f.calls...)
+}
+
+func (f *FakeLLMClient) record(messages []ChatMessage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, append([]ChatMessage(nil), messages...))
+}
+
+func (f *FakeLLMClient) reply(messages []ChatMessage) string {
+	if f.Reply != "" {
+		return f.Reply
+	}
+
+	if len(messages) == 0 {
+		return "echo:"
+	}
+
+	return "echo: " + messages[len(messages)-1].Content
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+	"github.com/google/uuid"
+)
+
+const FakePaymentSignatureHeader = "X-Payment-Signature"
+
+// FakePaymentGateway adalah PaymentGateway lokal tanpa network, untuk testing dan
+// development. Webhook ditandatangani HMAC-SHA256 atas body dengan secret yang sama,
+// gunakan SignWebhook untuk mensimulasikan notifikasi dari gateway.
+type FakePaymentGateway struct {
+	Err error
+
+	secret      []byte
+	checkoutURL string
+
+	mu      sync.Mutex
+	charges []PaymentChargeRequest
+	voided  []string
+	refunds map[string]PaymentRefund
+}
+
+type fakePaymentWebhookPayload struct {
+	EventID   string `json:"event_id"`
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+	Amount    int64  `json:"amount"`
+}
+
+func NewFakePaymentGateway(secret string, checkoutURL string) *FakePaymentGateway {
+	return &FakePaymentGateway{
+		secret:      []byte(secret),
+		checkoutURL: checkoutURL,
+	}
+}
+
+func (f *FakePaymentGateway) Name() string {
+	return constants.ENUM_PAYMENT_GATEWAY_FAKE
+}
+
+func (f *FakePaymentGateway) CreateCharge(ctx context.Context, req PaymentChargeRequest) (PaymentCharge, error) {
+	f.mu.Lock()
+	f.charges = append(f.charges, req)
+	f.mu.Unlock()
+
+	if f.Err != nil {
+		return PaymentCharge{}, f.Err
+	}
+
+	reference := "fake_" + uuid.NewString()
+
+	return PaymentCharge{
+		Reference:   reference,
+		CheckoutURL: f.checkoutURL + "?ref=" + url.QueryEscape(reference),
+	}, nil
+}
+
+func (f *FakePaymentGateway) VoidCharge(ctx context.Context, reference string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.voided = append(f.voided, reference)
+	return nil
+}
+func (f *FakePaymentGateway) Refund(ctx context.Context, req PaymentRefundRequest) (PaymentRefund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return PaymentRefund{}, f.Err
+	}
+
+	// refund dengan RefundID yang sama mengembalikan hasil sebelumnya
+	if refund, ok := f.refunds[req.RefundID]; ok {
+		return refund, nil
+	}
+
+	if f.refunds == nil {
+		f.refunds = make(map[string]PaymentRefund)
+	}
+
+	refund := PaymentRefund{
+		Reference: "fake_refund_" + uuid.NewString(),
+	}
+	f.refunds[req.RefundID] = refund
+
+	return refund, nil
+}
+
+func (f *FakePaymentGateway) ParseWebhook(payload []byte, header http.Header) (PaymentWebhookEvent, error) {
+	signature, err := hex.DecodeString(header.Get(FakePaymentSignatureHeader))
+	if err != nil || !hmac.Equal(signature, f.sign(payload)) {
+		return PaymentWebhookEvent{}, ErrInvalidWebhookSignature
+	}
+
+	var body fakePaymentWebhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return PaymentWebhookEvent{}, err
+	}
+
+	if body.EventID == "" || body.Reference == "" {
+		return PaymentWebhookEvent{}, errors.New("event_id and reference are required")
+	}
+
+	return PaymentWebhookEvent{
+		EventID:   body.EventID,
+		Reference: body.Reference,
+		Status:    body.Status,
+		Amount:    body.Amount,
+		Payload:   payload,
+	}, nil
+}
+
+// SignWebhook mengembalikan nilai header X-Payment-Signature untuk payload
+func (f *FakePaymentGateway) SignWebhook(payload []byte) string {
+	return hex.EncodeToString(f.sign(payload))
+}
+
+func (f *FakePaymentGateway) Charges() []PaymentChargeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]PaymentChargeRequest(nil), f.charges...)
+}
+
+// Voided mengembalikan reference charge yang sudah dibatalkan lewat VoidCharge
+func (f *FakePaymentGateway) Voided() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.voided...)
+}
+
+func (f *FakePaymentGateway) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,15 @@
+package utils
+
+import "strings"
+
+// FakeTokenizer menghitung satu token per kata agar budget di testing mudah
+// diprediksi.
+type FakeTokenizer struct{}
+
+func NewFakeTokenizer() *FakeTokenizer {
+	return &FakeTokenizer{}
+}
+
+func (t *FakeTokenizer) CountTokens(text string) int {
+	return len(strings.Fields(text))
+}
@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+	"gorm.io/gorm/schema"
+)
+
+// format nilai terenkripsi: enc:v1:<key id>:<data key terbungkus>:<ciphertext>
+// Setiap nilai memakai data key acak (envelope encryption), data key dibungkus
+// dengan KEK dari config sehingga rotasi cukup membungkus ulang lewat --reencrypt.
+const encryptedPrefix = "enc:v1:"
+
+var (
+	ErrEncryptionNotConfigured = errors.New("field encryption is not configured")
+	ErrUnknownEncryptionKey    = errors.New("unknown field encryption key")
+	ErrMalformedEncryptedValue = errors.New("malformed encrypted value")
+)
+
+type FieldCipher struct {
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+var (
+	fieldCipherMu sync.RWMutex
+	fieldCipher   *FieldCipher
+)
+
+// RegisterEncryptedSerializer memasang key dan serializer GORM "encrypted", panggil
+// sebelum query pertama agar field bertag serializer:encrypted bisa dibaca/ditulis
+func RegisterEncryptedSerializer(cfg *config.EncryptionConfig) {
+	fieldCipherMu.Lock()
+	fieldCipher = &FieldCipher{
+		activeKeyID: cfg.ActiveKeyID,
+		keys:        cfg.Keys,
+	}
+	fieldCipherMu.Unlock()
+
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+func GetFieldCipher() (*FieldCipher, error) {
+	fieldCipherMu.RLock()
+	defer fieldCipherMu.RUnlock()
+
+	if fieldCipher == nil {
+		return nil, ErrEncryptionNotConfigured
+	}
+
+	return fieldCipher, nil
+}
+
+func IsEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// EncryptedKeyID mengembalikan key id yang dipakai sebuah nilai terenkripsi
+func EncryptedKeyID(value string) string {
+	if !IsEncryptedValue(value) {
+		return ""
+	}
+
+	keyID, _, _ := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	return keyID
+}
+
+func sealAESGCM(key []byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+func openAESGCM(key []byte, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformedEncryptedValue
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func (fc *FieldCipher) Encrypt(plaintext []byte) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := sealAESGCM(fc.keys[fc.activeKeyID], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := sealAESGCM(dataKey, plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedPrefix + fc.activeKeyID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+func (fc *FieldCipher) Decrypt(value string) ([]byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if len(parts) != 3 {
+		return nil, ErrMalformedEncryptedValue
+	}
+
+	kek, ok := fc.keys[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEncryptionKey, parts[0])
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedEncryptedValue
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedEncryptedValue
+	}
+
+	dataKey, err := openAESGCM(kek, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAESGCM(dataKey, ciphertext)
+}
+
+// IsActiveKey dipakai --reencrypt untuk melewati nilai yang sudah memakai key aktif
+func (fc *FieldCipher) IsActiveKey(value string) bool {
+	return EncryptedKeyID(value) == fc.activeKeyID
+}
+
+// EncryptedSerializer mengenkripsi field bertag `gorm:"serializer:encrypted"`. Nilai
+// kosong disimpan apa adanya dan baris lama yang masih plain text tetap terbaca
+// sampai dienkripsi ulang dengan --reencrypt.
+type EncryptedSerializer struct{}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var raw string
+	switch v := dbValue.(type) {
+	case nil:
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		raw = fmt.Sprint(v)
+	}
+
+	if raw == "" {
+		return field.Set(ctx, dst, reflect.Zero(field.FieldType).Interface())
+	}
+
+	fieldValue := reflect.New(field.FieldType)
+	if !IsEncryptedValue(raw) {
+		if field.FieldType.Kind() == reflect.String {
+			fieldValue.Elem().SetString(raw)
+		} else if err := json.Unmarshal([]byte(raw), fieldValue.Interface()); err != nil {
+			return err
+		}
+
+		return field.Set(ctx, dst, fieldValue.Elem().Interface())
+	}
+
+	fc, err := GetFieldCipher()
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := fc.Decrypt(raw)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(plaintext, fieldValue.Interface()); err != nil {
+		return err
+	}
+
+	return field.Set(ctx, dst, fieldValue.Elem().Interface())
+}
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if fieldValue == nil || reflect.ValueOf(fieldValue).IsZero() {
+		return "", nil
+	}
+
+	fc, err := GetFieldCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(fieldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return fc.Encrypt(plaintext)
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"os"
+)
+
+const (
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmEdDSA = "EdDSA"
+)
+
+// LoadPrivateKey membaca private key RSA atau Ed25519 berformat PEM (PKCS#8 atau PKCS#1)
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("failed to parse private key " + path)
+}
+
+// LoadPublicKey membaca public key PEM (PKIX). File private key juga diterima,
+// public key-nya diambil dari private key tersebut.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	signer, err := LoadPrivateKey(path)
+	if err != nil {
+		return nil, errors.New("failed to parse public key " + path)
+	}
+
+	return signer.Public(), nil
+}
+
+// JWTAlgorithmForKey menentukan algoritma JWT dari tipe public key
+func JWTAlgorithmForKey(pub crypto.PublicKey) (string, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return JWTAlgorithmRS256, nil
+	case ed25519.PublicKey:
+		return JWTAlgorithmEdDSA, nil
+	default:
+		return "", errors.New("unsupported key type, only RSA and Ed25519 are supported")
+	}
+}
+
+// KeyThumbprint dipakai sebagai kid default, diturunkan dari public key agar stabil
+func KeyThumbprint(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func readPEMBlock(path string) (*pem.Block, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM data found in " + path)
+	}
+
+	return block, nil
+}
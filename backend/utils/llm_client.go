@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+)
+
+type (
+	ChatMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+
+	// LLMClient membungkus provider chat completion agar UserService tidak
+	// bergantung pada satu vendor tertentu.
+	LLMClient interface {
+		Chat(ctx context.Context, messages []ChatMessage) (string, error)
+		// ChatStream memanggil onDelta untuk setiap potongan teks dan mengembalikan
+		// teks yang sudah terkumpul, termasuk saat stream berhenti di tengah.
+		ChatStream(ctx context.Context, messages []ChatMessage, onDelta func(delta string) error) (string, error)
+	}
+)
+
+func NewLLMClient(cfg *config.LLMConfig) LLMClient {
+	switch cfg.Provider {
+	case "fake":
+		return NewFakeLLMClient("")
+	default:
+		// openai, ollama, vllm, dll. cukup diarahkan lewat LLM_BASE_URL
+		return NewOpenAICompatibleClient(cfg)
+	}
+}
@@ -1,76 +1,212 @@
 package utils
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
-	"os"
+	"strings"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
 )
 
-func GetChatGPTResponse(ctx context.Context, messages []map[string]string) (string, error) {
-	openaiAPIKey := os.Getenv("OPENAI_API_KEY")
-	url := "https://api.openai.com/v1/chat/completions"
-	body := map[string]interface{}{
-		"model":       "gpt-4o-mini",
-		"messages":    messages,
-		"temperature": 0.7,
+// ErrLLMStreamIdle dikembalikan jika provider berhenti mengirim data di tengah stream
+var ErrLLMStreamIdle = errors.New("LLM provider stopped sending stream data")
+
+// OpenAICompatibleClient berbicara dengan endpoint /chat/completions ala OpenAI,
+// sehingga bisa dipakai untuk OpenAI maupun server lokal seperti Ollama atau vLLM.
+type OpenAICompatibleClient struct {
+	baseURL     string
+	apiKey      string
+	model       string
+	temperature float64
+	maxTokens   int
+	timeout     time.Duration
+	httpClient  *http.Client
+}
+
+// NewOpenAICompatibleClient tidak memasang http.Client.Timeout karena batas itu ikut
+// memotong pembacaan body stream. Timeout dipasang di koneksi dan header respons,
+// sisanya diatur lewat context per request.
+func NewOpenAICompatibleClient(cfg *config.LLMConfig) *OpenAICompatibleClient {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+
+	return &OpenAICompatibleClient{
+		baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:      cfg.APIKey,
+		model:       cfg.Model,
+		temperature: cfg.Temperature,
+		maxTokens:   cfg.MaxTokens,
+		timeout:     timeout,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   timeout,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   timeout,
+				ResponseHeaderTimeout: timeout,
+				IdleConnTimeout:       90 * time.Second,
+				MaxIdleConns:          100,
+			},
+		},
 	}
-	jsonBody, err := json.Marshal(body)
+}
+
+func (oc *OpenAICompatibleClient) Chat(ctx context.Context, messages []ChatMessage) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, oc.timeout)
+	defer cancel()
+
+	res, err := oc.doRequest(ctx, messages, false)
 	if err != nil {
 		return "", err
 	}
+	defer res.Body.Close()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Authorization", "Bearer "+openaiAPIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	client := http.Client{}
-	res, err := client.Do(req)
-	if err != nil {
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
 		return "", err
 	}
-	defer res.Body.Close()
 
-	bodyBytes, err := io.ReadAll(res.Body)
+	if len(result.Choices) == 0 {
+		return "", errors.New("no choices returned by LLM provider")
+	}
+
+	return result.Choices[0].Message.Content, nil
+}
+
+// ChatStream tidak dibatasi total durasi. Stream hanya diputus jika ctx dibatalkan
+// (client disconnect) atau provider diam lebih lama dari timeout di antara chunk.
+func (oc *OpenAICompatibleClient) ChatStream(ctx context.Context, messages []ChatMessage, onDelta func(delta string) error) (string, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	idle := time.AfterFunc(oc.timeout, func() { cancel(ErrLLMStreamIdle) })
+	defer idle.Stop()
+
+	res, err := oc.doRequest(ctx, messages, true)
 	if err != nil {
-		return "", err
+		return "", streamError(ctx, err)
 	}
+	defer res.Body.Close()
 
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return "", fmt.Errorf("OpenAI API error: %s", string(bodyBytes))
+	var reply strings.Builder
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		idle.Reset(oc.timeout)
+
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return reply.String(), nil
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return reply.String(), err
+		}
+
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		reply.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return reply.String(), err
+		}
 	}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &result); err != nil {
-		return "", err
+	if err := scanner.Err(); err != nil {
+		return reply.String(), streamError(ctx, err)
+	}
+
+	if ctx.Err() != nil {
+		return reply.String(), context.Cause(ctx)
+	}
+
+	return reply.String(), nil
+}
+
+// streamError mengganti error baca body dengan penyebab pembatalan stream, misalnya
+// ErrLLMStreamIdle, agar pemanggil tahu kenapa stream terputus
+func streamError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	return err
+}
+
+func (oc *OpenAICompatibleClient) doRequest(ctx context.Context, messages []ChatMessage, stream bool) (*http.Response, error) {
+	body := map[string]interface{}{
+		"model":       oc.model,
+		"messages":    messages,
+		"temperature": oc.temperature,
+	}
+	if oc.maxTokens > 0 {
+		body["max_tokens"] = oc.maxTokens
+	}
+	if stream {
+		body["stream"] = true
 	}
 
-	choicesRaw, ok := result["choices"].([]interface{})
-	if !ok || len(choicesRaw) == 0 {
-		return "", errors.New("no choices returned by OpenAI")
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
 	}
 
-	choice, ok := choicesRaw[0].(map[string]interface{})
-	if !ok {
-		return "", errors.New("invalid choice format from OpenAI")
+	req, err := http.NewRequestWithContext(ctx, "POST", oc.baseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	if oc.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+oc.apiKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
 	}
 
-	message, ok := choice["message"].(map[string]interface{})
-	if !ok {
-		return "", errors.New("invalid message format from OpenAI")
+	res, err := oc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
 
-	content, ok := message["content"].(string)
-	if !ok {
-		return "", errors.New("missing content in OpenAI response")
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("LLM provider error: %s", string(bodyBytes))
 	}
 
-	return content, nil
+	return res, nil
 }
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+)
+
+// fakeCompletionServer meniru endpoint /chat/completions. chunks dikirim sebagai
+// event SSE dengan jeda gap, atau sebagai satu respons JSON jika request bukan stream.
+func fakeCompletionServer(t *testing.T, chunks []string, gap time.Duration, finish bool) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "missing api key", http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Model    string        `json:"model"`
+			Messages []ChatMessage `json:"messages"`
+			Stream   bool          `json:"stream"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Model != "test-model" || len(body.Messages) == 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		if !body.Stream {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"choices": []map[string]interface{}{
+					{"message": map[string]string{"role": "assistant", "content": strings.Join(chunks, "")}},
+				},
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for _, chunk := range chunks {
+			payload, _ := json.Marshal(map[string]interface{}{
+				"choices": []map[string]interface{}{
+					{"delta": map[string]string{"content": chunk}},
+				},
+			})
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+
+			select {
+			case <-time.After(gap):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if !finish {
+			// provider menggantung tanpa menutup stream
+			<-r.Context().Done()
+			return
+		}
+
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newTestOpenAIClient(baseURL string, timeout time.Duration) *OpenAICompatibleClient {
+	client := NewOpenAICompatibleClient(&config.LLMConfig{
+		BaseURL:        baseURL,
+		APIKey:         "test-key",
+		Model:          "test-model",
+		TimeoutSeconds: 1,
+	})
+	client.timeout = timeout
+
+	return client
+}
+
+func TestOpenAICompatibleClientChat(t *testing.T) {
+	srv := fakeCompletionServer(t, []string{"Halo, ", "apa kabar?"}, 0, true)
+	client := newTestOpenAIClient(srv.URL, time.Second)
+
+	reply, err := client.Chat(context.Background(), []ChatMessage{{Role: "user", Content: "halo"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reply != "Halo, apa kabar?" {
+		t.Errorf("unexpected reply %q", reply)
+	}
+}
+
+func TestOpenAICompatibleClientChatProviderError(t *testing.T) {
+	srv := fakeCompletionServer(t, nil, 0, true)
+	client := newTestOpenAIClient(srv.URL, time.Second)
+	client.apiKey = "wrong-key"
+
+	if _, err := client.Chat(context.Background(), []ChatMessage{{Role: "user", Content: "halo"}}); err == nil {
+		t.Fatal("expected provider error")
+	}
+}
+
+func TestOpenAICompatibleClientChatStream(t *testing.T) {
+	chunks := []string{"Tarik ", "napas ", "pelan-pelan, ", "lalu ", "hembuskan."}
+
+	tests := []struct {
+		name      string
+		gap       time.Duration
+		finish    bool
+		timeout   time.Duration
+		wantErr   error
+		wantReply string
+	}{
+		{
+			name:      "stream completes",
+			gap:       0,
+			finish:    true,
+			timeout:   time.Second,
+			wantReply: strings.Join(chunks, ""),
+		},
+		{
+			// total durasi stream jauh di atas timeout, tapi jeda antar chunk di bawahnya
+			name:      "long stream is not cut by timeout",
+			gap:       60 * time.Millisecond,
+			finish:    true,
+			timeout:   150 * time.Millisecond,
+			wantReply: strings.Join(chunks, ""),
+		},
+		{
+			name:      "idle provider is cut after timeout",
+			gap:       0,
+			finish:    false,
+			timeout:   150 * time.Millisecond,
+			wantErr:   ErrLLMStreamIdle,
+			wantReply: strings.Join(chunks, ""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fakeCompletionServer(t, chunks, tt.gap, tt.finish)
+			client := newTestOpenAIClient(srv.URL, tt.timeout)
+
+			var deltas []string
+			reply, err := client.ChatStream(context.Background(), []ChatMessage{{Role: "user", Content: "aku cemas"}}, func(delta string) error {
+				deltas = append(deltas, delta)
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if reply != tt.wantReply {
+				t.Errorf("expected reply %q, got %q", tt.wantReply, reply)
+			}
+			if strings.Join(deltas, "") != reply {
+				t.Errorf("deltas %q do not add up to reply %q", deltas, reply)
+			}
+		})
+	}
+}
+
+func TestOpenAICompatibleClientChatStreamCanceled(t *testing.T) {
+	srv := fakeCompletionServer(t, []string{"satu ", "dua ", "tiga"}, 50*time.Millisecond, true)
+	client := newTestOpenAIClient(srv.URL, time.Second)
+
+	// client disconnect setelah delta pertama
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reply, err := client.ChatStream(ctx, []ChatMessage{{Role: "user", Content: "halo"}}, func(delta string) error {
+		cancel()
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if reply != "satu " {
+		t.Errorf("expected partial reply %q, got %q", "satu ", reply)
+	}
+}
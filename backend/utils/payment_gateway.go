@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Reyysusanto/warasin-web/backend/config"
+	"github.com/Reyysusanto/warasin-web/backend/constants"
+)
+
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+type (
+	PaymentChargeRequest struct {
+		InvoiceNumber string
+		Amount        int64
+		Currency      string
+		Description   string
+		CustomerName  string
+		CustomerEmail string
+		// gateway harus menolak pembayaran setelah waktu ini agar sesuai dengan hold slot
+		ExpiresAt time.Time
+	}
+
+	PaymentCharge struct {
+		Reference   string
+		CheckoutURL string
+	}
+
+	PaymentRefundRequest struct {
+		// id refund lokal, dikirim sebagai idempotency key agar retry tidak mengembalikan dana dua kali
+		RefundID         string
+		PaymentReference string
+		Amount           int64
+		Reason           string
+	}
+
+	PaymentRefund struct {
+		Reference string
+	}
+
+	// PaymentWebhookEvent adalah hasil parsing webhook yang signature-nya sudah valid.
+	// Status memakai constants.ENUM_PAYMENT_STATUS_*.
+	PaymentWebhookEvent struct {
+		EventID   string
+		Reference string
+		Status    string
+		Amount    int64
+		Payload   []byte
+	}
+
+	// PaymentGateway membungkus provider pembayaran agar alur booking tidak
+	// bergantung pada satu vendor tertentu.
+	PaymentGateway interface {
+		Name() string
+		CreateCharge(ctx context.Context, req PaymentChargeRequest) (PaymentCharge, error)
+		// VoidCharge membatalkan charge yang belum dibayar agar tidak bisa dibayar lagi
+		VoidCharge(ctx context.Context, reference string) error
+		Refund(ctx context.Context, req PaymentRefundRequest) (PaymentRefund, error)
+		// ParseWebhook mengembalikan ErrInvalidWebhookSignature jika signature tidak cocok
+		ParseWebhook(payload []byte, header http.Header) (PaymentWebhookEvent, error)
+	}
+)
+
+func NewPaymentGateway(cfg *config.PaymentConfig) (PaymentGateway, error) {
+	switch cfg.Provider {
+	case constants.ENUM_PAYMENT_GATEWAY_FAKE:
+		return NewFakePaymentGateway(cfg.WebhookSecret, cfg.FakeCheckoutURL), nil
+	default:
+		return nil, fmt.Errorf("unsupported payment provider %q", cfg.Provider)
+	}
+}
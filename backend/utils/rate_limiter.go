@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter memutuskan apakah request untuk sebuah key (misal user ID) boleh
+// diproses. Implementasi in-memory cukup untuk satu instance, untuk deployment
+// multi instance bisa diganti implementasi berbasis Redis.
+type RateLimiter interface {
+	// Allow mengembalikan false beserta waktu tunggu jika key sedang dibatasi.
+	Allow(key string) (bool, time.Duration)
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// InMemoryRateLimiter adalah token bucket per key: bucket terisi ratePerMinute
+// token per menit hingga maksimal burst, setiap request memakai satu token.
+type InMemoryRateLimiter struct {
+	ratePerSecond float64
+	burst         float64
+	now           func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewInMemoryRateLimiter(ratePerMinute float64, burst int) *InMemoryRateLimiter {
+	return &InMemoryRateLimiter{
+		ratePerSecond: ratePerMinute / 60,
+		burst:         float64(burst),
+		now:           time.Now,
+		buckets:       map[string]*tokenBucket{},
+	}
+}
+
+func (rl *InMemoryRateLimiter) Allow(key string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{
+			tokens:   rl.burst,
+			lastSeen: now,
+		}
+		rl.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	bucket.tokens = math.Min(rl.burst, bucket.tokens+elapsed*rl.ratePerSecond)
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		wait := (1 - bucket.tokens) / rl.ratePerSecond
+		return false, time.Duration(math.Ceil(wait)) * time.Second
+	}
+
+	bucket.tokens--
+	return true, 0
+}
+
+// sweep membuang bucket yang sudah penuh kembali agar map tidak tumbuh terus
+func (rl *InMemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < time.Minute {
+		return
+	}
+	rl.lastSweep = now
+
+	fullAfter := time.Duration(rl.burst/rl.ratePerSecond) * time.Second
+	for key, bucket := range rl.buckets {
+		if now.Sub(bucket.lastSeen) > fullAfter {
+			delete(rl.buckets, key)
+		}
+	}
+}
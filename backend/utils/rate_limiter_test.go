@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// clock dipakai sebagai rl.now agar waktu bisa dimajukan tanpa sleep
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+func (c *clock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newTestRateLimiter(ratePerMinute float64, burst int) (*InMemoryRateLimiter, *clock) {
+	c := &clock{t: time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)}
+	rl := NewInMemoryRateLimiter(ratePerMinute, burst)
+	rl.now = c.now
+
+	return rl, c
+}
+
+func TestInMemoryRateLimiterAllow(t *testing.T) {
+	type step struct {
+		advance   time.Duration
+		wantAllow bool
+		wantWait  time.Duration
+	}
+
+	tests := []struct {
+		name          string
+		ratePerMinute float64
+		burst         int
+		steps         []step
+	}{
+		{
+			name:          "burst is allowed then limited",
+			ratePerMinute: 6,
+			burst:         3,
+			steps: []step{
+				{wantAllow: true},
+				{wantAllow: true},
+				{wantAllow: true},
+				{wantAllow: false, wantWait: 10 * time.Second},
+			},
+		},
+		{
+			name:          "one token refills after its interval",
+			ratePerMinute: 6,
+			burst:         1,
+			steps: []step{
+				{wantAllow: true},
+				{advance: 4 * time.Second, wantAllow: false, wantWait: 6 * time.Second},
+				{advance: 6 * time.Second, wantAllow: true},
+				{wantAllow: false, wantWait: 10 * time.Second},
+			},
+		},
+		{
+			name:          "partial refill rounds the wait up",
+			ratePerMinute: 6,
+			burst:         1,
+			steps: []step{
+				{wantAllow: true},
+				{advance: 2500 * time.Millisecond, wantAllow: false, wantWait: 8 * time.Second},
+			},
+		},
+		{
+			name:          "refill is capped at burst",
+			ratePerMinute: 60,
+			burst:         2,
+			steps: []step{
+				{wantAllow: true},
+				{wantAllow: true},
+				{advance: time.Hour, wantAllow: true},
+				{wantAllow: true},
+				{wantAllow: false, wantWait: time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl, c := newTestRateLimiter(tt.ratePerMinute, tt.burst)
+
+			for i, s := range tt.steps {
+				c.advance(s.advance)
+
+				allowed, wait := rl.Allow("user")
+				if allowed != s.wantAllow || wait != s.wantWait {
+					t.Fatalf("step %d: Allow() = %v, %v, want %v, %v", i, allowed, wait, s.wantAllow, s.wantWait)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryRateLimiterKeysAreIndependent(t *testing.T) {
+	rl, _ := newTestRateLimiter(6, 1)
+
+	if allowed, _ := rl.Allow("a"); !allowed {
+		t.Fatal("first request of a must be allowed")
+	}
+	if allowed, _ := rl.Allow("a"); allowed {
+		t.Fatal("second request of a must be limited")
+	}
+	if allowed, _ := rl.Allow("b"); !allowed {
+		t.Error("b must not share the bucket of a")
+	}
+}
+
+func TestInMemoryRateLimiterSweep(t *testing.T) {
+	// bucket penuh kembali setelah 2 token / 6 per menit = 20 detik
+	rl, c := newTestRateLimiter(6, 2)
+
+	rl.Allow("idle")
+	rl.Allow("active")
+
+	c.advance(50 * time.Second)
+	rl.Allow("active")
+
+	// sweep paling cepat sekali per menit sejak sweep terakhir
+	c.advance(15 * time.Second)
+	rl.Allow("active")
+
+	if _, ok := rl.buckets["idle"]; ok {
+		t.Error("bucket idle for longer than its refill time must be evicted")
+	}
+	if _, ok := rl.buckets["active"]; !ok {
+		t.Error("recently used bucket must be kept")
+	}
+
+	// bucket yang dibuang mulai lagi dari burst penuh
+	for i := 0; i < 2; i++ {
+		if allowed, _ := rl.Allow("idle"); !allowed {
+			t.Fatalf("request %d of an evicted key must be allowed", i)
+		}
+	}
+	if allowed, _ := rl.Allow("idle"); allowed {
+		t.Error("evicted key must still be limited after its burst")
+	}
+}
+
+func TestInMemoryRateLimiterSweepIsThrottled(t *testing.T) {
+	rl, c := newTestRateLimiter(60, 1)
+
+	// sweep pertama terjadi di Allow pertama, sebelum bucket ada
+	rl.Allow("idle")
+	c.advance(30 * time.Second)
+	rl.Allow("other")
+
+	if _, ok := rl.buckets["idle"]; !ok {
+		t.Error("bucket must not be evicted before a minute since the last sweep")
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import "unicode/utf8"
+
+// Tokenizer menghitung perkiraan jumlah token sebuah teks. Dibuat sebagai
+// interface agar perhitungan bisa diganti tokenizer asli provider atau fake saat testing.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// ApproxTokenizer memakai heuristik ~4 karakter per token yang cukup dekat
+// untuk model keluarga GPT tanpa perlu dependency tokenizer.
+type ApproxTokenizer struct{}
+
+func NewApproxTokenizer() *ApproxTokenizer {
+	return &ApproxTokenizer{}
+}
+
+func (t *ApproxTokenizer) CountTokens(text string) int {
+	n := utf8.RuneCountInString(text)
+	if n == 0 {
+		return 0
+	}
+
+	return (n + 3) / 4
+}
@@ -93,9 +93,8 @@ const DetailDoctorPage = () => {
       errors.email = "Email is invalid";
     }
 
-    if (!formData.password.trim()) {
-      errors.password = "Password is required";
-    } else if (formData.password.length < 6) {
+    // password kosong berarti tidak diubah
+    if (formData.password && formData.password.length < 6) {
       errors.password = "Password must be at least 6 characters";
     }
 
@@ -174,7 +173,7 @@ const DetailDoctorPage = () => {
             image: data.psy_image,
             str_number: data.psy_str_number,
             email: data.psy_email,
-            password: data.psy_password,
+            password: "",
             work_year: data.psy_work_year,
             description: data.psy_description,
             phone_number: data.psy_phone_number,
@@ -446,7 +445,7 @@ const DetailDoctorPage = () => {
         form.append("str_number", formData.str_number);
       }
 
-      if (formData.password !== original.psy_password) {
+      if (formData.password) {
         form.append("password", formData.password);
       }
 
@@ -547,7 +546,7 @@ const DetailDoctorPage = () => {
             name: newData.psy_name,
             str_number: newData.psy_str_number,
             email: newData.psy_email,
-            password: newData.psy_password,
+            password: "",
             image: newData.psy_image,
             work_year: newData.psy_work_year,
             description: newData.psy_description,
@@ -66,7 +66,7 @@ const UpdateProfilePage = () => {
             name: data.psy_name,
             str_number: data.psy_str_number,
             email: data.psy_email,
-            password: data.psy_password,
+            password: "",
             work_year: data.psy_work_year,
             description: data.psy_description,
             phone_number: data.psy_phone_number,
@@ -79,7 +79,6 @@ const UpdateProfilePage = () => {
           setValue("name", data.psy_name);
           setValue("str_number", data.psy_str_number);
           setValue("email", data.psy_email);
-          setValue("password", data.psy_password);
           setValue("work_year", data.psy_work_year);
           setValue("description", data.psy_description);
           setValue("phone_number", data.psy_phone_number);
@@ -22,7 +22,6 @@ export type Psycholog = {
   psy_name: string;
   psy_str_number: string;
   psy_email: string;
-  psy_password: string;
   psy_work_year: string;
   psy_description: string;
   psy_phone_number: string;
@@ -82,7 +81,6 @@ export type PsychologResponse = {
     psy_name: string;
     psy_str_number: string;
     psy_email: string;
-    psy_password: string;
     psy_work_year: string;
     psy_description: string;
     psy_phone_number: string;
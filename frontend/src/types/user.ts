@@ -2,7 +2,6 @@ export type User = {
   user_id: string;
   user_name: string;
   user_email: string;
-  user_password: string;
   user_image: string;
   user_gender: boolean;
   user_birth_date: string;
@@ -34,7 +33,6 @@ export type AllUserResponse = {
     user_id: string;
     user_name: string;
     user_email: string;
-    user_password: string;
     user_image: string;
     user_gender: boolean;
     user_birth_date: string;
@@ -89,7 +87,6 @@ export type UserResponse = {
     user_id: string;
     user_name: string;
     user_email: string;
-    user_password: string;
     user_image: string;
     user_gender: boolean;
     user_birth_date: string;
@@ -68,7 +68,11 @@ const psychologSchema = z.object({
   name: z.string().min(1, "Nama tidak boleh kosong"),
   str_number: z.string().min(1, "Nomor STR tidak boleh kosong"),
   email: z.string().email("Email tidak valid"),
-  password: z.string().min(8, "Password harus minimal 8 karakter"),
+  // kosong = password tidak diubah, API tidak lagi mengirim password
+  password: z
+    .string()
+    .min(8, "Password harus minimal 8 karakter")
+    .or(z.literal("")),
   work_year: z.string().min(1, "Tahun kerja tidak boleh kosong"),
   description: z.string().min(1, "Deskripsi tidak boleh kosong"),
   phone_number: z.string().min(8, "Nomor telepon tidak valid"),